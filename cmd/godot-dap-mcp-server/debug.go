@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/tools"
+)
+
+// stringList is a flag that may be given several times
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// debugRun drives the MCP tools directly, printing every tool call and its
+// result as one JSON line so runs can be scripted and compared with an
+// agent's tool calls
+type debugRun struct {
+	server *mcp.Server
+	out    *json.Encoder
+	failed bool
+}
+
+// call runs a tool and prints the call. Returns the result and whether the
+// tool succeeded.
+func (r *debugRun) call(name string, args map[string]interface{}) (interface{}, bool) {
+	line := map[string]interface{}{"tool": name, "arguments": args}
+	result, err := r.server.CallTool(name, args)
+	if err != nil {
+		line["error"] = err.Error()
+		r.failed = true
+	} else {
+		line["result"] = result
+	}
+	r.out.Encode(line)
+	return result, err == nil
+}
+
+// parseBreakpoint splits a file:line breakpoint spec. The last colon is the
+// separator, so Windows paths with drive letters work.
+func parseBreakpoint(spec string) (string, int, error) {
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("breakpoint %q must be file:line", spec)
+	}
+	line, err := strconv.Atoi(spec[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("breakpoint %q has an invalid line number", spec)
+	}
+	return spec[:i], line, nil
+}
+
+// runDebug implements the debug subcommand: connect, set breakpoints, launch,
+// wait for a stop, evaluate expressions, and disconnect. Returns the process
+// exit code.
+func runDebug(args []string, stdout io.Writer) int {
+	fs := flag.NewFlagSet("debug", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: godot-dap-mcp-server debug --project DIR [--break FILE:LINE]... [--eval EXPR]...")
+		fmt.Fprintln(fs.Output(), "\nRuns the MCP tools without an MCP client and prints each tool call as a JSON line.")
+		fs.PrintDefaults()
+	}
+	project := fs.String("project", "", "Absolute path to the Godot project directory (required)")
+	port := fs.Int("port", 6006, "Godot editor DAP port")
+	scene := fs.String("scene", "", "Scene to launch (res:// path; default: the project's main scene)")
+	timeout := fs.Int("timeout", 30, "Seconds to wait for a breakpoint to be hit")
+	keepRunning := fs.Bool("keep-running", false, "Leave the game running when done instead of ending it")
+	verbose := fs.Bool("verbose", false, "Write server logs to stderr")
+	var breaks, evals stringList
+	fs.Var(&breaks, "break", "Breakpoint as FILE:LINE (res:// or absolute path; repeatable)")
+	fs.Var(&evals, "eval", "Expression to evaluate once stopped (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *project == "" {
+		fmt.Fprintln(os.Stderr, "debug: --project is required")
+		fs.Usage()
+		return 2
+	}
+
+	type breakpoint struct {
+		file string
+		line int
+	}
+	var parsed []breakpoint
+	for _, spec := range breaks {
+		file, line, err := parseBreakpoint(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "debug: %v\n", err)
+			return 2
+		}
+		parsed = append(parsed, breakpoint{file, line})
+	}
+
+	if *verbose {
+		log.SetOutput(os.Stderr)
+	} else {
+		log.SetOutput(io.Discard)
+	}
+
+	// Notifications have no client to go to in this mode
+	server := mcp.NewServerWithTransport(mcp.NewTransportWithStreams(strings.NewReader(""), io.Discard))
+	tools.RegisterAll(server)
+	run := &debugRun{server: server, out: json.NewEncoder(stdout)}
+
+	if _, ok := run.call("godot_connect", map[string]interface{}{
+		"port":    float64(*port),
+		"project": *project,
+	}); !ok {
+		return 1
+	}
+	defer func() {
+		run.call("godot_disconnect", map[string]interface{}{"terminate": !*keepRunning})
+	}()
+
+	for _, bp := range parsed {
+		run.call("godot_set_breakpoint", map[string]interface{}{
+			"file": bp.file,
+			"line": float64(bp.line),
+		})
+	}
+
+	// Without breakpoints, expressions are evaluated in the entry stop
+	launchArgs := map[string]interface{}{
+		"project":       *project,
+		"stop_on_entry": len(parsed) == 0 && len(evals) > 0,
+	}
+	launchTool := "godot_launch_main_scene"
+	if *scene != "" {
+		launchTool = "godot_launch_scene"
+		launchArgs["scene"] = *scene
+	}
+	if _, ok := run.call(launchTool, launchArgs); !ok {
+		return 1
+	}
+
+	if len(evals) == 0 {
+		return run.exitCode()
+	}
+
+	if len(parsed) > 0 && !run.paused() {
+		result, ok := run.call("godot_wait_for_event", map[string]interface{}{
+			"types":   []interface{}{"stopped", "terminated", "exited"},
+			"timeout": float64(*timeout),
+		})
+		if event, _ := result.(map[string]interface{}); !ok || event["event"] != "stopped" {
+			fmt.Fprintln(os.Stderr, "debug: the game did not stop; skipping --eval")
+			run.failed = true
+			return run.exitCode()
+		}
+	}
+
+	run.call("godot_get_stop_context", nil)
+	for _, expression := range evals {
+		run.call("godot_evaluate", map[string]interface{}{"expression": expression})
+	}
+	return run.exitCode()
+}
+
+// paused reports whether the game is already stopped, e.g. because a
+// breakpoint was hit before waiting started
+func (r *debugRun) paused() bool {
+	result, err := r.server.CallTool("godot_get_session_state", nil)
+	if err != nil {
+		return false
+	}
+	state, _ := result.(map[string]interface{})
+	return state["run_state"] == "paused"
+}
+
+// exitCode is 1 if any tool call failed
+func (r *debugRun) exitCode() int {
+	if r.failed {
+		return 1
+	}
+	return 0
+}
@@ -1,21 +1,47 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/logging"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/tools"
 )
 
 func main() {
+	// godot-dap-mcp-server debug ... drives the tools from the command line
+	if len(os.Args) > 1 && os.Args[1] == "debug" {
+		os.Exit(runDebug(os.Args[2:], os.Stdout))
+	}
+
+	dumpTools := flag.Bool("dump-tools", false, "Print the registered tool catalog as JSON and exit")
+	flag.Parse()
+
+	if *dumpTools {
+		if err := printToolCatalog(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to dump tools: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Configure logging
 	// By default, log to stderr (MCP clients usually capture this)
-	// Can be overridden by GODOT_MCP_LOG_FILE environment variable
-	logOutput := os.Stderr
+	// Can be overridden by GODOT_MCP_LOG_FILE environment variable; the file
+	// is rotated at GODOT_MCP_LOG_MAX_SIZE_MB (default 10), keeping
+	// GODOT_MCP_LOG_MAX_FILES rotated files (default 3)
+	var logOutput io.Writer = os.Stderr
 
 	if logPath := os.Getenv("GODOT_MCP_LOG_FILE"); logPath != "" {
-		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		maxSize := int64(envInt("GODOT_MCP_LOG_MAX_SIZE_MB", 0)) * 1024 * 1024
+		maxFiles := envInt("GODOT_MCP_LOG_MAX_FILES", -1)
+		f, err := logging.OpenRotatingFile(logPath, maxSize, maxFiles)
 		if err != nil {
 			log.Printf("Failed to open log file %s: %v", logPath, err)
 		} else {
@@ -31,7 +57,20 @@ func main() {
 	log.Println("Starting Godot DAP MCP Server...")
 
 	// Create MCP server
+	// GODOT_MCP_RECORD_FILE records every MCP message as JSON lines, for
+	// replaying the session later (see mcp.Replay)
 	server := mcp.NewServer()
+	if recordPath := os.Getenv("GODOT_MCP_RECORD_FILE"); recordPath != "" {
+		f, err := os.Create(recordPath)
+		if err != nil {
+			log.Printf("Failed to open record file %s: %v", recordPath, err)
+		} else {
+			defer f.Close()
+			rec := mcp.NewRecorder(f)
+			server = mcp.NewServerWithTransport(mcp.NewTransportWithStreams(rec.WrapReader(os.Stdin), rec.WrapWriter(os.Stdout)))
+			log.Printf("Recording MCP session to %s", recordPath)
+		}
+	}
 
 	// Register all tools
 	tools.RegisterAll(server)
@@ -45,3 +84,32 @@ func main() {
 
 	log.Println("Server shutdown complete")
 }
+
+// printToolCatalog writes the full tool catalog (names, descriptions, input
+// schemas) as JSON, in the same shape as the tools/list result
+func printToolCatalog(w io.Writer) error {
+	// Registration logs each tool; keep that out of the catalog output
+	log.SetOutput(io.Discard)
+
+	server := mcp.NewServer()
+	tools.RegisterAll(server)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(mcp.ToolListResult{Tools: server.ToolCatalog()})
+}
+
+// envInt reads an integer environment variable, returning def if it is
+// unset or invalid
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q: %v", name, value, err)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,375 @@
+// Command godot-debug-tui is a terminal debugger for Godot games built on
+// the same DAP client as the MCP server. It shows the stack, variables,
+// breakpoints, and game output, with single-key commands for stepping, and
+// doubles as a manual test harness for the client.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	godap "github.com/google/go-dap"
+)
+
+// Godot reports a single thread
+const mainThread = 1
+
+// stringList is a flag that may be given several times
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// app holds the debugger state shared by the event and input loops
+type app struct {
+	mu      sync.Mutex
+	session *dap.Session
+	client  *dap.Client
+	out     io.Writer
+	view    screen
+}
+
+func main() {
+	port := flag.Int("port", 6006, "Godot editor DAP port")
+	project := flag.String("project", "", "Absolute path to the Godot project directory (required)")
+	scene := flag.String("scene", "", "Scene to launch (res:// path; default: the project's main scene)")
+	var breaks stringList
+	flag.Var(&breaks, "break", "Initial breakpoint as FILE:LINE (res:// or absolute path; repeatable)")
+	flag.Parse()
+
+	if *project == "" {
+		fmt.Fprintln(os.Stderr, "Usage: godot-debug-tui --project DIR [--port 6006] [--scene res://...] [--break FILE:LINE]...")
+		os.Exit(2)
+	}
+
+	// Exit only once run's deferred cleanup (session close, terminal
+	// restore) has happened
+	if err := run(*port, *project, *scene, breaks); err != nil {
+		fmt.Fprintf(os.Stderr, "godot-debug-tui: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run connects, launches the game, and runs the UI until the user quits
+func run(port int, project string, scene string, breaks []string) error {
+	// The screen belongs to the UI; client logs would garble it
+	log.SetOutput(io.Discard)
+
+	session := dap.NewSession("localhost", port, dap.WithLogger(log.New(io.Discard, "", 0)))
+	if err := session.InitializeSession(context.Background()); err != nil {
+		return fmt.Errorf("failed to connect to Godot on port %d: %w", port, err)
+	}
+	defer session.Close()
+	session.SetProjectRoot(project)
+
+	a := &app{
+		session: session,
+		client:  session.GetClient(),
+		out:     os.Stdout,
+		view: screen{
+			ProjectRoot: project,
+			Breakpoints: map[string][]int{},
+			Status:      "Launching...",
+		},
+	}
+
+	for _, spec := range breaks {
+		a.toggleBreakpoint(spec)
+	}
+
+	events, cleanup := a.client.SubscribeToEvents()
+	defer cleanup()
+	go a.followEvents(events)
+
+	config := &dap.GodotLaunchConfig{Project: project, Scene: dap.SceneLaunchMain, Platform: dap.PlatformHost}
+	if scene != "" {
+		config.Scene = dap.SceneLaunchCustom
+		config.ScenePath = scene
+	}
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	_, err := session.LaunchGodotScene(ctx, config)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to launch: %w", err)
+	}
+	a.setStatus("Game launched")
+
+	restore, cbreak := enterCbreak()
+	fmt.Fprint(a.out, hideCursor)
+	defer func() {
+		restore()
+		fmt.Fprint(a.out, showCursor+clearScreen)
+	}()
+
+	a.runInput(bufio.NewReader(os.Stdin), cbreak)
+	return nil
+}
+
+// draw redraws the screen; callers hold a.mu
+func (a *app) draw() {
+	a.view.State = a.session.GetState().String()
+	a.view.RunState = a.client.RunState()
+	a.view.Output = a.client.Output()
+	rows, cols := terminalSize()
+	fmt.Fprint(a.out, render(&a.view, rows, cols))
+}
+
+// setStatus shows a message in the status line
+func (a *app) setStatus(format string, args ...interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.view.Status = fmt.Sprintf(format, args...)
+	a.draw()
+}
+
+// followEvents keeps the panes in sync with the game
+func (a *app) followEvents(events <-chan godap.Message) {
+	for msg := range events {
+		switch e := msg.(type) {
+		case *godap.StoppedEvent:
+			a.refresh(fmt.Sprintf("Paused (%s)", e.Body.Reason))
+		case *godap.ContinuedEvent:
+			a.mu.Lock()
+			a.view.Frames, a.view.Scopes = nil, nil
+			a.draw()
+			a.mu.Unlock()
+		case *godap.TerminatedEvent, *godap.ExitedEvent:
+			a.mu.Lock()
+			a.view.Frames, a.view.Scopes = nil, nil
+			a.view.Status = "Game ended (q to quit)"
+			a.draw()
+			a.mu.Unlock()
+		case *godap.OutputEvent:
+			a.mu.Lock()
+			a.draw()
+			a.mu.Unlock()
+		}
+	}
+}
+
+// refresh fetches the stack and the selected frame's variables
+func (a *app) refresh(status string) {
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.view.Status = status
+
+	resp, err := a.client.StackTrace(ctx, mainThread, 0, 20)
+	if err != nil {
+		a.view.Status = fmt.Sprintf("Stack trace failed: %v", err)
+		a.draw()
+		return
+	}
+	a.view.Frames = resp.Body.StackFrames
+	if a.view.Selected >= len(a.view.Frames) {
+		a.view.Selected = 0
+	}
+	a.view.Scopes = a.loadScopes(ctx)
+	a.draw()
+}
+
+// loadScopes fetches the variables of the selected frame; callers hold a.mu
+func (a *app) loadScopes(ctx context.Context) []scopeVariables {
+	if len(a.view.Frames) == 0 {
+		return nil
+	}
+	scopes, err := a.client.Scopes(ctx, a.view.Frames[a.view.Selected].Id)
+	if err != nil {
+		a.view.Status = fmt.Sprintf("Scopes failed: %v", err)
+		return nil
+	}
+	var result []scopeVariables
+	for _, scope := range scopes.Body.Scopes {
+		vars, err := a.client.Variables(ctx, scope.VariablesReference)
+		if err != nil {
+			continue
+		}
+		result = append(result, scopeVariables{Name: scope.Name, Variables: vars.Body.Variables})
+	}
+	return result
+}
+
+// selectFrame moves the frame selection by delta and reloads its variables
+func (a *app) selectFrame(delta int) {
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	next := a.view.Selected + delta
+	if next < 0 || next >= len(a.view.Frames) {
+		return
+	}
+	a.view.Selected = next
+	a.view.Scopes = a.loadScopes(ctx)
+	a.draw()
+}
+
+// resolvePath turns a res:// path into an absolute one
+func (a *app) resolvePath(file string) string {
+	if strings.HasPrefix(file, "res://") {
+		return filepath.Join(a.view.ProjectRoot, strings.TrimPrefix(file, "res://"))
+	}
+	return file
+}
+
+// toggleBreakpoint adds or removes the breakpoint in a FILE:LINE spec and
+// sends the file's full breakpoint list
+func (a *app) toggleBreakpoint(spec string) {
+	i := strings.LastIndex(spec, ":")
+	line, err := strconv.Atoi(strings.TrimSpace(spec[i+1:]))
+	if i <= 0 || err != nil || line < 1 {
+		a.setStatus("Breakpoints are FILE:LINE (got %q)", spec)
+		return
+	}
+
+	a.mu.Lock()
+	path := a.resolvePath(strings.TrimSpace(spec[:i]))
+	lines := a.view.Breakpoints[path]
+	removed := false
+	for j, l := range lines {
+		if l == line {
+			lines = append(lines[:j:j], lines[j+1:]...)
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		lines = append(lines, line)
+		sort.Ints(lines)
+	}
+	a.mu.Unlock()
+
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+	if _, err := a.client.SetBreakpoints(ctx, path, lines); err != nil {
+		a.setStatus("Setting breakpoints failed: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	if len(lines) == 0 {
+		delete(a.view.Breakpoints, path)
+	} else {
+		a.view.Breakpoints[path] = lines
+	}
+	a.mu.Unlock()
+
+	if removed {
+		a.setStatus("Removed breakpoint %s", spec)
+	} else {
+		a.setStatus("Added breakpoint %s", spec)
+	}
+}
+
+// readLine reads a line of input, echoing it in the prompt line.
+// Returns "" if the input is cancelled with Escape.
+func (a *app) readLine(in *bufio.Reader, prompt string, cbreak bool) string {
+	if !cbreak {
+		fmt.Fprint(a.out, "\r\n"+prompt)
+		line, _ := in.ReadString('\n')
+		return strings.TrimSpace(line)
+	}
+
+	var input []rune
+	for {
+		a.mu.Lock()
+		a.view.Prompt = prompt + string(input) + "█"
+		a.draw()
+		a.mu.Unlock()
+
+		r, _, err := in.ReadRune()
+		if err != nil {
+			r = '\x1b'
+		}
+		switch r {
+		case '\r', '\n', '\x1b':
+			a.mu.Lock()
+			a.view.Prompt = ""
+			a.mu.Unlock()
+			if r == '\x1b' {
+				return ""
+			}
+			return strings.TrimSpace(string(input))
+		case '\x7f', '\b':
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			input = append(input, r)
+		}
+	}
+}
+
+// runInput handles key commands until q or end of input
+func (a *app) runInput(in *bufio.Reader, cbreak bool) {
+	a.mu.Lock()
+	a.draw()
+	a.mu.Unlock()
+
+	for {
+		r, _, err := in.ReadRune()
+		if err != nil {
+			return
+		}
+
+		ctx, cancel := dap.WithCommandTimeout(context.Background())
+		switch r {
+		case 'q':
+			cancel()
+			return
+		case 'c':
+			if _, err := a.client.Continue(ctx, mainThread); err != nil {
+				a.setStatus("Continue failed: %v", err)
+			} else {
+				a.setStatus("Running")
+			}
+		case 'n':
+			if _, err := a.client.Next(ctx, mainThread); err != nil {
+				a.setStatus("Step over failed: %v", err)
+			}
+		case 's':
+			if _, err := a.client.StepIn(ctx, mainThread); err != nil {
+				a.setStatus("Step into failed: %v", err)
+			}
+		case 'p':
+			if _, err := a.client.Pause(ctx, mainThread); err != nil {
+				a.setStatus("Pause failed: %v", err)
+			}
+		case '[':
+			a.selectFrame(-1)
+		case ']':
+			a.selectFrame(1)
+		case 'r':
+			if a.client.RunState() == dap.RunStatePaused {
+				a.refresh("Refreshed")
+			} else {
+				a.setStatus("Refreshed")
+			}
+		case 'b':
+			if spec := a.readLine(in, "Toggle breakpoint (FILE:LINE): ", cbreak); spec != "" {
+				a.toggleBreakpoint(spec)
+			} else {
+				a.setStatus("")
+			}
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ANSI sequences used to draw the screen
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	bold        = "\x1b[1m"
+	dim         = "\x1b[2m"
+	reverse     = "\x1b[7m"
+	reset       = "\x1b[0m"
+)
+
+// stty runs stty against the controlling terminal
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// enterCbreak switches the terminal to unbuffered input without echo, so
+// single keys can act as commands. Returns a function restoring the previous
+// settings, and false if the terminal can't be switched (not a terminal, or
+// no stty); keys then need Enter.
+func enterCbreak() (func(), bool) {
+	saved, err := stty("-g")
+	if err != nil {
+		return func() {}, false
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return func() {}, false
+	}
+	return func() { stty(saved) }, true
+}
+
+// terminalSize returns the terminal's rows and columns, or 24x80 if unknown
+func terminalSize() (int, int) {
+	out, err := stty("size")
+	if err != nil {
+		return 24, 80
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 24, 80
+	}
+	rows, err1 := strconv.Atoi(fields[0])
+	cols, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || rows < 10 || cols < 20 {
+		return 24, 80
+	}
+	return rows, cols
+}
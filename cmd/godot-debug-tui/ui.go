@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	godap "github.com/google/go-dap"
+)
+
+// Fixed pane heights; the output pane gets the remaining rows
+const (
+	stackPaneRows       = 8
+	variablesPaneRows   = 12
+	breakpointsPaneRows = 4
+	minOutputPaneRows   = 3
+)
+
+// keyHelp is shown in the footer
+const keyHelp = "c continue  n step over  s step into  p pause  [ ] frame  b toggle breakpoint  r refresh  q quit"
+
+// scopeVariables holds the variables of one scope of the selected frame
+type scopeVariables struct {
+	Name      string
+	Variables []godap.Variable
+}
+
+// screen is everything the UI shows. It is filled by the app and drawn by
+// render, which has no side effects.
+type screen struct {
+	ProjectRoot string
+	State       string // Session state
+	RunState    dap.RunState
+	Status      string // Result of the last command
+	Frames      []godap.StackFrame
+	Selected    int
+	Scopes      []scopeVariables
+	Breakpoints map[string][]int // Absolute path → lines
+	Output      []dap.OutputRecord
+	Prompt      string // Non-empty while reading a line of input
+}
+
+// displayPath shows paths inside the project as res:// paths
+func displayPath(path string, projectRoot string) string {
+	if projectRoot != "" {
+		if rel, err := filepath.Rel(projectRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return "res://" + filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+// fit truncates s to width columns and pads it to exactly width
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// pane renders a titled section of exactly rows lines (title included)
+func pane(b *strings.Builder, title string, lines []string, rows int, width int) {
+	b.WriteString(bold + fit("── "+title+" "+strings.Repeat("─", width), width) + reset + "\r\n")
+	for i := 0; i < rows-1; i++ {
+		if i < len(lines) {
+			b.WriteString(fit(lines[i], width))
+		} else {
+			b.WriteString(strings.Repeat(" ", width))
+		}
+		b.WriteString("\r\n")
+	}
+}
+
+// render draws the whole screen for a terminal of the given size
+func render(s *screen, rows int, cols int) string {
+	var b strings.Builder
+	b.WriteString(clearScreen)
+
+	header := fmt.Sprintf(" Godot debugger │ session: %s │ game: %s", s.State, s.RunState)
+	if s.ProjectRoot != "" {
+		header += " │ " + s.ProjectRoot
+	}
+	b.WriteString(reverse + fit(header, cols) + reset + "\r\n")
+
+	// Stack
+	var stack []string
+	for i, frame := range s.Frames {
+		marker := "  "
+		if i == s.Selected {
+			marker = "▶ "
+		}
+		location := ""
+		if frame.Source != nil {
+			location = fmt.Sprintf("%s:%d", displayPath(frame.Source.Path, s.ProjectRoot), frame.Line)
+		}
+		stack = append(stack, fmt.Sprintf("%s#%d %s  %s", marker, i, frame.Name, location))
+	}
+	if len(stack) == 0 {
+		stack = []string{"(not paused)"}
+	}
+	pane(&b, "Stack", stack, stackPaneRows, cols)
+
+	// Variables of the selected frame
+	var variables []string
+	for _, scope := range s.Scopes {
+		variables = append(variables, scope.Name)
+		for _, v := range scope.Variables {
+			variables = append(variables, fmt.Sprintf("  %s: %s = %s", v.Name, v.Type, v.Value))
+		}
+	}
+	pane(&b, "Variables", variables, variablesPaneRows, cols)
+
+	// Breakpoints
+	paths := make([]string, 0, len(s.Breakpoints))
+	for path := range s.Breakpoints {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var breakpoints []string
+	for _, path := range paths {
+		lines := make([]string, len(s.Breakpoints[path]))
+		for i, line := range s.Breakpoints[path] {
+			lines[i] = fmt.Sprint(line)
+		}
+		breakpoints = append(breakpoints, fmt.Sprintf("%s: %s", displayPath(path, s.ProjectRoot), strings.Join(lines, ", ")))
+	}
+	pane(&b, "Breakpoints", breakpoints, breakpointsPaneRows, cols)
+
+	// Output gets what's left; header, footer, and status take 3 rows
+	outputRows := rows - 3 - stackPaneRows - variablesPaneRows - breakpointsPaneRows
+	if outputRows < minOutputPaneRows {
+		outputRows = minOutputPaneRows
+	}
+	var output []string
+	for _, record := range s.Output {
+		for _, line := range strings.Split(strings.TrimRight(record.Output, "\n"), "\n") {
+			output = append(output, line)
+		}
+	}
+	if len(output) > outputRows-1 {
+		output = output[len(output)-(outputRows-1):]
+	}
+	pane(&b, "Output", output, outputRows, cols)
+
+	if s.Prompt != "" {
+		b.WriteString(fit(s.Prompt, cols))
+	} else {
+		b.WriteString(fit(s.Status, cols) + "\r\n")
+		b.WriteString(dim + fit(keyHelp, cols) + reset)
+	}
+	return b.String()
+}
@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
 )
 
 // ANSI color codes
@@ -196,10 +198,11 @@ func init() {
 }
 
 func main() {
+	mock := flag.Bool("mock", false, "Run against a simulated Godot (daptest.SimulateGodot) instead of the editor on localhost:6006")
 	flag.Parse()
 	args := flag.Args()
 	if len(args) < 1 {
-		fmt.Println("Usage: go run main.go <scenario_file>")
+		fmt.Println("Usage: go run main.go [--mock] <scenario_file>")
 		os.Exit(1)
 	}
 
@@ -212,15 +215,28 @@ func main() {
 
 	printHeader(scenarioFile)
 
-	conn, err := connectToGodot()
+	addr := "localhost:6006"
+	if *mock {
+		server, err := daptest.Listen()
+		if err != nil {
+			fmt.Printf("%s✗ Failed to start the simulated Godot: %v%s\n", colorRed, err, colorReset)
+			os.Exit(1)
+		}
+		defer server.Close()
+		go server.Serve(daptest.SimulateGodot(server))
+		addr = server.Address()
+		fmt.Printf("%s[INFO] Using a simulated Godot; responses come from daptest.SimulateGodot%s\n", colorYellow, colorReset)
+	}
+
+	conn, err := connectToGodot(addr)
 	if err != nil {
 		fmt.Printf("%s✗ Failed to connect: %v%s\n", colorRed, err, colorReset)
-		fmt.Println("Make sure Godot editor is running with DAP enabled")
+		fmt.Println("Make sure Godot editor is running with DAP enabled, or pass --mock")
 		os.Exit(1)
 	}
 	defer conn.Close()
 
-	fmt.Printf("%s✓ Connected to localhost:6006%s\n\n", colorGreen, colorReset)
+	fmt.Printf("%s✓ Connected to %s%s\n\n", colorGreen, addr, colorReset)
 
 	reader := bufio.NewReader(conn)
 	stdin := bufio.NewReader(os.Stdin)
@@ -286,8 +302,8 @@ func readScenario(path string) ([]string, error) {
 // ... (Copy helper functions from test-dap-protocol: connectToGodot, sendDAPMessage, readAllDAPMessages, readDAPMessage, checkTerminationEvents)
 // ... (Also printHeader, printInstructions, printSummary, runTest)
 
-func connectToGodot() (net.Conn, error) {
-	return net.DialTimeout("tcp", "localhost:6006", 5*time.Second)
+func connectToGodot(addr string) (net.Conn, error) {
+	return net.DialTimeout("tcp", addr, 5*time.Second)
 }
 
 func sendDAPMessage(conn net.Conn, message map[string]interface{}) error {
@@ -0,0 +1,294 @@
+package dap
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/google/go-dap"
+)
+
+// lineStrings maps absolute path → line → a string attached to the
+// breakpoint there (its condition, hit condition, log message, or function)
+type lineStrings map[string]map[int]string
+
+// set replaces the values of a file's breakpoints; none forgets the file
+func (l *lineStrings) set(path string, values map[int]string) {
+	if len(values) == 0 {
+		delete(*l, path)
+		return
+	}
+	if *l == nil {
+		*l = lineStrings{}
+	}
+	copied := make(map[int]string, len(values))
+	for line, value := range values {
+		copied[line] = value
+	}
+	(*l)[path] = copied
+}
+
+// keep forgets the values of a file's lines that aren't listed
+func (l *lineStrings) keep(path string, lines []int) {
+	kept := map[int]string{}
+	for _, line := range lines {
+		if value, ok := (*l)[path][line]; ok {
+			kept[line] = value
+		}
+	}
+	l.set(path, kept)
+}
+
+// remap moves a file's values to the lines move maps its lines to, in the
+// order of lines. Of lines moved onto the same line, the first one's value
+// is kept, even if it has none.
+func (l *lineStrings) remap(path string, lines []int, move func(line int) int) {
+	moved := map[int]string{}
+	taken := map[int]bool{}
+	for _, line := range lines {
+		to := move(line)
+		if taken[to] {
+			continue
+		}
+		taken[to] = true
+		if value, ok := (*l)[path][line]; ok {
+			moved[to] = value
+		}
+	}
+	l.set(path, moved)
+}
+
+// copy returns a deep copy, or nil if there are no values
+func (l lineStrings) copy() map[string]map[int]string {
+	if len(l) == 0 {
+		return nil
+	}
+	copied := make(map[string]map[int]string, len(l))
+	for path, lines := range l {
+		copied[path] = make(map[int]string, len(lines))
+		for line, value := range lines {
+			copied[path][line] = value
+		}
+	}
+	return copied
+}
+
+// RequestedBreakpoint is one breakpoint line recorded in a BreakpointRegistry
+// with everything recorded about it
+type RequestedBreakpoint struct {
+	File         string
+	Line         int
+	Condition    string
+	HitCondition string
+	LogMessage   string
+	Function     string // Function of an emulated function breakpoint
+}
+
+// BreakpointRegistry remembers the breakpoint lines requested per file, the
+// conditions and hit conditions of conditional ones, the messages of
+// logpoints, and the functions of emulated function breakpoints. Each Session
+// has its own, so breakpoints end with the connection they were set on;
+// setBreakpoints replaces a file's breakpoints, so tools rebuild each request
+// from it. The zero value is empty and ready to use.
+type BreakpointRegistry struct {
+	mu            sync.Mutex
+	files         map[string][]int
+	conditions    lineStrings
+	hitConditions lineStrings
+	logMessages   lineStrings
+	functions     lineStrings
+}
+
+// Set records the lines of a file; no lines forgets the file. Conditions,
+// hit conditions, log messages, and functions of lines that remain are kept.
+func (b *BreakpointRegistry) Set(path string, lines []int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(lines) == 0 {
+		delete(b.files, path)
+	} else {
+		if b.files == nil {
+			b.files = map[string][]int{}
+		}
+		b.files[path] = append([]int(nil), lines...)
+	}
+	b.conditions.keep(path, lines)
+	b.hitConditions.keep(path, lines)
+	b.logMessages.keep(path, lines)
+	b.functions.keep(path, lines)
+}
+
+// Remap moves a file's breakpoints, with everything recorded about them, to
+// the lines move maps them to. Breakpoints moved onto the same line are
+// merged into the first. Returns the new lines in request order.
+func (b *BreakpointRegistry) Remap(path string, move func(line int) int) []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	old := b.files[path]
+	lines := MovedLines(old, move)
+	if len(lines) > 0 {
+		b.files[path] = lines
+	}
+	b.conditions.remap(path, old, move)
+	b.hitConditions.remap(path, old, move)
+	b.logMessages.remap(path, old, move)
+	b.functions.remap(path, old, move)
+	return append([]int(nil), lines...)
+}
+
+// MovedLines maps lines through move, dropping lines moved onto an earlier one
+func MovedLines(lines []int, move func(line int) int) []int {
+	var moved []int
+	for _, line := range lines {
+		to := move(line)
+		if !slices.Contains(moved, to) {
+			moved = append(moved, to)
+		}
+	}
+	return moved
+}
+
+// SetConditions replaces the conditions of a file's breakpoints
+func (b *BreakpointRegistry) SetConditions(path string, conditions map[int]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conditions.set(path, conditions)
+}
+
+// SetHitConditions replaces the hit conditions of a file's breakpoints
+func (b *BreakpointRegistry) SetHitConditions(path string, hitConditions map[int]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hitConditions.set(path, hitConditions)
+}
+
+// SetLogMessages replaces the log messages of a file's logpoints
+func (b *BreakpointRegistry) SetLogMessages(path string, messages map[int]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logMessages.set(path, messages)
+}
+
+// SetFunction records that the breakpoint at path:line was set on entry to
+// function, keeping the file's other functions
+func (b *BreakpointRegistry) SetFunction(path string, line int, function string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	functions := map[int]string{line: function}
+	for l, f := range b.functions[path] {
+		if l != line {
+			functions[l] = f
+		}
+	}
+	b.functions.set(path, functions)
+}
+
+// SetFunctions replaces the functions of a file's function breakpoints
+func (b *BreakpointRegistry) SetFunctions(path string, functions map[int]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.functions.set(path, functions)
+}
+
+// Lines returns a copy of a file's breakpoint lines in request order
+func (b *BreakpointRegistry) Lines(path string) []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]int(nil), b.files[path]...)
+}
+
+// Condition returns the condition of the breakpoint at path:line, if any
+func (b *BreakpointRegistry) Condition(path string, line int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conditions[path][line]
+}
+
+// HitCondition returns the hit condition of the breakpoint at path:line, if any
+func (b *BreakpointRegistry) HitCondition(path string, line int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.hitConditions[path][line]
+}
+
+// LogMessage returns the log message of the logpoint at path:line, if any
+func (b *BreakpointRegistry) LogMessage(path string, line int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.logMessages[path][line]
+}
+
+// AllConditions returns a copy of the recorded conditions
+func (b *BreakpointRegistry) AllConditions() map[string]map[int]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conditions.copy()
+}
+
+// AllHitConditions returns a copy of the recorded hit conditions
+func (b *BreakpointRegistry) AllHitConditions() map[string]map[int]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.hitConditions.copy()
+}
+
+// AllLogMessages returns a copy of the recorded log messages
+func (b *BreakpointRegistry) AllLogMessages() map[string]map[int]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.logMessages.copy()
+}
+
+// AllFunctions returns a copy of the recorded functions
+func (b *BreakpointRegistry) AllFunctions() map[string]map[int]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.functions.copy()
+}
+
+// All returns a copy of the recorded breakpoint lines
+func (b *BreakpointRegistry) All() map[string][]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	files := make(map[string][]int, len(b.files))
+	for path, lines := range b.files {
+		files[path] = append([]int(nil), lines...)
+	}
+	return files
+}
+
+// Requested returns every recorded breakpoint, in no particular order
+func (b *BreakpointRegistry) Requested() []RequestedBreakpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var requested []RequestedBreakpoint
+	for path, lines := range b.files {
+		for _, line := range lines {
+			requested = append(requested, RequestedBreakpoint{
+				File:         path,
+				Line:         line,
+				Condition:    b.conditions[path][line],
+				HitCondition: b.hitConditions[path][line],
+				LogMessage:   b.logMessages[path][line],
+				Function:     b.functions[path][line],
+			})
+		}
+	}
+	return requested
+}
+
+// SourceBreakpoints builds the setBreakpoints arguments for lines of a file
+// with their recorded conditions, hit conditions, and log messages
+func (b *BreakpointRegistry) SourceBreakpoints(path string, lines []int) []dap.SourceBreakpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breakpoints := make([]dap.SourceBreakpoint, len(lines))
+	for i, line := range lines {
+		breakpoints[i] = dap.SourceBreakpoint{
+			Line:         line,
+			Condition:    b.conditions[path][line],
+			HitCondition: b.hitConditions[path][line],
+			LogMessage:   b.logMessages[path][line],
+		}
+	}
+	return breakpoints
+}
@@ -0,0 +1,188 @@
+package dap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/go-dap"
+)
+
+// BreakpointState is a breakpoint as the adapter last reported it, in the
+// setBreakpoints response or a later breakpoint event
+type BreakpointState struct {
+	dap.Breakpoint
+
+	// Line the client requested; 0 for breakpoints the adapter announced
+	// itself (e.g. one toggled in the Godot editor)
+	RequestedLine int
+}
+
+// breakpointTracker follows setBreakpoints requests: how many are still
+// waiting for a response, and the breakpoints the adapter acknowledged for
+// each file. Launches use it to hold configurationDone until every
+// breakpoint is in place, so breakpoints in autoload scripts, which run as
+// soon as the game starts, are hit.
+type breakpointTracker struct {
+	mu       sync.Mutex
+	pending  int
+	settled  chan struct{}                // Closed when pending drops to 0; nil while nothing is pending
+	acked    map[string][]BreakpointState // File → breakpoints from the last response, updated by events
+	failures map[string]error             // File → error of the last request, if it failed
+}
+
+// begin records a setBreakpoints request for the lines of file being sent.
+// The returned function records its outcome and must be called exactly once.
+func (bt *breakpointTracker) begin(file string, lines []int) func(resp *dap.SetBreakpointsResponse, err error) {
+	bt.mu.Lock()
+	bt.pending++
+	if bt.settled == nil {
+		bt.settled = make(chan struct{})
+	}
+	bt.mu.Unlock()
+
+	return func(resp *dap.SetBreakpointsResponse, err error) {
+		bt.mu.Lock()
+		defer bt.mu.Unlock()
+		if bt.acked == nil {
+			bt.acked = make(map[string][]BreakpointState)
+			bt.failures = make(map[string]error)
+		}
+		if err != nil {
+			bt.failures[file] = err
+		} else {
+			delete(bt.failures, file)
+			if len(resp.Body.Breakpoints) == 0 {
+				delete(bt.acked, file)
+			} else {
+				// Breakpoints come back in request order
+				states := make([]BreakpointState, len(resp.Body.Breakpoints))
+				for i, bp := range resp.Body.Breakpoints {
+					states[i] = BreakpointState{Breakpoint: bp}
+					if i < len(lines) {
+						states[i].RequestedLine = lines[i]
+					}
+				}
+				bt.acked[file] = states
+			}
+		}
+		bt.pending--
+		if bt.pending == 0 {
+			close(bt.settled)
+			bt.settled = nil
+		}
+	}
+}
+
+// applyEvent applies breakpoint events, which Godot sends when a breakpoint
+// changes after the fact: "changed" when it is verified or moved (e.g. once
+// its script loads), "new" and "removed" when it is toggled in the editor.
+// Breakpoints are matched by id, or by line if the adapter sent no id.
+func (bt *breakpointTracker) applyEvent(msg dap.Message) {
+	e, ok := msg.(*dap.BreakpointEvent)
+	if !ok || e.Body.Breakpoint.Source == nil {
+		return
+	}
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	updated := e.Body.Breakpoint
+	file := updated.Source.Path
+	states := bt.acked[file]
+	match := -1
+	for i, state := range states {
+		if (updated.Id != 0 && state.Id == updated.Id) || (updated.Id == 0 && state.Line == updated.Line) {
+			match = i
+			break
+		}
+	}
+
+	switch {
+	case e.Body.Reason == "removed":
+		if match < 0 {
+			return
+		}
+		states = append(states[:match:match], states[match+1:]...)
+		if len(states) == 0 {
+			delete(bt.acked, file)
+		} else {
+			bt.acked[file] = states
+		}
+	case match >= 0:
+		states[match].Breakpoint = updated
+	case e.Body.Reason == "new":
+		if bt.acked == nil {
+			bt.acked = make(map[string][]BreakpointState)
+			bt.failures = make(map[string]error)
+		}
+		bt.acked[file] = append(states, BreakpointState{Breakpoint: updated})
+	}
+}
+
+// wait blocks until no setBreakpoints request is waiting for a response
+func (bt *breakpointTracker) wait(ctx context.Context) error {
+	bt.mu.Lock()
+	settled := bt.settled
+	pending := bt.pending
+	bt.mu.Unlock()
+	if settled == nil {
+		return nil
+	}
+	select {
+	case <-settled:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d setBreakpoints request(s) still unanswered: %w", pending, ctx.Err())
+	}
+}
+
+// unverified lists the acknowledged breakpoints the adapter didn't verify,
+// and files whose last request failed, as "file:line" (or "file") sorted
+func (bt *breakpointTracker) unverified() []string {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	var result []string
+	for file, bps := range bt.acked {
+		for _, bp := range bps {
+			if !bp.Verified {
+				result = append(result, fmt.Sprintf("%s:%d", file, bp.Line))
+			}
+		}
+	}
+	for file := range bt.failures {
+		result = append(result, file)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// acknowledged returns a copy of the breakpoints the adapter acknowledged,
+// by file
+func (bt *breakpointTracker) acknowledged() map[string][]BreakpointState {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	files := make(map[string][]BreakpointState, len(bt.acked))
+	for file, states := range bt.acked {
+		files[file] = append([]BreakpointState(nil), states...)
+	}
+	return files
+}
+
+// WaitForBreakpoints blocks until every setBreakpoints request sent so far
+// has been answered
+func (c *Client) WaitForBreakpoints(ctx context.Context) error {
+	return c.breakpoints.wait(ctx)
+}
+
+// UnverifiedBreakpoints lists breakpoints the adapter acknowledged but didn't
+// verify, as "file:line", plus files whose setBreakpoints request failed
+func (c *Client) UnverifiedBreakpoints() []string {
+	return c.breakpoints.unverified()
+}
+
+// AcknowledgedBreakpoints returns the breakpoints the adapter returned for
+// each file's last setBreakpoints request, in request order, updated by
+// breakpoint events since. Breakpoints the adapter announced itself follow.
+func (c *Client) AcknowledgedBreakpoints() map[string][]BreakpointState {
+	return c.breakpoints.acknowledged()
+}
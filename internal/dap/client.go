@@ -4,16 +4,37 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap/quirks"
 	"github.com/google/go-dap"
 )
 
+// maxContentLength caps the size of a single incoming DAP message.
+// Anything larger is treated as a corrupt header rather than allocated.
+const maxContentLength = 64 * 1024 * 1024
+
+// maxPooledBodySize is the largest body buffer returned to bodyBufferPool.
+// Occasional huge messages (e.g. large variable dumps) are left to the GC
+// so the pool doesn't pin their memory.
+const maxPooledBodySize = 64 * 1024
+
+// bodyBufferPool reuses message body buffers across reads to reduce GC
+// pressure during event-heavy sessions (e.g. lots of output events)
+var bodyBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 4096)
+		return &buf
+	},
+}
+
 // Client manages a connection to a Godot DAP server
 type Client struct {
 	host   string
@@ -26,28 +47,103 @@ type Client struct {
 	mu      sync.Mutex
 	nextSeq int
 
-	// Pending requests (seq -> channel)
-	pendingReqs map[int]chan dap.Message
-	reqMu       sync.Mutex
+	// Requests waiting for a response, and counts by outcome
+	requests    requestTable
+	janitorStop chan struct{} // Closed to stop the janitor; nil when it isn't running
+
+	// Serializes writes so concurrent requests don't interleave frames
+	writeMu sync.Mutex
 
-	// Event listeners
-	eventListeners []chan dap.Message
+	// Event listeners, and events dropped for listeners already removed
+	eventListeners []*eventListener
 	eventMu        sync.Mutex
+	droppedEvents  int
+
+	// Events that arrived before the first subscription, held for it
+	earlyEvents []dap.Message
+	holdEarly   bool
+
+	// Watches for the initialized event from Connect on
+	handshake handshake
+
+	// Threads list, refreshed from thread events
+	threads threadCache
+
+	// Recent output events from the game
+	output outputBuffer
+
+	// Recent events of every type, for polling
+	history eventHistory
+
+	// How the current run ended (exited/terminated events)
+	exit exitTracker
+
+	// Milestones of the most recent launch/attach
+	timeline launchTimeline
+
+	// Output from the most recent launch/attach up to the first stop
+	startup startupCapture
+
+	// Debuggee process and modules (process/module events)
+	process processTracker
+
+	// setBreakpoints requests in flight and their acknowledged breakpoints
+	breakpoints breakpointTracker
+
+	// Malformed messages received from the adapter
+	violations violationLog
+
+	// Adapter capabilities from the initialize response
+	capabilities dap.Capabilities
+
+	// Protocol deviations to work around (WithQuirks)
+	quirks quirks.Set
+
+	// Adapter profile (WithProfile) and the capabilities it doesn't trust
+	profile     string
+	unsupported []string
 
 	// Connection state
 	connected bool
+
+	// Settings from ClientOptions
+	logger          *log.Logger
+	dialer          Dialer
+	connectTimeout  time.Duration
+	commandTimeout  time.Duration
+	eventBufferSize int
+	keepAlive       time.Duration
+	noDelay         bool
+	readBufferSize  int
+	requestMaxAge   time.Duration
+
+	launchStallTimeout time.Duration
 }
 
-// NewClient creates a new DAP client for connecting to Godot
-func NewClient(host string, port int) *Client {
-	return &Client{
-		host:           host,
-		port:           port,
-		nextSeq:        1,
-		codec:          dap.NewCodec(),
-		pendingReqs:    make(map[int]chan dap.Message),
-		eventListeners: make([]chan dap.Message, 0),
-	}
+// NewClient creates a new DAP client for connecting to Godot.
+// Options override the defaults (standard logger, TCP dialer, default timeouts).
+func NewClient(host string, port int, opts ...ClientOption) *Client {
+	c := &Client{
+		host:            host,
+		port:            port,
+		nextSeq:         1,
+		codec:           dap.NewCodec(),
+		logger:          log.Default(),
+		dialer:          &net.Dialer{},
+		connectTimeout:  DefaultConnectTimeout,
+		commandTimeout:  DefaultCommandTimeout,
+		eventBufferSize: defaultEventBufferSize,
+		keepAlive:       defaultKeepAlive,
+		noDelay:         true,
+		readBufferSize:  defaultReadBufferSize,
+		requestMaxAge:   defaultRequestMaxAge,
+
+		launchStallTimeout: defaultLaunchStallTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Connect establishes a TCP connection to the Godot DAP server
@@ -59,19 +155,33 @@ func (c *Client) Connect(ctx context.Context) error {
 	address := fmt.Sprintf("%s:%d", c.host, c.port)
 
 	// Establish TCP connection
-	var d net.Dialer
-	conn, err := d.DialContext(ctx, "tcp", address)
+	conn, err := c.dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
 		return fmt.Errorf("failed to connect to %s: %w", address, err)
 	}
 
+	c.tuneConnection(conn)
+
 	c.conn = conn
-	c.reader = bufio.NewReader(conn)
+	c.reader = bufio.NewReaderSize(conn, c.readBufferSize)
 	c.connected = true
+	c.handshake.reset()
+	c.eventMu.Lock()
+	c.earlyEvents = nil
+	c.holdEarly = true
+	c.eventMu.Unlock()
 
 	// Start background read loop
 	go c.readLoop()
 
+	// Age out requests the adapter never answers
+	if c.requestMaxAge > 0 {
+		c.mu.Lock()
+		c.janitorStop = make(chan struct{})
+		go c.runJanitor(c.janitorStop)
+		c.mu.Unlock()
+	}
+
 	return nil
 }
 
@@ -79,10 +189,17 @@ func (c *Client) Connect(ctx context.Context) error {
 func (c *Client) readLoop() {
 	for {
 		msg, err := c.read()
+		var violation *ProtocolViolation
+		if errors.As(err, &violation) {
+			c.handleViolation(violation)
+			continue
+		}
 		if err != nil {
 			if c.connected {
-				log.Printf("Connection error: %v", err)
+				c.logger.Printf("Connection error: %v", err)
 				c.connected = false
+				c.stopJanitor()
+				c.exit.connectionLost()
 			}
 			return
 		}
@@ -107,6 +224,8 @@ func (c *Client) handleMessage(msg dap.Message) {
 		c.dispatchResponse(m.RequestSeq, m)
 	case *dap.SetBreakpointsResponse:
 		c.dispatchResponse(m.RequestSeq, m)
+	case *dap.SetFunctionBreakpointsResponse:
+		c.dispatchResponse(m.RequestSeq, m)
 	case *dap.ContinueResponse:
 		c.dispatchResponse(m.RequestSeq, m)
 	case *dap.NextResponse:
@@ -129,6 +248,12 @@ func (c *Client) handleMessage(msg dap.Message) {
 		c.dispatchResponse(m.RequestSeq, m)
 	case *dap.SetVariableResponse:
 		c.dispatchResponse(m.RequestSeq, m)
+	case *dap.GotoTargetsResponse:
+		c.dispatchResponse(m.RequestSeq, m)
+	case *dap.GotoResponse:
+		c.dispatchResponse(m.RequestSeq, m)
+	case *dap.TerminateResponse:
+		c.dispatchResponse(m.RequestSeq, m)
 	case *dap.DisconnectResponse:
 		c.dispatchResponse(m.RequestSeq, m)
 	default:
@@ -136,63 +261,29 @@ func (c *Client) handleMessage(msg dap.Message) {
 		// For now, just log it or handle via event listeners
 		if _, ok := msg.(dap.EventMessage); ok {
 			c.logEvent(msg)
+			c.threads.applyEvent(msg)
+			c.output.applyEvent(msg)
+			c.history.applyEvent(msg)
+			c.exit.applyEvent(msg)
+			c.timeline.applyEvent(msg)
+			c.startup.applyEvent(msg)
+			c.process.applyEvent(msg)
+			c.breakpoints.applyEvent(msg)
+			c.handshake.applyEvent(msg)
 			c.broadcastEvent(msg)
 		} else {
-			log.Printf("Received unknown message type: %T", msg)
-		}
-	}
-}
-
-// SubscribeToEvents subscribes to all DAP events.
-// Returns a channel to receive events and a cleanup function.
-func (c *Client) SubscribeToEvents() (<-chan dap.Message, func()) {
-	ch := make(chan dap.Message, 100) // Buffer to prevent blocking
-	c.eventMu.Lock()
-	c.eventListeners = append(c.eventListeners, ch)
-	c.eventMu.Unlock()
-
-	cleanup := func() {
-		c.eventMu.Lock()
-		defer c.eventMu.Unlock()
-		for i, listener := range c.eventListeners {
-			if listener == ch {
-				// Remove (swap with last and shrink)
-				c.eventListeners[i] = c.eventListeners[len(c.eventListeners)-1]
-				c.eventListeners = c.eventListeners[:len(c.eventListeners)-1]
-				// close(ch) // Don't close, just stop sending. Caller might still be reading.
-				break
-			}
-		}
-	}
-	return ch, cleanup
-}
-
-// broadcastEvent sends an event to all listeners
-func (c *Client) broadcastEvent(event dap.Message) {
-	c.eventMu.Lock()
-	defer c.eventMu.Unlock()
-	for _, ch := range c.eventListeners {
-		select {
-		case ch <- event:
-		default:
-			log.Printf("Warning: Event listener buffer full, dropping event")
+			c.logger.Printf("Received unknown message type: %T", msg)
 		}
 	}
 }
 
 // dispatchResponse sends a response to the waiting request
 func (c *Client) dispatchResponse(seq int, msg dap.Message) {
-	c.reqMu.Lock()
-	ch, ok := c.pendingReqs[seq]
-	if ok {
-		delete(c.pendingReqs, seq)
-	}
-	c.reqMu.Unlock()
-
+	ch, ok := c.requests.resolve(seq)
 	if ok {
 		ch <- msg
 	} else {
-		log.Printf("Received response for unknown/timed-out request seq %d: %T", seq, msg)
+		c.logger.Printf("Received response for unknown/timed-out request seq %d: %T", seq, msg)
 	}
 }
 
@@ -203,12 +294,50 @@ func (c *Client) Disconnect() error {
 	}
 
 	c.connected = false
+	c.threads.reset()
+	c.stopJanitor()
 	if c.conn != nil {
 		return c.conn.Close()
 	}
 	return nil
 }
 
+// SupportsGotoTargets reports whether the adapter implements gotoTargets and
+// goto (set next statement). Godot doesn't advertise it yet.
+func (c *Client) SupportsGotoTargets() bool {
+	return c.capabilities.SupportsGotoTargetsRequest
+}
+
+// SupportsSingleThreadExecution reports whether the adapter honors the
+// singleThread flag on continue/step requests. Godot doesn't advertise it yet.
+func (c *Client) SupportsSingleThreadExecution() bool {
+	return c.capabilities.SupportsSingleThreadExecutionRequests && !c.quirks.IgnoresStepArguments
+}
+
+// SupportsConditionalBreakpoints reports whether the adapter evaluates
+// breakpoint conditions itself. Godot doesn't advertise it yet.
+func (c *Client) SupportsConditionalBreakpoints() bool {
+	return c.capabilities.SupportsConditionalBreakpoints
+}
+
+// SupportsHitConditionalBreakpoints reports whether the adapter honors
+// breakpoint hit conditions itself. Godot doesn't advertise it yet.
+func (c *Client) SupportsHitConditionalBreakpoints() bool {
+	return c.capabilities.SupportsHitConditionalBreakpoints
+}
+
+// SupportsLogPoints reports whether the adapter prints breakpoint log
+// messages itself. Godot doesn't advertise it yet.
+func (c *Client) SupportsLogPoints() bool {
+	return c.capabilities.SupportsLogPoints
+}
+
+// SupportsFunctionBreakpoints reports whether the adapter accepts
+// setFunctionBreakpoints. Godot doesn't.
+func (c *Client) SupportsFunctionBreakpoints() bool {
+	return c.capabilities.SupportsFunctionBreakpoints
+}
+
 // IsConnected returns whether the client is currently connected
 func (c *Client) IsConnected() bool {
 	return c.connected
@@ -237,8 +366,8 @@ func (c *Client) read() (dap.Message, error) {
 			return nil, fmt.Errorf("failed to read header: %w", err)
 		}
 
-		// Remove trailing whitespace
-		line = line[:len(line)-2] // remove \r\n
+		// Remove trailing \r\n (tolerate bare \n)
+		line = strings.TrimRight(line, "\r\n")
 
 		if line == "" {
 			// Empty line marks end of headers
@@ -252,12 +381,25 @@ func (c *Client) read() (dap.Message, error) {
 		}
 	}
 
-	if contentLength == 0 {
+	if contentLength <= 0 {
 		return nil, fmt.Errorf("missing or invalid Content-Length header")
 	}
+	if contentLength > maxContentLength {
+		return nil, fmt.Errorf("Content-Length %d exceeds maximum of %d bytes", contentLength, maxContentLength)
+	}
 
-	// Read body
-	body := make([]byte, contentLength)
+	// Read body into a pooled buffer. Decoding copies everything it keeps
+	// (including json.RawMessage fields), so the buffer can be reused afterwards.
+	bufPtr := bodyBufferPool.Get().(*[]byte)
+	defer func() {
+		if cap(*bufPtr) <= maxPooledBodySize {
+			bodyBufferPool.Put(bufPtr)
+		}
+	}()
+	if cap(*bufPtr) < contentLength {
+		*bufPtr = make([]byte, contentLength)
+	}
+	body := (*bufPtr)[:contentLength]
 	_, err := io.ReadFull(reader, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read body: %w", err)
@@ -267,16 +409,30 @@ func (c *Client) read() (dap.Message, error) {
 	var rawMsg map[string]interface{}
 	if err := json.Unmarshal(body, &rawMsg); err == nil {
 		if prettyBytes, err := json.MarshalIndent(rawMsg, "", "  "); err == nil {
-			log.Printf("[DAP RCVD] %s", string(prettyBytes))
+			c.logger.Printf("[DAP RCVD] %s", string(prettyBytes))
 		} else {
-			log.Printf("[DAP RCVD] %s", string(body))
+			c.logger.Printf("[DAP RCVD] %s", string(body))
 		}
 	} else {
-		log.Printf("[DAP RCVD] %s", string(body))
+		c.logger.Printf("[DAP RCVD] %s", string(body))
+	}
+
+	// Check the shape before decoding, which would turn missing fields into
+	// zero values and fail on the first wrong type
+	if rawMsg == nil {
+		return nil, &ProtocolViolation{Problems: []string{"not a JSON object"}, Time: time.Now()}
 	}
+	problems := checkShape(rawMsg)
 
 	// Decode into specific type based on Type and Command/Event
-	return dap.DecodeProtocolMessage(body)
+	msg, err := dap.DecodeProtocolMessage(body)
+	if err != nil && len(problems) == 0 {
+		problems = []string{err.Error()}
+	}
+	if len(problems) > 0 {
+		return nil, newViolation(rawMsg, problems)
+	}
+	return msg, nil
 }
 
 // write sends a message to the connection
@@ -285,30 +441,62 @@ func (c *Client) write(msg dap.Message) error {
 		return fmt.Errorf("not connected")
 	}
 
+	if req, ok := msg.(dap.RequestMessage); ok && c.quirks.ReadsOptionalFieldsUnsafely {
+		padded, err := padRequest(req)
+		if err != nil {
+			return fmt.Errorf("failed to pad %s request: %w", req.GetRequest().Command, err)
+		}
+		msg = padded
+	}
+
 	if jsonBytes, err := json.MarshalIndent(msg, "", "  "); err == nil {
-		log.Printf("[DAP SENT] %s", string(jsonBytes))
+		c.logger.Printf("[DAP SENT] %s", string(jsonBytes))
 	} else {
-		log.Printf("[DAP SENT] (failed to marshal for logging): %v", msg)
+		c.logger.Printf("[DAP SENT] (failed to marshal for logging): %v", msg)
 	}
 
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 	return dap.WriteProtocolMessage(c.conn, msg)
 }
 
+// paddedRequest is a request re-encoded with the optional fields Godot reads
+// (quirks.Set.ReadsOptionalFieldsUnsafely)
+type paddedRequest struct {
+	seq    int
+	fields map[string]interface{}
+}
+
+func (p *paddedRequest) GetSeq() int { return p.seq }
+
+func (p *paddedRequest) MarshalJSON() ([]byte, error) { return json.Marshal(p.fields) }
+
+// padRequest adds the fields of quirks.GodotReadFields that req leaves out
+func padRequest(req dap.RequestMessage) (*paddedRequest, error) {
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	arguments, _ := fields["arguments"].(map[string]interface{})
+	fields["arguments"] = quirks.PadArguments(req.GetRequest().Command, arguments)
+	return &paddedRequest{seq: req.GetSeq(), fields: fields}, nil
+}
+
 // sendRequestAndWait sends a request and waits for the response
 func (c *Client) sendRequestAndWait(ctx context.Context, req dap.Message) (dap.Message, error) {
 	seq := req.GetSeq()
-	ch := make(chan dap.Message, 1)
-
-	c.reqMu.Lock()
-	c.pendingReqs[seq] = ch
-	c.reqMu.Unlock()
+	command := ""
+	if r, ok := req.(dap.RequestMessage); ok {
+		command = r.GetRequest().Command
+	}
+	ch := c.requests.register(seq, command)
 
 	// Ensure cleanup
-	defer func() {
-		c.reqMu.Lock()
-		delete(c.pendingReqs, seq)
-		c.reqMu.Unlock()
-	}()
+	defer c.requests.release(seq)
 
 	if err := c.write(req); err != nil {
 		return nil, err
@@ -326,21 +514,47 @@ func (c *Client) sendRequestAndWait(ctx context.Context, req dap.Message) (dap.M
 	}
 }
 
+// newRequest builds a request header for command with the next sequence number
+func (c *Client) newRequest(command string) dap.Request {
+	return dap.Request{
+		ProtocolMessage: dap.ProtocolMessage{
+			Seq:  c.nextRequestSeq(),
+			Type: "request",
+		},
+		Command: command,
+	}
+}
+
+// sendTyped sends a request and returns its response as TResp.
+// A DAP command only needs to build its request (header from newRequest) and
+// call this; error responses and unexpected response types are handled here.
+// Methods can't have type parameters, so the client is passed explicitly.
+func sendTyped[TReq dap.RequestMessage, TResp dap.ResponseMessage](ctx context.Context, c *Client, req TReq) (TResp, error) {
+	var zero TResp
+
+	resp, err := c.sendRequestAndWait(ctx, req)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := resp.(TResp)
+	if !ok {
+		return zero, fmt.Errorf("unexpected response type for %s: %T", req.GetRequest().Command, resp)
+	}
+	return typed, nil
+}
+
 // Initialize sends the initialize request to the DAP server
 // This must be the first request sent after connecting
 func (c *Client) Initialize(ctx context.Context) (*dap.InitializeResponse, error) {
-	// Subscribe to events BEFORE sending request to avoid missing "initialized" event
-	events, cleanup := c.SubscribeToEvents()
-	defer cleanup()
+	// Take the initialized signal BEFORE sending the request. The client
+	// watches for the event from Connect on, so it isn't missed if it comes
+	// before the response or before this call, nor dropped behind a burst
+	// of output events.
+	initialized := c.handshake.done()
 
 	request := &dap.InitializeRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "initialize",
-		},
+		Request: c.newRequest("initialize"),
 		Arguments: dap.InitializeRequestArguments{
 			ClientID:                     "godot-dap-mcp-server",
 			ClientName:                   "Godot DAP MCP Server",
@@ -358,28 +572,21 @@ func (c *Client) Initialize(ctx context.Context) (*dap.InitializeResponse, error
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
+	initResp, err := sendTyped[*dap.InitializeRequest, *dap.InitializeResponse](ctx, c, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send initialize request: %w", err)
 	}
-
-	initResp, ok := resp.(*dap.InitializeResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
+	c.applyUnsupported(&initResp.Body)
+	c.capabilities = initResp.Body
 
 	// Wait for initialized event
-	log.Println("Waiting for initialized event...")
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("timeout waiting for initialized event: %w", ctx.Err())
-		case msg := <-events:
-			if _, ok := msg.(*dap.InitializedEvent); ok {
-				log.Println("Received initialized event")
-				return initResp, nil
-			}
-		}
+	c.logger.Println("Waiting for initialized event...")
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timeout waiting for initialized event: %w", ctx.Err())
+	case <-initialized:
+		c.logger.Println("Received initialized event")
+		return initResp, nil
 	}
 }
 
@@ -387,16 +594,10 @@ func (c *Client) Initialize(ctx context.Context) (*dap.InitializeResponse, error
 // This must be sent after Initialize and before launching/attaching
 func (c *Client) ConfigurationDone(ctx context.Context) error {
 	request := &dap.ConfigurationDoneRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "configurationDone",
-		},
+		Request: c.newRequest("configurationDone"),
 	}
 
-	_, err := c.sendRequestAndWait(ctx, request)
+	_, err := sendTyped[*dap.ConfigurationDoneRequest, *dap.ConfigurationDoneResponse](ctx, c, request)
 	if err != nil {
 		return fmt.Errorf("failed to send configurationDone request: %w", err)
 	}
@@ -414,15 +615,15 @@ func (c *Client) SetBreakpoints(ctx context.Context, file string, lines []int) (
 			Line: line,
 		}
 	}
+	return c.SetSourceBreakpoints(ctx, file, breakpoints)
+}
 
+// SetSourceBreakpoints sets breakpoints for a specific file with their
+// conditions and hit conditions. Like SetBreakpoints, it replaces the file's
+// breakpoints.
+func (c *Client) SetSourceBreakpoints(ctx context.Context, file string, breakpoints []dap.SourceBreakpoint) (*dap.SetBreakpointsResponse, error) {
 	request := &dap.SetBreakpointsRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "setBreakpoints",
-		},
+		Request: c.newRequest("setBreakpoints"),
 		Arguments: dap.SetBreakpointsArguments{
 			Source: dap.Source{
 				Path: file,
@@ -431,44 +632,58 @@ func (c *Client) SetBreakpoints(ctx context.Context, file string, lines []int) (
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
+	lines := make([]int, len(breakpoints))
+	for i, bp := range breakpoints {
+		lines[i] = bp.Line
 	}
+	done := c.breakpoints.begin(file, lines)
+	resp, err := sendTyped[*dap.SetBreakpointsRequest, *dap.SetBreakpointsResponse](ctx, c, request)
+	done(resp, err)
+	return resp, err
+}
 
-	bpResp, ok := resp.(*dap.SetBreakpointsResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
+// SetFunctionBreakpoints replaces all function breakpoints with breakpoints
+// on the named functions. Only for adapters that SupportsFunctionBreakpoints.
+func (c *Client) SetFunctionBreakpoints(ctx context.Context, names []string) (*dap.SetFunctionBreakpointsResponse, error) {
+	breakpoints := make([]dap.FunctionBreakpoint, len(names))
+	for i, name := range names {
+		breakpoints[i] = dap.FunctionBreakpoint{Name: name}
+	}
+	request := &dap.SetFunctionBreakpointsRequest{
+		Request: c.newRequest("setFunctionBreakpoints"),
+		Arguments: dap.SetFunctionBreakpointsArguments{
+			Breakpoints: breakpoints,
+		},
 	}
 
-	return bpResp, nil
+	return sendTyped[*dap.SetFunctionBreakpointsRequest, *dap.SetFunctionBreakpointsResponse](ctx, c, request)
 }
 
 // Continue resumes execution of the specified thread
 // Use threadId 0 to continue all threads (Godot typically uses single thread)
 func (c *Client) Continue(ctx context.Context, threadId int) (*dap.ContinueResponse, error) {
+	return c.ContinueThread(ctx, threadId, false)
+}
+
+// ContinueThread resumes execution, optionally asking the adapter to resume
+// only threadId. singleThread is only honored if SupportsSingleThreadExecution.
+func (c *Client) ContinueThread(ctx context.Context, threadId int, singleThread bool) (*dap.ContinueResponse, error) {
+	singleThread = singleThread && !c.quirks.IgnoresStepArguments
 	request := &dap.ContinueRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "continue",
-		},
+		Request: c.newRequest("continue"),
 		Arguments: dap.ContinueArguments{
-			ThreadId: threadId,
+			ThreadId:     threadId,
+			SingleThread: singleThread,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
+	contResp, err := sendTyped[*dap.ContinueRequest, *dap.ContinueResponse](ctx, c, request)
 	if err != nil {
 		return nil, err
 	}
 
-	contResp, ok := resp.(*dap.ContinueResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
+	// Adapters omit allThreadsContinued when only one thread exists
+	c.threads.markRunning(threadId, contResp.Body.AllThreadsContinued || !singleThread)
 
 	return contResp, nil
 }
@@ -476,28 +691,27 @@ func (c *Client) Continue(ctx context.Context, threadId int) (*dap.ContinueRespo
 // Next steps over the current line (step over)
 // Use threadId from the stopped event
 func (c *Client) Next(ctx context.Context, threadId int) (*dap.NextResponse, error) {
+	return c.NextThread(ctx, threadId, false)
+}
+
+// NextThread steps over the current line of threadId. Unless singleThread is
+// set (and supported), other threads run freely while the step executes.
+func (c *Client) NextThread(ctx context.Context, threadId int, singleThread bool) (*dap.NextResponse, error) {
+	singleThread = singleThread && !c.quirks.IgnoresStepArguments
 	request := &dap.NextRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "next",
-		},
+		Request: c.newRequest("next"),
 		Arguments: dap.NextArguments{
-			ThreadId: threadId,
+			ThreadId:     threadId,
+			SingleThread: singleThread,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
+	nextResp, err := sendTyped[*dap.NextRequest, *dap.NextResponse](ctx, c, request)
 	if err != nil {
 		return nil, err
 	}
 
-	nextResp, ok := resp.(*dap.NextResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
+	c.threads.markRunning(threadId, !singleThread)
 
 	return nextResp, nil
 }
@@ -505,85 +719,100 @@ func (c *Client) Next(ctx context.Context, threadId int) (*dap.NextResponse, err
 // StepIn steps into the function at the current line
 // Use threadId from the stopped event
 func (c *Client) StepIn(ctx context.Context, threadId int) (*dap.StepInResponse, error) {
+	return c.StepInThread(ctx, threadId, false)
+}
+
+// StepInThread steps into the function at the current line of threadId.
+// Unless singleThread is set (and supported), other threads run freely.
+func (c *Client) StepInThread(ctx context.Context, threadId int, singleThread bool) (*dap.StepInResponse, error) {
+	singleThread = singleThread && !c.quirks.IgnoresStepArguments
 	request := &dap.StepInRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "stepIn",
-		},
+		Request: c.newRequest("stepIn"),
 		Arguments: dap.StepInArguments{
-			ThreadId: threadId,
+			ThreadId:     threadId,
+			SingleThread: singleThread,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
+	stepInResp, err := sendTyped[*dap.StepInRequest, *dap.StepInResponse](ctx, c, request)
 	if err != nil {
 		return nil, err
 	}
 
-	stepInResp, ok := resp.(*dap.StepInResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
+	c.threads.markRunning(threadId, !singleThread)
 
 	return stepInResp, nil
 }
 
+// GotoTargets asks where execution can jump to at a line of file.
+// Only valid if SupportsGotoTargets.
+func (c *Client) GotoTargets(ctx context.Context, file string, line int) (*dap.GotoTargetsResponse, error) {
+	request := &dap.GotoTargetsRequest{
+		Request: c.newRequest("gotoTargets"),
+		Arguments: dap.GotoTargetsArguments{
+			Source: dap.Source{
+				Path: file,
+			},
+			Line: line,
+		},
+	}
+
+	return sendTyped[*dap.GotoTargetsRequest, *dap.GotoTargetsResponse](ctx, c, request)
+}
+
+// Goto moves threadId's next statement to a target from GotoTargets without
+// executing the code in between. The adapter reports the new location with a
+// 'stopped' event (reason 'goto').
+func (c *Client) Goto(ctx context.Context, threadId int, targetId int) (*dap.GotoResponse, error) {
+	request := &dap.GotoRequest{
+		Request: c.newRequest("goto"),
+		Arguments: dap.GotoArguments{
+			ThreadId: threadId,
+			TargetId: targetId,
+		},
+	}
+
+	return sendTyped[*dap.GotoRequest, *dap.GotoResponse](ctx, c, request)
+}
+
 // Pause pauses execution of the specified thread
 // Use threadId 1 for Godot (single thread)
 // This will trigger a 'stopped' event with reason='pause'
 func (c *Client) Pause(ctx context.Context, threadId int) (*dap.PauseResponse, error) {
 	request := &dap.PauseRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "pause",
-		},
+		Request: c.newRequest("pause"),
 		Arguments: dap.PauseArguments{
 			ThreadId: threadId,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
+	return sendTyped[*dap.PauseRequest, *dap.PauseResponse](ctx, c, request)
+}
 
-	pauseResp, ok := resp.(*dap.PauseResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
+// Terminate asks the adapter to end the debuggee (the running game).
+// The DAP connection stays open.
+func (c *Client) Terminate(ctx context.Context) error {
+	request := &dap.TerminateRequest{
+		Request: c.newRequest("terminate"),
 	}
 
-	return pauseResp, nil
+	_, err := sendTyped[*dap.TerminateRequest, *dap.TerminateResponse](ctx, c, request)
+	return err
 }
 
-// Threads requests the list of active threads.
+// Threads requests the list of active threads and refreshes the threads cache.
 // Godot always returns a single thread with ID 1 named "Main".
 func (c *Client) Threads(ctx context.Context) (*dap.ThreadsResponse, error) {
 	request := &dap.ThreadsRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "threads",
-		},
+		Request: c.newRequest("threads"),
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
+	threadsResp, err := sendTyped[*dap.ThreadsRequest, *dap.ThreadsResponse](ctx, c, request)
 	if err != nil {
 		return nil, err
 	}
 
-	threadsResp, ok := resp.(*dap.ThreadsResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
+	c.threads.set(threadsResp.Body.Threads)
 	return threadsResp, nil
 }
 
@@ -591,13 +820,7 @@ func (c *Client) Threads(ctx context.Context) (*dap.ThreadsResponse, error) {
 // Returns stack frames with source file paths, line numbers, and frame IDs.
 func (c *Client) StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*dap.StackTraceResponse, error) {
 	request := &dap.StackTraceRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "stackTrace",
-		},
+		Request: c.newRequest("stackTrace"),
 		Arguments: dap.StackTraceArguments{
 			ThreadId:   threadId,
 			StartFrame: startFrame,
@@ -605,75 +828,33 @@ func (c *Client) StackTrace(ctx context.Context, threadId int, startFrame int, l
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	stackResp, ok := resp.(*dap.StackTraceResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return stackResp, nil
+	return sendTyped[*dap.StackTraceRequest, *dap.StackTraceResponse](ctx, c, request)
 }
 
 // Scopes requests the variable scopes for the specified stack frame.
 // Returns Locals, Members, and Globals scopes with variablesReference IDs.
 func (c *Client) Scopes(ctx context.Context, frameId int) (*dap.ScopesResponse, error) {
 	request := &dap.ScopesRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "scopes",
-		},
+		Request: c.newRequest("scopes"),
 		Arguments: dap.ScopesArguments{
 			FrameId: frameId,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	scopesResp, ok := resp.(*dap.ScopesResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return scopesResp, nil
+	return sendTyped[*dap.ScopesRequest, *dap.ScopesResponse](ctx, c, request)
 }
 
 // Variables requests the variables in the specified scope or expands a complex variable.
 // Use variablesReference from scopes response or from a variable with variablesReference > 0.
 func (c *Client) Variables(ctx context.Context, variablesReference int) (*dap.VariablesResponse, error) {
 	request := &dap.VariablesRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "variables",
-		},
+		Request: c.newRequest("variables"),
 		Arguments: dap.VariablesArguments{
 			VariablesReference: variablesReference,
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	varsResp, ok := resp.(*dap.VariablesResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return varsResp, nil
+	return sendTyped[*dap.VariablesRequest, *dap.VariablesResponse](ctx, c, request)
 }
 
 // Evaluate evaluates the specified expression in the context of the specified stack frame.
@@ -681,13 +862,7 @@ func (c *Client) Variables(ctx context.Context, variablesReference int) (*dap.Va
 // Context can be "watch", "repl", or "hover" to indicate the evaluation context.
 func (c *Client) Evaluate(ctx context.Context, expression string, frameId int, context string) (*dap.EvaluateResponse, error) {
 	request := &dap.EvaluateRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "evaluate",
-		},
+		Request: c.newRequest("evaluate"),
 		Arguments: dap.EvaluateArguments{
 			Expression: expression,
 			FrameId:    frameId,
@@ -695,17 +870,7 @@ func (c *Client) Evaluate(ctx context.Context, expression string, frameId int, c
 		},
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	evalResp, ok := resp.(*dap.EvaluateResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return evalResp, nil
+	return sendTyped[*dap.EvaluateRequest, *dap.EvaluateResponse](ctx, c, request)
 }
 
 // Launch sends a launch request to start the Godot game with specified parameters.
@@ -726,111 +891,107 @@ func (c *Client) Launch(ctx context.Context, args map[string]interface{}) (*dap.
 	}
 
 	request := &dap.LaunchRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "launch",
-		},
+		Request:   c.newRequest("launch"),
 		Arguments: argsJSON,
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	launchResp, ok := resp.(*dap.LaunchResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return launchResp, nil
+	return sendTyped[*dap.LaunchRequest, *dap.LaunchResponse](ctx, c, request)
 }
 
-// LaunchWithConfigurationDone sends a launch request followed immediately by configurationDone.
-// This is required for Godot, which only sends the launch response AFTER receiving configurationDone.
+// LaunchWithConfigurationDone sends a launch request followed by configurationDone.
+// With the LaunchResponseAfterConfigurationDone quirk (Godot), configurationDone
+// is sent right away, since Godot only answers launch once it has arrived.
+// If the adapter stops responding while the connection stays up, the launch
+// fails early with a *LaunchStallError (see WithLaunchStallTimeout).
 func (c *Client) LaunchWithConfigurationDone(ctx context.Context, args map[string]interface{}) (*dap.LaunchResponse, error) {
-	// 1. Send Launch Request and Wait
-	// Godot 4.x sends LaunchResponse immediately before/during ConfigurationDone?
-	// Actually, standard DAP says LaunchResponse comes first.
-	// Godot might be interleaving.
-	// If Godot blocks sending LaunchResponse until it gets ConfigurationDone, we have a deadlock if we wait synchronously.
-	// But my simulation showed LaunchResponse came back.
-	// However, the comment "This is required for Godot, which only sends the launch response AFTER receiving configurationDone"
-	// contradicts my finding or implies a deadlock risk.
+	return c.launchWithConfigurationDone(ctx, args, false)
+}
 
-	// Let's assume Godot MIGHT block launch response.
-	// If so, we should send both requests asynchronously?
-	// But sendRequestAndWait blocks.
+// LaunchAfterBreakpoints is LaunchWithConfigurationDone, but holds
+// configurationDone until every setBreakpoints request sent so far has been
+// answered. Godot starts the game on configurationDone, so this guarantees
+// breakpoints in scripts that run immediately (autoloads, the main scene's
+// _ready) are in place.
+func (c *Client) LaunchAfterBreakpoints(ctx context.Context, args map[string]interface{}) (*dap.LaunchResponse, error) {
+	return c.launchWithConfigurationDone(ctx, args, true)
+}
 
-	// If Godot waits for configDone to send launch response, we CANNOT wait for launch response before sending configDone.
-	// We must send Launch, then Send ConfigDone, then wait for both.
+func (c *Client) launchWithConfigurationDone(ctx context.Context, args map[string]interface{}, waitForBreakpoints bool) (*dap.LaunchResponse, error) {
+	ctx, watchdog := c.watchLaunch(ctx, "launch")
+	resp, err := c.launchSequence(ctx, args, waitForBreakpoints)
+	if stall := watchdog.finish(); stall != nil && err != nil {
+		return nil, stall
+	}
+	return resp, err
+}
 
-	// To do this with sendRequestAndWait, we'd need goroutines.
+// launchSequence sends launch and configurationDone in the order the
+// adapter's quirks require and waits for both responses
+func (c *Client) launchSequence(ctx context.Context, args map[string]interface{}, waitForBreakpoints bool) (*dap.LaunchResponse, error) {
+	if !c.quirks.LaunchResponseAfterConfigurationDone {
+		var launchResp *dap.LaunchResponse
+		err := c.startInOrder(ctx, "launch", waitForBreakpoints, func(ctx context.Context) (err error) {
+			launchResp, err = c.Launch(ctx, args)
+			return err
+		})
+		return launchResp, err
+	}
 
-	// Marshal arguments to JSON
+	// Godot answers launch only after configurationDone, so both requests
+	// are sent before waiting for either response
 	argsJSON, err := json.Marshal(args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal launch arguments: %w", err)
 	}
 
 	launchRequest := &dap.LaunchRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "launch",
-		},
+		Request:   c.newRequest("launch"),
 		Arguments: argsJSON,
 	}
 
 	configDoneRequest := &dap.ConfigurationDoneRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "configurationDone",
-		},
+		Request: c.newRequest("configurationDone"),
 	}
 
 	// Use channels to wait
 	launchSeq := launchRequest.Seq
 	configSeq := configDoneRequest.Seq
 
-	launchCh := make(chan dap.Message, 1)
-	configCh := make(chan dap.Message, 1)
-
-	c.reqMu.Lock()
-	c.pendingReqs[launchSeq] = launchCh
-	c.pendingReqs[configSeq] = configCh
-	c.reqMu.Unlock()
+	launchCh := c.requests.register(launchSeq, "launch")
+	configCh := c.requests.register(configSeq, "configurationDone")
 
 	defer func() {
-		c.reqMu.Lock()
-		delete(c.pendingReqs, launchSeq)
-		delete(c.pendingReqs, configSeq)
-		c.reqMu.Unlock()
+		c.requests.release(launchSeq)
+		c.requests.release(configSeq)
 	}()
 
-	log.Println("DEBUG: Sending Launch Request...")
+	c.timeline.begin()
+	c.startup.begin()
+
+	c.logger.Println("DEBUG: Sending Launch Request...")
 	if err := c.write(launchRequest); err != nil {
 		return nil, fmt.Errorf("failed to send launch request: %w", err)
 	}
+	c.timeline.mark(MilestoneRequestSent, "launch")
+
+	if waitForBreakpoints {
+		if err := c.breakpoints.wait(ctx); err != nil {
+			return nil, fmt.Errorf("breakpoints were not acknowledged before configurationDone: %w", err)
+		}
+		c.timeline.mark(MilestoneBreakpointsAcked, "")
+	}
 
-	log.Println("DEBUG: Sending ConfigurationDone Request...")
+	c.logger.Println("DEBUG: Sending ConfigurationDone Request...")
 	if err := c.write(configDoneRequest); err != nil {
 		return nil, fmt.Errorf("failed to send configurationDone request: %w", err)
 	}
+	c.timeline.mark(MilestoneConfigDoneSent, "")
 
 	// Wait for both
 	var launchResp *dap.LaunchResponse
 
 	// We need to collect both. Order doesn't matter.
-	timeout := time.After(30 * time.Second) // Default timeout
+	timeout := time.After(c.commandTimeout) // Default timeout
 	if d, ok := ctx.Deadline(); ok {
 		timeout = time.After(time.Until(d))
 	}
@@ -842,6 +1003,7 @@ func (c *Client) LaunchWithConfigurationDone(ctx context.Context, args map[strin
 		select {
 		case msg := <-launchCh:
 			gotLaunch = true
+			c.timeline.mark(MilestoneLaunchResponse, "launch")
 			if m, ok := msg.(*dap.LaunchResponse); ok {
 				if !m.Success {
 					return nil, fmt.Errorf("launch failed: %s", m.Message)
@@ -852,6 +1014,7 @@ func (c *Client) LaunchWithConfigurationDone(ctx context.Context, args map[strin
 			}
 		case msg := <-configCh:
 			gotConfig = true
+			c.timeline.mark(MilestoneConfigDoneAcked, "")
 			if m, ok := msg.(*dap.ConfigurationDoneResponse); ok {
 				if !m.Success {
 					return nil, fmt.Errorf("configurationDone failed: %s", m.Message)
@@ -869,6 +1032,34 @@ func (c *Client) LaunchWithConfigurationDone(ctx context.Context, args map[strin
 	return launchResp, nil
 }
 
+// startInOrder is the spec's start sequence for adapters without the
+// quirks.Set.LaunchResponseAfterConfigurationDone quirk: the launch or attach
+// request made by send is answered before configurationDone is sent
+func (c *Client) startInOrder(ctx context.Context, command string, waitForBreakpoints bool, send func(context.Context) error) error {
+	c.timeline.begin()
+	c.startup.begin()
+
+	c.timeline.mark(MilestoneRequestSent, command)
+	if err := send(ctx); err != nil {
+		return fmt.Errorf("%s failed: %w", command, err)
+	}
+	c.timeline.mark(MilestoneLaunchResponse, command)
+
+	if waitForBreakpoints {
+		if err := c.breakpoints.wait(ctx); err != nil {
+			return fmt.Errorf("breakpoints were not acknowledged before configurationDone: %w", err)
+		}
+		c.timeline.mark(MilestoneBreakpointsAcked, "")
+	}
+
+	c.timeline.mark(MilestoneConfigDoneSent, "")
+	if err := c.ConfigurationDone(ctx); err != nil {
+		return err
+	}
+	c.timeline.mark(MilestoneConfigDoneAcked, "")
+	return nil
+}
+
 // Attach sends an attach request to connect to an already running Godot game.
 // Note: The attach request only stores parameters. The connection won't actually happen
 // until configurationDone() is called after this.
@@ -880,32 +1071,26 @@ func (c *Client) Attach(ctx context.Context, args map[string]interface{}) (*dap.
 	}
 
 	request := &dap.AttachRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "attach",
-		},
+		Request:   c.newRequest("attach"),
 		Arguments: argsJSON,
 	}
 
-	resp, err := c.sendRequestAndWait(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-
-	attachResp, ok := resp.(*dap.AttachResponse)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %T", resp)
-	}
-
-	return attachResp, nil
+	return sendTyped[*dap.AttachRequest, *dap.AttachResponse](ctx, c, request)
 }
 
-// AttachWithConfigurationDone sends an attach request followed immediately by configurationDone.
-// This is required for Godot, which only sends the attach response AFTER receiving configurationDone.
+// AttachWithConfigurationDone sends an attach request followed by configurationDone,
+// right away with the LaunchResponseAfterConfigurationDone quirk (see
+// LaunchWithConfigurationDone).
 func (c *Client) AttachWithConfigurationDone(ctx context.Context, args map[string]interface{}) (*dap.AttachResponse, error) {
+	if !c.quirks.LaunchResponseAfterConfigurationDone {
+		var attachResp *dap.AttachResponse
+		err := c.startInOrder(ctx, "attach", false, func(ctx context.Context) (err error) {
+			attachResp, err = c.Attach(ctx, args)
+			return err
+		})
+		return attachResp, err
+	}
+
 	// Marshal arguments to JSON
 	argsJSON, err := json.Marshal(args)
 	if err != nil {
@@ -913,60 +1098,46 @@ func (c *Client) AttachWithConfigurationDone(ctx context.Context, args map[strin
 	}
 
 	attachRequest := &dap.AttachRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "attach",
-		},
+		Request:   c.newRequest("attach"),
 		Arguments: argsJSON,
 	}
 
 	configDoneRequest := &dap.ConfigurationDoneRequest{
-		Request: dap.Request{
-			ProtocolMessage: dap.ProtocolMessage{
-				Seq:  c.nextRequestSeq(),
-				Type: "request",
-			},
-			Command: "configurationDone",
-		},
+		Request: c.newRequest("configurationDone"),
 	}
 
 	// Use channels to wait
 	attachSeq := attachRequest.Seq
 	configSeq := configDoneRequest.Seq
 
-	attachCh := make(chan dap.Message, 1)
-	configCh := make(chan dap.Message, 1)
-
-	c.reqMu.Lock()
-	c.pendingReqs[attachSeq] = attachCh
-	c.pendingReqs[configSeq] = configCh
-	c.reqMu.Unlock()
+	attachCh := c.requests.register(attachSeq, "attach")
+	configCh := c.requests.register(configSeq, "configurationDone")
 
 	defer func() {
-		c.reqMu.Lock()
-		delete(c.pendingReqs, attachSeq)
-		delete(c.pendingReqs, configSeq)
-		c.reqMu.Unlock()
+		c.requests.release(attachSeq)
+		c.requests.release(configSeq)
 	}()
 
-	log.Println("DEBUG: Sending Attach Request...")
+	c.timeline.begin()
+	c.startup.begin()
+
+	c.logger.Println("DEBUG: Sending Attach Request...")
 	if err := c.write(attachRequest); err != nil {
 		return nil, fmt.Errorf("failed to send attach request: %w", err)
 	}
+	c.timeline.mark(MilestoneRequestSent, "attach")
 
-	log.Println("DEBUG: Sending ConfigurationDone Request...")
+	c.logger.Println("DEBUG: Sending ConfigurationDone Request...")
 	if err := c.write(configDoneRequest); err != nil {
 		return nil, fmt.Errorf("failed to send configurationDone request: %w", err)
 	}
+	c.timeline.mark(MilestoneConfigDoneSent, "")
 
 	// Wait for both
 	var attachResp *dap.AttachResponse
 
 	// We need to collect both. Order doesn't matter.
-	timeout := time.After(30 * time.Second) // Default timeout
+	timeout := time.After(c.commandTimeout) // Default timeout
 	if d, ok := ctx.Deadline(); ok {
 		timeout = time.After(time.Until(d))
 	}
@@ -978,6 +1149,7 @@ func (c *Client) AttachWithConfigurationDone(ctx context.Context, args map[strin
 		select {
 		case msg := <-attachCh:
 			gotAttach = true
+			c.timeline.mark(MilestoneLaunchResponse, "attach")
 			if m, ok := msg.(*dap.AttachResponse); ok {
 				if !m.Success {
 					return nil, fmt.Errorf("attach failed: %s", m.Message)
@@ -988,6 +1160,7 @@ func (c *Client) AttachWithConfigurationDone(ctx context.Context, args map[strin
 			}
 		case msg := <-configCh:
 			gotConfig = true
+			c.timeline.mark(MilestoneConfigDoneAcked, "")
 			if m, ok := msg.(*dap.ConfigurationDoneResponse); ok {
 				if !m.Success {
 					return nil, fmt.Errorf("configurationDone failed: %s", m.Message)
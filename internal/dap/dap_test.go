@@ -1,11 +1,24 @@
 package dap
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/google/go-dap"
 )
 
 func TestNewClient(t *testing.T) {
@@ -100,6 +113,7 @@ func TestSessionStateTransitions(t *testing.T) {
 		{StateInitialized, "initialized"},
 		{StateConfigured, "configured"},
 		{StateLaunched, "launched"},
+		{StateAttached, "attached"},
 	}
 
 	for _, tc := range states {
@@ -133,6 +147,74 @@ func TestSessionStateTransitions(t *testing.T) {
 	if !session.IsReady() {
 		t.Error("session should be ready when launched")
 	}
+
+	session.state = StateAttached
+	if !session.IsReady() {
+		t.Error("session should be ready when attached")
+	}
+}
+
+func TestSessionStateCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to SessionState
+		want     bool
+	}{
+		{StateDisconnected, StateConnected, true},
+		{StateDisconnected, StateInitialized, false},
+		{StateConnected, StateInitialized, true},
+		{StateConnected, StateLaunched, false},
+		{StateInitialized, StateConfigured, true},
+		{StateInitialized, StateLaunched, true},
+		{StateInitialized, StateAttached, true},
+		{StateConfigured, StateConnected, false},
+		{StateLaunched, StateLaunched, true},
+		{StateLaunched, StateAttached, true},
+		{StateLaunched, StateInitialized, false},
+		{StateAttached, StateDisconnected, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestSessionOnStateChange(t *testing.T) {
+	session := NewSession("localhost", 6006)
+
+	var changes []StateChange
+	remove := session.OnStateChange(func(change StateChange) {
+		changes = append(changes, change)
+	})
+
+	if err := session.transition(StateConnected); err != nil {
+		t.Fatalf("disconnected -> connected should be valid: %v", err)
+	}
+	if err := session.transition(StateLaunched); err == nil {
+		t.Error("connected -> launched should be rejected")
+	}
+	if session.GetState() != StateConnected {
+		t.Errorf("invalid transition changed the state to %s", session.GetState())
+	}
+	if err := session.transition(StateConnected); err != nil {
+		t.Errorf("re-entering the current state should be accepted: %v", err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 state change, got %d: %v", len(changes), changes)
+	}
+	if changes[0].From != StateDisconnected || changes[0].To != StateConnected || changes[0].Time.IsZero() {
+		t.Errorf("unexpected state change: %+v", changes[0])
+	}
+
+	remove()
+	if err := session.transition(StateDisconnected); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("removed handler should not be called, got %d changes", len(changes))
+	}
 }
 
 func TestSessionRequireReady(t *testing.T) {
@@ -272,6 +354,105 @@ func TestGodotLaunchConfigToLaunchArgs(t *testing.T) {
 	if args["additional_options"] != "--verbose" {
 		t.Errorf("expected additional_options to be --verbose, got %v", args["additional_options"])
 	}
+
+	if _, ok := args["stopOnEntry"]; ok {
+		t.Error("stopOnEntry should be omitted unless requested")
+	}
+	config.StopOnEntry = true
+	if args := config.ToLaunchArgs(); args["stopOnEntry"] != true {
+		t.Errorf("expected stopOnEntry to be true, got %v", args["stopOnEntry"])
+	}
+}
+
+func TestAttachConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AttachConfig
+		wantErr bool
+	}{
+		{name: "defaults", config: AttachConfig{}},
+		{name: "port only", config: AttachConfig{Port: 6007}},
+		{name: "address and port", config: AttachConfig{Address: "192.168.1.20", Port: 6007}},
+		{name: "negative port", config: AttachConfig{Port: -1}, wantErr: true},
+		{name: "port too large", config: AttachConfig{Port: 70000}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttachConfigToAttachArgs(t *testing.T) {
+	config := &AttachConfig{Port: 6007}
+	args := config.ToAttachArgs()
+	if args["address"] != "127.0.0.1" || args["port"] != 6007 {
+		t.Errorf("expected default address and port 6007, got %v", args)
+	}
+
+	if args := (&AttachConfig{}).ToAttachArgs(); len(args) != 0 {
+		t.Errorf("default config should send no arguments, got %v", args)
+	}
+
+	config.Args = map[string]interface{}{"port": 7000, "platform": "android"}
+	args = config.ToAttachArgs()
+	if args["port"] != 7000 || args["platform"] != "android" {
+		t.Errorf("custom args should be included and override fields, got %v", args)
+	}
+}
+
+func TestAttachGodotRequiresInitializedSession(t *testing.T) {
+	session := NewSession("localhost", 6006)
+
+	if _, err := session.AttachGodot(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "must be initialized") {
+		t.Errorf("attach on a disconnected session should fail, got %v", err)
+	}
+	if _, err := session.AttachGodot(context.Background(), &AttachConfig{Port: -5}); err == nil || !strings.Contains(err.Error(), "invalid attach configuration") {
+		t.Errorf("invalid config should be rejected before attaching, got %v", err)
+	}
+}
+
+func TestLaunchGodotSceneRequiresInitializedSession(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "project.godot"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	session := NewSession("127.0.0.1", l.Addr().(*net.TCPAddr).Port)
+	if err := session.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	// Connected but not initialized: the game must not be started
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := session.LaunchMainScene(ctx, project); err == nil || !strings.Contains(err.Error(), "must be initialized") {
+		t.Errorf("launch on an uninitialized session should fail, got %v", err)
+	}
+	session.Close()
+	if data := <-received; strings.Contains(string(data), `"launch"`) {
+		t.Errorf("no launch request should be sent, got %s", data)
+	}
 }
 
 func TestGodotLaunchConfigSceneModes(t *testing.T) {
@@ -475,3 +656,1244 @@ func TestClientEvaluate_NotConnected(t *testing.T) {
 		t.Error("Evaluate should error when not connected")
 	}
 }
+
+// frame wraps a JSON body in a DAP Content-Length header
+func frame(body string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestClientRead_PooledBuffersDoNotAlias(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	input := frame(`{"seq":1,"type":"request","command":"launch","arguments":{"project":"/first"}}`) +
+		frame(`{"seq":2,"type":"request","command":"launch","arguments":{"project":"/second"}}`)
+
+	client := NewClient("localhost", 6006)
+	client.reader = bufio.NewReader(bytes.NewBufferString(input))
+
+	first, err := client.read()
+	if err != nil {
+		t.Fatalf("first read failed: %v", err)
+	}
+	if _, err := client.read(); err != nil {
+		t.Fatalf("second read failed: %v", err)
+	}
+
+	// The first message's raw arguments must not be overwritten by the second read
+	launch, ok := first.(*dap.LaunchRequest)
+	if !ok {
+		t.Fatalf("expected *dap.LaunchRequest, got %T", first)
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(launch.Arguments, &args); err != nil {
+		t.Fatalf("failed to unmarshal arguments: %v", err)
+	}
+	if args["project"] != "/first" {
+		t.Errorf("expected project /first, got %v", args["project"])
+	}
+}
+
+// repeatReader endlessly replays the same bytes
+type repeatReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		c := copy(p[n:], r.data[r.pos:])
+		n += c
+		r.pos = (r.pos + c) % len(r.data)
+	}
+	return n, nil
+}
+
+func BenchmarkClientRead(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	msg := frame(`{"seq":1,"type":"event","event":"output","body":{"category":"stdout","output":"Player position updated: (120.5, 340.25)\n"}}`)
+
+	client := NewClient("localhost", 6006)
+	client.reader = bufio.NewReader(&repeatReader{data: []byte(msg)})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.read(); err != nil {
+			b.Fatalf("read failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkClientWrite(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	go io.Copy(io.Discard, serverConn)
+
+	client := NewClient("localhost", 6006)
+	client.conn = clientConn
+	client.connected = true
+
+	req := &dap.VariablesRequest{
+		Request: dap.Request{
+			ProtocolMessage: dap.ProtocolMessage{Seq: 1, Type: "request"},
+			Command:         "variables",
+		},
+		Arguments: dap.VariablesArguments{VariablesReference: 1000},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := client.write(req); err != nil {
+			b.Fatalf("write failed: %v", err)
+		}
+	}
+}
+
+func TestClientRead_MalformedHeaders(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"bare newline", "\n"},
+		{"missing content length", "Content-Type: json\r\n\r\n{}"},
+		{"negative content length", "Content-Length: -5\r\n\r\n{}"},
+		{"oversized content length", "Content-Length: 99999999999\r\n\r\n{}"},
+		{"truncated body", "Content-Length: 100\r\n\r\n{}"},
+		{"invalid json body", frame("not json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("localhost", 6006)
+			client.reader = bufio.NewReader(bytes.NewBufferString(tt.input))
+
+			if _, err := client.read(); err == nil {
+				t.Error("expected read to fail")
+			}
+		})
+	}
+}
+
+func FuzzClientRead(f *testing.F) {
+	f.Add([]byte(frame(`{"seq":1,"type":"event","event":"initialized"}`)))
+	f.Add([]byte(frame(`{"seq":2,"type":"response","request_seq":1,"command":"threads","success":true,"body":{"threads":[{"id":1,"name":"Main"}]}}`)))
+	f.Add([]byte("Content-Length: 2\r\n\r\n{}"))
+	f.Add([]byte("Content-Length: -1\r\n\r\n"))
+	f.Add([]byte("\n\n"))
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		client := NewClient("localhost", 6006)
+		client.reader = bufio.NewReader(bytes.NewReader(data))
+
+		// Every read consumes input, so this terminates once the data runs out.
+		// The fuzzer is checking for panics and hangs, not specific errors.
+		for i := 0; i < 100; i++ {
+			if _, err := client.read(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func TestThreadCache(t *testing.T) {
+	client := NewClient("localhost", 6006)
+	ctx := context.Background()
+
+	// Empty cache falls through to a threads request, which fails when not connected
+	if _, err := client.CachedThreads(ctx); err == nil {
+		t.Error("CachedThreads should error when cache is empty and not connected")
+	}
+
+	client.threads.set([]dap.Thread{{Id: 1, Name: "Main"}, {Id: 2, Name: "Worker"}})
+
+	threads, err := client.CachedThreads(ctx)
+	if err != nil {
+		t.Fatalf("CachedThreads should use cache without a request: %v", err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("expected 2 cached threads, got %d", len(threads))
+	}
+
+	// Exited thread is removed without invalidating the cache
+	client.threads.applyEvent(&dap.ThreadEvent{Body: dap.ThreadEventBody{Reason: "exited", ThreadId: 2}})
+	threads, err = client.CachedThreads(ctx)
+	if err != nil || len(threads) != 1 || threads[0].Id != 1 {
+		t.Errorf("expected only thread 1 after exit, got %v (err=%v)", threads, err)
+	}
+
+	// Started thread invalidates the cache (name unknown)
+	client.threads.applyEvent(&dap.ThreadEvent{Body: dap.ThreadEventBody{Reason: "started", ThreadId: 3}})
+	if _, ok := client.threads.get(); ok {
+		t.Error("cache should be invalid after a thread start")
+	}
+
+	events := client.ThreadEvents()
+	if len(events) != 2 || events[0].Reason != "exited" || events[1].ThreadId != 3 {
+		t.Errorf("unexpected thread events: %+v", events)
+	}
+
+	// Termination invalidates as well
+	client.threads.set([]dap.Thread{{Id: 1, Name: "Main"}})
+	client.threads.applyEvent(&dap.TerminatedEvent{})
+	if _, ok := client.threads.get(); ok {
+		t.Error("cache should be invalid after termination")
+	}
+}
+
+func TestThreadCache_EventHistoryBounded(t *testing.T) {
+	var tc threadCache
+	for i := 0; i < maxThreadEventHistory+10; i++ {
+		tc.applyEvent(&dap.ThreadEvent{Body: dap.ThreadEventBody{Reason: "started", ThreadId: i}})
+	}
+
+	events := tc.recentEvents()
+	if len(events) != maxThreadEventHistory {
+		t.Fatalf("expected %d events, got %d", maxThreadEventHistory, len(events))
+	}
+	if events[0].ThreadId != 10 {
+		t.Errorf("expected oldest events to be dropped, first is thread %d", events[0].ThreadId)
+	}
+}
+
+func TestThreadStates(t *testing.T) {
+	var tc threadCache
+	tc.set([]dap.Thread{{Id: 1, Name: "Main"}, {Id: 2, Name: "Worker"}})
+
+	// A single-thread stop only affects that thread
+	tc.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "breakpoint", ThreadId: 2}})
+	states := tc.stateSnapshot()
+	if st := states[2]; !st.Stopped || st.Reason != "breakpoint" {
+		t.Errorf("thread 2 should be stopped at breakpoint, got %+v", st)
+	}
+	if _, ok := states[1]; ok {
+		t.Errorf("thread 1 state should be unknown, got %+v", states[1])
+	}
+
+	// allThreadsStopped marks every known thread
+	tc.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "pause", ThreadId: 1, AllThreadsStopped: true}})
+	states = tc.stateSnapshot()
+	for _, id := range []int{1, 2} {
+		if st := states[id]; !st.Stopped || st.Reason != "pause" {
+			t.Errorf("thread %d should be stopped by pause, got %+v", id, st)
+		}
+	}
+
+	// Resuming a single thread leaves the others stopped
+	tc.markRunning(1, false)
+	states = tc.stateSnapshot()
+	if states[1].Stopped || !states[2].Stopped {
+		t.Errorf("only thread 1 should be running, got %+v", states)
+	}
+
+	// allThreadsContinued resumes everything
+	tc.applyEvent(&dap.ContinuedEvent{Body: dap.ContinuedEventBody{ThreadId: 2, AllThreadsContinued: true}})
+	for id, st := range tc.stateSnapshot() {
+		if st.Stopped {
+			t.Errorf("thread %d should be running after allThreadsContinued", id)
+		}
+	}
+
+	// Exited threads drop their state; termination clears it all
+	tc.applyEvent(&dap.ThreadEvent{Body: dap.ThreadEventBody{Reason: "exited", ThreadId: 2}})
+	if _, ok := tc.stateSnapshot()[2]; ok {
+		t.Error("exited thread should have no state")
+	}
+	tc.applyEvent(&dap.TerminatedEvent{})
+	if len(tc.stateSnapshot()) != 0 {
+		t.Error("termination should clear thread states")
+	}
+}
+
+func TestOutputBuffer(t *testing.T) {
+	var ob outputBuffer
+	ob.applyEvent(&dap.StoppedEvent{}) // ignored
+	for i := 0; i < maxOutputHistory+5; i++ {
+		ob.applyEvent(&dap.OutputEvent{Body: dap.OutputEventBody{Category: "stdout", Output: "line\n"}})
+	}
+
+	records := ob.snapshot()
+	if len(records) != maxOutputHistory {
+		t.Fatalf("expected %d records, got %d", maxOutputHistory, len(records))
+	}
+	if records[0].Seq != 6 || records[len(records)-1].Seq != maxOutputHistory+5 {
+		t.Errorf("unexpected sequence range %d..%d", records[0].Seq, records[len(records)-1].Seq)
+	}
+	if records[0].Category != "stdout" || records[0].Output != "line\n" {
+		t.Errorf("unexpected record: %+v", records[0])
+	}
+
+	// Output recorded on the adapter's behalf is kept alongside the game's
+	client := NewClient("localhost", 6006)
+	client.output.applyEvent(&dap.OutputEvent{Body: dap.OutputEventBody{Category: "stdout", Output: "line\n"}})
+	client.RecordOutput("console", "hp=80\n")
+	if output := client.Output(); len(output) != 2 || output[1].Seq != 2 || output[1].Category != "console" || output[1].Output != "hp=80\n" {
+		t.Errorf("unexpected recorded output: %+v", output)
+	}
+}
+
+func TestEventHistory(t *testing.T) {
+	var eh eventHistory
+	eh.applyEvent(&dap.ThreadsResponse{}) // ignored
+	eh.applyEvent(&dap.StoppedEvent{Event: dap.Event{Event: "stopped"}})
+	eh.applyEvent(&dap.OutputEvent{Event: dap.Event{Event: "output"}})
+	eh.applyEvent(&dap.ContinuedEvent{Event: dap.Event{Event: "continued"}})
+
+	all := eh.since(0, nil)
+	if len(all.Events) != 3 || all.LastSeq != 3 || all.Missed != 0 {
+		t.Fatalf("unexpected history: %+v", all)
+	}
+	if all.Events[0].Event != "stopped" || all.Events[2].Seq != 3 {
+		t.Errorf("unexpected events: %+v", all.Events)
+	}
+	if h := eh.since(1, []string{"stopped", "continued"}); len(h.Events) != 1 || h.Events[0].Event != "continued" || h.LastSeq != 3 {
+		t.Errorf("expected only the continued event after seq 1, got %+v", h)
+	}
+	if h := eh.since(3, nil); len(h.Events) != 0 || h.LastSeq != 3 {
+		t.Errorf("expected nothing after the last seq, got %+v", h)
+	}
+
+	// Once the ring wraps, the oldest events are overwritten and reported missed
+	for i := 0; i < maxEventHistory; i++ {
+		eh.applyEvent(&dap.OutputEvent{Event: dap.Event{Event: "output"}})
+	}
+	h := eh.since(0, nil)
+	if len(h.Events) != maxEventHistory || h.Missed != 3 {
+		t.Fatalf("expected %d events and 3 missed, got %d and %d", maxEventHistory, len(h.Events), h.Missed)
+	}
+	for i, r := range h.Events {
+		if r.Seq != i+4 {
+			t.Fatalf("events out of order at %d: seq %d", i, r.Seq)
+		}
+	}
+	if h := eh.since(maxEventHistory, nil); len(h.Events) != 3 || h.Missed != 0 || h.Events[0].Seq != maxEventHistory+1 {
+		t.Errorf("unexpected events after seq %d: %+v", maxEventHistory, h)
+	}
+}
+
+func TestWaitForEvent(t *testing.T) {
+	client := NewClient("localhost", 6006)
+
+	go func() {
+		// Wait until the waiter has subscribed before broadcasting
+		for {
+			client.eventMu.Lock()
+			n := len(client.eventListeners)
+			client.eventMu.Unlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		client.broadcastEvent(&dap.StoppedEvent{Event: dap.Event{Event: "stopped"}})
+		client.broadcastEvent(&dap.ExitedEvent{Event: dap.Event{Event: "exited"}, Body: dap.ExitedEventBody{ExitCode: 1}})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	event, err := client.WaitForEvent(ctx, []string{"terminated", "exited"})
+	if err != nil {
+		t.Fatalf("WaitForEvent failed: %v", err)
+	}
+	exited, ok := event.(*dap.ExitedEvent)
+	if !ok || exited.Body.ExitCode != 1 {
+		t.Errorf("expected exited event with code 1, got %#v", event)
+	}
+
+	// Times out when no matching event arrives
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitForEvent(ctx, []string{"terminated"}); err == nil {
+		t.Error("WaitForEvent should time out without a matching event")
+	}
+}
+
+func TestExitTracker(t *testing.T) {
+	var et exitTracker
+	if et.get() != nil {
+		t.Fatal("exit status should be nil before the run ends")
+	}
+
+	et.applyEvent(&dap.ExitedEvent{Body: dap.ExitedEventBody{ExitCode: 3}})
+	et.applyEvent(&dap.TerminatedEvent{})
+	st := et.get()
+	if st == nil || st.Reason != ExitReasonExited || !st.Exited || st.ExitCode != 3 || !st.Terminated {
+		t.Errorf("unexpected exit status: %+v", st)
+	}
+
+	// A dropped connection after a normal exit doesn't change the reason
+	et.connectionLost()
+	if et.get().Reason != ExitReasonExited {
+		t.Error("connection loss should not override a recorded exit")
+	}
+
+	et.reset()
+	et.applyEvent(&dap.TerminatedEvent{})
+	if st := et.get(); st.Reason != ExitReasonTerminated || st.Exited {
+		t.Errorf("terminated without exited should report terminated, got %+v", st)
+	}
+
+	et.reset()
+	et.connectionLost()
+	if st := et.get(); st.Reason != ExitReasonConnectionLost {
+		t.Errorf("expected connection_lost, got %+v", st)
+	}
+}
+
+func TestProcessTracker(t *testing.T) {
+	var pt processTracker
+	if p, _ := pt.get(); p != nil {
+		t.Fatal("process info should be nil before a process event")
+	}
+
+	pt.applyEvent(&dap.ProcessEvent{Body: dap.ProcessEventBody{Name: "my-game", SystemProcessId: 4242, StartMethod: "launch"}})
+	pt.applyEvent(&dap.ModuleEvent{Body: dap.ModuleEventBody{Reason: "new", Module: dap.Module{Id: 1, Name: "libgame"}}})
+	pt.applyEvent(&dap.ModuleEvent{Body: dap.ModuleEventBody{Reason: "new", Module: dap.Module{Id: "ext", Name: "extension"}}})
+	pt.applyEvent(&dap.ModuleEvent{Body: dap.ModuleEventBody{Reason: "changed", Module: dap.Module{Id: 1, Name: "libgame", Version: "2"}}})
+	pt.applyEvent(&dap.ModuleEvent{Body: dap.ModuleEventBody{Reason: "removed", Module: dap.Module{Id: "ext"}}})
+
+	process, modules := pt.get()
+	if process == nil || process.Name != "my-game" || process.PID != 4242 {
+		t.Errorf("unexpected process info: %+v", process)
+	}
+	if len(modules) != 1 || modules[0].ID != "1" || modules[0].Version != "2" {
+		t.Errorf("unexpected modules: %+v", modules)
+	}
+
+	pt.reset()
+	if process, modules := pt.get(); process != nil || len(modules) != 0 {
+		t.Error("reset should clear process info and modules")
+	}
+}
+
+func TestLaunchTimeline(t *testing.T) {
+	var lt launchTimeline
+
+	// Nothing is recorded before a launch begins
+	lt.mark(MilestoneRequestSent, "launch")
+	if len(lt.snapshot()) != 0 {
+		t.Fatal("milestones should not be recorded before begin")
+	}
+
+	lt.begin()
+	lt.mark(MilestoneRequestSent, "launch")
+	lt.mark(MilestoneRequestSent, "launch")
+	lt.applyEvent(&dap.ProcessEvent{Body: dap.ProcessEventBody{Name: "game"}})
+	lt.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "breakpoint"}})
+	lt.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "step"}})
+
+	milestones := lt.snapshot()
+	if len(milestones) != 3 {
+		t.Fatalf("expected 3 milestones, got %+v", milestones)
+	}
+	if milestones[0].Name != MilestoneRequestSent || milestones[1].Name != MilestoneProcessStarted {
+		t.Errorf("unexpected milestone order: %+v", milestones)
+	}
+	if milestones[2].Name != MilestoneFirstStopped || milestones[2].Detail != "breakpoint" {
+		t.Errorf("first stop should keep the first reason, got %+v", milestones[2])
+	}
+
+	lt.begin()
+	if len(lt.snapshot()) != 0 {
+		t.Error("begin should discard the previous launch's milestones")
+	}
+}
+
+func TestStartupCapture(t *testing.T) {
+	var sc startupCapture
+	output := func(text string) *dap.OutputEvent {
+		return &dap.OutputEvent{Body: dap.OutputEventBody{Category: "stdout", Output: text}}
+	}
+
+	// Nothing is collected before a launch begins
+	sc.applyEvent(output("before"))
+	if got := sc.snapshot(); !got.Start.IsZero() || len(got.Records) != 0 {
+		t.Fatalf("output should not be collected before begin, got %+v", got)
+	}
+
+	sc.begin()
+	sc.applyEvent(output("Godot Engine v4.3\n"))
+	sc.applyEvent(output("WARNING: deprecated\n"))
+	if got := sc.snapshot(); got.EndReason != StartupCollecting || len(got.Records) != 2 {
+		t.Fatalf("expected 2 records while collecting, got %+v", got)
+	}
+
+	sc.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "breakpoint"}})
+	sc.applyEvent(output("after the stop"))
+	got := sc.snapshot()
+	if got.EndReason != StartupFirstStop || got.End.IsZero() {
+		t.Errorf("expected collection to end at the first stop, got %+v", got)
+	}
+	if len(got.Records) != 2 || got.Records[1].Seq != 2 {
+		t.Errorf("output after the first stop should not be collected, got %+v", got.Records)
+	}
+
+	sc.begin()
+	sc.applyEvent(&dap.TerminatedEvent{})
+	if got := sc.snapshot(); got.EndReason != StartupEnded || len(got.Records) != 0 {
+		t.Errorf("begin should reset, and termination should end collection, got %+v", got)
+	}
+
+	// The window closes on its own once maxStartupWindow has passed
+	sc.begin()
+	sc.output.Start = time.Now().Add(-2 * maxStartupWindow)
+	sc.applyEvent(output("late"))
+	if got := sc.snapshot(); got.EndReason != StartupTimeout || len(got.Records) != 0 {
+		t.Errorf("expected a timeout without late output, got %+v", got)
+	}
+}
+
+func TestRunState(t *testing.T) {
+	client := NewClient("localhost", 6006)
+	if got := client.RunState(); got != RunStateNotLaunched {
+		t.Errorf("expected not_launched, got %s", got)
+	}
+
+	client.timeline.begin()
+	if got := client.RunState(); got != RunStateRunning {
+		t.Errorf("expected running after launch, got %s", got)
+	}
+
+	client.threads.applyEvent(&dap.StoppedEvent{Body: dap.StoppedEventBody{Reason: "breakpoint", ThreadId: 1}})
+	if got := client.RunState(); got != RunStatePaused {
+		t.Errorf("expected paused after stopped event, got %s", got)
+	}
+
+	client.threads.applyEvent(&dap.ContinuedEvent{Body: dap.ContinuedEventBody{ThreadId: 1}})
+	if got := client.RunState(); got != RunStateRunning {
+		t.Errorf("expected running after continued event, got %s", got)
+	}
+
+	client.exit.applyEvent(&dap.TerminatedEvent{})
+	if got := client.RunState(); got != RunStateEnded {
+		t.Errorf("expected ended after terminated event, got %s", got)
+	}
+}
+
+func TestSendTyped(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client := NewClient("localhost", 6006)
+	client.conn = clientConn
+	client.reader = bufio.NewReader(clientConn)
+	client.connected = true
+	go client.readLoop()
+
+	// Fake adapter: answer threads correctly, but answer pause with a
+	// threads response to exercise the type check
+	go func() {
+		reader := bufio.NewReader(serverConn)
+		for {
+			msg, err := dap.ReadProtocolMessage(reader)
+			if err != nil {
+				return
+			}
+			req := msg.(dap.RequestMessage).GetRequest()
+			dap.WriteProtocolMessage(serverConn, &dap.ThreadsResponse{
+				Response: dap.Response{
+					ProtocolMessage: dap.ProtocolMessage{Seq: req.Seq + 100, Type: "response"},
+					Command:         "threads",
+					RequestSeq:      req.Seq,
+					Success:         true,
+				},
+				Body: dap.ThreadsResponseBody{Threads: []dap.Thread{{Id: 1, Name: "Main"}}},
+			})
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := client.Threads(ctx)
+	if err != nil {
+		t.Fatalf("Threads failed: %v", err)
+	}
+	if len(resp.Body.Threads) != 1 || resp.Body.Threads[0].Name != "Main" {
+		t.Errorf("unexpected threads: %+v", resp.Body.Threads)
+	}
+
+	_, err = client.Pause(ctx, 1)
+	if err == nil || !strings.Contains(err.Error(), "unexpected response type for pause") {
+		t.Errorf("expected unexpected response type error, got %v", err)
+	}
+}
+
+// recordingDialer hands out one end of a pipe and records the dialed address
+type recordingDialer struct {
+	conn    net.Conn
+	address string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.address = address
+	return d.conn, nil
+}
+
+func TestClientOptions(t *testing.T) {
+	defaults := NewClient("localhost", 6006)
+	if defaults.connectTimeout != DefaultConnectTimeout || defaults.commandTimeout != DefaultCommandTimeout {
+		t.Errorf("unexpected default timeouts: %v, %v", defaults.connectTimeout, defaults.commandTimeout)
+	}
+	if defaults.eventBufferSize != defaultEventBufferSize || defaults.logger == nil || defaults.dialer == nil {
+		t.Error("defaults should be set when no options are given")
+	}
+
+	var logs bytes.Buffer
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+	dialer := &recordingDialer{conn: clientConn}
+
+	client := NewClient("example", 7000,
+		WithLogger(log.New(&logs, "", 0)),
+		WithTimeouts(time.Second, 0),
+		WithDialer(dialer),
+		WithEventBufferSize(5),
+	)
+	if client.connectTimeout != time.Second || client.commandTimeout != DefaultCommandTimeout {
+		t.Errorf("WithTimeouts should override only non-zero values, got %v, %v", client.connectTimeout, client.commandTimeout)
+	}
+
+	events, cleanup := client.SubscribeToEvents()
+	defer cleanup()
+	if cap(events) != 5 {
+		t.Errorf("expected event buffer of 5, got %d", cap(events))
+	}
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect with custom dialer failed: %v", err)
+	}
+	defer client.Disconnect()
+	if dialer.address != "example:7000" {
+		t.Errorf("dialer got address %q", dialer.address)
+	}
+
+	client.logEvent(&dap.TerminatedEvent{})
+	if !strings.Contains(logs.String(), "[DAP Event] Terminated") {
+		t.Errorf("expected event logged to custom logger, got %q", logs.String())
+	}
+}
+
+func TestDialerFunc(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	var dialed string
+	client := NewClient("localhost", 6006, WithDialer(DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = network + " " + address
+		return clientConn, nil
+	})))
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	if dialed != "tcp localhost:6006" {
+		t.Errorf("unexpected dial: %q", dialed)
+	}
+}
+
+func TestSSHDialer_Failure(t *testing.T) {
+	// "false" stands in for an ssh client that fails to authenticate
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d := &SSHDialer{Target: "me@devbox", Command: "false"}
+	_, err := d.DialContext(ctx, "tcp", "localhost:6006")
+	if err == nil || !strings.Contains(err.Error(), "ssh tunnel to me@devbox failed") {
+		t.Errorf("expected tunnel failure, got %v", err)
+	}
+}
+
+func TestSocketDialer_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dap.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	client := NewClient("ignored", 0, WithDialer(&SocketDialer{Path: path}))
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect over unix socket failed: %v", err)
+	}
+	client.Disconnect()
+
+	d := &SocketDialer{Path: filepath.Join(t.TempDir(), "missing.sock")}
+	if _, err := d.DialContext(context.Background(), "tcp", ""); err == nil {
+		t.Error("dialing a missing socket should fail")
+	}
+}
+
+func TestIsNamedPipePath(t *testing.T) {
+	tests := map[string]bool{
+		`\\.\pipe\godot-dap`:  true,
+		`//./pipe/godot-dap`:  true,
+		"/tmp/godot-dap.sock": false,
+		`C:\temp\dap.sock`:    false,
+	}
+	for path, want := range tests {
+		if got := isNamedPipePath(path); got != want {
+			t.Errorf("isNamedPipePath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestConnectionTuning(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			defer conn.Close()
+			io.Copy(io.Discard, conn)
+		}
+	}()
+
+	addr := l.Addr().(*net.TCPAddr)
+	client := NewClient("127.0.0.1", addr.Port,
+		WithKeepAlive(10*time.Second),
+		WithNoDelay(false),
+		WithReadBufferSize(64*1024),
+	)
+	if client.keepAlive != 10*time.Second || client.noDelay {
+		t.Errorf("options not applied: keepAlive=%v noDelay=%v", client.keepAlive, client.noDelay)
+	}
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Disconnect()
+
+	if client.reader.Size() != 64*1024 {
+		t.Errorf("expected 64KiB read buffer, got %d", client.reader.Size())
+	}
+}
+
+func TestBreakpointTracker(t *testing.T) {
+	var bt breakpointTracker
+
+	// Nothing pending: no wait
+	if err := bt.wait(context.Background()); err != nil {
+		t.Fatalf("wait with nothing pending failed: %v", err)
+	}
+
+	done := bt.begin("/p/autoload.gd", []int{8, 11})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	err := bt.wait(ctx)
+	cancel()
+	if err == nil || !strings.Contains(err.Error(), "1 setBreakpoints") {
+		t.Errorf("expected wait to time out with the pending count, got %v", err)
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- bt.wait(context.Background()) }()
+	resp := &dap.SetBreakpointsResponse{}
+	resp.Body.Breakpoints = []dap.Breakpoint{
+		{Id: 1, Line: 8, Verified: true},
+		{Id: 2, Line: 12, Verified: false},
+	}
+	done(resp, nil)
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Errorf("wait failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait didn't return once the response arrived")
+	}
+
+	if got := bt.unverified(); len(got) != 1 || got[0] != "/p/autoload.gd:12" {
+		t.Errorf("unexpected unverified breakpoints: %v", got)
+	}
+
+	// Godot verifies the breakpoint once the script loads
+	event := &dap.BreakpointEvent{}
+	event.Body.Reason = "changed"
+	event.Body.Breakpoint = dap.Breakpoint{Id: 2, Line: 12, Verified: true, Source: &dap.Source{Path: "/p/autoload.gd"}}
+	bt.applyEvent(event)
+	if got := bt.unverified(); len(got) != 0 {
+		t.Errorf("expected the event to verify the breakpoint, got %v", got)
+	}
+	acked := bt.acknowledged()
+	if bps := acked["/p/autoload.gd"]; len(bps) != 2 || !bps[1].Verified || bps[1].RequestedLine != 11 || bps[1].Line != 12 {
+		t.Errorf("unexpected acknowledged breakpoints: %+v", acked)
+	}
+	acked["/p/autoload.gd"][0].Line = 99
+	if bt.acknowledged()["/p/autoload.gd"][0].Line != 8 {
+		t.Error("acknowledged should return a copy")
+	}
+
+	// Breakpoints toggled in the Godot editor are announced by events
+	event = &dap.BreakpointEvent{}
+	event.Body.Reason = "new"
+	event.Body.Breakpoint = dap.Breakpoint{Id: 3, Line: 20, Verified: true, Source: &dap.Source{Path: "/p/autoload.gd"}}
+	bt.applyEvent(event)
+	event.Body.Reason = "removed"
+	event.Body.Breakpoint = dap.Breakpoint{Id: 1, Line: 8, Source: &dap.Source{Path: "/p/autoload.gd"}}
+	bt.applyEvent(event)
+	bps := bt.acknowledged()["/p/autoload.gd"]
+	if len(bps) != 2 || bps[0].Id != 2 || bps[1].Id != 3 || bps[1].RequestedLine != 0 {
+		t.Errorf("expected the editor's changes to be applied, got %+v", bps)
+	}
+	event.Body.Reason = "changed"
+	event.Body.Breakpoint = dap.Breakpoint{Id: 9, Line: 40, Source: &dap.Source{Path: "/p/autoload.gd"}}
+	bt.applyEvent(event)
+	if bps := bt.acknowledged()["/p/autoload.gd"]; len(bps) != 2 {
+		t.Errorf("a change to an unknown breakpoint should be ignored, got %+v", bps)
+	}
+	for _, id := range []int{2, 3} {
+		event.Body.Reason = "removed"
+		event.Body.Breakpoint = dap.Breakpoint{Id: id, Source: &dap.Source{Path: "/p/autoload.gd"}}
+		bt.applyEvent(event)
+	}
+	if _, ok := bt.acknowledged()["/p/autoload.gd"]; ok {
+		t.Error("a file whose breakpoints were all removed should be forgotten")
+	}
+
+	// Failed requests are reported by file
+	bt.begin("/p/missing.gd", nil)(nil, fmt.Errorf("no such file"))
+	if got := bt.unverified(); len(got) != 1 || got[0] != "/p/missing.gd" {
+		t.Errorf("expected the failed file, got %v", got)
+	}
+}
+
+func TestPadRequest(t *testing.T) {
+	padded, err := padRequest(&dap.EvaluateRequest{
+		Request:   dap.Request{ProtocolMessage: dap.ProtocolMessage{Seq: 7, Type: "request"}, Command: "evaluate"},
+		Arguments: dap.EvaluateArguments{Expression: "health"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if padded.GetSeq() != 7 {
+		t.Errorf("padding should keep the seq, got %d", padded.GetSeq())
+	}
+	encoded, err := json.Marshal(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// frameId 0 (Godot's top frame) is dropped by omitempty unless padded
+	for _, field := range []string{`"command":"evaluate"`, `"expression":"health"`, `"frameId":0`, `"context":""`} {
+		if !strings.Contains(string(encoded), field) {
+			t.Errorf("expected %s in %s", field, encoded)
+		}
+	}
+
+	padded, err = padRequest(&dap.ThreadsRequest{Request: dap.Request{ProtocolMessage: dap.ProtocolMessage{Seq: 8, Type: "request"}, Command: "threads"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded, _ := json.Marshal(padded); !strings.Contains(string(encoded), `"arguments":{}`) {
+		t.Errorf("requests without arguments should get an empty object, got %s", encoded)
+	}
+}
+
+func TestCheckShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"well-formed response", `{"seq":3,"type":"response","request_seq":2,"command":"threads","success":true,"body":{"threads":[{"id":1,"name":"Main"}]}}`, nil},
+		{"failed response without body", `{"seq":3,"type":"response","request_seq":2,"command":"stackTrace","success":false,"message":"not paused"}`, nil},
+		{"missing body", `{"seq":3,"type":"response","request_seq":2,"command":"stackTrace","success":true}`, []string{"body: missing"}},
+		{"wrong element type", `{"seq":3,"type":"response","request_seq":2,"command":"stackTrace","success":true,"body":{"stackFrames":[{"id":1,"name":"_ready","line":4},{"id":"2","name":"_process","line":9}]}}`,
+			[]string{"body.stackFrames[1].id: expected number, got string"}},
+		{"missing header field", `{"seq":3,"type":"response","command":"threads","success":true,"body":{"threads":[]}}`, []string{"request_seq: missing"}},
+		{"unlisted command", `{"seq":3,"type":"response","request_seq":2,"command":"pause","success":true}`, nil},
+		{"event missing field", `{"seq":4,"type":"event","event":"stopped","body":{"threadId":1}}`, []string{"body.reason: missing"}},
+		{"event without body", `{"seq":4,"type":"event","event":"terminated"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.message), &raw); err != nil {
+				t.Fatal(err)
+			}
+			if got := checkShape(raw); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("checkShape = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientProtocolViolations(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client := NewClient("localhost", 6006)
+	client.conn = clientConn
+	client.reader = bufio.NewReader(clientConn)
+	client.connected = true
+	go client.readLoop()
+	events, cleanup := client.SubscribeToEvents()
+	defer cleanup()
+
+	// Fake adapter: send a stopped event without a reason, then answer with
+	// a body-less stackTrace response and a well-formed threads response
+	go func() {
+		reader := bufio.NewReader(serverConn)
+		io.WriteString(serverConn, frame(`{"seq":1,"type":"event","event":"stopped","body":{"threadId":1}}`))
+		for {
+			msg, err := dap.ReadProtocolMessage(reader)
+			if err != nil {
+				return
+			}
+			req := msg.(dap.RequestMessage).GetRequest()
+			body := `"body":{"threads":[{"id":1,"name":"Main"}]}`
+			if req.Command == "stackTrace" {
+				body = `"message":"ok"`
+			}
+			io.WriteString(serverConn, frame(fmt.Sprintf(`{"seq":%d,"type":"response","request_seq":%d,"command":"%s","success":true,%s}`,
+				req.Seq+100, req.Seq, req.Command, body)))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.StackTrace(ctx, 1, 0, 1)
+	if err == nil || !strings.Contains(err.Error(), "malformed stackTrace response from the debug adapter: body: missing") {
+		t.Fatalf("expected a descriptive error instead of a timeout, got %v", err)
+	}
+	// The connection survives the malformed messages
+	if _, err := client.Threads(ctx); err != nil {
+		t.Fatalf("Threads after a malformed response failed: %v", err)
+	}
+
+	select {
+	case msg := <-events:
+		t.Errorf("the malformed event should be dropped, got %T", msg)
+	default:
+	}
+	violations := client.ProtocolViolations()
+	if len(violations) != 2 || violations[0].Name != "stopped" || violations[1].Name != "stackTrace" || violations[1].RequestSeq == 0 {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+}
+
+func TestViolationLog_Bounded(t *testing.T) {
+	var vl violationLog
+	for i := 0; i < maxViolations+5; i++ {
+		vl.add(&ProtocolViolation{RequestSeq: i})
+	}
+	entries := vl.snapshot()
+	if len(entries) != maxViolations || entries[0].RequestSeq != 5 {
+		t.Errorf("expected the newest %d violations, got %d starting at %d", maxViolations, len(entries), entries[0].RequestSeq)
+	}
+}
+
+func TestRequestTable(t *testing.T) {
+	var rt requestTable
+	answered := rt.register(1, "threads")
+	rt.register(2, "evaluate")
+	rt.register(3, "stackTrace")
+	now := time.Now()
+
+	ch, ok := rt.resolve(1)
+	if !ok || ch != answered {
+		t.Fatal("resolve should return the registered channel")
+	}
+	rt.release(1) // Already answered: not counted again
+
+	rt.release(2) // Caller timed out
+	if _, ok := rt.resolve(2); ok {
+		t.Error("a released request should not resolve")
+	}
+
+	stats := rt.snapshot(now.Add(time.Minute))
+	if stats.Sent != 3 || stats.Answered != 1 || stats.Unanswered != 1 || stats.Late != 1 || stats.Pending != 1 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+	if stats.OldestPending < time.Minute {
+		t.Errorf("expected the stackTrace request to be a minute old, got %v", stats.OldestPending)
+	}
+
+	if expired := rt.expire(time.Hour, now.Add(time.Minute)); len(expired) != 0 {
+		t.Errorf("nothing should expire yet, got %v", expired)
+	}
+	expired := rt.expire(time.Minute, now.Add(2*time.Minute))
+	if len(expired) != 1 || expired[3] == nil || expired[3].command != "stackTrace" {
+		t.Fatalf("expected the stackTrace request to expire, got %v", expired)
+	}
+
+	stats = rt.snapshot(now)
+	if stats.Abandoned != 1 || stats.Pending != 0 || stats.UnansweredByCommand["evaluate"] != 1 || stats.UnansweredByCommand["stackTrace"] != 1 {
+		t.Errorf("unexpected stats after expiry: %v", stats)
+	}
+	if got := stats.String(); !strings.Contains(got, "1 abandoned") || !strings.Contains(got, "evaluate=1, stackTrace=1") {
+		t.Errorf("unexpected summary: %s", got)
+	}
+}
+
+func TestClientJanitor_FailsAbandonedRequests(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client := NewClient("localhost", 6006, WithRequestMaxAge(time.Minute))
+	client.conn = clientConn
+	client.reader = bufio.NewReader(clientConn)
+	client.connected = true
+	go client.readLoop()
+
+	// Fake adapter that reads requests and never answers
+	go func() {
+		reader := bufio.NewReader(serverConn)
+		for {
+			if _, err := dap.ReadProtocolMessage(reader); err != nil {
+				return
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Threads(context.Background())
+		done <- err
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for client.RequestStats().Pending == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the threads request was never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	client.sweepRequests(time.Now().Add(2 * time.Minute))
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "no response to threads request") {
+			t.Errorf("expected an abandoned request error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the janitor didn't unblock the caller")
+	}
+	if stats := client.RequestStats(); stats.Abandoned != 1 || stats.Unanswered != 0 || stats.Pending != 0 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+}
+
+func TestEventListeners_Backpressure(t *testing.T) {
+	var logs bytes.Buffer
+	client := NewClient("localhost", 6006, WithLogger(log.New(&logs, "", 0)), WithTimeouts(0, 200*time.Millisecond))
+
+	lossy, cleanupLossy := client.SubscribeToEvents(WithListenerName("lossy"), WithListenerBuffer(1))
+	defer cleanupLossy()
+	blocking, cleanupBlocking := client.SubscribeToEvents(WithListenerName("stops"), WithListenerBuffer(1), WithBlockWhenFull())
+	defer cleanupBlocking()
+	if cap(lossy) != 1 || cap(blocking) != 1 {
+		t.Fatalf("expected buffers of 1, got %d and %d", cap(lossy), cap(blocking))
+	}
+
+	// The blocking listener reads its first event late; the second waits for it
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-blocking
+	}()
+	client.broadcastEvent(&dap.StoppedEvent{Event: dap.Event{Event: "stopped"}})
+	client.broadcastEvent(&dap.StoppedEvent{Event: dap.Event{Event: "stopped"}})
+
+	stats := client.EventStats()
+	if stats.Dropped != 1 || len(stats.Listeners) != 2 {
+		t.Fatalf("expected one dropped event, got %+v", stats)
+	}
+	for _, l := range stats.Listeners {
+		switch l.Name {
+		case "lossy":
+			if l.Dropped != 1 || l.Buffered != 1 || l.Blocking {
+				t.Errorf("unexpected lossy listener stats: %+v", l)
+			}
+		case "stops":
+			if l.Dropped != 0 || l.Buffered != 1 || !l.Blocking {
+				t.Errorf("the blocking listener should have waited instead of dropping: %+v", l)
+			}
+		}
+	}
+	if !strings.Contains(logs.String(), `"lossy" buffer full`) {
+		t.Errorf("expected a drop warning naming the listener, got %q", logs.String())
+	}
+
+	// A blocking listener that stops reading gives up after the command timeout
+	start := time.Now()
+	client.broadcastEvent(&dap.StoppedEvent{Event: dap.Event{Event: "stopped"}})
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the send to wait for the command timeout, took %v", elapsed)
+	}
+	if got := client.EventStats().Dropped; got != 3 {
+		t.Errorf("expected both listeners to drop the event, total %d", got)
+	}
+
+	// Cleanup removes the listener and unblocks pending sends
+	cleanupBlocking()
+	cleanupBlocking()
+	if got := client.EventStats(); len(got.Listeners) != 1 || got.Dropped != 3 {
+		t.Errorf("expected the removed listener's drops to stay counted, got %+v", got)
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	for _, name := range []string{"godot-4.2", "godot-4.3", "godot-4.4", "generic"} {
+		p, err := LookupProfile(name)
+		if err != nil || p.Name != name {
+			t.Errorf("LookupProfile(%q) = %+v, %v", name, p, err)
+		}
+	}
+	if p, err := LookupProfile(""); err != nil || p.Name != DefaultProfile {
+		t.Errorf("empty name should give %s, got %+v, %v", DefaultProfile, p, err)
+	}
+	if _, err := LookupProfile("lldb"); err == nil || !strings.Contains(err.Error(), "generic") {
+		t.Errorf("unknown profile should list the known ones, got %v", err)
+	}
+
+	godot, _ := LookupProfile("godot-4.3")
+	generic, _ := LookupProfile("generic")
+	if !godot.Quirks.LaunchResponseAfterConfigurationDone || generic.Quirks.LaunchResponseAfterConfigurationDone {
+		t.Error("only Godot profiles should work around Godot's quirks")
+	}
+	if generic.LaunchStallTimeout >= generic.CommandTimeout {
+		t.Error("generic launch stall timeout should fire before the command timeout")
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	caps := dap.Capabilities{SupportsLogPoints: true, SupportsConditionalBreakpoints: true, SupportsFunctionBreakpoints: true}
+
+	godot, _ := LookupProfile("godot-4.4")
+	client := NewClient("localhost", 6006, WithLogger(log.New(io.Discard, "", 0)), WithProfile(godot), WithTimeouts(0, time.Second))
+	if client.Profile() != "godot-4.4" || !client.quirks.LaunchResponseAfterConfigurationDone {
+		t.Errorf("profile not applied: %q, %+v", client.Profile(), client.quirks)
+	}
+	if client.commandTimeout != time.Second || client.connectTimeout != godot.ConnectTimeout {
+		t.Errorf("later options should override the profile's timeouts, got %v/%v", client.connectTimeout, client.commandTimeout)
+	}
+	godotCaps := caps
+	client.applyUnsupported(&godotCaps)
+	if godotCaps.SupportsLogPoints || godotCaps.SupportsConditionalBreakpoints || !godotCaps.SupportsFunctionBreakpoints {
+		t.Errorf("Godot profile should only drop the capabilities Godot lacks, got %+v", godotCaps)
+	}
+
+	generic, _ := LookupProfile("generic")
+	client = NewClient("localhost", 6006, WithProfile(generic))
+	genericCaps := caps
+	client.applyUnsupported(&genericCaps)
+	if !genericCaps.SupportsLogPoints || !genericCaps.SupportsConditionalBreakpoints || !genericCaps.SupportsFunctionBreakpoints {
+		t.Errorf("generic profile should trust advertised capabilities, got %+v", genericCaps)
+	}
+	if client.launchStallTimeout != generic.LaunchStallTimeout {
+		t.Errorf("expected launch stall timeout %v, got %v", generic.LaunchStallTimeout, client.launchStallTimeout)
+	}
+}
+
+func TestBreakpointRegistry(t *testing.T) {
+	var registry BreakpointRegistry
+
+	registry.Set("/p/a.gd", []int{3})
+	registry.Set("/p/b.gd", []int{7, 9})
+	registry.Set("/p/a.gd", []int{5})
+	registry.Set("/p/b.gd", nil)
+
+	files := registry.All()
+	if !reflect.DeepEqual(files, map[string][]int{"/p/a.gd": {5}}) {
+		t.Errorf("unexpected breakpoints: %v", files)
+	}
+
+	files["/p/a.gd"][0] = 99
+	if registry.All()["/p/a.gd"][0] != 5 {
+		t.Error("All() should return a copy")
+	}
+
+	// Conditions and hit conditions follow their lines
+	registry.Set("/p/c.gd", []int{10, 20})
+	registry.SetConditions("/p/c.gd", map[int]string{10: "x > 1", 20: "y > 2"})
+	registry.SetHitConditions("/p/c.gd", map[int]string{10: "5", 20: ">= 3"})
+	registry.Set("/p/c.gd", []int{20, 30})
+	if registry.Condition("/p/c.gd", 10) != "" || registry.Condition("/p/c.gd", 20) != "y > 2" {
+		t.Errorf("conditions of removed lines should be dropped, got %v", registry.AllConditions())
+	}
+	if registry.HitCondition("/p/c.gd", 10) != "" || registry.HitCondition("/p/c.gd", 20) != ">= 3" {
+		t.Errorf("hit conditions of removed lines should be dropped, got %v", registry.AllHitConditions())
+	}
+	registry.Set("/p/c.gd", nil)
+	if registry.AllConditions() != nil || registry.AllHitConditions() != nil {
+		t.Errorf("clearing a file should drop its conditions, got %v, %v", registry.AllConditions(), registry.AllHitConditions())
+	}
+
+	// Remapping moves lines with everything recorded about them
+	registry.Set("/p/d.gd", []int{10, 20, 30})
+	registry.SetConditions("/p/d.gd", map[int]string{20: "x > 1"})
+	registry.SetFunction("/p/d.gd", 10, "_ready")
+	registry.SetFunction("/p/d.gd", 30, "take_damage")
+	lines := registry.Remap("/p/d.gd", func(line int) int {
+		if line == 30 {
+			return 23 // Merged into the breakpoint moved from 20
+		}
+		return line + 3
+	})
+	if !reflect.DeepEqual(lines, []int{13, 23}) || !reflect.DeepEqual(registry.All()["/p/d.gd"], []int{13, 23}) {
+		t.Errorf("unexpected remapped lines %v", lines)
+	}
+	if registry.Condition("/p/d.gd", 23) != "x > 1" {
+		t.Errorf("condition should follow its line, got %v", registry.AllConditions())
+	}
+	if functions := registry.AllFunctions()["/p/d.gd"]; !reflect.DeepEqual(functions, map[int]string{13: "_ready"}) {
+		t.Errorf("the merged breakpoint should keep the first one's settings, got functions %v", functions)
+	}
+
+	// Requested lists each breakpoint with everything recorded about it
+	requested := registry.Requested()
+	sort.Slice(requested, func(i, j int) bool {
+		return requested[i].File < requested[j].File || requested[i].File == requested[j].File && requested[i].Line < requested[j].Line
+	})
+	want := []RequestedBreakpoint{
+		{File: "/p/a.gd", Line: 5},
+		{File: "/p/d.gd", Line: 13, Function: "_ready"},
+		{File: "/p/d.gd", Line: 23, Condition: "x > 1"},
+	}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("unexpected requested breakpoints %+v", requested)
+	}
+	if lines := registry.Lines("/p/d.gd"); !reflect.DeepEqual(lines, []int{13, 23}) {
+		t.Errorf("unexpected lines %v", lines)
+	}
+}
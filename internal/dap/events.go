@@ -3,7 +3,8 @@ package dap
 import (
 	"context"
 	"fmt"
-	"log"
+	"sync"
+	"time"
 
 	"github.com/google/go-dap"
 )
@@ -24,9 +25,9 @@ func (c *Client) initEventHandling() {
 
 // WaitForStop waits for a stopped event
 func (c *Client) WaitForStop(ctx context.Context) (*dap.StoppedEventBody, error) {
-	log.Printf("Waiting for stopped event...")
+	c.logger.Printf("Waiting for stopped event...")
 
-	events, cleanup := c.SubscribeToEvents()
+	events, cleanup := c.SubscribeToEvents(WithListenerName("WaitForStop"), WithBlockWhenFull())
 	defer cleanup()
 
 	for {
@@ -35,41 +36,246 @@ func (c *Client) WaitForStop(ctx context.Context) (*dap.StoppedEventBody, error)
 			return nil, fmt.Errorf("wait for stop timeout: %w", ctx.Err())
 		case msg := <-events:
 			if stopped, ok := msg.(*dap.StoppedEvent); ok {
-				log.Printf("Received StoppedEvent: %s", stopped.Body.Reason)
+				c.logger.Printf("Received StoppedEvent: %s", stopped.Body.Reason)
 				return &stopped.Body, nil
 			}
 		}
 	}
 }
 
+// WaitForEvent waits for the first event whose name (e.g. "stopped",
+// "terminated", "exited") is in eventTypes, and returns it.
+// Events that arrived before the call are not considered.
+func (c *Client) WaitForEvent(ctx context.Context, eventTypes []string) (dap.EventMessage, error) {
+	wanted := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		wanted[t] = true
+	}
+
+	c.logger.Printf("Waiting for event (types: %v)...", eventTypes)
+
+	events, cleanup := c.SubscribeToEvents(WithListenerName("WaitForEvent"), WithBlockWhenFull())
+	defer cleanup()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for event timeout: %w", ctx.Err())
+		case msg := <-events:
+			event, ok := msg.(dap.EventMessage)
+			if !ok {
+				continue
+			}
+			if name := event.GetEvent().Event; wanted[name] {
+				c.logger.Printf("Received awaited event: %s", name)
+				return event, nil
+			}
+		}
+	}
+}
+
 // logEvent logs a DAP event
 func (c *Client) logEvent(event interface{}) {
 	switch e := event.(type) {
 	case *dap.InitializedEvent:
-		log.Printf("[DAP Event] Initialized")
+		c.logger.Printf("[DAP Event] Initialized")
 	case *dap.StoppedEvent:
-		log.Printf("[DAP Event] Stopped: reason=%s, threadId=%d", e.Body.Reason, e.Body.ThreadId)
+		c.logger.Printf("[DAP Event] Stopped: reason=%s, threadId=%d, allThreadsStopped=%t", e.Body.Reason, e.Body.ThreadId, e.Body.AllThreadsStopped)
 	case *dap.ContinuedEvent:
-		log.Printf("[DAP Event] Continued: threadId=%d", e.Body.ThreadId)
+		c.logger.Printf("[DAP Event] Continued: threadId=%d", e.Body.ThreadId)
 	case *dap.ExitedEvent:
-		log.Printf("[DAP Event] Exited: exitCode=%d", e.Body.ExitCode)
+		c.logger.Printf("[DAP Event] Exited: exitCode=%d", e.Body.ExitCode)
 	case *dap.TerminatedEvent:
-		log.Printf("[DAP Event] Terminated")
+		c.logger.Printf("[DAP Event] Terminated")
 	case *dap.ThreadEvent:
-		log.Printf("[DAP Event] Thread: reason=%s, threadId=%d", e.Body.Reason, e.Body.ThreadId)
+		c.logger.Printf("[DAP Event] Thread: reason=%s, threadId=%d", e.Body.Reason, e.Body.ThreadId)
 	case *dap.OutputEvent:
-		log.Printf("[DAP Event] Output: category=%s, output=%s", e.Body.Category, e.Body.Output)
+		c.logger.Printf("[DAP Event] Output: category=%s, output=%s", e.Body.Category, e.Body.Output)
 	case *dap.BreakpointEvent:
-		log.Printf("[DAP Event] Breakpoint: reason=%s", e.Body.Reason)
+		c.logger.Printf("[DAP Event] Breakpoint: reason=%s, id=%d, line=%d, verified=%t", e.Body.Reason, e.Body.Breakpoint.Id, e.Body.Breakpoint.Line, e.Body.Breakpoint.Verified)
 	case *dap.ModuleEvent:
-		log.Printf("[DAP Event] Module: reason=%s", e.Body.Reason)
+		c.logger.Printf("[DAP Event] Module: reason=%s", e.Body.Reason)
 	case *dap.LoadedSourceEvent:
-		log.Printf("[DAP Event] LoadedSource: reason=%s", e.Body.Reason)
+		c.logger.Printf("[DAP Event] LoadedSource: reason=%s", e.Body.Reason)
 	case *dap.ProcessEvent:
-		log.Printf("[DAP Event] Process: name=%s", e.Body.Name)
+		c.logger.Printf("[DAP Event] Process: name=%s", e.Body.Name)
 	case *dap.CapabilitiesEvent:
-		log.Printf("[DAP Event] Capabilities")
+		c.logger.Printf("[DAP Event] Capabilities")
 	default:
-		log.Printf("[DAP Event] Unknown event type: %T", event)
+		c.logger.Printf("[DAP Event] Unknown event type: %T", event)
+	}
+}
+
+// eventListener is one SubscribeToEvents subscription
+type eventListener struct {
+	ch       chan dap.Message
+	name     string
+	blocking bool
+	done     chan struct{} // Closed when the subscription is cleaned up
+	dropped  int           // Events dropped because ch was full; guarded by Client.eventMu
+}
+
+// subscription collects the SubscribeOptions of one subscription
+type subscription struct {
+	name       string
+	bufferSize int
+	blocking   bool
+}
+
+// SubscribeOption configures one event subscription
+type SubscribeOption func(*subscription)
+
+// WithListenerName names the subscription in EventStats and drop warnings
+func WithListenerName(name string) SubscribeOption {
+	return func(s *subscription) {
+		s.name = name
+	}
+}
+
+// WithListenerBuffer sets the subscription's channel capacity instead of
+// the client's (WithEventBufferSize)
+func WithListenerBuffer(size int) SubscribeOption {
+	return func(s *subscription) {
+		if size > 0 {
+			s.bufferSize = size
+		}
+	}
+}
+
+// WithBlockWhenFull makes the client wait for room in a full channel
+// instead of dropping the event, for consumers that must see every stop
+// (e.g. ones that resume the game). The wait holds up all incoming messages,
+// so it is bounded by the command timeout; the consumer must keep reading
+// and must not wait on a response while its buffer fills.
+func WithBlockWhenFull() SubscribeOption {
+	return func(s *subscription) {
+		s.blocking = true
+	}
+}
+
+// SubscribeToEvents subscribes to all DAP events.
+// Returns a channel to receive events and a cleanup function.
+// The first subscription of a connection also receives the events that
+// arrived before it (up to maxEarlyEvents).
+// Events are dropped for a subscriber whose buffer is full unless it
+// subscribed WithBlockWhenFull.
+func (c *Client) SubscribeToEvents(opts ...SubscribeOption) (<-chan dap.Message, func()) {
+	sub := subscription{bufferSize: c.eventBufferSize}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+	listener := &eventListener{
+		ch:       make(chan dap.Message, sub.bufferSize), // Buffer to prevent blocking
+		name:     sub.name,
+		blocking: sub.blocking,
+		done:     make(chan struct{}),
+	}
+	c.eventMu.Lock()
+	c.eventListeners = append(c.eventListeners, listener)
+	c.replayEarlyEventsLocked(listener)
+	c.eventMu.Unlock()
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			c.eventMu.Lock()
+			defer c.eventMu.Unlock()
+			close(listener.done)
+			for i, l := range c.eventListeners {
+				if l == listener {
+					// Remove (swap with last and shrink)
+					c.eventListeners[i] = c.eventListeners[len(c.eventListeners)-1]
+					c.eventListeners = c.eventListeners[:len(c.eventListeners)-1]
+					// Don't close the channel, just stop sending. Caller might still be reading.
+					break
+				}
+			}
+		})
+	}
+	return listener.ch, cleanup
+}
+
+// broadcastEvent sends an event to all listeners. The listeners are copied
+// first so a blocking send doesn't hold eventMu, which cleanup needs.
+func (c *Client) broadcastEvent(event dap.Message) {
+	c.eventMu.Lock()
+	if len(c.eventListeners) == 0 && c.holdEarlyEventsLocked(event) {
+		c.eventMu.Unlock()
+		return
+	}
+	listeners := append([]*eventListener(nil), c.eventListeners...)
+	c.eventMu.Unlock()
+
+	for _, l := range listeners {
+		select {
+		case l.ch <- event:
+			continue
+		case <-l.done:
+			continue
+		default:
+		}
+		if l.blocking && c.sendBlocking(l, event) {
+			continue
+		}
+		c.eventMu.Lock()
+		l.dropped++
+		c.droppedEvents++
+		c.eventMu.Unlock()
+		c.logger.Printf("Warning: Event listener %s buffer full, dropping event %T", listenerLabel(l.name), event)
+	}
+}
+
+// sendBlocking waits for room in a blocking listener's channel. Returns
+// false if the command timeout passed first.
+func (c *Client) sendBlocking(l *eventListener, event dap.Message) bool {
+	timer := time.NewTimer(c.commandTimeout)
+	defer timer.Stop()
+	select {
+	case l.ch <- event:
+		return true
+	case <-l.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// listenerLabel names a listener in log messages
+func listenerLabel(name string) string {
+	if name == "" {
+		return "(unnamed)"
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// ListenerStats describes one event subscription
+type ListenerStats struct {
+	Name     string // From WithListenerName; empty if unnamed
+	Buffered int    // Events waiting to be read
+	Capacity int    // Channel capacity
+	Dropped  int    // Events dropped because the channel was full
+	Blocking bool   // Subscribed WithBlockWhenFull
+}
+
+// EventStats describes event delivery to subscribers
+type EventStats struct {
+	Dropped   int // Events dropped for any subscriber, including removed ones
+	Listeners []ListenerStats
+}
+
+// EventStats returns the current subscriptions and their dropped events
+func (c *Client) EventStats() EventStats {
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	stats := EventStats{Dropped: c.droppedEvents, Listeners: make([]ListenerStats, len(c.eventListeners))}
+	for i, l := range c.eventListeners {
+		stats.Listeners[i] = ListenerStats{
+			Name:     l.name,
+			Buffered: len(l.ch),
+			Capacity: cap(l.ch),
+			Dropped:  l.dropped,
+			Blocking: l.blocking,
+		}
 	}
+	return stats
 }
@@ -0,0 +1,103 @@
+package dap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// Termination reasons reported in ExitStatus
+const (
+	ExitReasonExited         = "exited"          // Game process exited (exit code known)
+	ExitReasonTerminated     = "terminated"      // Debug session ended without an exit code
+	ExitReasonConnectionLost = "connection_lost" // DAP connection dropped unexpectedly
+)
+
+// ExitStatus describes how the last debugged run ended
+type ExitStatus struct {
+	Reason     string    // One of the ExitReason* constants
+	Exited     bool      // An exited event was received
+	ExitCode   int       // Process exit code (only meaningful if Exited)
+	Terminated bool      // A terminated event was received
+	Restart    bool      // The terminated event asked for a restart
+	Time       time.Time // When the run ended
+}
+
+// exitTracker records exited/terminated events for the current run
+type exitTracker struct {
+	mu     sync.Mutex
+	status *ExitStatus
+}
+
+// applyEvent updates the exit status from exited and terminated events.
+// Godot sends exited (with the code) before terminated; either order is handled.
+func (et *exitTracker) applyEvent(msg dap.Message) {
+	switch e := msg.(type) {
+	case *dap.ExitedEvent:
+		et.mu.Lock()
+		defer et.mu.Unlock()
+		st := et.currentLocked()
+		st.Exited = true
+		st.ExitCode = e.Body.ExitCode
+		st.Reason = ExitReasonExited
+	case *dap.TerminatedEvent:
+		et.mu.Lock()
+		defer et.mu.Unlock()
+		st := et.currentLocked()
+		st.Terminated = true
+		st.Restart = e.Body.Restart != nil
+		if !st.Exited {
+			st.Reason = ExitReasonTerminated
+		}
+	}
+}
+
+// currentLocked returns the status being built, creating it on the first
+// termination event of a run. Caller must hold et.mu.
+func (et *exitTracker) currentLocked() *ExitStatus {
+	if et.status == nil {
+		et.status = &ExitStatus{Time: time.Now()}
+	}
+	return et.status
+}
+
+// connectionLost records that the connection dropped, unless the run had
+// already ended normally
+func (et *exitTracker) connectionLost() {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+	if et.status == nil {
+		et.status = &ExitStatus{Reason: ExitReasonConnectionLost, Time: time.Now()}
+	}
+}
+
+// reset clears the exit status at the start of a new run
+func (et *exitTracker) reset() {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+	et.status = nil
+}
+
+// get returns a copy of the exit status, or nil if the run hasn't ended
+func (et *exitTracker) get() *ExitStatus {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+	if et.status == nil {
+		return nil
+	}
+	st := *et.status
+	return &st
+}
+
+// ExitStatus returns how the last run ended, or nil if it is still running
+// (or no run has been started)
+func (c *Client) ExitStatus() *ExitStatus {
+	return c.exit.get()
+}
+
+// GetExitStatus returns how the session's last run ended, or nil if the game
+// hasn't exited or terminated
+func (s *Session) GetExitStatus() *ExitStatus {
+	return s.client.ExitStatus()
+}
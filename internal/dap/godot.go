@@ -32,39 +32,50 @@ const (
 	PlatformWeb     Platform = "web"
 )
 
-// GodotLaunchConfig contains configuration for launching a Godot scene
+// GodotLaunchConfig contains configuration for launching a Godot scene.
+// The JSON form is used by session snapshots.
 type GodotLaunchConfig struct {
 	// Project is the absolute path to the Godot project directory
 	// Must contain a project.godot file
-	Project string
+	Project string `json:"project"`
 
 	// Scene determines which scene to launch
-	Scene SceneLaunchMode
+	Scene SceneLaunchMode `json:"scene"`
 
 	// ScenePath is the path to the scene file (e.g., "res://scenes/level1.tscn")
 	// Only used when Scene is SceneLaunchCustom
-	ScenePath string
+	ScenePath string `json:"scene_path,omitempty"`
 
 	// Platform is the target platform (default: host)
-	Platform Platform
+	Platform Platform `json:"platform,omitempty"`
 
 	// NoDebug disables debugging features
-	NoDebug bool
+	NoDebug bool `json:"no_debug,omitempty"`
 
 	// Profiling enables profiling
-	Profiling bool
+	Profiling bool `json:"profiling,omitempty"`
 
 	// DebugCollisions shows collision shapes
-	DebugCollisions bool
+	DebugCollisions bool `json:"debug_collisions,omitempty"`
 
 	// DebugPaths shows navigation paths
-	DebugPaths bool
+	DebugPaths bool `json:"debug_paths,omitempty"`
 
 	// DebugNavigation shows navigation debug
-	DebugNavigation bool
+	DebugNavigation bool `json:"debug_navigation,omitempty"`
 
 	// AdditionalOptions contains additional command-line options
-	AdditionalOptions string
+	AdditionalOptions string `json:"additional_options,omitempty"`
+
+	// StopOnEntry asks the adapter to pause before any game code runs.
+	// Adapters that don't support it ignore the flag.
+	StopOnEntry bool `json:"stop_on_entry,omitempty"`
+
+	// WaitForBreakpoints holds configurationDone, which starts the game,
+	// until every setBreakpoints request sent so far has been answered.
+	// Breakpoints in autoload scripts need this when they are set
+	// concurrently with the launch. Not sent to the adapter.
+	WaitForBreakpoints bool `json:"wait_for_breakpoints,omitempty"`
 }
 
 // Validate checks if the launch configuration is valid
@@ -123,6 +134,50 @@ func (c *GodotLaunchConfig) ToLaunchArgs() map[string]interface{} {
 		args["additional_options"] = c.AdditionalOptions
 	}
 
+	if c.StopOnEntry {
+		args["stopOnEntry"] = true
+	}
+
+	return args
+}
+
+// AttachConfig contains configuration for attaching to a running Godot game
+type AttachConfig struct {
+	// Address is the host the game's remote debugger runs on (default: 127.0.0.1 when Port is set)
+	Address string
+
+	// Port is the game's remote debug port (0 = adapter's choice, usually 6007)
+	Port int
+
+	// Args contains additional adapter-specific attach arguments.
+	// Keys set here override Address and Port.
+	Args map[string]interface{}
+}
+
+// Validate checks if the attach configuration is valid
+func (c *AttachConfig) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("port must be between 0 and 65535, 0 for the adapter default (got %d)", c.Port)
+	}
+	return nil
+}
+
+// ToAttachArgs converts the config to DAP attach request arguments.
+// Godot's attach currently takes no arguments and ignores them.
+func (c *AttachConfig) ToAttachArgs() map[string]interface{} {
+	args := map[string]interface{}{}
+	if c.Address != "" {
+		args["address"] = c.Address
+	}
+	if c.Port != 0 {
+		args["port"] = c.Port
+		if c.Address == "" {
+			args["address"] = "127.0.0.1"
+		}
+	}
+	for k, v := range c.Args {
+		args[k] = v
+	}
 	return args
 }
 
@@ -132,10 +187,29 @@ func (s *Session) LaunchGodotScene(ctx context.Context, config *GodotLaunchConfi
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid launch configuration: %w", err)
 	}
+	if state := s.GetState(); !state.CanTransitionTo(StateLaunched) {
+		return nil, fmt.Errorf("cannot launch: session is in state %s (must be initialized)", state)
+	}
+
+	// A new run starts; forget how the previous one ended
+	s.client.exit.reset()
+	s.client.process.reset()
 
 	// Launch with the converted arguments using the Godot-specific sequence
 	// (Launch -> ConfigurationDone -> Wait for ConfigDone -> Wait for Launch)
-	return s.client.LaunchWithConfigurationDone(ctx, config.ToLaunchArgs())
+	launch := s.client.LaunchWithConfigurationDone
+	if config.WaitForBreakpoints {
+		launch = s.client.LaunchAfterBreakpoints
+	}
+	resp, err := launch(ctx, config.ToLaunchArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(StateLaunched); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 // LaunchMainScene is a convenience method to launch the project's main scene
@@ -171,12 +245,31 @@ func (s *Session) LaunchCustomScene(ctx context.Context, projectPath string, sce
 
 // AttachGodot attaches the debugger to an already running Godot game instance.
 // The game must have been started with --remote-debug connecting to the editor.
-func (s *Session) AttachGodot(ctx context.Context) (*dap.AttachResponse, error) {
-	// Attach takes no arguments in Godot implementation
-	args := map[string]interface{}{}
+// A nil config attaches with the adapter's defaults.
+func (s *Session) AttachGodot(ctx context.Context, config *AttachConfig) (*dap.AttachResponse, error) {
+	if config == nil {
+		config = &AttachConfig{}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid attach configuration: %w", err)
+	}
+	if state := s.GetState(); !state.CanTransitionTo(StateAttached) {
+		return nil, fmt.Errorf("cannot attach: session is in state %s (must be initialized)", state)
+	}
+
+	s.client.exit.reset()
+	s.client.process.reset()
 
 	// Attach with the Godot-specific sequence (Attach -> ConfigurationDone)
-	return s.client.AttachWithConfigurationDone(ctx, args)
+	resp, err := s.client.AttachWithConfigurationDone(ctx, config.ToAttachArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(StateAttached); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 // Additional Godot-specific DAP commands can be added here as needed
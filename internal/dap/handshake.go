@@ -0,0 +1,85 @@
+package dap
+
+import (
+	"sync"
+
+	"github.com/google/go-dap"
+)
+
+// maxEarlyEvents caps the events held for the first subscriber
+const maxEarlyEvents = 64
+
+// handshake watches for the initialized event from Connect on. Initialize
+// can't rely on an event subscription alone: the adapter may send the event
+// before the initialize response or before Initialize is called at all, and
+// a burst of output events can fill the subscription's buffer first.
+type handshake struct {
+	mu          sync.Mutex
+	initialized chan struct{} // Closed when the initialized event arrives
+	seen        bool
+}
+
+// reset starts watching a new connection
+func (h *handshake) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.initialized = make(chan struct{})
+	h.seen = false
+}
+
+// applyEvent records the initialized event
+func (h *handshake) applyEvent(msg dap.Message) {
+	if _, ok := msg.(*dap.InitializedEvent); !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.initialized == nil {
+		h.initialized = make(chan struct{})
+	}
+	if !h.seen {
+		h.seen = true
+		close(h.initialized)
+	}
+}
+
+// done returns a channel that is closed once the connection's initialized
+// event has arrived, including if it already has
+func (h *handshake) done() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.initialized == nil {
+		h.initialized = make(chan struct{})
+	}
+	return h.initialized
+}
+
+// holdEarlyEventsLocked keeps an event that arrived before anyone subscribed
+// for the first subscriber. Returns false once a subscriber has taken them.
+// Caller must hold c.eventMu.
+func (c *Client) holdEarlyEventsLocked(event dap.Message) bool {
+	if !c.holdEarly {
+		return false
+	}
+	if len(c.earlyEvents) < maxEarlyEvents {
+		c.earlyEvents = append(c.earlyEvents, event)
+	} else {
+		c.droppedEvents++
+	}
+	return true
+}
+
+// replayEarlyEventsLocked hands the held events to the first subscriber and
+// stops holding events. Caller must hold c.eventMu.
+func (c *Client) replayEarlyEventsLocked(l *eventListener) {
+	for _, event := range c.earlyEvents {
+		select {
+		case l.ch <- event:
+		default:
+			l.dropped++
+			c.droppedEvents++
+		}
+	}
+	c.earlyEvents = nil
+	c.holdEarly = false
+}
@@ -0,0 +1,118 @@
+package dap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// maxEventHistory bounds the number of events kept for polling
+const maxEventHistory = 500
+
+// EventRecord is one event received from the adapter
+type EventRecord struct {
+	Seq     int              // Monotonic sequence number, starting at 1; not the adapter's seq
+	Event   string           // Event type ("stopped", "output", ...)
+	Time    time.Time        // When the event was received
+	Message dap.EventMessage // The event as received
+}
+
+// EventHistory is the part of the event history a poll asked for
+type EventHistory struct {
+	Events  []EventRecord // Matching events after the requested sequence number, oldest first
+	LastSeq int           // Sequence number of the newest event received, matching or not
+	Missed  int           // Events after the requested sequence number already evicted
+}
+
+// eventHistory keeps the most recent events in a ring buffer so tools can
+// poll for what happened since their last call instead of subscribing
+type eventHistory struct {
+	mu      sync.Mutex
+	ring    []EventRecord
+	lastSeq int
+}
+
+// applyEvent records an event, overwriting the oldest once the ring is full
+func (eh *eventHistory) applyEvent(msg dap.Message) {
+	e, ok := msg.(dap.EventMessage)
+	if !ok {
+		return
+	}
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+
+	eh.lastSeq++
+	record := EventRecord{Seq: eh.lastSeq, Event: e.GetEvent().Event, Time: time.Now(), Message: e}
+	if len(eh.ring) < maxEventHistory {
+		eh.ring = append(eh.ring, record)
+		return
+	}
+	eh.ring[(eh.lastSeq-1)%maxEventHistory] = record
+}
+
+// since returns the events after sequence number seq whose type is in
+// types (all events if types is empty), oldest first
+func (eh *eventHistory) since(seq int, types []string) EventHistory {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+
+	history := EventHistory{LastSeq: eh.lastSeq}
+	oldest := eh.lastSeq - len(eh.ring) + 1
+	if seq+1 < oldest {
+		history.Missed = oldest - seq - 1
+	}
+	// The oldest record sits at the write position once the ring has wrapped
+	start := 0
+	if len(eh.ring) == maxEventHistory {
+		start = eh.lastSeq % maxEventHistory
+	}
+	for i := range eh.ring {
+		r := eh.ring[(start+i)%len(eh.ring)]
+		if r.Seq <= seq || (len(wanted) > 0 && !wanted[r.Event]) {
+			continue
+		}
+		history.Events = append(history.Events, r)
+	}
+	return history
+}
+
+// Events returns the recent events (up to 500) received after sequence
+// number since, filtered by type. Pass the returned LastSeq as since on the
+// next call to see only what happened in between.
+func (c *Client) Events(since int, types []string) EventHistory {
+	return c.history.since(since, types)
+}
+
+// seqOf returns the sequence number of event, or 0 if it isn't in the ring
+func (eh *eventHistory) seqOf(event dap.Message) int {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	for _, r := range eh.ring {
+		if dap.Message(r.Message) == event {
+			return r.Seq
+		}
+	}
+	return 0
+}
+
+// EventSeq returns the history sequence number of an event delivered to
+// subscribers (the same message, not an equal one), or 0 if it is no longer
+// among the recent events
+func (c *Client) EventSeq(event dap.Message) int {
+	return c.history.seqOf(event)
+}
+
+// LastEventSeq returns the sequence number of the newest event received.
+// Every event with a higher number is delivered to the subscriptions that
+// exist when it is called.
+func (c *Client) LastEventSeq() int {
+	c.history.mu.Lock()
+	defer c.history.mu.Unlock()
+	return c.history.lastSeq
+}
@@ -0,0 +1,165 @@
+package dap
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap/quirks"
+)
+
+// defaultEventBufferSize is the per-subscriber event channel capacity.
+// Events are dropped for a subscriber whose buffer is full.
+const defaultEventBufferSize = 100
+
+// defaultKeepAlive is the TCP keepalive period. Sessions sit idle for long
+// stretches while waiting for a breakpoint, and NAT devices or firewalls
+// silently drop idle connections without keepalive probes.
+const defaultKeepAlive = 30 * time.Second
+
+// defaultReadBufferSize is the size of the buffered reader on the connection
+const defaultReadBufferSize = 4096
+
+// Dialer opens the connection to the DAP server. *net.Dialer satisfies it;
+// custom implementations can tunnel the connection (e.g. over SSH).
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// ClientOption configures a Client at construction time
+type ClientOption func(*Client)
+
+// WithLogger sends the client's protocol and event logging to logger
+// instead of the standard logger
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithTimeouts overrides the connect and command timeouts.
+// A zero duration keeps the corresponding default.
+func WithTimeouts(connect, command time.Duration) ClientOption {
+	return func(c *Client) {
+		if connect > 0 {
+			c.connectTimeout = connect
+		}
+		if command > 0 {
+			c.commandTimeout = command
+		}
+	}
+}
+
+// WithDialer replaces the TCP dialer used by Connect
+func WithDialer(dialer Dialer) ClientOption {
+	return func(c *Client) {
+		if dialer != nil {
+			c.dialer = dialer
+		}
+	}
+}
+
+// WithEventBufferSize sets the channel capacity for each event subscriber
+func WithEventBufferSize(size int) ClientOption {
+	return func(c *Client) {
+		if size > 0 {
+			c.eventBufferSize = size
+		}
+	}
+}
+
+// WithKeepAlive sets the TCP keepalive period. A negative period disables
+// keepalive probes; zero keeps the default.
+func WithKeepAlive(period time.Duration) ClientOption {
+	return func(c *Client) {
+		if period != 0 {
+			c.keepAlive = period
+		}
+	}
+}
+
+// WithNoDelay controls TCP_NODELAY (enabled by default). DAP messages are
+// small request/response pairs, so Nagle's algorithm only adds latency.
+func WithNoDelay(noDelay bool) ClientOption {
+	return func(c *Client) {
+		c.noDelay = noDelay
+	}
+}
+
+// WithReadBufferSize sets the size of the buffered reader on the connection.
+// A larger buffer helps with output-heavy games.
+func WithReadBufferSize(size int) ClientOption {
+	return func(c *Client) {
+		if size > 0 {
+			c.readBufferSize = size
+		}
+	}
+}
+
+// WithRequestMaxAge sets how long a request may go unanswered before the
+// janitor abandons it and fails it. Zero keeps the default; a negative age
+// disables the janitor.
+func WithRequestMaxAge(age time.Duration) ClientOption {
+	return func(c *Client) {
+		if age != 0 {
+			c.requestMaxAge = age
+		}
+	}
+}
+
+// WithLaunchStallTimeout sets how long a launch may go without any response
+// from the adapter before the watchdog probes it and fails the launch with a
+// *LaunchStallError. Zero keeps the default; a negative timeout disables the
+// watchdog.
+func WithLaunchStallTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeout != 0 {
+			c.launchStallTimeout = timeout
+		}
+	}
+}
+
+// WithQuirks enables workarounds for an adapter's protocol deviations.
+// Without it the client speaks plain DAP; NewSession enables the quirks of
+// current Godot releases through DefaultProfile.
+func WithQuirks(set quirks.Set) ClientOption {
+	return func(c *Client) {
+		c.quirks = set
+	}
+}
+
+// tuneConnection applies keepalive and no-delay settings to TCP connections.
+// Other connections (unix sockets, pipes, ssh tunnels) are left alone.
+func (c *Client) tuneConnection(conn net.Conn) {
+	tcp, ok := conn.(*net.TCPConn)
+	if !ok {
+		return
+	}
+
+	if c.keepAlive > 0 {
+		if err := tcp.SetKeepAlive(true); err != nil {
+			c.logger.Printf("Warning: failed to enable TCP keepalive: %v", err)
+		} else if err := tcp.SetKeepAlivePeriod(c.keepAlive); err != nil {
+			c.logger.Printf("Warning: failed to set TCP keepalive period: %v", err)
+		}
+	} else if err := tcp.SetKeepAlive(false); err != nil {
+		c.logger.Printf("Warning: failed to disable TCP keepalive: %v", err)
+	}
+
+	if err := tcp.SetNoDelay(c.noDelay); err != nil {
+		c.logger.Printf("Warning: failed to set TCP no-delay: %v", err)
+	}
+}
+
+// withConnectTimeout derives a context bounded by the client's connect timeout
+func (c *Client) withConnectTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	return WithTimeout(parent, c.connectTimeout)
+}
+
+// withCommandTimeout derives a context bounded by the client's command timeout
+func (c *Client) withCommandTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	return WithTimeout(parent, c.commandTimeout)
+}
@@ -0,0 +1,71 @@
+package dap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// maxOutputHistory bounds the number of output events kept for inspection
+const maxOutputHistory = 1000
+
+// OutputRecord is a single output event received from the adapter.
+// Output is stored exactly as Godot sent it; markup is stripped when rendered.
+type OutputRecord struct {
+	Seq      int       // Monotonic sequence number, starting at 1
+	Category string    // "stdout", "stderr", "console", ...
+	Output   string    // Raw output text (may contain BBCode or ANSI sequences)
+	Time     time.Time // When the event was received
+}
+
+// outputBuffer keeps the most recent output events from the debugged game
+type outputBuffer struct {
+	mu      sync.Mutex
+	records []OutputRecord
+	lastSeq int
+}
+
+// applyEvent records output events; other events are ignored
+func (ob *outputBuffer) applyEvent(msg dap.Message) {
+	e, ok := msg.(*dap.OutputEvent)
+	if !ok {
+		return
+	}
+	ob.add(e.Body.Category, e.Body.Output)
+}
+
+// add appends an output record, dropping the oldest beyond the history limit
+func (ob *outputBuffer) add(category, output string) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.lastSeq++
+	ob.records = append(ob.records, OutputRecord{
+		Seq:      ob.lastSeq,
+		Category: category,
+		Output:   output,
+		Time:     time.Now(),
+	})
+	if len(ob.records) > maxOutputHistory {
+		ob.records = ob.records[len(ob.records)-maxOutputHistory:]
+	}
+}
+
+// snapshot returns a copy of the buffered output, oldest first
+func (ob *outputBuffer) snapshot() []OutputRecord {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	return append([]OutputRecord(nil), ob.records...)
+}
+
+// Output returns the most recent output events from the game, oldest first
+func (c *Client) Output() []OutputRecord {
+	return c.output.snapshot()
+}
+
+// RecordOutput adds output produced on the adapter's behalf, such as
+// logpoint messages the adapter doesn't print itself, to the game's output
+func (c *Client) RecordOutput(category, output string) {
+	c.output.add(category, output)
+}
@@ -0,0 +1,229 @@
+package dap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// defaultRequestMaxAge is how long a request may wait for its response
+// before the janitor abandons it. Callers normally give up much sooner
+// (DefaultCommandTimeout); only requests sent without a deadline get this old.
+const defaultRequestMaxAge = 10 * time.Minute
+
+// janitorInterval is how often the janitor looks for abandoned requests
+const janitorInterval = 30 * time.Second
+
+// pendingRequest is a request waiting for its response
+type pendingRequest struct {
+	ch      chan dap.Message
+	command string
+	sent    time.Time
+}
+
+// RequestStats counts requests by outcome, so an adapter that stops
+// answering shows up as a growing number of unanswered requests
+type RequestStats struct {
+	Sent          int           // Requests that expected a response
+	Answered      int           // Responses delivered to the waiting caller
+	Unanswered    int           // Requests the caller gave up on (timeout, cancel, write failure)
+	Abandoned     int           // Requests the janitor aged out
+	Late          int           // Responses that arrived after their request was given up
+	Pending       int           // Requests still waiting
+	OldestPending time.Duration // Age of the oldest waiting request
+
+	// Unanswered and abandoned requests per command
+	UnansweredByCommand map[string]int
+}
+
+// requestTable tracks the requests waiting for a response by seq
+type requestTable struct {
+	mu         sync.Mutex
+	pending    map[int]*pendingRequest
+	stats      RequestStats
+	lastAnswer time.Time // When the latest response was delivered
+}
+
+// register records that a request for command is waiting for its response
+// and returns the channel the response will be delivered on. Every register
+// must be followed by a release once the caller stops waiting.
+func (rt *requestTable) register(seq int, command string) chan dap.Message {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.pending == nil {
+		rt.pending = make(map[int]*pendingRequest)
+	}
+	ch := make(chan dap.Message, 1)
+	rt.pending[seq] = &pendingRequest{ch: ch, command: command, sent: time.Now()}
+	rt.stats.Sent++
+	return ch
+}
+
+// resolve removes a request that was answered and returns its channel;
+// false means nobody is waiting for the response anymore
+func (rt *requestTable) resolve(seq int) (chan dap.Message, bool) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	req, ok := rt.pending[seq]
+	if !ok {
+		rt.stats.Late++
+		return nil, false
+	}
+	delete(rt.pending, seq)
+	rt.stats.Answered++
+	rt.lastAnswer = time.Now()
+	return req.ch, true
+}
+
+// lastAnswered returns when the latest response was delivered, or the zero
+// time if none was
+func (rt *requestTable) lastAnswered() time.Time {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.lastAnswer
+}
+
+// pendingCommands returns the commands of the waiting requests, oldest first
+func (rt *requestTable) pendingCommands() []string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	reqs := make([]*pendingRequest, 0, len(rt.pending))
+	for _, req := range rt.pending {
+		reqs = append(reqs, req)
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].sent.Before(reqs[j].sent) })
+	commands := make([]string, len(reqs))
+	for i, req := range reqs {
+		commands[i] = req.command
+	}
+	return commands
+}
+
+// release removes a request its caller stopped waiting for. A request that
+// is still pending at this point went unanswered.
+func (rt *requestTable) release(seq int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	req, ok := rt.pending[seq]
+	if !ok {
+		return
+	}
+	delete(rt.pending, seq)
+	rt.stats.Unanswered++
+	rt.countUnansweredLocked(req.command)
+}
+
+// expire removes the requests older than maxAge and returns them
+func (rt *requestTable) expire(maxAge time.Duration, now time.Time) map[int]*pendingRequest {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	var expired map[int]*pendingRequest
+	for seq, req := range rt.pending {
+		if now.Sub(req.sent) <= maxAge {
+			continue
+		}
+		if expired == nil {
+			expired = make(map[int]*pendingRequest)
+		}
+		expired[seq] = req
+		delete(rt.pending, seq)
+		rt.stats.Abandoned++
+		rt.countUnansweredLocked(req.command)
+	}
+	return expired
+}
+
+// countUnansweredLocked counts an unanswered request. Caller must hold rt.mu.
+func (rt *requestTable) countUnansweredLocked(command string) {
+	if rt.stats.UnansweredByCommand == nil {
+		rt.stats.UnansweredByCommand = make(map[string]int)
+	}
+	rt.stats.UnansweredByCommand[command]++
+}
+
+// snapshot returns a copy of the statistics as of now
+func (rt *requestTable) snapshot(now time.Time) RequestStats {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	stats := rt.stats
+	stats.Pending = len(rt.pending)
+	for _, req := range rt.pending {
+		if age := now.Sub(req.sent); age > stats.OldestPending {
+			stats.OldestPending = age
+		}
+	}
+	if rt.stats.UnansweredByCommand != nil {
+		stats.UnansweredByCommand = make(map[string]int, len(rt.stats.UnansweredByCommand))
+		for command, n := range rt.stats.UnansweredByCommand {
+			stats.UnansweredByCommand[command] = n
+		}
+	}
+	return stats
+}
+
+// String summarizes the statistics for logs
+func (s RequestStats) String() string {
+	summary := fmt.Sprintf("%d sent, %d answered, %d unanswered, %d abandoned, %d late, %d pending",
+		s.Sent, s.Answered, s.Unanswered, s.Abandoned, s.Late, s.Pending)
+	if len(s.UnansweredByCommand) == 0 {
+		return summary
+	}
+	commands := make([]string, 0, len(s.UnansweredByCommand))
+	for command, n := range s.UnansweredByCommand {
+		commands = append(commands, fmt.Sprintf("%s=%d", command, n))
+	}
+	sort.Strings(commands)
+	return summary + " (unanswered by command: " + strings.Join(commands, ", ") + ")"
+}
+
+// sweepRequests abandons the requests unanswered for longer than the
+// client's maximum age, failing them so their callers stop waiting
+func (c *Client) sweepRequests(now time.Time) {
+	expired := c.requests.expire(c.requestMaxAge, now)
+	if len(expired) == 0 {
+		return
+	}
+	for seq, req := range expired {
+		message := fmt.Sprintf("no response to %s request (seq %d) after %v; the debug adapter may be deadlocked", req.command, seq, now.Sub(req.sent).Round(time.Second))
+		c.logger.Printf("Abandoning request: %s", message)
+		select {
+		case req.ch <- &dap.ErrorResponse{Response: dap.Response{Command: req.command, RequestSeq: seq, Message: message}}:
+		default:
+		}
+	}
+	c.logger.Printf("Request statistics: %v", c.requests.snapshot(now))
+}
+
+// runJanitor sweeps abandoned requests until stop is closed
+func (c *Client) runJanitor(stop <-chan struct{}) {
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c.sweepRequests(now)
+		}
+	}
+}
+
+// stopJanitor stops the janitor started by Connect, if it is running
+func (c *Client) stopJanitor() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.janitorStop != nil {
+		close(c.janitorStop)
+		c.janitorStop = nil
+	}
+}
+
+// RequestStats returns counts of requests by outcome and the requests still
+// waiting for a response
+func (c *Client) RequestStats() RequestStats {
+	return c.requests.snapshot(time.Now())
+}
@@ -0,0 +1,111 @@
+package dap
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// ProcessInfo describes the debuggee process, from the adapter's process event
+type ProcessInfo struct {
+	Name           string    // Process name (Godot reports the game's executable or project name)
+	PID            int       // OS process ID, 0 if the adapter didn't report one
+	IsLocalProcess bool      // The process runs on the adapter's machine
+	StartMethod    string    // "launch", "attach", or "attachForSuspendedLaunch"
+	PointerSize    int       // Pointer size in bits, 0 if unknown
+	Time           time.Time // When the process event arrived
+}
+
+// ModuleInfo describes a module (library, GDExtension, ...) loaded by the debuggee
+type ModuleInfo struct {
+	ID      string // Module ID, as a string whether the adapter sent a number or a string
+	Name    string
+	Path    string
+	Version string
+}
+
+// processTracker records process and module events for the current run
+type processTracker struct {
+	mu      sync.Mutex
+	process *ProcessInfo
+	modules []ModuleInfo
+}
+
+// applyEvent updates the process info and module list
+func (pt *processTracker) applyEvent(msg dap.Message) {
+	switch e := msg.(type) {
+	case *dap.ProcessEvent:
+		pt.mu.Lock()
+		defer pt.mu.Unlock()
+		pt.process = &ProcessInfo{
+			Name:           e.Body.Name,
+			PID:            e.Body.SystemProcessId,
+			IsLocalProcess: e.Body.IsLocalProcess,
+			StartMethod:    e.Body.StartMethod,
+			PointerSize:    e.Body.PointerSize,
+			Time:           time.Now(),
+		}
+	case *dap.ModuleEvent:
+		pt.mu.Lock()
+		defer pt.mu.Unlock()
+		module := ModuleInfo{
+			ID:      fmt.Sprint(e.Body.Module.Id),
+			Name:    e.Body.Module.Name,
+			Path:    e.Body.Module.Path,
+			Version: e.Body.Module.Version,
+		}
+		idx := -1
+		for i, m := range pt.modules {
+			if m.ID == module.ID {
+				idx = i
+				break
+			}
+		}
+		switch {
+		case e.Body.Reason == "removed":
+			if idx >= 0 {
+				pt.modules = append(pt.modules[:idx], pt.modules[idx+1:]...)
+			}
+		case idx >= 0:
+			pt.modules[idx] = module
+		default:
+			pt.modules = append(pt.modules, module)
+		}
+	}
+}
+
+// reset clears process info and modules at the start of a new run
+func (pt *processTracker) reset() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.process = nil
+	pt.modules = nil
+}
+
+// get returns copies of the process info (nil if no process event arrived)
+// and the loaded modules
+func (pt *processTracker) get() (*ProcessInfo, []ModuleInfo) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	var process *ProcessInfo
+	if pt.process != nil {
+		p := *pt.process
+		process = &p
+	}
+	return process, append([]ModuleInfo(nil), pt.modules...)
+}
+
+// ProcessInfo returns the debuggee process reported by the adapter, or nil
+// if no process event has been received for the current run
+func (c *Client) ProcessInfo() *ProcessInfo {
+	process, _ := c.process.get()
+	return process
+}
+
+// Modules returns the modules reported by module events for the current run
+func (c *Client) Modules() []ModuleInfo {
+	_, modules := c.process.get()
+	return modules
+}
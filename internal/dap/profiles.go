@@ -0,0 +1,156 @@
+package dap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap/quirks"
+	"github.com/google/go-dap"
+)
+
+// DefaultProfile is the profile of the newest supported Godot release
+const DefaultProfile = "godot-4.4"
+
+// Profile bundles what the client assumes about one debug adapter: the
+// protocol deviations to work around, capabilities not to trust, and
+// timeouts suited to how fast it answers. Profiles other than Godot's let
+// the client run against other adapters for comparison testing.
+type Profile struct {
+	Name        string
+	Description string
+	Quirks      quirks.Set
+
+	// Capabilities treated as unsupported even when the initialize response
+	// advertises them, by their DAP name (e.g. "supportsLogPoints")
+	Unsupported []string
+
+	ConnectTimeout     time.Duration
+	CommandTimeout     time.Duration
+	LaunchStallTimeout time.Duration
+}
+
+// godotUnsupported are the capabilities Godot's DAP server doesn't
+// implement: it ignores a breakpoint's condition, hitCondition, and
+// logMessage, which the tools then emulate
+var godotUnsupported = []string{
+	"supportsConditionalBreakpoints",
+	"supportsHitConditionalBreakpoints",
+	"supportsLogPoints",
+}
+
+// godotProfile is the profile of a Godot release
+func godotProfile(version string) Profile {
+	return Profile{
+		Name:               "godot-" + version,
+		Description:        fmt.Sprintf("Godot %s editor's DAP server", version),
+		Quirks:             quirks.Godot(version),
+		Unsupported:        godotUnsupported,
+		ConnectTimeout:     DefaultConnectTimeout,
+		CommandTimeout:     DefaultCommandTimeout,
+		LaunchStallTimeout: defaultLaunchStallTimeout,
+	}
+}
+
+// profiles lists the known adapter profiles by name
+var profiles = map[string]Profile{
+	"godot-4.2": godotProfile("4.2"),
+	"godot-4.3": godotProfile("4.3"),
+	"godot-4.4": godotProfile("4.4"),
+	"generic": {
+		Name:           "generic",
+		Description:    "Spec-compliant DAP adapter; trusts its advertised capabilities",
+		Quirks:         quirks.None,
+		ConnectTimeout: DefaultConnectTimeout,
+		// Adapters that build the program before answering launch (e.g.
+		// Delve) take much longer than Godot
+		CommandTimeout:     60 * time.Second,
+		LaunchStallTimeout: 45 * time.Second,
+	},
+}
+
+// Profiles returns the known adapter profiles, sorted by name
+func Profiles() []Profile {
+	list := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ProfileNames returns the names of the known adapter profiles, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the adapter profile called name; an empty name
+// returns DefaultProfile
+func LookupProfile(name string) (Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	p, ok := profiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown adapter profile %q (known: %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// WithProfile applies an adapter profile: its quirks, unsupported
+// capabilities, and timeouts. Options after it override the profile's.
+func WithProfile(p Profile) ClientOption {
+	return func(c *Client) {
+		c.profile = p.Name
+		c.quirks = p.Quirks
+		c.unsupported = append([]string(nil), p.Unsupported...)
+		WithTimeouts(p.ConnectTimeout, p.CommandTimeout)(c)
+		WithLaunchStallTimeout(p.LaunchStallTimeout)(c)
+	}
+}
+
+// Profile returns the name of the client's adapter profile, or "" if it was
+// configured without one
+func (c *Client) Profile() string {
+	return c.profile
+}
+
+// capabilityFlag returns the field of caps named by its DAP name, or nil
+// for capabilities the client doesn't use
+func capabilityFlag(caps *dap.Capabilities, name string) *bool {
+	switch name {
+	case "supportsConditionalBreakpoints":
+		return &caps.SupportsConditionalBreakpoints
+	case "supportsHitConditionalBreakpoints":
+		return &caps.SupportsHitConditionalBreakpoints
+	case "supportsLogPoints":
+		return &caps.SupportsLogPoints
+	case "supportsFunctionBreakpoints":
+		return &caps.SupportsFunctionBreakpoints
+	case "supportsGotoTargetsRequest":
+		return &caps.SupportsGotoTargetsRequest
+	case "supportsSingleThreadExecutionRequests":
+		return &caps.SupportsSingleThreadExecutionRequests
+	}
+	return nil
+}
+
+// applyUnsupported clears the capabilities the profile doesn't trust
+func (c *Client) applyUnsupported(caps *dap.Capabilities) {
+	for _, name := range c.unsupported {
+		flag := capabilityFlag(caps, name)
+		if flag == nil {
+			continue
+		}
+		if *flag {
+			c.logger.Printf("Adapter advertises %s, but profile %s treats it as unsupported", name, c.profile)
+		}
+		*flag = false
+	}
+}
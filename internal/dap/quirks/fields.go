@@ -0,0 +1,59 @@
+package quirks
+
+// GodotReadFields lists, per command, the request arguments Godot's DAP
+// server reads with Dictionary's operator[] even though the spec makes them
+// optional, with the value the spec implies when they are absent. Nested
+// maps are objects whose own fields Godot reads (e.g. Source.from_json).
+// Every request also gets an arguments object.
+var GodotReadFields = map[string]map[string]interface{}{
+	"initialize": {
+		"linesStartAt1":            true,
+		"columnsStartAt1":          true,
+		"supportsVariableType":     false,
+		"supportsInvalidatedEvent": false,
+	},
+	"setBreakpoints": {
+		"source":      map[string]interface{}{"name": "", "path": "", "checksums": []interface{}{}},
+		"breakpoints": []interface{}{},
+	},
+	"continue":   {"threadId": 1},
+	"next":       {"threadId": 1},
+	"stepIn":     {"threadId": 1},
+	"stackTrace": {"threadId": 1, "startFrame": 0, "levels": 0},
+	"evaluate":   {"frameId": 0, "context": ""},
+	"variables":  {"filter": ""},
+	"disconnect": {"restart": false, "terminateDebuggee": false},
+	"terminate":  {"restart": false},
+}
+
+// PadArguments adds the fields Godot reads for command (GodotReadFields)
+// that arguments lacks. Fields already present are left alone, and nested
+// objects are padded field by field. Returns arguments, or a new map if it
+// was nil.
+func PadArguments(command string, arguments map[string]interface{}) map[string]interface{} {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	pad(arguments, GodotReadFields[command])
+	return arguments
+}
+
+// pad copies the fields of defaults missing from into
+func pad(into, defaults map[string]interface{}) {
+	for field, value := range defaults {
+		existing, ok := into[field]
+		nested, isObject := value.(map[string]interface{})
+		switch {
+		case !ok && isObject:
+			copied := map[string]interface{}{}
+			pad(copied, nested)
+			into[field] = copied
+		case !ok:
+			into[field] = value
+		case isObject:
+			if object, ok := existing.(map[string]interface{}); ok {
+				pad(object, nested)
+			}
+		}
+	}
+}
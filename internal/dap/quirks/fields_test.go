@@ -0,0 +1,34 @@
+package quirks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPadArguments(t *testing.T) {
+	args := PadArguments("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": "/game/player.gd"},
+		"breakpoints": []interface{}{map[string]interface{}{"line": 12}},
+	})
+	want := map[string]interface{}{
+		"source":      map[string]interface{}{"path": "/game/player.gd", "name": "", "checksums": []interface{}{}},
+		"breakpoints": []interface{}{map[string]interface{}{"line": 12}},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+
+	// Missing objects get every nested field, without sharing the defaults
+	args = PadArguments("setBreakpoints", nil)
+	args["source"].(map[string]interface{})["path"] = "/changed.gd"
+	if GodotReadFields["setBreakpoints"]["source"].(map[string]interface{})["path"] != "" {
+		t.Error("padding should copy nested defaults")
+	}
+
+	if args := PadArguments("evaluate", map[string]interface{}{"expression": "hp", "frameId": 3}); args["frameId"] != 3 || args["context"] != "" {
+		t.Errorf("present fields should be kept and missing ones added, got %v", args)
+	}
+	if args := PadArguments("threads", nil); args == nil || len(args) != 0 {
+		t.Errorf("commands without read fields should get an empty arguments object, got %v", args)
+	}
+}
@@ -0,0 +1,83 @@
+// Package quirks records where Godot's DAP server deviates from the Debug
+// Adapter Protocol, per Godot version.
+//
+// The client in internal/dap speaks plain DAP and only works around a
+// deviation when it is given a Set that enables it (dap.WithQuirks), so each
+// workaround can be switched on and tested on its own.
+package quirks
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Set is the collection of protocol deviations an adapter has
+type Set struct {
+	// LaunchResponseAfterConfigurationDone: the adapter answers launch and
+	// attach only once configurationDone has arrived. The client must send
+	// configurationDone without waiting for the launch response, or both
+	// sides wait for each other.
+	LaunchResponseAfterConfigurationDone bool
+
+	// IgnoresStepArguments: continue, next, and stepIn resume the whole game
+	// whatever their singleThread and granularity arguments say. The client
+	// doesn't send them and treats every thread as running afterwards.
+	IgnoresStepArguments bool
+
+	// ReadsOptionalFieldsUnsafely: the adapter reads optional request
+	// arguments with Dictionary's operator[], which logs an error in the
+	// editor console for each one the request leaves out. The client pads
+	// every request with the fields in GodotReadFields.
+	ReadsOptionalFieldsUnsafely bool
+}
+
+// None is the quirk set of a spec-compliant adapter
+var None = Set{}
+
+// godotQuirks lists the quirks of Godot versions, oldest first. A version
+// has the quirks of the last entry it is at least. When a fix lands
+// upstream, add an entry for the release that ships it.
+var godotQuirks = []struct {
+	major, minor int
+	quirks       Set
+}{
+	{4, 0, Set{
+		LaunchResponseAfterConfigurationDone: true,
+		IgnoresStepArguments:                 true,
+		ReadsOptionalFieldsUnsafely:          true,
+	}},
+}
+
+// Godot returns the quirks of a Godot version, as printed by godot --version
+// (e.g. "4.3.stable.official.77dcf97d8" or "v4.2.1"). An empty or
+// unrecognized version gets the quirks of the newest known release.
+func Godot(version string) Set {
+	major, minor, ok := parseVersion(version)
+	if !ok {
+		return godotQuirks[len(godotQuirks)-1].quirks
+	}
+	quirks := godotQuirks[0].quirks
+	for _, entry := range godotQuirks {
+		if major > entry.major || (major == entry.major && minor >= entry.minor) {
+			quirks = entry.quirks
+		}
+	}
+	return quirks
+}
+
+// parseVersion extracts major.minor from a Godot version string
+func parseVersion(version string) (major, minor int, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
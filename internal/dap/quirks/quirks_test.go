@@ -0,0 +1,58 @@
+package quirks
+
+import "testing"
+
+func TestGodot(t *testing.T) {
+	newest := godotQuirks[len(godotQuirks)-1].quirks
+	for _, version := range []string{"", "unknown", "4.3.stable.official.77dcf97d8", "v4.2.1", "5.0"} {
+		if got := Godot(version); got != newest {
+			t.Errorf("Godot(%q) = %+v, want the newest known quirks", version, got)
+		}
+	}
+	if !Godot("4.3").LaunchResponseAfterConfigurationDone {
+		t.Error("Godot 4.3 answers launch only after configurationDone")
+	}
+}
+
+func TestGodot_VersionRanges(t *testing.T) {
+	saved := godotQuirks
+	t.Cleanup(func() { godotQuirks = saved })
+	fixed := Set{IgnoresStepArguments: true}
+	godotQuirks = append(godotQuirks[:len(godotQuirks):len(godotQuirks)], struct {
+		major, minor int
+		quirks       Set
+	}{4, 5, fixed})
+
+	if Godot("4.4.1.stable") != saved[0].quirks {
+		t.Error("4.4 should keep the 4.0 quirks")
+	}
+	for _, version := range []string{"4.5", "4.6.dev", "5.1", ""} {
+		if Godot(version) != fixed {
+			t.Errorf("Godot(%q) should have the 4.5 quirks", version)
+		}
+	}
+	if Godot("3.5.3") != saved[0].quirks {
+		t.Error("versions older than every entry should get the oldest quirks")
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		major, minor int
+		ok           bool
+	}{
+		{"4.3.stable.official.77dcf97d8", 4, 3, true},
+		{"v4.2.1", 4, 2, true},
+		{" 4.0\n", 4, 0, true},
+		{"4", 0, 0, false},
+		{"stable", 0, 0, false},
+		{"4.x", 0, 0, false},
+	}
+	for _, tt := range tests {
+		major, minor, ok := parseVersion(tt.version)
+		if major != tt.major || minor != tt.minor || ok != tt.ok {
+			t.Errorf("parseVersion(%q) = %d, %d, %v", tt.version, major, minor, ok)
+		}
+	}
+}
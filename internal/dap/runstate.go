@@ -0,0 +1,31 @@
+package dap
+
+// RunState is the tracked state of the debugged game, derived from launch
+// milestones, stopped/continued events, and exit events. It lets tools reject
+// requests that Godot would never answer instead of waiting for a timeout.
+type RunState string
+
+const (
+	RunStateNotLaunched RunState = "not_launched" // No launch or attach since connecting
+	RunStateRunning     RunState = "running"      // Game is executing
+	RunStatePaused      RunState = "paused"       // At least one thread is stopped
+	RunStateEnded       RunState = "ended"        // Game exited, terminated, or the connection dropped
+)
+
+// RunState returns the tracked state of the game.
+// A stopped thread wins over everything else, so a game started from the
+// editor (without a launch tool) can still be inspected once it pauses.
+func (c *Client) RunState() RunState {
+	for _, st := range c.threads.stateSnapshot() {
+		if st.Stopped {
+			return RunStatePaused
+		}
+	}
+	if c.exit.get() != nil {
+		return RunStateEnded
+	}
+	if !c.timeline.started() {
+		return RunStateNotLaunched
+	}
+	return RunStateRunning
+}
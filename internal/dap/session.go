@@ -3,6 +3,8 @@ package dap
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	dap "github.com/google/go-dap"
 )
@@ -16,6 +18,7 @@ const (
 	StateInitialized
 	StateConfigured
 	StateLaunched
+	StateAttached
 )
 
 func (s SessionState) String() string {
@@ -30,23 +33,72 @@ func (s SessionState) String() string {
 		return "configured"
 	case StateLaunched:
 		return "launched"
+	case StateAttached:
+		return "attached"
 	default:
 		return "unknown"
 	}
 }
 
-// Session manages the lifecycle of a DAP debugging session
+// sessionTransitions lists the states each state may move to.
+// Any state may move to StateDisconnected, and re-entering the current state
+// (e.g. relaunching while launched) is always allowed.
+var sessionTransitions = map[SessionState][]SessionState{
+	StateDisconnected: {StateConnected},
+	StateConnected:    {StateInitialized},
+	StateInitialized:  {StateConfigured, StateLaunched, StateAttached},
+	StateConfigured:   {StateLaunched, StateAttached},
+	StateLaunched:     {StateAttached},
+	StateAttached:     {StateLaunched},
+}
+
+// CanTransitionTo reports whether a session may move from s to next
+func (s SessionState) CanTransitionTo(next SessionState) bool {
+	if next == s || next == StateDisconnected {
+		return true
+	}
+	for _, allowed := range sessionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StateChange describes one session state transition
+type StateChange struct {
+	From SessionState
+	To   SessionState
+	Time time.Time
+}
+
+// StateChangeHandler is called after each session state transition
+type StateChangeHandler func(change StateChange)
+
+// Session manages the lifecycle of a DAP debugging session.
+// State and project root are guarded by mu so concurrent tool calls can
+// read them safely; the client does its own locking.
 type Session struct {
 	client      *Client
+	mu          sync.RWMutex
 	state       SessionState
 	projectRoot string
+	breakpoints *BreakpointRegistry // Breakpoints requested on this connection
+
+	// State change observers, keyed so they can be removed individually
+	handlers      map[int]StateChangeHandler
+	nextHandlerID int
 }
 
-// NewSession creates a new DAP session
-func NewSession(host string, port int) *Session {
+// NewSession creates a new DAP session; opts configure the underlying client.
+// The client uses the adapter profile of current Godot releases
+// (DefaultProfile) unless opts include WithProfile or WithQuirks.
+func NewSession(host string, port int, opts ...ClientOption) *Session {
+	opts = append([]ClientOption{WithProfile(profiles[DefaultProfile])}, opts...)
 	return &Session{
-		client: NewClient(host, port),
-		state:  StateDisconnected,
+		client:      NewClient(host, port, opts...),
+		state:       StateDisconnected,
+		breakpoints: &BreakpointRegistry{},
 	}
 }
 
@@ -55,18 +107,89 @@ func (s *Session) GetClient() *Client {
 	return s.client
 }
 
+// Breakpoints returns the breakpoints requested on this session
+func (s *Session) Breakpoints() *BreakpointRegistry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.breakpoints
+}
+
+// ReplaceBreakpoints makes breakpoints the session's requested breakpoints,
+// e.g. ones restored from a snapshot before connecting. Nothing is sent to
+// the adapter.
+func (s *Session) ReplaceBreakpoints(breakpoints *BreakpointRegistry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakpoints = breakpoints
+}
+
 // GetState returns the current session state
 func (s *Session) GetState() SessionState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.state
 }
 
+// OnStateChange registers a handler called after every state transition.
+// Handlers run synchronously on the goroutine that changed the state, in no
+// particular order, and must not block. Call the returned function to remove
+// the handler.
+func (s *Session) OnStateChange(handler StateChangeHandler) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[int]StateChangeHandler)
+	}
+	id := s.nextHandlerID
+	s.nextHandlerID++
+	s.handlers[id] = handler
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.handlers, id)
+	}
+}
+
+// transition moves the session to state and notifies the state change
+// handlers. Returns an error, leaving the state unchanged, if the transition
+// isn't valid. Re-entering the current state is accepted without notifying.
+func (s *Session) transition(state SessionState) error {
+	s.mu.Lock()
+	from := s.state
+	if !from.CanTransitionTo(state) {
+		s.mu.Unlock()
+		return fmt.Errorf("invalid session state transition: %s -> %s", from, state)
+	}
+	s.state = state
+	handlers := make([]StateChangeHandler, 0, len(s.handlers))
+	for _, handler := range s.handlers {
+		handlers = append(handlers, handler)
+	}
+	s.mu.Unlock()
+
+	if from == state {
+		return nil
+	}
+	change := StateChange{From: from, To: state, Time: time.Now()}
+	for _, handler := range handlers {
+		handler(change)
+	}
+	return nil
+}
+
 // SetProjectRoot sets the project root directory for path resolution
 func (s *Session) SetProjectRoot(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.projectRoot = path
 }
 
 // GetProjectRoot returns the project root directory
 func (s *Session) GetProjectRoot() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.projectRoot
 }
 
@@ -81,7 +204,7 @@ func (s *Session) InitializeSession(ctx context.Context) error {
 	// Send initialize request
 	if err := s.Initialize(ctx); err != nil {
 		s.client.Disconnect() // Clean up on error
-		s.state = StateDisconnected
+		s.transition(StateDisconnected)
 		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
@@ -94,28 +217,27 @@ func (s *Session) InitializeSession(ctx context.Context) error {
 
 // Connect establishes a connection to the DAP server
 func (s *Session) Connect(ctx context.Context) error {
-	if s.state != StateDisconnected {
-		return fmt.Errorf("cannot connect: session is in state %s", s.state)
+	if state := s.GetState(); state != StateDisconnected {
+		return fmt.Errorf("cannot connect: session is in state %s", state)
 	}
 
-	ctx, cancel := WithConnectTimeout(ctx)
+	ctx, cancel := s.client.withConnectTimeout(ctx)
 	defer cancel()
 
 	if err := s.client.Connect(ctx); err != nil {
 		return err
 	}
 
-	s.state = StateConnected
-	return nil
+	return s.transition(StateConnected)
 }
 
 // Initialize sends the initialize request
 func (s *Session) Initialize(ctx context.Context) error {
-	if s.state != StateConnected {
-		return fmt.Errorf("cannot initialize: session is in state %s (must be connected)", s.state)
+	if state := s.GetState(); state != StateConnected {
+		return fmt.Errorf("cannot initialize: session is in state %s (must be connected)", state)
 	}
 
-	ctx, cancel := WithCommandTimeout(ctx)
+	ctx, cancel := s.client.withCommandTimeout(ctx)
 	defer cancel()
 
 	_, err := s.client.Initialize(ctx)
@@ -123,55 +245,54 @@ func (s *Session) Initialize(ctx context.Context) error {
 		return err
 	}
 
-	s.state = StateInitialized
-	return nil
+	return s.transition(StateInitialized)
 }
 
 // ConfigurationDone sends the configurationDone request
 func (s *Session) ConfigurationDone(ctx context.Context) error {
-	if s.state != StateInitialized {
-		return fmt.Errorf("cannot send configurationDone: session is in state %s (must be initialized)", s.state)
+	if state := s.GetState(); state != StateInitialized {
+		return fmt.Errorf("cannot send configurationDone: session is in state %s (must be initialized)", state)
 	}
 
-	ctx, cancel := WithCommandTimeout(ctx)
+	ctx, cancel := s.client.withCommandTimeout(ctx)
 	defer cancel()
 
 	if err := s.client.ConfigurationDone(ctx); err != nil {
 		return err
 	}
 
-	s.state = StateConfigured
-	return nil
+	return s.transition(StateConfigured)
 }
 
 // Close closes the session and disconnects from the DAP server
 func (s *Session) Close() error {
-	if s.state == StateDisconnected {
+	if s.GetState() == StateDisconnected {
 		return nil
 	}
 
 	err := s.client.Disconnect()
-	s.state = StateDisconnected
+	s.transition(StateDisconnected)
 	return err
 }
 
 // IsReady returns whether the session is ready for debugging operations
-// (i.e., in Configured or Launched state)
+// (i.e., in Configured, Launched, or Attached state)
 func (s *Session) IsReady() bool {
-	return s.state == StateConfigured || s.state == StateLaunched
+	state := s.GetState()
+	return state == StateConfigured || state == StateLaunched || state == StateAttached
 }
 
 // RequireReady returns an error if the session is not ready
 func (s *Session) RequireReady() error {
 	if !s.IsReady() {
-		return fmt.Errorf("session not ready: current state is %s", s.state)
+		return fmt.Errorf("session not ready: current state is %s", s.GetState())
 	}
 	return nil
 }
 
 // SetLaunched marks the session as launched (called after successful launch)
-func (s *Session) SetLaunched() {
-	s.state = StateLaunched
+func (s *Session) SetLaunched() error {
+	return s.transition(StateLaunched)
 }
 
 // Launch is a convenience method that sends a launch request
@@ -181,11 +302,11 @@ func (s *Session) SetLaunched() {
 // The launch request is stored by Godot and executed when ConfigurationDone is sent.
 func (s *Session) Launch(ctx context.Context, args map[string]interface{}) (*dap.LaunchResponse, error) {
 	// Launch must be called after Initialize but BEFORE ConfigurationDone
-	if s.state != StateInitialized {
-		return nil, fmt.Errorf("cannot launch: session is in state %s (must be initialized)", s.state)
+	if state := s.GetState(); state != StateInitialized {
+		return nil, fmt.Errorf("cannot launch: session is in state %s (must be initialized)", state)
 	}
 
-	ctx, cancel := WithCommandTimeout(ctx)
+	ctx, cancel := s.client.withCommandTimeout(ctx)
 	defer cancel()
 
 	launchResp, err := s.client.Launch(ctx, args)
@@ -0,0 +1,42 @@
+package dap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// namedPipePrefix marks a Windows named pipe path (\\.\pipe\name)
+const namedPipePrefix = `\\.\pipe\`
+
+// SocketDialer connects to a DAP server over a local socket instead of TCP:
+// a unix domain socket path, or a Windows named pipe (\\.\pipe\name).
+// Useful when the editor's DAP port is bridged locally and TCP is restricted.
+// The host and port the client was created with are ignored.
+type SocketDialer struct {
+	Path string
+}
+
+// DialContext connects to the socket or named pipe at d.Path
+func (d *SocketDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if isNamedPipePath(d.Path) {
+		conn, err := dialNamedPipe(ctx, d.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open named pipe %s: %w", d.Path, err)
+		}
+		return conn, nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to unix socket %s: %w", d.Path, err)
+	}
+	return conn, nil
+}
+
+// isNamedPipePath reports whether path names a Windows named pipe
+func isNamedPipePath(path string) bool {
+	return strings.HasPrefix(strings.ReplaceAll(path, "/", `\`), namedPipePrefix)
+}
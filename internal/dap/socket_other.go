@@ -0,0 +1,14 @@
+//go:build !windows
+
+package dap
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// dialNamedPipe is only supported on Windows
+func dialNamedPipe(ctx context.Context, path string) (net.Conn, error) {
+	return nil, errors.New("named pipes are only supported on Windows")
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package dap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// errPipeBusy is ERROR_PIPE_BUSY: every instance of the pipe is in use
+const errPipeBusy = syscall.Errno(231)
+
+// pipePollInterval is how often dialNamedPipe retries a busy pipe
+const pipePollInterval = 50 * time.Millisecond
+
+// dialNamedPipe opens the client end of a Windows named pipe.
+// Opening fails while every pipe instance is busy, so it retries until the
+// context expires.
+func dialNamedPipe(ctx context.Context, path string) (net.Conn, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err == nil {
+			return &pipeConn{File: f}, nil
+		}
+		if !errors.Is(err, errPipeBusy) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pipePollInterval):
+		}
+	}
+}
+
+// pipeConn adapts a named pipe file handle to net.Conn.
+// Deadlines are not supported; the client relies on contexts instead.
+type pipeConn struct {
+	*os.File
+}
+
+// pipeAddr is the net.Addr of a named pipe
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+func (p *pipeConn) LocalAddr() net.Addr  { return pipeAddr(p.Name()) }
+func (p *pipeConn) RemoteAddr() net.Addr { return pipeAddr(p.Name()) }
+
+func (p *pipeConn) SetDeadline(t time.Time) error      { return nil }
+func (p *pipeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (p *pipeConn) SetWriteDeadline(t time.Time) error { return nil }
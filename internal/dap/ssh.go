@@ -0,0 +1,118 @@
+package dap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DialerFunc adapts an ordinary dial function to the Dialer interface
+type DialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext calls f(ctx, network, address)
+func (f DialerFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+// sshPollInterval is how often SSHDialer retries the local end of the tunnel
+// while ssh is still authenticating
+const sshPollInterval = 100 * time.Millisecond
+
+// SSHDialer reaches a DAP server on a remote machine through an SSH tunnel.
+// It runs the system ssh client with a local port forward, so keys, agents,
+// and ~/.ssh/config apply exactly as on the command line. The dialed address
+// is resolved on the remote side: "localhost:6006" is the remote editor.
+type SSHDialer struct {
+	Target  string   // "user@host" or a Host alias from ~/.ssh/config
+	Args    []string // Extra ssh arguments (e.g. "-p", "2222")
+	Command string   // ssh executable (default: "ssh")
+}
+
+// DialContext starts the tunnel and connects through it. The ssh process is
+// stopped when the returned connection is closed. ssh sends its own keepalives
+// (ServerAliveInterval), since TCP keepalive on the local end can't reach the
+// remote link.
+func (d *SSHDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	localAddr, err := freeLocalAddr()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve a local port for the ssh tunnel: %w", err)
+	}
+
+	command := d.Command
+	if command == "" {
+		command = "ssh"
+	}
+	args := []string{
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ServerAliveInterval=%d", int(defaultKeepAlive.Seconds())),
+		"-L", localAddr + ":" + address,
+	}
+	args = append(args, d.Args...)
+	args = append(args, d.Target)
+
+	cmd := exec.Command(command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// ssh only opens the local port once it has authenticated, so keep
+	// trying until the dial succeeds, ssh gives up, or the context expires
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", localAddr)
+		if err == nil {
+			return &tunnelConn{Conn: conn, cmd: cmd, exited: exited}, nil
+		}
+
+		select {
+		case err := <-exited:
+			return nil, fmt.Errorf("ssh tunnel to %s failed: %v: %s", d.Target, err, strings.TrimSpace(stderr.String()))
+		case <-ctx.Done():
+			cmd.Process.Kill()
+			<-exited
+			return nil, fmt.Errorf("timed out waiting for ssh tunnel to %s: %w", d.Target, ctx.Err())
+		case <-time.After(sshPollInterval):
+		}
+	}
+}
+
+// freeLocalAddr returns a loopback address with a currently unused port
+func freeLocalAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// tunnelConn is a connection through an ssh tunnel that stops the ssh
+// process when closed
+type tunnelConn struct {
+	net.Conn
+	cmd       *exec.Cmd
+	exited    chan error
+	closeOnce sync.Once
+}
+
+// Close closes the connection and stops the tunnel
+func (t *tunnelConn) Close() error {
+	err := t.Conn.Close()
+	t.closeOnce.Do(func() {
+		t.cmd.Process.Kill()
+		<-t.exited
+	})
+	return err
+}
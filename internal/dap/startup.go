@@ -0,0 +1,115 @@
+package dap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// Bounds on the output kept from a launch's startup
+const (
+	maxStartupWindow = 60 * time.Second // Output after this is no longer startup output
+	maxStartupOutput = 1000             // Output events kept per launch
+)
+
+// How startup output collection ended
+const (
+	StartupCollecting = "collecting" // Still collecting: no stop, exit, or timeout yet
+	StartupFirstStop  = "first_stop" // The game stopped (breakpoint, pause, ...)
+	StartupTimeout    = "timeout"    // maxStartupWindow passed without a stop
+	StartupEnded      = "ended"      // The game exited or terminated
+)
+
+// StartupOutput is the output the game printed between the launch or attach
+// and its first stop
+type StartupOutput struct {
+	Start     time.Time      // When the launch or attach began
+	End       time.Time      // When collection ended (zero while collecting)
+	EndReason string         // One of the Startup* constants
+	Records   []OutputRecord // Output events, oldest first; Seq counts from 1
+	Dropped   int            // Events past maxStartupOutput that were not kept
+}
+
+// startupCapture collects the output of the most recent launch or attach
+// until the game first stops. The output buffer only keeps recent events,
+// so warnings printed at boot would otherwise be lost in long sessions.
+type startupCapture struct {
+	mu     sync.Mutex
+	output StartupOutput
+}
+
+// begin starts collecting for a new launch, discarding the previous one's output
+func (sc *startupCapture) begin() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.output = StartupOutput{Start: time.Now(), EndReason: StartupCollecting}
+}
+
+// finishLocked ends collection. Caller must hold sc.mu.
+func (sc *startupCapture) finishLocked(reason string, at time.Time) {
+	sc.output.EndReason = reason
+	sc.output.End = at
+}
+
+// expireLocked ends collection if the startup window has passed.
+// Caller must hold sc.mu.
+func (sc *startupCapture) expireLocked(now time.Time) {
+	if sc.output.EndReason != StartupCollecting {
+		return
+	}
+	if deadline := sc.output.Start.Add(maxStartupWindow); now.After(deadline) {
+		sc.finishLocked(StartupTimeout, deadline)
+	}
+}
+
+// applyEvent records output events until the first stopped, exited, or
+// terminated event
+func (sc *startupCapture) applyEvent(msg dap.Message) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.output.Start.IsZero() {
+		return
+	}
+	now := time.Now()
+	sc.expireLocked(now)
+	if sc.output.EndReason != StartupCollecting {
+		return
+	}
+
+	switch e := msg.(type) {
+	case *dap.OutputEvent:
+		if len(sc.output.Records) >= maxStartupOutput {
+			sc.output.Dropped++
+			return
+		}
+		sc.output.Records = append(sc.output.Records, OutputRecord{
+			Seq:      len(sc.output.Records) + 1,
+			Category: e.Body.Category,
+			Output:   e.Body.Output,
+			Time:     now,
+		})
+	case *dap.StoppedEvent:
+		sc.finishLocked(StartupFirstStop, now)
+	case *dap.ExitedEvent, *dap.TerminatedEvent:
+		sc.finishLocked(StartupEnded, now)
+	}
+}
+
+// snapshot returns a copy of the startup output
+func (sc *startupCapture) snapshot() StartupOutput {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.expireLocked(time.Now())
+	out := sc.output
+	out.Records = append([]OutputRecord(nil), sc.output.Records...)
+	return out
+}
+
+// StartupOutput returns the output of the most recent launch or attach up to
+// the game's first stop, or the first minute if it doesn't stop. Start is
+// zero if there was no launch or attach.
+func (c *Client) StartupOutput() StartupOutput {
+	return c.startup.snapshot()
+}
@@ -0,0 +1,185 @@
+package dap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// maxThreadEventHistory bounds the number of thread events kept for inspection
+const maxThreadEventHistory = 50
+
+// ThreadEventRecord is a thread start/exit notification received from the adapter
+type ThreadEventRecord struct {
+	Reason   string    // "started" or "exited"
+	ThreadId int       // Thread the event refers to
+	Time     time.Time // When the event was received
+}
+
+// ThreadState is the last known execution state of a single thread.
+// It is derived from stopped/continued events and from execution requests,
+// since adapters don't send a continued event after a continue/step request.
+type ThreadState struct {
+	Stopped bool      // Whether the thread is paused
+	Reason  string    // Stop reason ("breakpoint", "step", "pause", ...); empty while running
+	Time    time.Time // When the state last changed
+}
+
+// threadCache caches the threads list between requests.
+// It is kept current from DAP thread events, so tools that only need a thread
+// id don't have to issue a threads request every time.
+type threadCache struct {
+	mu      sync.Mutex
+	threads []dap.Thread
+	valid   bool
+	events  []ThreadEventRecord
+	states  map[int]ThreadState
+}
+
+// get returns a copy of the cached threads and whether the cache is valid
+func (tc *threadCache) get() ([]dap.Thread, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if !tc.valid {
+		return nil, false
+	}
+	return append([]dap.Thread(nil), tc.threads...), true
+}
+
+// set replaces the cached threads with a fresh threads response
+func (tc *threadCache) set(threads []dap.Thread) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.threads = append([]dap.Thread(nil), threads...)
+	tc.valid = true
+}
+
+// reset drops the cached threads and all per-thread state
+func (tc *threadCache) reset() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.threads = nil
+	tc.valid = false
+	tc.states = nil
+}
+
+// setStateLocked records the state of one thread, or of every known thread
+// when all is true. Caller must hold tc.mu.
+func (tc *threadCache) setStateLocked(threadId int, all bool, state ThreadState) {
+	if tc.states == nil {
+		tc.states = make(map[int]ThreadState)
+	}
+	if all {
+		for _, t := range tc.threads {
+			tc.states[t.Id] = state
+		}
+		for id := range tc.states {
+			tc.states[id] = state
+		}
+	}
+	if threadId != 0 {
+		tc.states[threadId] = state
+	}
+}
+
+// markRunning records that a thread (or every thread) was resumed by an
+// execution request
+func (tc *threadCache) markRunning(threadId int, all bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.setStateLocked(threadId, all, ThreadState{Time: time.Now()})
+}
+
+// stateSnapshot returns a copy of the known per-thread states
+func (tc *threadCache) stateSnapshot() map[int]ThreadState {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	states := make(map[int]ThreadState, len(tc.states))
+	for id, st := range tc.states {
+		states[id] = st
+	}
+	return states
+}
+
+// applyEvent updates the cache from a DAP event.
+// Exited threads are removed directly; a started thread invalidates the cache
+// because the event doesn't carry the thread's name. Stopped and continued
+// events update per-thread state, honoring allThreadsStopped/allThreadsContinued.
+func (tc *threadCache) applyEvent(msg dap.Message) {
+	switch e := msg.(type) {
+	case *dap.StoppedEvent:
+		tc.mu.Lock()
+		defer tc.mu.Unlock()
+		tc.setStateLocked(e.Body.ThreadId, e.Body.AllThreadsStopped, ThreadState{
+			Stopped: true,
+			Reason:  e.Body.Reason,
+			Time:    time.Now(),
+		})
+	case *dap.ContinuedEvent:
+		tc.mu.Lock()
+		defer tc.mu.Unlock()
+		tc.setStateLocked(e.Body.ThreadId, e.Body.AllThreadsContinued, ThreadState{Time: time.Now()})
+	case *dap.ThreadEvent:
+		tc.mu.Lock()
+		defer tc.mu.Unlock()
+
+		tc.events = append(tc.events, ThreadEventRecord{
+			Reason:   e.Body.Reason,
+			ThreadId: e.Body.ThreadId,
+			Time:     time.Now(),
+		})
+		if len(tc.events) > maxThreadEventHistory {
+			tc.events = tc.events[len(tc.events)-maxThreadEventHistory:]
+		}
+
+		switch e.Body.Reason {
+		case "exited":
+			for i, t := range tc.threads {
+				if t.Id == e.Body.ThreadId {
+					tc.threads = append(tc.threads[:i], tc.threads[i+1:]...)
+					break
+				}
+			}
+			delete(tc.states, e.Body.ThreadId)
+		default:
+			tc.threads = nil
+			tc.valid = false
+		}
+	case *dap.TerminatedEvent, *dap.ExitedEvent:
+		tc.reset()
+	}
+}
+
+// recentEvents returns a copy of the recorded thread events, oldest first
+func (tc *threadCache) recentEvents() []ThreadEventRecord {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return append([]ThreadEventRecord(nil), tc.events...)
+}
+
+// CachedThreads returns the threads list, issuing a threads request only if
+// the cache was invalidated (by a thread start, termination, or reconnect)
+func (c *Client) CachedThreads(ctx context.Context) ([]dap.Thread, error) {
+	if threads, ok := c.threads.get(); ok {
+		return threads, nil
+	}
+
+	resp, err := c.Threads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body.Threads, nil
+}
+
+// ThreadEvents returns the most recent thread start/exit events, oldest first
+func (c *Client) ThreadEvents() []ThreadEventRecord {
+	return c.threads.recentEvents()
+}
+
+// ThreadStates returns the last known state of each thread, keyed by thread id.
+// Threads that haven't stopped or been resumed since connecting are absent.
+func (c *Client) ThreadStates() map[int]ThreadState {
+	return c.threads.stateSnapshot()
+}
@@ -0,0 +1,103 @@
+package dap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// Launch milestones recorded in the launch timeline
+const (
+	MilestoneRequestSent      = "request_sent"                    // launch/attach request written
+	MilestoneBreakpointsAcked = "breakpoints_acknowledged"        // pending setBreakpoints answered (LaunchAfterBreakpoints)
+	MilestoneConfigDoneSent   = "configuration_done_sent"         // configurationDone written
+	MilestoneConfigDoneAcked  = "configuration_done_acknowledged" // configurationDone response received
+	MilestoneLaunchResponse   = "launch_response_received"        // launch/attach response received
+	MilestoneProcessStarted   = "process_started"                 // process event received
+	MilestoneFirstStopped     = "first_stopped"                   // first stopped event after launch
+	MilestoneTerminated       = "terminated"                      // run ended (exited/terminated)
+	MilestoneStalled          = "stalled"                         // launch watchdog gave up waiting for a response
+)
+
+// LaunchMilestone is one step of the launch sequence
+type LaunchMilestone struct {
+	Name    string        // One of the Milestone* constants
+	Detail  string        // Extra context (stop reason, process name, ...)
+	Time    time.Time     // When the milestone was reached
+	Elapsed time.Duration // Time since the launch started
+}
+
+// launchTimeline records milestones of the most recent launch or attach.
+// Godot only answers the launch request after configurationDone and the game
+// can take a while to start, so the timeline shows how far a launch got.
+type launchTimeline struct {
+	mu         sync.Mutex
+	start      time.Time
+	milestones []LaunchMilestone
+}
+
+// begin starts a new timeline, discarding the previous launch's milestones
+func (lt *launchTimeline) begin() {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.start = time.Now()
+	lt.milestones = nil
+}
+
+// mark records a milestone. Each milestone is recorded at most once per launch,
+// and nothing is recorded before a launch has begun.
+func (lt *launchTimeline) mark(name string, detail string) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	if lt.start.IsZero() {
+		return
+	}
+	for _, m := range lt.milestones {
+		if m.Name == name {
+			return
+		}
+	}
+
+	now := time.Now()
+	lt.milestones = append(lt.milestones, LaunchMilestone{
+		Name:    name,
+		Detail:  detail,
+		Time:    now,
+		Elapsed: now.Sub(lt.start),
+	})
+}
+
+// applyEvent records milestones signalled by adapter events
+func (lt *launchTimeline) applyEvent(msg dap.Message) {
+	switch e := msg.(type) {
+	case *dap.ProcessEvent:
+		lt.mark(MilestoneProcessStarted, e.Body.Name)
+	case *dap.StoppedEvent:
+		lt.mark(MilestoneFirstStopped, e.Body.Reason)
+	case *dap.ExitedEvent, *dap.TerminatedEvent:
+		lt.mark(MilestoneTerminated, "")
+	}
+}
+
+// started reports whether a launch or attach has begun
+func (lt *launchTimeline) started() bool {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return !lt.start.IsZero()
+}
+
+// snapshot returns a copy of the recorded milestones, in the order reached
+func (lt *launchTimeline) snapshot() []LaunchMilestone {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return append([]LaunchMilestone(nil), lt.milestones...)
+}
+
+// LaunchTimeline returns the milestones of the most recent launch or attach.
+// Milestones that arrive after the launch call returns (process start, first
+// stop) keep being added, so calling this later gives a fuller picture.
+func (c *Client) LaunchTimeline() []LaunchMilestone {
+	return c.timeline.snapshot()
+}
@@ -10,6 +10,11 @@ const (
 	DefaultConnectTimeout = 10 * time.Second
 	DefaultCommandTimeout = 30 * time.Second
 	DefaultReadTimeout    = 5 * time.Second
+
+	// DefaultEvaluateTimeout bounds a single user evaluate. An expression
+	// runs on the game's main thread, so a heavy getter or an endless loop
+	// would otherwise hold the session for the whole command timeout.
+	DefaultEvaluateTimeout = 10 * time.Second
 )
 
 // WithConnectTimeout creates a context with the default connect timeout
@@ -0,0 +1,221 @@
+package dap
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+)
+
+// maxViolations is how many protocol violations a client keeps
+const maxViolations = 50
+
+// ProtocolViolation is an incoming message that doesn't have the shape the
+// DAP specification gives it, such as a response without its body or a field
+// of the wrong type. The client drops such messages instead of decoding them
+// into zero values; a malformed response fails its request with this error.
+type ProtocolViolation struct {
+	Kind       string    // "response", "event", or "" if the message isn't a JSON object
+	Name       string    // Command or event name
+	RequestSeq int       // Request a malformed response answers, if known
+	Problems   []string  // What's wrong, e.g. "body.stackFrames: missing"
+	Time       time.Time // When the message arrived
+}
+
+func (v *ProtocolViolation) Error() string {
+	what := "message"
+	if v.Name != "" {
+		what = v.Name + " " + v.Kind
+	}
+	return fmt.Sprintf("malformed %s from the debug adapter: %s", what, strings.Join(v.Problems, "; "))
+}
+
+// fieldSpec is a field a message must have: a dotted path, where "[]" checks
+// every element of an array, and the JSON kind of its value
+type fieldSpec struct {
+	path string
+	kind string // object, array, string, number, or boolean
+}
+
+// responseShapes lists the fields successful responses must have, by command.
+// Commands whose body is optional or unused aren't listed.
+var responseShapes = map[string][]fieldSpec{
+	"setBreakpoints":         {{"body", "object"}, {"body.breakpoints", "array"}, {"body.breakpoints[].verified", "boolean"}},
+	"setFunctionBreakpoints": {{"body", "object"}, {"body.breakpoints", "array"}, {"body.breakpoints[].verified", "boolean"}},
+	"threads":                {{"body", "object"}, {"body.threads", "array"}, {"body.threads[].id", "number"}, {"body.threads[].name", "string"}},
+	"stackTrace": {{"body", "object"}, {"body.stackFrames", "array"}, {"body.stackFrames[].id", "number"},
+		{"body.stackFrames[].name", "string"}, {"body.stackFrames[].line", "number"}},
+	"scopes": {{"body", "object"}, {"body.scopes", "array"}, {"body.scopes[].name", "string"}, {"body.scopes[].variablesReference", "number"}},
+	"variables": {{"body", "object"}, {"body.variables", "array"}, {"body.variables[].name", "string"},
+		{"body.variables[].value", "string"}, {"body.variables[].variablesReference", "number"}},
+	"evaluate":    {{"body", "object"}, {"body.result", "string"}, {"body.variablesReference", "number"}},
+	"gotoTargets": {{"body", "object"}, {"body.targets", "array"}, {"body.targets[].id", "number"}, {"body.targets[].line", "number"}},
+}
+
+// eventShapes lists the fields events must have, by event
+var eventShapes = map[string][]fieldSpec{
+	"stopped":    {{"body", "object"}, {"body.reason", "string"}},
+	"continued":  {{"body", "object"}, {"body.threadId", "number"}},
+	"exited":     {{"body", "object"}, {"body.exitCode", "number"}},
+	"thread":     {{"body", "object"}, {"body.reason", "string"}, {"body.threadId", "number"}},
+	"output":     {{"body", "object"}, {"body.output", "string"}},
+	"breakpoint": {{"body", "object"}, {"body.reason", "string"}, {"body.breakpoint", "object"}},
+	"process":    {{"body", "object"}, {"body.name", "string"}},
+}
+
+// headerShapes lists the fields every message of a type must have
+var headerShapes = map[string][]fieldSpec{
+	"response": {{"seq", "number"}, {"request_seq", "number"}, {"success", "boolean"}, {"command", "string"}},
+	"event":    {{"seq", "number"}, {"event", "string"}},
+}
+
+// checkShape returns what's wrong with a decoded JSON message, or nil
+func checkShape(raw map[string]interface{}) []string {
+	specs := headerShapes[kindOf(raw)]
+	switch kindOf(raw) {
+	case "response":
+		if success, _ := raw["success"].(bool); success {
+			command, _ := raw["command"].(string)
+			specs = append(specs[:len(specs):len(specs)], responseShapes[command]...)
+		}
+	case "event":
+		event, _ := raw["event"].(string)
+		specs = append(specs[:len(specs):len(specs)], eventShapes[event]...)
+	}
+	// Nested specs repeat the problem of a missing parent; report it once
+	var problems []string
+	reported := map[string]bool{}
+	for _, spec := range specs {
+		for _, problem := range checkField(raw, "", strings.Split(spec.path, "."), spec.kind) {
+			if !reported[problem] {
+				reported[problem] = true
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return problems
+}
+
+// checkField checks the field at path under value, whose own path is prefix
+func checkField(value interface{}, prefix string, path []string, kind string) []string {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		// The parent's own spec reports it
+		return nil
+	}
+	name := path[0]
+	elements := strings.HasSuffix(name, "[]")
+	name = strings.TrimSuffix(name, "[]")
+	if prefix != "" {
+		prefix += "."
+	}
+	field, present := object[name]
+	if !present || field == nil {
+		if elements {
+			return nil
+		}
+		return []string{prefix + name + ": missing"}
+	}
+	if !elements {
+		if len(path) == 1 {
+			if got := jsonKind(field); got != kind {
+				return []string{fmt.Sprintf("%s%s: expected %s, got %s", prefix, name, kind, got)}
+			}
+			return nil
+		}
+		return checkField(field, prefix+name, path[1:], kind)
+	}
+	array, _ := field.([]interface{})
+	var problems []string
+	for i, element := range array {
+		problems = append(problems, checkField(element, fmt.Sprintf("%s%s[%d]", prefix, name, i), path[1:], kind)...)
+	}
+	return problems
+}
+
+// jsonKind names the JSON kind of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// kindOf returns a message's type field
+func kindOf(raw map[string]interface{}) string {
+	kind, _ := raw["type"].(string)
+	return kind
+}
+
+// newViolation describes a malformed message
+func newViolation(raw map[string]interface{}, problems []string) *ProtocolViolation {
+	v := &ProtocolViolation{Kind: kindOf(raw), Problems: problems, Time: time.Now()}
+	switch v.Kind {
+	case "response":
+		v.Name, _ = raw["command"].(string)
+		if seq, ok := raw["request_seq"].(float64); ok {
+			v.RequestSeq = int(seq)
+		}
+	case "event":
+		v.Name, _ = raw["event"].(string)
+	}
+	return v
+}
+
+// violationLog keeps the most recent protocol violations
+type violationLog struct {
+	mu      sync.Mutex
+	entries []ProtocolViolation
+}
+
+// add records a violation, dropping the oldest beyond maxViolations
+func (vl *violationLog) add(v *ProtocolViolation) {
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+	vl.entries = append(vl.entries, *v)
+	if len(vl.entries) > maxViolations {
+		vl.entries = vl.entries[len(vl.entries)-maxViolations:]
+	}
+}
+
+// snapshot returns a copy of the recorded violations, oldest first
+func (vl *violationLog) snapshot() []ProtocolViolation {
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+	return append([]ProtocolViolation(nil), vl.entries...)
+}
+
+// handleViolation records a malformed message and fails the request it
+// answers, so the caller gets the violation instead of a timeout
+func (c *Client) handleViolation(v *ProtocolViolation) {
+	c.logger.Printf("[DAP VIOLATION] %v", v)
+	c.violations.add(v)
+	if v.Kind != "response" || v.RequestSeq == 0 {
+		return
+	}
+	c.dispatchResponse(v.RequestSeq, &dap.ErrorResponse{
+		Response: dap.Response{
+			ProtocolMessage: dap.ProtocolMessage{Type: "response"},
+			Command:         v.Name,
+			RequestSeq:      v.RequestSeq,
+			Message:         v.Error(),
+		},
+	})
+}
+
+// ProtocolViolations returns the most recent malformed messages received
+// from the adapter, oldest first
+func (c *Client) ProtocolViolations() []ProtocolViolation {
+	return c.violations.snapshot()
+}
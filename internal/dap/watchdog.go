@@ -0,0 +1,143 @@
+package dap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultLaunchStallTimeout is how long a launch may go without any response
+// from the adapter before the watchdog probes it. Godot answers launch and
+// configurationDone within a second or two even for large projects; the
+// game's own startup isn't waited for.
+const defaultLaunchStallTimeout = 10 * time.Second
+
+// stallProbeTimeout bounds the threads request that probes a stalled adapter
+const stallProbeTimeout = 2 * time.Second
+
+// LaunchStallError is returned by LaunchWithConfigurationDone and
+// LaunchAfterBreakpoints when the adapter sends no response for the launch
+// stall timeout (WithLaunchStallTimeout) while the connection stays up: the
+// signature of Godot's launch deadlock, where each side waits for the other.
+// The watchdog probes the adapter with a threads request to tell a stuck
+// launch sequence from an adapter that stopped answering altogether.
+type LaunchStallError struct {
+	Command       string        // Request that started the sequence ("launch")
+	Waited        time.Duration // Time without a response
+	Pending       []string      // Commands still waiting for a response, oldest first
+	ProbeAnswered bool          // The adapter answered the threads probe
+	Diagnosis     string        // What the probe suggests is wrong
+	Suggestions   []string      // Workarounds, most likely first
+}
+
+func (e *LaunchStallError) Error() string {
+	return fmt.Sprintf("%s stalled: no response from the debug adapter for %v (waiting for %s): %s",
+		e.Command, e.Waited.Round(time.Second), strings.Join(e.Pending, ", "), e.Diagnosis)
+}
+
+// launchWatchdog fails a launch sequence that stalls; see watchLaunch
+type launchWatchdog struct {
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	stall  *LaunchStallError // Set before done is closed if the launch stalled
+}
+
+// watchLaunch starts a watchdog over a launch sequence run with the returned
+// context. If no response arrives for the launch stall timeout while the
+// connection is up, the watchdog probes the adapter, records its diagnosis,
+// and cancels the context. The caller must call finish when the sequence
+// returns.
+func (c *Client) watchLaunch(ctx context.Context, command string) (context.Context, *launchWatchdog) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &launchWatchdog{cancel: cancel, stop: make(chan struct{}), done: make(chan struct{})}
+	if c.launchStallTimeout <= 0 {
+		close(w.done)
+		return ctx, w
+	}
+	go c.runLaunchWatchdog(w, command, time.Now())
+	return ctx, w
+}
+
+// finish stops the watchdog and returns its diagnosis if it cancelled the
+// launch
+func (w *launchWatchdog) finish() *LaunchStallError {
+	close(w.stop)
+	<-w.done
+	w.cancel()
+	return w.stall
+}
+
+// runLaunchWatchdog checks for responses until the launch finishes or stalls
+func (c *Client) runLaunchWatchdog(w *launchWatchdog, command string, started time.Time) {
+	defer close(w.done)
+	ticker := time.NewTicker(c.launchStallTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			last := c.requests.lastAnswered()
+			if last.Before(started) {
+				last = started
+			}
+			waited := now.Sub(last)
+			// A dropped connection fails the launch by itself
+			if waited < c.launchStallTimeout || !c.connected {
+				continue
+			}
+			w.stall = c.diagnoseLaunchStall(command, waited)
+			c.logger.Printf("Launch watchdog: %v", w.stall)
+			c.timeline.mark(MilestoneStalled, strings.Join(w.stall.Pending, ", "))
+			w.cancel()
+			return
+		}
+	}
+}
+
+// diagnoseLaunchStall probes a stalled adapter with a threads request and
+// explains the stall
+func (c *Client) diagnoseLaunchStall(command string, waited time.Duration) *LaunchStallError {
+	stall := &LaunchStallError{Command: command, Waited: waited, Pending: c.requests.pendingCommands()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stallProbeTimeout)
+	defer cancel()
+	// Any response, even an error, shows the adapter is still reading requests
+	_, err := c.Threads(ctx)
+	stall.ProbeAnswered = err == nil || ctx.Err() == nil
+
+	switch {
+	case !stall.ProbeAnswered:
+		stall.Diagnosis = "the adapter doesn't answer a threads request either, so the editor's debug server is blocked or frozen"
+		stall.Suggestions = []string{
+			"Bring the Godot editor to the front and dismiss any modal dialog (unsaved changes, reimport, script errors)",
+			"Restart the Godot editor if it doesn't respond",
+			"Then reconnect and launch again",
+		}
+	case !c.quirks.LaunchResponseAfterConfigurationDone:
+		stall.Diagnosis = "the adapter answers other requests but not the launch; Godot answers launch only after configurationDone, which the client holds until launch is answered"
+		stall.Suggestions = []string{
+			"Connect with a Godot adapter profile, which enables Godot's quirks (the default unless the generic profile was chosen)",
+			"Reconnect and launch again",
+		}
+	default:
+		stall.Diagnosis = "the adapter answers other requests but not the launch sequence, so the editor couldn't start the game"
+		stall.Suggestions = []string{
+			"Check the editor for a dialog blocking the run (unsaved scene, export or build prompt) and dismiss it",
+			"Check the editor's Output panel for errors starting the project",
+			"Reconnect and launch again",
+		}
+	}
+	if !stall.ProbeAnswered {
+		return stall
+	}
+	for _, pending := range stall.Pending {
+		if pending == "setBreakpoints" {
+			stall.Suggestions = append(stall.Suggestions, "A setBreakpoints request is unanswered too; launch without waiting for breakpoints to be acknowledged")
+			break
+		}
+	}
+	return stall
+}
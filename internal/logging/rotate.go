@@ -0,0 +1,126 @@
+// Package logging provides a size-capped, rotating log file for the server.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// DefaultMaxSize is the size at which the log file is rotated
+	DefaultMaxSize = 10 * 1024 * 1024
+
+	// DefaultMaxBackups is how many rotated files are kept (path.1 ... path.N)
+	DefaultMaxBackups = 3
+)
+
+// RotatingFile is an io.WriteCloser that appends to a log file and rotates
+// it once it would grow past MaxSize. Rotated files are renamed path.1
+// (newest) through path.N (oldest); older ones are deleted.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens (or creates) the log file at path.
+// Non-positive maxSize or negative maxBackups use the defaults.
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups < 0 {
+		maxBackups = DefaultMaxBackups
+	}
+
+	rf := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open opens the current log file for appending and records its size
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would push the file past the cap.
+// A single write larger than the cap still goes to a fresh file intact.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// rotate shifts existing backups up by one and starts a new file.
+// Caller must hold rf.mu.
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	rf.file = nil
+
+	if rf.maxBackups == 0 {
+		if err := os.Remove(rf.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return rf.open()
+	}
+
+	os.Remove(rf.backupPath(rf.maxBackups))
+	for i := rf.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(rf.backupPath(i), rf.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(rf.path, rf.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return rf.open()
+}
+
+// backupPath returns the name of the i-th rotated file
+func (rf *RotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", rf.path, i)
+}
+
+// Close closes the current log file
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
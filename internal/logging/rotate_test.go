@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	rf, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer rf.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+
+	if got := read(path); got != "fourth\n" {
+		t.Errorf("current file = %q", got)
+	}
+	if got := read(path + ".1"); got != "third\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+	if got := read(path + ".2"); got != "second\n" {
+		t.Errorf("backup 2 = %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("only maxBackups rotated files should be kept")
+	}
+}
+
+func TestRotatingFile_AppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rf, err := OpenRotatingFile(path, 12, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	rf.Write([]byte("new\n"))
+	rf.Close()
+
+	// The existing size counts towards the cap, so this write rotated
+	data, _ := os.ReadFile(path + ".1")
+	if !strings.Contains(string(data), "existing") {
+		t.Errorf("existing content should have been rotated, got %q", data)
+	}
+
+	if _, err := rf.Write([]byte("x")); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
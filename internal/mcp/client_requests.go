@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultClientRequestTimeout bounds how long the server waits for the client
+// to answer a server-initiated request
+const DefaultClientRequestTimeout = 10 * time.Second
+
+// Root is a workspace root advertised by the MCP client (roots capability)
+type Root struct {
+	URI  string `json:"uri"`            // Usually a file:// URI
+	Name string `json:"name,omitempty"` // Optional human-readable name
+}
+
+// RootsHandler is called with the client's workspace roots each time they are fetched
+type RootsHandler func(roots []Root)
+
+// OnRootsChanged registers a handler that receives the client's workspace roots.
+// Roots are fetched after the client sends notifications/initialized and again
+// on every notifications/roots/list_changed, if the client supports roots.
+func (s *Server) OnRootsChanged(handler RootsHandler) {
+	s.rootsMu.Lock()
+	defer s.rootsMu.Unlock()
+	s.rootsHandler = handler
+}
+
+// ClientSupportsRoots reports whether the client declared the roots capability
+func (s *Server) ClientSupportsRoots() bool {
+	s.capabilitiesMu.RLock()
+	defer s.capabilitiesMu.RUnlock()
+	_, ok := s.clientCapabilities["roots"]
+	return ok
+}
+
+// ListRoots asks the client for its workspace roots (roots/list)
+func (s *Server) ListRoots(ctx context.Context) ([]Root, error) {
+	if !s.ClientSupportsRoots() {
+		return nil, fmt.Errorf("client does not support roots")
+	}
+
+	result, err := s.requestClient(ctx, "roots/list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Roots []Root `json:"roots"`
+	}
+	if err := json.Unmarshal(result, &body); err != nil {
+		return nil, fmt.Errorf("invalid roots/list result: %w", err)
+	}
+	return body.Roots, nil
+}
+
+// refreshRoots fetches the client's roots and passes them to the roots handler.
+// Does nothing if no handler is registered or the client doesn't support roots.
+func (s *Server) refreshRoots() {
+	s.rootsMu.RLock()
+	handler := s.rootsHandler
+	s.rootsMu.RUnlock()
+
+	if handler == nil || !s.ClientSupportsRoots() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultClientRequestTimeout)
+	defer cancel()
+
+	roots, err := s.ListRoots(ctx)
+	if err != nil {
+		log.Printf("Failed to list client roots: %v", err)
+		return
+	}
+
+	log.Printf("Client reported %d root(s)", len(roots))
+	handler(roots)
+}
+
+// requestClient sends a request to the client and waits for its response.
+// The response arrives through the normal read loop and is routed back by id.
+func (s *Server) requestClient(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	ch := make(chan *MCPRequest, 1)
+
+	s.pendingOutgoingMu.Lock()
+	s.nextOutgoingID++
+	id := s.nextOutgoingID
+	s.pendingOutgoing[id] = ch
+	s.pendingOutgoingMu.Unlock()
+
+	// Ensure cleanup
+	defer func() {
+		s.pendingOutgoingMu.Lock()
+		delete(s.pendingOutgoing, id)
+		s.pendingOutgoingMu.Unlock()
+	}()
+
+	var reqID interface{} = id
+	if err := s.transport.WriteRequest(MCPRequest{
+		ID:     &reqID,
+		Method: method,
+		Params: params,
+	}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("client error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for client response to %s: %w", method, ctx.Err())
+	}
+}
+
+// handleClientResponse routes a client response to the waiting server-initiated request
+func (s *Server) handleClientResponse(resp *MCPRequest) {
+	// JSON numbers decode as float64; the server only issues integer ids
+	idFloat, ok := (*resp.ID).(float64)
+	if !ok {
+		log.Printf("Ignoring client response with unexpected id: %v", *resp.ID)
+		return
+	}
+	id := int(idFloat)
+
+	s.pendingOutgoingMu.Lock()
+	ch, ok := s.pendingOutgoing[id]
+	if ok {
+		delete(s.pendingOutgoing, id)
+	}
+	s.pendingOutgoingMu.Unlock()
+
+	if !ok {
+		log.Printf("Received client response for unknown/timed-out request id %d", id)
+		return
+	}
+	ch <- resp
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestServer_RootsFlow verifies that the server asks for roots after the client
+// initializes and hands the client's answer to the roots handler
+func TestServer_RootsFlow(t *testing.T) {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	server := NewServerWithTransport(NewTransportWithStreams(inReader, outWriter))
+
+	rootsCh := make(chan []Root, 1)
+	server.OnRootsChanged(func(roots []Root) {
+		rootsCh <- roots
+	})
+
+	go server.ListenAndServe()
+	defer inWriter.Close()
+
+	encoder := json.NewEncoder(inWriter)
+	decoder := json.NewDecoder(outReader)
+
+	// 1. Initialize with roots capability
+	if err := encoder.Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]interface{}{
+			"capabilities": map[string]interface{}{"roots": map[string]interface{}{"listChanged": true}},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to send initialize: %v", err)
+	}
+	var initResp map[string]interface{}
+	if err := decoder.Decode(&initResp); err != nil {
+		t.Fatalf("Failed to read initialize response: %v", err)
+	}
+
+	// 2. Initialized notification triggers roots/list
+	if err := encoder.Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "notifications/initialized",
+	}); err != nil {
+		t.Fatalf("Failed to send initialized: %v", err)
+	}
+
+	var rootsReq map[string]interface{}
+	if err := decoder.Decode(&rootsReq); err != nil {
+		t.Fatalf("Failed to read roots/list request: %v", err)
+	}
+	if rootsReq["method"] != "roots/list" {
+		t.Fatalf("Expected roots/list request, got %v", rootsReq)
+	}
+
+	// 3. Client answers
+	if err := encoder.Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      rootsReq["id"],
+		"result": map[string]interface{}{
+			"roots": []map[string]interface{}{{"uri": "file:///home/dev/game", "name": "game"}},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to send roots response: %v", err)
+	}
+
+	select {
+	case roots := <-rootsCh:
+		if len(roots) != 1 || roots[0].URI != "file:///home/dev/game" || roots[0].Name != "game" {
+			t.Errorf("Unexpected roots: %+v", roots)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for roots handler")
+	}
+}
+
+// TestServer_RootsUnsupported verifies that roots are not requested from
+// clients that don't declare the capability
+func TestServer_RootsUnsupported(t *testing.T) {
+	server := NewServer()
+
+	called := false
+	server.OnRootsChanged(func(roots []Root) {
+		called = true
+	})
+
+	server.handleRequest(&MCPRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+
+	if called {
+		t.Error("Roots handler should not be called when client lacks roots capability")
+	}
+	if server.ClientSupportsRoots() {
+		t.Error("ClientSupportsRoots should be false before initialize")
+	}
+}
@@ -0,0 +1,329 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Directions of recorded messages
+const (
+	FromClient = "client"
+	FromServer = "server"
+)
+
+// RecordedMessage is one line of a session recording
+type RecordedMessage struct {
+	OffsetMs int64           `json:"t_ms"` // Milliseconds since recording started
+	From     string          `json:"from"` // FromClient or FromServer
+	Message  json.RawMessage `json:"message"`
+}
+
+// Recorder captures every message between the MCP client and the server as
+// JSON lines, for replaying a session later with Replay.
+// Safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, start: time.Now()}
+}
+
+// record writes one message. Lines that aren't valid JSON (malformed client
+// input) are kept as JSON strings so the recording stays parseable.
+func (r *Recorder) record(from string, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	message := json.RawMessage(line)
+	if !json.Valid(line) {
+		quoted, _ := json.Marshal(string(line))
+		message = quoted
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.Marshal(RecordedMessage{
+		OffsetMs: time.Since(r.start).Milliseconds(),
+		From:     from,
+		Message:  message,
+	})
+	if err != nil {
+		return
+	}
+	r.w.Write(append(data, '\n'))
+}
+
+// WrapReader returns a reader that records each line the client sends
+func (r *Recorder) WrapReader(in io.Reader) io.Reader {
+	return &lineTap{reader: in, emit: func(line []byte) { r.record(FromClient, line) }}
+}
+
+// WrapWriter returns a writer that records each line the server sends
+func (r *Recorder) WrapWriter(out io.Writer) io.Writer {
+	return &lineTap{writer: out, emit: func(line []byte) { r.record(FromServer, line) }}
+}
+
+// lineTap passes data through and calls emit for every complete line
+type lineTap struct {
+	reader  io.Reader
+	writer  io.Writer
+	mu      sync.Mutex
+	pending []byte
+	emit    func(line []byte)
+}
+
+func (t *lineTap) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.tap(p[:n])
+	if err == io.EOF {
+		t.flush()
+	}
+	return n, err
+}
+
+// Write records before passing data on, so a line is in the recording by
+// the time the client can see it
+func (t *lineTap) Write(p []byte) (int, error) {
+	t.tap(p)
+	return t.writer.Write(p)
+}
+
+func (t *lineTap) tap(data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, data...)
+	for {
+		i := bytes.IndexByte(t.pending, '\n')
+		if i < 0 {
+			return
+		}
+		t.emit(t.pending[:i])
+		t.pending = t.pending[i+1:]
+	}
+}
+
+// flush emits a final line without a trailing newline
+func (t *lineTap) flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) > 0 {
+		t.emit(t.pending)
+		t.pending = nil
+	}
+}
+
+// ReadRecording parses a recording written by a Recorder
+func ReadRecording(r io.Reader) ([]RecordedMessage, error) {
+	var messages []RecordedMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxRequestSize+1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg RecordedMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return nil, fmt.Errorf("recording line %d: %w", lineNo, err)
+		}
+		if msg.From != FromClient && msg.From != FromServer {
+			return nil, fmt.Errorf("recording line %d: unknown direction %q", lineNo, msg.From)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, scanner.Err()
+}
+
+// ReplayExchange pairs a client request from a recording with the response
+// recorded for it and the response the replayed server sent
+type ReplayExchange struct {
+	Request  json.RawMessage
+	Recorded json.RawMessage // nil if the recording has no response
+	Replayed json.RawMessage // nil if the server didn't answer in time
+}
+
+// ReplayOptions adjusts a replay
+type ReplayOptions struct {
+	// Rewrite, if set, may modify each client message before it is sent,
+	// e.g. to point godot_connect at a mock DAP server's port
+	Rewrite func(message map[string]interface{})
+
+	// Timeout bounds the wait for each response (default: 10s)
+	Timeout time.Duration
+}
+
+// replayKey pairs client requests with server responses. For a client
+// request it is the id; for a server response, the id it answers. Batches
+// (arrays) use "batch" and are answered by the next array. Other messages
+// (notifications, server requests, responses to server requests) get "".
+func replayKey(message json.RawMessage, from string) string {
+	trimmed := bytes.TrimSpace(message)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return "batch"
+	}
+	var m struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if json.Unmarshal(message, &m) != nil || len(m.ID) == 0 || string(m.ID) == "null" {
+		return ""
+	}
+	if (from == FromClient) != (m.Method != "") {
+		return ""
+	}
+	return string(m.ID)
+}
+
+// Replay feeds the client messages of a recording to a fresh server, one at
+// a time, waiting for each response before sending the next message so the
+// run is deterministic. register sets up the server's tools.
+func Replay(recording []RecordedMessage, register func(*Server), opts ReplayOptions) ([]ReplayExchange, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	in, clientWriter := io.Pipe()
+	out := make(chan json.RawMessage, 1024)
+	server := NewServerWithTransport(NewTransportWithStreams(in, &lineTap{
+		writer: io.Discard,
+		emit: func(line []byte) {
+			out <- append(json.RawMessage(nil), line...)
+		},
+	}))
+	register(server)
+
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe() }()
+	defer func() {
+		clientWriter.Close()
+		<-done
+	}()
+
+	var exchanges []ReplayExchange
+	for i, msg := range recording {
+		if msg.From != FromClient {
+			continue
+		}
+
+		message := msg.Message
+		if opts.Rewrite != nil {
+			var decoded map[string]interface{}
+			if json.Unmarshal(message, &decoded) == nil {
+				opts.Rewrite(decoded)
+				if rewritten, err := json.Marshal(decoded); err == nil {
+					message = rewritten
+				}
+			}
+		}
+		if _, err := clientWriter.Write(append(append([]byte(nil), message...), '\n')); err != nil {
+			return exchanges, fmt.Errorf("writing message %d: %w", i, err)
+		}
+
+		key := replayKey(msg.Message, FromClient)
+		if key == "" {
+			continue
+		}
+
+		exchange := ReplayExchange{Request: message}
+		for _, later := range recording[i+1:] {
+			if later.From == FromServer && replayKey(later.Message, FromServer) == key {
+				exchange.Recorded = later.Message
+				break
+			}
+		}
+
+		timeout := time.After(opts.Timeout)
+	wait:
+		for {
+			select {
+			case line := <-out:
+				if replayKey(line, FromServer) == key {
+					exchange.Replayed = line
+					break wait
+				}
+			case <-timeout:
+				break wait
+			}
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, nil
+}
+
+// normalizeReplayValue decodes tool results embedded as JSON text and drops
+// volatile keys, so responses can be compared structurally
+func normalizeReplayValue(v interface{}, ignore map[string]bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if ignore[k] {
+				continue
+			}
+			if k == "text" {
+				if text, ok := item.(string); ok {
+					var decoded interface{}
+					if json.Unmarshal([]byte(text), &decoded) == nil {
+						if _, scalar := decoded.(string); !scalar {
+							item = decoded
+						}
+					}
+				}
+			}
+			result[k] = normalizeReplayValue(item, ignore)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			result[i] = normalizeReplayValue(item, ignore)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// Diff compares the recorded and replayed responses, ignoring the given
+// keys at any depth (e.g. timestamps and durations). Tool results whose text
+// is JSON are compared as JSON. Returns "" if they match.
+func (e ReplayExchange) Diff(ignoreKeys ...string) string {
+	if e.Replayed == nil {
+		return fmt.Sprintf("no response to %s", e.Request)
+	}
+	if e.Recorded == nil {
+		return fmt.Sprintf("recording has no response to %s", e.Request)
+	}
+
+	ignore := make(map[string]bool, len(ignoreKeys))
+	for _, k := range ignoreKeys {
+		ignore[k] = true
+	}
+	var recorded, replayed interface{}
+	if err := json.Unmarshal(e.Recorded, &recorded); err != nil {
+		return fmt.Sprintf("invalid recorded response: %v", err)
+	}
+	if err := json.Unmarshal(e.Replayed, &replayed); err != nil {
+		return fmt.Sprintf("invalid replayed response: %v", err)
+	}
+	recorded = normalizeReplayValue(recorded, ignore)
+	replayed = normalizeReplayValue(replayed, ignore)
+	if reflect.DeepEqual(recorded, replayed) {
+		return ""
+	}
+
+	want, _ := json.Marshal(recorded)
+	got, _ := json.Marshal(replayed)
+	return fmt.Sprintf("response to %s differs:\nrecorded: %s\nreplayed: %s", e.Request, want, got)
+}
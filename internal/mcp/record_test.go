@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// registerCounter registers a tool whose result depends on its arguments and
+// a call count, so replays can tell a changed server from an unchanged one
+func registerCounter(step int) func(*Server) {
+	return func(server *Server) {
+		calls := 0
+		server.RegisterTool(Tool{
+			Name:       "count",
+			Parameters: []Parameter{{Name: "label", Type: "string", Required: true}},
+			Handler: func(params map[string]interface{}) (interface{}, error) {
+				calls += step
+				return map[string]interface{}{
+					"label": params["label"],
+					"calls": calls,
+					"time":  time.Now().UnixNano(),
+				}, nil
+			},
+		})
+	}
+}
+
+// responseCounter signals each line the server writes
+type responseCounter chan struct{}
+
+func (c responseCounter) Write(p []byte) (int, error) {
+	for range bytes.Count(p, []byte("\n")) {
+		c <- struct{}{}
+	}
+	return len(p), nil
+}
+
+// recordSession runs the client lines through a recorded server, waiting
+// for the given number of responses before ending the session
+func recordSession(t *testing.T, responses int, lines ...string) []RecordedMessage {
+	t.Helper()
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	in, client := io.Pipe()
+	written := make(responseCounter, responses)
+	server := NewServerWithTransport(NewTransportWithStreams(rec.WrapReader(in), rec.WrapWriter(written)))
+	registerCounter(1)(server)
+
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe() }()
+	for _, line := range lines {
+		io.WriteString(client, line+"\n")
+	}
+	for i := 0; i < responses; i++ {
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for response %d", i+1)
+		}
+	}
+	client.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("ListenAndServe failed: %v", err)
+	}
+
+	recording, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("ReadRecording failed: %v", err)
+	}
+	return recording
+}
+
+var sessionLines = []string{
+	`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+	`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"count","arguments":{"label":"a"}}}`,
+}
+
+func TestRecorder_CapturesBothDirections(t *testing.T) {
+	recording := recordSession(t, 2, sessionLines...)
+
+	var fromClient, fromServer int
+	for _, msg := range recording {
+		switch msg.From {
+		case FromClient:
+			fromClient++
+		case FromServer:
+			fromServer++
+		}
+	}
+	if fromClient != 3 {
+		t.Errorf("expected 3 client messages, got %d", fromClient)
+	}
+	if fromServer != 2 {
+		t.Errorf("expected 2 server responses, got %d", fromServer)
+	}
+	if string(recording[0].Message) != sessionLines[0] {
+		t.Errorf("first message should be recorded verbatim, got %s", recording[0].Message)
+	}
+}
+
+func TestRecorder_KeepsMalformedInput(t *testing.T) {
+	recording := recordSession(t, 1, `not json`)
+	if len(recording) == 0 {
+		t.Fatal("expected the malformed line to be recorded")
+	}
+	var text string
+	if err := json.Unmarshal(recording[0].Message, &text); err != nil || text != "not json" {
+		t.Errorf("malformed input should be kept as a JSON string, got %s", recording[0].Message)
+	}
+}
+
+func TestReadRecording_Invalid(t *testing.T) {
+	if _, err := ReadRecording(strings.NewReader("{oops\n")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if _, err := ReadRecording(strings.NewReader(`{"t_ms":0,"from":"nobody","message":{}}`)); err == nil {
+		t.Error("expected an error for an unknown direction")
+	}
+}
+
+func TestReplay_Matches(t *testing.T) {
+	recording := recordSession(t, 2, sessionLines...)
+
+	exchanges, err := Replay(recording, registerCounter(1), ReplayOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(exchanges) != 2 {
+		t.Fatalf("expected 2 exchanges (initialize, tools/call), got %d", len(exchanges))
+	}
+	for _, e := range exchanges {
+		if diff := e.Diff("time"); diff != "" {
+			t.Error(diff)
+		}
+	}
+
+	// Without ignoring the timestamp the tool result differs
+	if exchanges[1].Diff() == "" {
+		t.Error("expected the timestamp to differ")
+	}
+}
+
+func TestReplay_DetectsChanges(t *testing.T) {
+	recording := recordSession(t, 2, sessionLines...)
+
+	exchanges, err := Replay(recording, registerCounter(2), ReplayOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if diff := exchanges[1].Diff("time"); !strings.Contains(diff, `"calls":2`) {
+		t.Errorf("expected the changed result in the diff, got %q", diff)
+	}
+}
+
+func TestReplay_Rewrite(t *testing.T) {
+	recording := recordSession(t, 2, sessionLines...)
+
+	exchanges, err := Replay(recording, registerCounter(1), ReplayOptions{
+		Timeout: time.Second,
+		Rewrite: func(message map[string]interface{}) {
+			if params, ok := message["params"].(map[string]interface{}); ok {
+				if args, ok := params["arguments"].(map[string]interface{}); ok {
+					args["label"] = "b"
+				}
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if !strings.Contains(string(exchanges[1].Request), `"label":"b"`) {
+		t.Errorf("expected the rewritten request, got %s", exchanges[1].Request)
+	}
+	if diff := exchanges[1].Diff("time"); !strings.Contains(diff, `"label":"b"`) {
+		t.Errorf("expected the rewritten label in the diff, got %q", diff)
+	}
+}
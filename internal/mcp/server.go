@@ -2,46 +2,161 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 )
 
+// NotificationHandler handles a client notification (a request without an id).
+// Notifications are never answered, so handlers have no return value.
+type NotificationHandler func(params map[string]interface{})
+
 // Server is the core MCP server that handles tool registration and request routing
 type Server struct {
 	transport *Transport
 	tools     map[string]Tool
+
+	notificationHandlers map[string]NotificationHandler
+	notificationMu       sync.RWMutex
+
+	// Capabilities the client declared in initialize
+	clientCapabilities map[string]interface{}
+	capabilitiesMu     sync.RWMutex
+
+	// Server-initiated requests awaiting a client response (id -> channel)
+	nextOutgoingID    int
+	pendingOutgoing   map[int]chan *MCPRequest
+	pendingOutgoingMu sync.Mutex
+
+	// Called whenever the client's workspace roots are (re)fetched
+	rootsHandler RootsHandler
+	rootsMu      sync.RWMutex
+
+	// Rewrites tool call results and errors sent to the client
+	textFilter TextFilter
+
+	// Runs around every tool call
+	callHook CallHook
+
+	// Lowest severity of log notifications sent, set by logging/setLevel
+	// (index into logLevels; 0 sends everything)
+	minLogLevel int
+	logLevelMu  sync.RWMutex
 }
 
+// logLevels are the log notification severities (RFC 5424), least severe first
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// TextFilter rewrites the text of a tool call's result or error message
+// (isError) before it is sent to the client, e.g. to shorten paths
+type TextFilter func(tool string, text string, isError bool) string
+
+// CallHook is called when a tool call starts; the function it returns is
+// called when the call ends, e.g. to know which calls are running
+type CallHook func(tool string) (end func())
+
 // NewServer creates a new MCP server with default stdio transport
 func NewServer() *Server {
-	return &Server{
-		transport: NewTransport(),
-		tools:     make(map[string]Tool),
-	}
+	return NewServerWithTransport(NewTransport())
 }
 
 // NewServerWithTransport creates a new MCP server with custom transport (for testing)
 func NewServerWithTransport(transport *Transport) *Server {
-	return &Server{
-		transport: transport,
-		tools:     make(map[string]Tool),
+	s := &Server{
+		transport:            transport,
+		tools:                make(map[string]Tool),
+		notificationHandlers: make(map[string]NotificationHandler),
+		pendingOutgoing:      make(map[int]chan *MCPRequest),
 	}
+	s.registerDefaultNotificationHandlers()
+	return s
 }
 
-// RegisterTool registers a new tool with the server
+// RegisterTool registers a new tool with the server. It panics if a
+// parameter's Pattern isn't a valid regular expression, so a bad pattern
+// fails at startup instead of on every call.
 func (s *Server) RegisterTool(tool Tool) {
+	parameters, err := compilePatterns(tool.Parameters)
+	if err != nil {
+		panic(fmt.Sprintf("tool %s: %v", tool.Name, err))
+	}
+	tool.Parameters = parameters
 	s.tools[tool.Name] = tool
 	log.Printf("Registered tool: %s", tool.Name)
 }
 
+// compilePatterns returns a copy of params with their patterns compiled,
+// including those of array items and object properties
+func compilePatterns(params []Parameter) ([]Parameter, error) {
+	if params == nil {
+		return nil, nil
+	}
+	compiled := make([]Parameter, len(params))
+	for i, param := range params {
+		if param.Pattern != "" {
+			re, err := regexp.Compile(param.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q for parameter %s: %w", param.Pattern, param.Name, err)
+			}
+			param.pattern = re
+		}
+		if param.Items != nil {
+			items, err := compilePatterns([]Parameter{*param.Items})
+			if err != nil {
+				return nil, err
+			}
+			param.Items = &items[0]
+		}
+		properties, err := compilePatterns(param.Properties)
+		if err != nil {
+			return nil, err
+		}
+		param.Properties = properties
+		compiled[i] = param
+	}
+	return compiled, nil
+}
+
+// SetTextFilter installs filter for the results and errors of tools/call.
+// CallTool returns results unfiltered.
+func (s *Server) SetTextFilter(filter TextFilter) {
+	s.textFilter = filter
+}
+
+// filterText applies the text filter, if any
+func (s *Server) filterText(tool string, text string, isError bool) string {
+	if s.textFilter == nil {
+		return text
+	}
+	return s.textFilter(tool, text, isError)
+}
+
+// SetCallHook installs hook around every tools/call and CallTool
+func (s *Server) SetCallHook(hook CallHook) {
+	s.callHook = hook
+}
+
+// runTool runs a tool's handler within the call hook, if any
+func (s *Server) runTool(tool Tool, params map[string]interface{}) (interface{}, error) {
+	if s.callHook != nil {
+		defer s.callHook(tool.Name)()
+	}
+	return tool.Handler(params)
+}
+
 // ListenAndServe starts the server and processes requests until EOF or error
 func (s *Server) ListenAndServe() error {
 	log.Println("MCP server started, listening on stdin...")
 
 	for {
-		// Read next request
-		req, err := s.transport.ReadRequest()
+		// Read next frame (single request or batch)
+		frame, err := s.transport.ReadFrame()
 		if err != nil {
 			if err == io.EOF {
 				// Clean shutdown
@@ -50,6 +165,25 @@ func (s *Server) ListenAndServe() error {
 			}
 			// Log error but continue (send error response if possible)
 			log.Printf("Error reading request: %v", err)
+
+			// Malformed frames get a JSON-RPC parse error with a null id
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				if err := s.transport.WriteError(nil, -32700, fmt.Sprintf("parse error: %v", parseErr.Err)); err != nil {
+					log.Printf("Error writing response: %v", err)
+				}
+			}
+			continue
+		}
+
+		if frame.Batch {
+			go s.handleBatch(frame)
+			continue
+		}
+
+		// Responses to server-initiated requests are routed to their waiter
+		if frame.Requests[0].isResponse() {
+			s.handleClientResponse(frame.Requests[0])
 			continue
 		}
 
@@ -67,20 +201,102 @@ func (s *Server) ListenAndServe() error {
 			if err := s.transport.WriteResponse(resp); err != nil {
 				log.Printf("Error writing response: %v", err)
 			}
-		}(req)
+		}(frame.Requests[0])
+	}
+}
+
+// handleBatch processes a JSON-RPC batch. Requests run concurrently and the
+// responses are written back as a single array in request order.
+// Notifications produce no entry; if nothing needs answering, nothing is written.
+func (s *Server) handleBatch(frame *Frame) {
+	// An empty batch is itself an invalid request (single response, not an array)
+	if len(frame.Requests) == 0 && len(frame.Invalid) == 0 {
+		if err := s.transport.WriteResponse(s.errorResponse(nil, -32600, "invalid request: empty batch")); err != nil {
+			log.Printf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	resps := make([]MCPResponse, len(frame.Requests))
+	var wg sync.WaitGroup
+	for i, req := range frame.Requests {
+		if req.isResponse() {
+			s.handleClientResponse(req)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, r *MCPRequest) {
+			defer wg.Done()
+			resps[i] = s.handleRequest(r)
+		}(i, req)
+	}
+	wg.Wait()
+
+	batch := make([]MCPResponse, 0, len(resps)+len(frame.Invalid))
+	for i, req := range frame.Requests {
+		if req.ID != nil && !req.isResponse() {
+			batch = append(batch, resps[i])
+		}
+	}
+	for _, err := range frame.Invalid {
+		batch = append(batch, s.errorResponse(nil, -32600, fmt.Sprintf("invalid request: %v", err)))
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+	if err := s.transport.WriteBatch(batch); err != nil {
+		log.Printf("Error writing batch response: %v", err)
+	}
+}
+
+// RegisterNotificationHandler registers a handler for a client notification method.
+// Registering the same method again replaces the previous handler.
+func (s *Server) RegisterNotificationHandler(method string, handler NotificationHandler) {
+	s.notificationMu.Lock()
+	defer s.notificationMu.Unlock()
+	s.notificationHandlers[method] = handler
+}
+
+// registerDefaultNotificationHandlers registers handlers for the standard MCP notifications
+func (s *Server) registerDefaultNotificationHandlers() {
+	s.RegisterNotificationHandler("notifications/initialized", func(params map[string]interface{}) {
+		log.Println("Client initialized notification received")
+		s.refreshRoots()
+	})
+
+	// Tool calls run to completion; cancellation is only logged for now
+	s.RegisterNotificationHandler("notifications/cancelled", func(params map[string]interface{}) {
+		log.Printf("Client cancelled request %v (reason: %v)", params["requestId"], params["reason"])
+	})
+
+	s.RegisterNotificationHandler("notifications/roots/list_changed", func(params map[string]interface{}) {
+		log.Println("Client roots list changed")
+		s.refreshRoots()
+	})
+}
+
+// handleNotification dispatches a notification to its registered handler.
+// Unknown notifications are logged and ignored, as JSON-RPC requires.
+func (s *Server) handleNotification(req *MCPRequest) {
+	s.notificationMu.RLock()
+	handler, ok := s.notificationHandlers[req.Method]
+	s.notificationMu.RUnlock()
+
+	if !ok {
+		log.Printf("Ignoring unknown notification: %s", req.Method)
+		return
 	}
+	handler(req.Params)
 }
 
 // handleRequest routes a request to the appropriate handler
 func (s *Server) handleRequest(req *MCPRequest) MCPResponse {
-	// Handle notifications that don't require a response
+	// Any request without an id is a notification and never gets a response.
+	// Return an empty response (which won't be sent).
 	if req.ID == nil {
-		switch req.Method {
-		case "notifications/initialized":
-			// Just log and return empty response (which won't be sent)
-			log.Println("Client initialized notification received")
-			return MCPResponse{}
-		}
+		s.handleNotification(req)
+		return MCPResponse{}
 	}
 
 	var id interface{}
@@ -95,11 +311,41 @@ func (s *Server) handleRequest(req *MCPRequest) MCPResponse {
 		return s.handleToolsCall(req)
 	case "initialize":
 		return s.handleInitialize(req)
+	case "logging/setLevel":
+		return s.handleSetLevel(req)
 	default:
 		return s.errorResponse(id, -32601, fmt.Sprintf("method not found: %s", req.Method))
 	}
 }
 
+// handleSetLevel handles logging/setLevel: later log notifications less
+// severe than the level are not sent
+func (s *Server) handleSetLevel(req *MCPRequest) MCPResponse {
+	var id interface{}
+	if req.ID != nil {
+		id = *req.ID
+	}
+
+	level, _ := req.Params["level"].(string)
+	index := slices.Index(logLevels, level)
+	if index < 0 {
+		return s.errorResponse(id, -32602, fmt.Sprintf("invalid log level %q: expected one of %s", level, strings.Join(logLevels, ", ")))
+	}
+	s.logLevelMu.Lock()
+	s.minLogLevel = index
+	s.logLevelMu.Unlock()
+	return s.successResponse(id, map[string]interface{}{})
+}
+
+// logged reports whether a log notification of level is sent at the level
+// the client set. Unknown levels are sent.
+func (s *Server) logged(level string) bool {
+	index := slices.Index(logLevels, level)
+	s.logLevelMu.RLock()
+	defer s.logLevelMu.RUnlock()
+	return index < 0 || index >= s.minLogLevel
+}
+
 // handleInitialize handles the initialize method (optional but good practice)
 func (s *Server) handleInitialize(req *MCPRequest) MCPResponse {
 	var id interface{}
@@ -107,11 +353,16 @@ func (s *Server) handleInitialize(req *MCPRequest) MCPResponse {
 		id = *req.ID
 	}
 
+	// Remember what the client supports (e.g. roots) for server-initiated requests
+	if caps, ok := req.Params["capabilities"].(map[string]interface{}); ok {
+		s.capabilitiesMu.Lock()
+		s.clientCapabilities = caps
+		s.capabilitiesMu.Unlock()
+	}
+
 	return s.successResponse(id, map[string]interface{}{
 		"protocolVersion": "2024-11-05",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+		"capabilities":    s.capabilities(),
 		"serverInfo": map[string]interface{}{
 			"name":    "godot-dap-mcp-server",
 			"version": "0.1.0",
@@ -119,21 +370,53 @@ func (s *Server) handleInitialize(req *MCPRequest) MCPResponse {
 	})
 }
 
-// handleToolsList handles the tools/list method
-func (s *Server) handleToolsList(req *MCPRequest) MCPResponse {
-	tools := make([]ToolMetadata, 0, len(s.tools))
+// capabilities describes what the server actually supports, for the
+// initialize response. Only features with handlers are advertised: there are
+// no resources or prompts, and the tool list is fixed once registration is
+// done, so tools never announce listChanged.
+func (s *Server) capabilities() map[string]interface{} {
+	caps := map[string]interface{}{
+		// notifications/message is sent, filtered by logging/setLevel
+		"logging": map[string]interface{}{},
+	}
+	if len(s.tools) > 0 {
+		caps["tools"] = map[string]interface{}{"listChanged": false}
+	}
+	return caps
+}
+
+// Notify sends a notification (e.g. notifications/message) to the client.
+// Log notifications less severe than the level set with logging/setLevel are
+// dropped. Safe to call from any goroutine; the transport serializes writes.
+func (s *Server) Notify(method string, params map[string]interface{}) error {
+	if level, _ := params["level"].(string); method == "notifications/message" && !s.logged(level) {
+		return nil
+	}
+	return s.transport.WriteNotification(MCPNotification{
+		Method: method,
+		Params: params,
+	})
+}
+
+// ToolCatalog returns the metadata (name, description, input schema) of
+// every registered tool, as served by tools/list. Tools are sorted by name so
+// the listing is stable across runs.
+func (s *Server) ToolCatalog() []ToolMetadata {
+	names := make([]string, 0, len(s.tools))
+	for name := range s.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	for _, tool := range s.tools {
+	tools := make([]ToolMetadata, 0, len(names))
+	for _, name := range names {
+		tool := s.tools[name]
 		// Build input schema from parameters
 		properties := make(map[string]PropertyDefinition)
 		required := []string{}
 
 		for _, param := range tool.Parameters {
-			properties[param.Name] = PropertyDefinition{
-				Type:        param.Type,
-				Description: param.Description,
-				Default:     param.Default,
-			}
+			properties[param.Name] = propertySchema(param)
 
 			if param.Required {
 				required = append(required, param.Name)
@@ -144,19 +427,57 @@ func (s *Server) handleToolsList(req *MCPRequest) MCPResponse {
 			Name:        tool.Name,
 			Description: tool.Description,
 			InputSchema: ToolInputSchema{
-				Type:       "object",
-				Properties: properties,
-				Required:   required,
+				Schema:               jsonSchemaDialect,
+				Type:                 "object",
+				Properties:           properties,
+				Required:             required,
+				AdditionalProperties: false,
 			},
 		})
 	}
 
+	return tools
+}
+
+// propertySchema builds the JSON schema for a parameter, recursing into
+// array items and object properties
+func propertySchema(param Parameter) PropertyDefinition {
+	def := PropertyDefinition{
+		Type:        param.Type,
+		Description: param.Description,
+		Default:     param.Default,
+		Enum:        param.Enum,
+		Minimum:     param.Minimum,
+		Maximum:     param.Maximum,
+		Pattern:     param.Pattern,
+	}
+
+	if param.Items != nil {
+		items := propertySchema(*param.Items)
+		def.Items = &items
+	}
+
+	if len(param.Properties) > 0 {
+		def.Properties = make(map[string]PropertyDefinition, len(param.Properties))
+		for _, field := range param.Properties {
+			def.Properties[field.Name] = propertySchema(field)
+			if field.Required {
+				def.Required = append(def.Required, field.Name)
+			}
+		}
+	}
+
+	return def
+}
+
+// handleToolsList handles the tools/list method
+func (s *Server) handleToolsList(req *MCPRequest) MCPResponse {
 	var id interface{}
 	if req.ID != nil {
 		id = *req.ID
 	}
 
-	return s.successResponse(id, ToolListResult{Tools: tools})
+	return s.successResponse(id, ToolListResult{Tools: s.ToolCatalog()})
 }
 
 // handleToolsCall handles the tools/call method
@@ -189,11 +510,14 @@ func (s *Server) handleToolsCall(req *MCPRequest) MCPResponse {
 	if err := s.validateRequired(tool, params); err != nil {
 		return s.errorResponse(id, -32602, err.Error())
 	}
+	if err := s.validateParams(tool, params); err != nil {
+		return s.errorResponse(id, -32602, err.Error())
+	}
 
 	// Call tool handler
-	result, err := tool.Handler(params)
+	result, err := s.runTool(tool, params)
 	if err != nil {
-		return s.errorResponse(id, -32000, fmt.Sprintf("tool execution failed: %v", err))
+		return s.errorResponse(id, -32000, s.filterText(name, fmt.Sprintf("tool execution failed: %v", err), true))
 	}
 
 	// Format result as tool call result
@@ -201,7 +525,7 @@ func (s *Server) handleToolsCall(req *MCPRequest) MCPResponse {
 		Content: []ContentBlock{
 			{
 				Type: "text",
-				Text: formatResult(result),
+				Text: s.filterText(name, formatResult(result), false),
 			},
 		},
 	}
@@ -209,6 +533,28 @@ func (s *Server) handleToolsCall(req *MCPRequest) MCPResponse {
 	return s.successResponse(id, toolResult)
 }
 
+// CallTool runs a registered tool directly, without an MCP client, applying
+// the same defaults and parameter validation as tools/call. Used by the
+// command-line debug mode.
+func (s *Server) CallTool(name string, arguments map[string]interface{}) (interface{}, error) {
+	tool, exists := s.tools[name]
+	if !exists {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	params := s.applyDefaults(tool, arguments)
+	if err := s.validateRequired(tool, params); err != nil {
+		return nil, err
+	}
+	if err := s.validateParams(tool, params); err != nil {
+		return nil, err
+	}
+	return s.runTool(tool, params)
+}
+
 // applyDefaults applies default values to parameters
 func (s *Server) applyDefaults(tool Tool, arguments map[string]interface{}) map[string]interface{} {
 	params := make(map[string]interface{})
@@ -240,6 +586,76 @@ func (s *Server) validateRequired(tool Tool, params map[string]interface{}) erro
 	return nil
 }
 
+// validateParams checks provided parameters against their declared enum,
+// bounds, pattern, and custom validator
+func (s *Server) validateParams(tool Tool, params map[string]interface{}) error {
+	for _, param := range tool.Parameters {
+		value, exists := params[param.Name]
+		if !exists {
+			continue
+		}
+		if err := validateParam(param, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", param.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateParam checks a single parameter value
+func validateParam(param Parameter, value interface{}) error {
+	if len(param.Enum) > 0 {
+		str, ok := value.(string)
+		if !ok || !slices.Contains(param.Enum, str) {
+			return fmt.Errorf("%v (must be one of: %s)", value, strings.Join(param.Enum, ", "))
+		}
+	}
+
+	if param.Minimum != nil || param.Maximum != nil {
+		num, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%v (must be a number)", value)
+		}
+		if param.Minimum != nil && num < *param.Minimum {
+			return fmt.Errorf("%v (must be at least %v)", value, *param.Minimum)
+		}
+		if param.Maximum != nil && num > *param.Maximum {
+			return fmt.Errorf("%v (must be at most %v)", value, *param.Maximum)
+		}
+	}
+
+	if param.pattern != nil {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v (must be a string)", value)
+		}
+		if !param.pattern.MatchString(str) {
+			return fmt.Errorf("%q (must match %s)", str, param.Pattern)
+		}
+	}
+
+	if param.Validate != nil {
+		return param.Validate(value)
+	}
+	return nil
+}
+
+// toFloat converts a numeric parameter to float64. Arguments decoded from
+// JSON are float64, but defaults declared in Go are often ints.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
 // formatResult converts a tool result to a string
 func formatResult(result interface{}) string {
 	switch v := result.(type) {
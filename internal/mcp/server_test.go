@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -81,6 +84,31 @@ func TestHandleInitialize(t *testing.T) {
 	}
 }
 
+func TestHandleInitialize_Capabilities(t *testing.T) {
+	server := NewServer()
+	req := &MCPRequest{JSONRPC: "2.0", ID: intPtr(1), Method: "initialize"}
+
+	caps := server.handleInitialize(req).Result.(map[string]interface{})["capabilities"].(map[string]interface{})
+	if _, ok := caps["tools"]; ok {
+		t.Error("tools capability should not be advertised without tools")
+	}
+	if _, ok := caps["logging"]; !ok {
+		t.Error("logging capability should always be advertised")
+	}
+
+	server.RegisterTool(Tool{Name: "test_tool"})
+	caps = server.handleInitialize(req).Result.(map[string]interface{})["capabilities"].(map[string]interface{})
+	tools, ok := caps["tools"].(map[string]interface{})
+	if !ok || tools["listChanged"] != false {
+		t.Errorf("Expected tools capability with listChanged=false, got %v", caps["tools"])
+	}
+	for _, unsupported := range []string{"resources", "prompts"} {
+		if _, ok := caps[unsupported]; ok {
+			t.Errorf("%s capability should not be advertised", unsupported)
+		}
+	}
+}
+
 // TestHandleToolsList_Empty verifies tools/list with no tools registered
 func TestHandleToolsList_Empty(t *testing.T) {
 	server := NewServer()
@@ -139,6 +167,199 @@ func TestServer_HandleToolsList(t *testing.T) {
 	}
 }
 
+func TestServer_ToolCatalog(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name:        "test_tool",
+		Description: "A test tool",
+		Parameters: []Parameter{
+			{Name: "file", Type: "string", Required: true, Description: "File path"},
+			{Name: "line", Type: "number", Default: 1, Description: "Line number"},
+		},
+	})
+
+	catalog := server.ToolCatalog()
+	if len(catalog) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(catalog))
+	}
+
+	schema := catalog[0].InputSchema
+	if len(schema.Properties) != 2 || schema.Properties["line"].Default != 1 {
+		t.Errorf("Unexpected properties: %+v", schema.Properties)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "file" {
+		t.Errorf("Expected required [file], got %v", schema.Required)
+	}
+}
+
+func TestServer_EnumParameter(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name: "test_tool",
+		Parameters: []Parameter{
+			{Name: "mode", Type: "string", Default: "fast", Enum: []string{"fast", "slow"}},
+		},
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			return params["mode"], nil
+		},
+	})
+
+	if enum := server.ToolCatalog()[0].InputSchema.Properties["mode"].Enum; len(enum) != 2 {
+		t.Errorf("Expected enum in schema, got %v", enum)
+	}
+
+	call := func(args map[string]interface{}) MCPResponse {
+		return server.handleRequest(&MCPRequest{
+			JSONRPC: "2.0",
+			ID:      intPtr(1),
+			Method:  "tools/call",
+			Params:  map[string]interface{}{"name": "test_tool", "arguments": args},
+		})
+	}
+
+	if resp := call(map[string]interface{}{"mode": "slow"}); resp.Error != nil {
+		t.Errorf("Expected allowed value to succeed, got %v", resp.Error)
+	}
+	if resp := call(map[string]interface{}{}); resp.Error != nil {
+		t.Errorf("Expected default value to succeed, got %v", resp.Error)
+	}
+	resp := call(map[string]interface{}{"mode": "medium"})
+	if resp.Error == nil || resp.Error.Code != -32602 || !strings.Contains(resp.Error.Message, "must be one of: fast, slow") {
+		t.Errorf("Expected -32602 for value outside enum, got %+v", resp.Error)
+	}
+}
+
+func TestServer_ArrayAndObjectParameters(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name: "test_tool",
+		Parameters: []Parameter{
+			{
+				Name:        "lines",
+				Type:        "array",
+				Description: "Line numbers",
+				Items:       &Parameter{Type: "integer"},
+			},
+			{
+				Name:        "breakpoints",
+				Type:        "array",
+				Description: "Breakpoint specs",
+				Items: &Parameter{
+					Type: "object",
+					Properties: []Parameter{
+						{Name: "line", Type: "integer", Required: true, Description: "Line number"},
+						{Name: "condition", Type: "string", Description: "Condition"},
+					},
+				},
+			},
+		},
+	})
+
+	data, err := json.Marshal(server.ToolCatalog()[0].InputSchema)
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Items struct {
+				Type       string                            `json:"type"`
+				Properties map[string]map[string]interface{} `json:"properties"`
+				Required   []string                          `json:"required"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	if got := schema.Properties["lines"].Items.Type; got != "integer" {
+		t.Errorf("Expected integer items for lines, got %q", got)
+	}
+	bp := schema.Properties["breakpoints"].Items
+	if bp.Type != "object" || bp.Properties["condition"]["type"] != "string" {
+		t.Errorf("Unexpected breakpoint item schema: %+v", bp)
+	}
+	if len(bp.Required) != 1 || bp.Required[0] != "line" {
+		t.Errorf("Expected nested required [line], got %v", bp.Required)
+	}
+}
+
+func TestServer_ParameterValidation(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name: "test_tool",
+		Parameters: []Parameter{
+			{Name: "port", Type: "number", Default: 6006, Minimum: Float64(1), Maximum: Float64(65535)},
+			{Name: "name", Type: "string", Pattern: "^[a-z_]+$"},
+			{Name: "path", Type: "string", Validate: func(v interface{}) error {
+				if s, _ := v.(string); !strings.HasPrefix(s, "/") {
+					return fmt.Errorf("must be absolute")
+				}
+				return nil
+			}},
+		},
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			return "ok", nil
+		},
+	})
+
+	schema := server.ToolCatalog()[0].InputSchema.Properties
+	if *schema["port"].Minimum != 1 || *schema["port"].Maximum != 65535 || schema["name"].Pattern == "" {
+		t.Errorf("Expected bounds and pattern in schema, got %+v", schema)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"defaults pass", map[string]interface{}{}, ""},
+		{"valid values", map[string]interface{}{"port": float64(7000), "name": "player", "path": "/tmp"}, ""},
+		{"below minimum", map[string]interface{}{"port": float64(0)}, "must be at least 1"},
+		{"above maximum", map[string]interface{}{"port": float64(70000)}, "must be at most 65535"},
+		{"not a number", map[string]interface{}{"port": "6006"}, "must be a number"},
+		{"pattern mismatch", map[string]interface{}{"name": "Player 1"}, "must match"},
+		{"custom validator", map[string]interface{}{"path": "relative"}, "invalid value for path: must be absolute"},
+	}
+	for _, tt := range tests {
+		resp := server.handleRequest(&MCPRequest{
+			JSONRPC: "2.0",
+			ID:      intPtr(1),
+			Method:  "tools/call",
+			Params:  map[string]interface{}{"name": "test_tool", "arguments": tt.args},
+		})
+		if tt.wantErr == "" {
+			if resp.Error != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, resp.Error)
+			}
+			continue
+		}
+		if resp.Error == nil || resp.Error.Code != -32602 || !strings.Contains(resp.Error.Message, tt.wantErr) {
+			t.Errorf("%s: expected -32602 containing %q, got %+v", tt.name, tt.wantErr, resp.Error)
+		}
+	}
+}
+
+func TestRegisterTool_InvalidPattern(t *testing.T) {
+	server := NewServer()
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "bad_tool") || !strings.Contains(fmt.Sprint(r), "invalid pattern") {
+			t.Errorf("expected a registration panic naming the tool and pattern, got %v", r)
+		}
+		if len(server.ToolCatalog()) != 0 {
+			t.Error("a tool with an invalid pattern should not be registered")
+		}
+	}()
+	server.RegisterTool(Tool{
+		Name: "bad_tool",
+		Parameters: []Parameter{
+			{Name: "files", Type: "array", Items: &Parameter{Type: "string", Pattern: "([a-z"}},
+		},
+	})
+}
+
 func TestServer_HandleToolsCall_Success(t *testing.T) {
 	server := NewServer()
 	server.RegisterTool(Tool{
@@ -177,6 +398,97 @@ func TestServer_HandleToolsCall_Success(t *testing.T) {
 	}
 }
 
+func TestServer_TextFilter(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name: "where",
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			if params["fail"] == true {
+				return nil, fmt.Errorf("cannot read /games/demo/player.gd")
+			}
+			return map[string]interface{}{"path": "/games/demo/player.gd"}, nil
+		},
+	})
+	server.SetTextFilter(func(tool string, text string, isError bool) string {
+		return strings.ReplaceAll(text, "/games/demo/", tool+"://")
+	})
+
+	call := func(args map[string]interface{}) MCPResponse {
+		return server.handleRequest(&MCPRequest{
+			JSONRPC: "2.0",
+			ID:      intPtr(1),
+			Method:  "tools/call",
+			Params:  map[string]interface{}{"name": "where", "arguments": args},
+		})
+	}
+
+	resp := call(nil)
+	if text := resp.Result.(ToolCallResult).Content[0].Text; text != `{"path":"where://player.gd"}` {
+		t.Errorf("expected the result to be filtered, got %s", text)
+	}
+	resp = call(map[string]interface{}{"fail": true})
+	if resp.Error == nil || !strings.Contains(resp.Error.Message, "cannot read where://player.gd") {
+		t.Errorf("expected the error to be filtered, got %+v", resp.Error)
+	}
+
+	// Direct calls get the handler's result as is
+	result, _ := server.CallTool("where", nil)
+	if result.(map[string]interface{})["path"] != "/games/demo/player.gd" {
+		t.Errorf("CallTool should not filter, got %v", result)
+	}
+}
+
+func TestServer_CallHook(t *testing.T) {
+	server := NewServer()
+	var calls []string
+	server.RegisterTool(Tool{
+		Name: "test_tool",
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			calls = append(calls, "handler")
+			return "ok", nil
+		},
+	})
+	server.SetCallHook(func(tool string) func() {
+		calls = append(calls, "begin "+tool)
+		return func() { calls = append(calls, "end "+tool) }
+	})
+
+	server.handleToolsCall(&MCPRequest{JSONRPC: "2.0", ID: intPtr(1), Method: "tools/call", Params: map[string]interface{}{"name": "test_tool"}})
+	if _, err := server.CallTool("test_tool", nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "[begin test_tool handler end test_tool begin test_tool handler end test_tool]"
+	if fmt.Sprint(calls) != want {
+		t.Errorf("hook should run around each call, got %v", calls)
+	}
+}
+
+func TestServer_CallTool(t *testing.T) {
+	server := NewServer()
+	server.RegisterTool(Tool{
+		Name: "greet",
+		Parameters: []Parameter{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "greeting", Type: "string", Default: "hello"},
+		},
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			return fmt.Sprintf("%s %s", params["greeting"], params["name"]), nil
+		},
+	})
+
+	result, err := server.CallTool("greet", map[string]interface{}{"name": "godot"})
+	if err != nil || result != "hello godot" {
+		t.Errorf("expected defaults to be applied, got %v (%v)", result, err)
+	}
+
+	if _, err := server.CallTool("greet", nil); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Errorf("missing required parameter should fail, got %v", err)
+	}
+	if _, err := server.CallTool("missing", nil); err == nil || !strings.Contains(err.Error(), "tool not found") {
+		t.Errorf("unknown tool should fail, got %v", err)
+	}
+}
+
 func TestServer_HandleToolsCall_NotFound(t *testing.T) {
 	server := NewServer()
 
@@ -331,6 +643,43 @@ func TestServer_MethodNotFound(t *testing.T) {
 	}
 }
 
+func TestServer_SetLevel(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(""), stdout))
+	notify := func(level string) {
+		if err := server.Notify("notifications/message", map[string]interface{}{"level": level, "data": level}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	notify("debug")
+	resp := server.handleRequest(&MCPRequest{JSONRPC: "2.0", ID: intPtr(1), Method: "logging/setLevel", Params: map[string]interface{}{"level": "error"}})
+	if resp.Error != nil {
+		t.Fatalf("setLevel failed: %v", resp.Error)
+	}
+	notify("info")
+	notify("warning")
+	notify("error")
+	notify("critical")
+
+	var sent []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var n MCPNotification
+		if err := json.Unmarshal([]byte(line), &n); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		sent = append(sent, n.Params["level"].(string))
+	}
+	if fmt.Sprint(sent) != "[debug error critical]" {
+		t.Errorf("expected only notifications at or above the level, got %v", sent)
+	}
+
+	resp = server.handleRequest(&MCPRequest{JSONRPC: "2.0", ID: intPtr(2), Method: "logging/setLevel", Params: map[string]interface{}{"level": "loud"}})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("an unknown level should be rejected, got %+v", resp)
+	}
+}
+
 func TestServer_Notification(t *testing.T) {
 	server := NewServer()
 
@@ -533,3 +882,140 @@ func TestJSONSchemaValidation_InvalidAnyType(t *testing.T) {
 	t.Log("✓ To accept any type, use Type: \"\" instead of Type: \"any\"")
 	t.Log("✓ See TestJSONSchemaValidation_AnyType for the correct pattern")
 }
+
+// TestServer_ParseErrorResponse verifies that malformed frames get a -32700
+// response with a null id and that the server keeps reading afterwards
+func TestServer_ParseErrorResponse(t *testing.T) {
+	input := "{not json}\n" + `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(input), stdout))
+
+	if err := server.ListenAndServe(); err != nil {
+		t.Fatalf("ListenAndServe returned error: %v", err)
+	}
+
+	line, _, _ := strings.Cut(stdout.String(), "\n")
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", line, err)
+	}
+
+	if id, ok := resp["id"]; !ok || id != nil {
+		t.Errorf("Expected explicit null id, got %v (present=%v)", id, ok)
+	}
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected error object, got %v", resp["error"])
+	}
+	if errObj["code"] != float64(-32700) {
+		t.Errorf("Expected code -32700, got %v", errObj["code"])
+	}
+}
+
+// TestServer_HandleBatch verifies that batch responses preserve request order,
+// skip notifications, and include errors for invalid elements
+func TestServer_HandleBatch(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(""), stdout))
+	server.RegisterTool(Tool{
+		Name: "echo",
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			return params["msg"], nil
+		},
+	})
+
+	frame := &Frame{
+		Batch: true,
+		Requests: []*MCPRequest{
+			{JSONRPC: "2.0", ID: intPtr(1), Method: "tools/call", Params: map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"msg": "first"}}},
+			{JSONRPC: "2.0", Method: "notifications/initialized"},
+			{JSONRPC: "2.0", ID: intPtr(2), Method: "tools/call", Params: map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"msg": "second"}}},
+		},
+		Invalid: []error{fmt.Errorf("bad element")},
+	}
+
+	server.handleBatch(frame)
+
+	var resps []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resps); err != nil {
+		t.Fatalf("Expected batch array response, got %q: %v", stdout.String(), err)
+	}
+	if len(resps) != 3 {
+		t.Fatalf("Expected 3 responses (2 calls + 1 invalid), got %d", len(resps))
+	}
+	if resps[0]["id"] != float64(1) || resps[1]["id"] != float64(2) {
+		t.Errorf("Expected responses in request order, got ids %v, %v", resps[0]["id"], resps[1]["id"])
+	}
+	if !strings.Contains(stdout.String(), "first") || !strings.Contains(stdout.String(), "second") {
+		t.Errorf("Expected both tool results in output, got %s", stdout.String())
+	}
+	errObj, ok := resps[2]["error"].(map[string]interface{})
+	if !ok || errObj["code"] != float64(-32600) || resps[2]["id"] != nil {
+		t.Errorf("Expected -32600 with null id for invalid element, got %v", resps[2])
+	}
+}
+
+// TestServer_HandleBatch_Empty verifies that an empty batch gets a single error response
+func TestServer_HandleBatch_Empty(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(""), stdout))
+
+	server.handleBatch(&Frame{Batch: true})
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected single response object, got %q: %v", stdout.String(), err)
+	}
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok || errObj["code"] != float64(-32600) {
+		t.Errorf("Expected -32600 error, got %v", resp)
+	}
+}
+
+// TestServer_HandleBatch_AllNotifications verifies that nothing is written
+// when a batch contains only notifications
+func TestServer_HandleBatch_AllNotifications(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(""), stdout))
+
+	server.handleBatch(&Frame{
+		Batch:    true,
+		Requests: []*MCPRequest{{JSONRPC: "2.0", Method: "notifications/initialized"}},
+	})
+
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output, got %q", stdout.String())
+	}
+}
+
+// TestServer_NotificationRegistry verifies that notifications are dispatched to
+// registered handlers and never produce a response, even for unknown methods
+func TestServer_NotificationRegistry(t *testing.T) {
+	server := NewServer()
+
+	var gotParams map[string]interface{}
+	server.RegisterNotificationHandler("notifications/cancelled", func(params map[string]interface{}) {
+		gotParams = params
+	})
+
+	resp := server.handleRequest(&MCPRequest{
+		JSONRPC: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  map[string]interface{}{"requestId": float64(7)},
+	})
+	if resp.ID != nil || resp.Result != nil || resp.Error != nil {
+		t.Errorf("Expected empty response for notification, got %+v", resp)
+	}
+	if gotParams["requestId"] != float64(7) {
+		t.Errorf("Expected handler to receive params, got %v", gotParams)
+	}
+
+	// Requests without id are notifications even when the method is unknown
+	// or would otherwise be a regular method
+	for _, method := range []string{"notifications/unknown", "tools/list"} {
+		resp := server.handleRequest(&MCPRequest{JSONRPC: "2.0", Method: method})
+		if resp.ID != nil || resp.Result != nil || resp.Error != nil {
+			t.Errorf("Expected empty response for notification %s, got %+v", method, resp)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,12 +10,32 @@ import (
 	"sync"
 )
 
+// MaxRequestSize is the largest single request line accepted from the client.
+// Longer lines are discarded and reported as parse errors.
+const MaxRequestSize = 10 * 1024 * 1024
+
+// ParseError indicates that a request line could not be parsed as JSON
+// (or exceeded MaxRequestSize). The transport has already skipped past the
+// offending line, so reading can continue with the next request.
+// The server answers these with a -32700 error and a null id.
+type ParseError struct {
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse JSON request: %v", e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // Transport handles stdin/stdout communication for MCP protocol
 type Transport struct {
-	stdin   io.Reader
-	stdout  io.Writer
-	decoder *json.Decoder
-	mu      sync.Mutex
+	stdin  io.Reader
+	stdout io.Writer
+	reader *bufio.Reader
+	mu     sync.Mutex
 }
 
 // NewTransport creates a new transport using os.Stdin and os.Stdout
@@ -24,29 +46,147 @@ func NewTransport() *Transport {
 // NewTransportWithStreams creates a new transport with custom streams (useful for testing)
 func NewTransportWithStreams(stdin io.Reader, stdout io.Writer) *Transport {
 	return &Transport{
-		stdin:   stdin,
-		stdout:  stdout,
-		decoder: json.NewDecoder(stdin),
+		stdin:  stdin,
+		stdout: stdout,
+		reader: bufio.NewReader(stdin),
 	}
 }
 
+// Frame is a single newline-delimited message from the client:
+// either one request or a JSON-RPC 2.0 batch array of requests
+type Frame struct {
+	// Requests holds the parsed requests (exactly one unless Batch is set)
+	Requests []*MCPRequest
+
+	// Batch is true if the frame was a JSON array
+	Batch bool
+
+	// Invalid holds one error per batch element that was not a valid request.
+	// Each one is answered with a -32600 error and a null id.
+	Invalid []error
+}
+
 // ReadRequest reads and parses a single MCP request from stdin
-// Returns the parsed request or an error if reading/parsing fails
+// Requests are newline-delimited. Blank lines are skipped.
+// Returns the parsed request, a *ParseError for malformed or oversized lines,
+// or io.EOF when the stream ends.
 func (t *Transport) ReadRequest() (*MCPRequest, error) {
+	line, err := t.readLine()
+	if err != nil {
+		return nil, err
+	}
+
 	var req MCPRequest
-	if err := t.decoder.Decode(&req); err != nil {
-		if err == io.EOF {
-			return nil, io.EOF
+	if err := json.Unmarshal(line, &req); err != nil {
+		return nil, &ParseError{Err: err}
+	}
+
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// ReadFrame reads the next frame from stdin, which may be a single request
+// or a batch. Errors follow the same rules as ReadRequest. Invalid elements
+// inside a batch do not fail the frame; they are reported in Frame.Invalid.
+func (t *Transport) ReadFrame() (*Frame, error) {
+	line, err := t.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	// Batches are JSON arrays; everything else is a single request
+	if line[0] != '[' {
+		var req MCPRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			return nil, &ParseError{Err: err}
+		}
+		if err := validateRequest(&req); err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to parse JSON request: %w", err)
+		return &Frame{Requests: []*MCPRequest{&req}}, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(line, &elements); err != nil {
+		return nil, &ParseError{Err: err}
 	}
 
+	frame := &Frame{Batch: true}
+	for i, raw := range elements {
+		var req MCPRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			frame.Invalid = append(frame.Invalid, fmt.Errorf("batch element %d is not a valid request: %w", i, err))
+			continue
+		}
+		if err := validateRequest(&req); err != nil {
+			frame.Invalid = append(frame.Invalid, fmt.Errorf("batch element %d: %w", i, err))
+			continue
+		}
+		frame.Requests = append(frame.Requests, &req)
+	}
+
+	return frame, nil
+}
+
+// validateRequest checks the JSON-RPC envelope of a decoded request
+func validateRequest(req *MCPRequest) error {
 	// Validate JSON-RPC version
 	if req.JSONRPC != "2.0" {
-		return nil, fmt.Errorf("invalid JSON-RPC version: %s (expected 2.0)", req.JSONRPC)
+		return fmt.Errorf("invalid JSON-RPC version: %s (expected 2.0)", req.JSONRPC)
 	}
+	return nil
+}
 
-	return &req, nil
+// readLine returns the next non-blank line without its trailing newline.
+// Lines longer than MaxRequestSize are consumed in full and reported as a *ParseError.
+func (t *Transport) readLine() ([]byte, error) {
+	for {
+		var line []byte
+		oversized := false
+
+		for {
+			chunk, err := t.reader.ReadSlice('\n')
+			if !oversized {
+				if len(line)+len(chunk) > MaxRequestSize+1 { // +1 for the newline
+					oversized = true
+					line = nil
+				} else {
+					line = append(line, chunk...)
+				}
+			}
+
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			if err == io.EOF {
+				if oversized {
+					return nil, &ParseError{Err: fmt.Errorf("request exceeds maximum size of %d bytes", MaxRequestSize)}
+				}
+				if len(bytes.TrimSpace(line)) == 0 {
+					return nil, io.EOF
+				}
+				// Final line without trailing newline
+				return bytes.TrimSpace(line), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		if oversized {
+			return nil, &ParseError{Err: fmt.Errorf("request exceeds maximum size of %d bytes", MaxRequestSize)}
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		return line, nil
+	}
 }
 
 // WriteResponse writes an MCP response to stdout
@@ -71,6 +211,68 @@ func (t *Transport) WriteResponse(resp MCPResponse) error {
 	return nil
 }
 
+// WriteRequest writes a server-initiated request to stdout
+func (t *Transport) WriteRequest(req MCPRequest) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Ensure JSON-RPC version is set
+	req.JSONRPC = "2.0"
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if _, err := t.stdout.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+
+	return nil
+}
+
+// WriteNotification writes a server-initiated notification to stdout
+func (t *Transport) WriteNotification(notification MCPNotification) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Ensure JSON-RPC version is set
+	notification.JSONRPC = "2.0"
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	if _, err := t.stdout.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+
+	return nil
+}
+
+// WriteBatch writes a JSON-RPC batch response (array of responses) to stdout
+func (t *Transport) WriteBatch(resps []MCPResponse) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Ensure JSON-RPC version is set on every response
+	for i := range resps {
+		resps[i].JSONRPC = "2.0"
+	}
+
+	data, err := json.Marshal(resps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch response: %w", err)
+	}
+
+	if _, err := t.stdout.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+
+	return nil
+}
+
 // WriteError is a convenience method to write an error response
 func (t *Transport) WriteError(requestID interface{}, code int, message string) error {
 	return t.WriteResponse(MCPResponse{
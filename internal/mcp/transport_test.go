@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -63,6 +65,69 @@ func TestReadRequest_InvalidJSON(t *testing.T) {
 	}
 }
 
+// TestReadRequest_RecoversAfterMalformedLine verifies that a bad line is skipped
+// and the following request is still read
+func TestReadRequest_RecoversAfterMalformedLine(t *testing.T) {
+	input := "{\"jsonrpc\":\"2.0\",\"id\":1,INVALID}\n\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+	transport := NewTransportWithStreams(strings.NewReader(input), &bytes.Buffer{})
+
+	_, err := transport.ReadRequest()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+
+	req, err := transport.ReadRequest()
+	if err != nil {
+		t.Fatalf("Expected next request to parse, got %v", err)
+	}
+	if req.Method != "tools/list" {
+		t.Errorf("Expected method 'tools/list', got '%s'", req.Method)
+	}
+
+	if _, err := transport.ReadRequest(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+// TestReadRequest_Oversized verifies that oversized lines are rejected and skipped
+func TestReadRequest_Oversized(t *testing.T) {
+	huge := `{"jsonrpc":"2.0","id":1,"method":"` + strings.Repeat("x", MaxRequestSize) + `"}`
+	input := huge + "\n" + `{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+	transport := NewTransportWithStreams(strings.NewReader(input), &bytes.Buffer{})
+
+	_, err := transport.ReadRequest()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError for oversized request, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "exceeds maximum size") {
+		t.Errorf("Expected size error, got: %v", err)
+	}
+
+	req, err := transport.ReadRequest()
+	if err != nil {
+		t.Fatalf("Expected next request to parse, got %v", err)
+	}
+	if req.Method != "tools/list" {
+		t.Errorf("Expected method 'tools/list', got '%s'", req.Method)
+	}
+}
+
+// TestReadRequest_NoTrailingNewline verifies that a final line without newline is read
+func TestReadRequest_NoTrailingNewline(t *testing.T) {
+	transport := NewTransportWithStreams(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`), &bytes.Buffer{})
+
+	req, err := transport.ReadRequest()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if req.Method != "ping" {
+		t.Errorf("Expected method 'ping', got '%s'", req.Method)
+	}
+}
+
 // TestReadRequest_WrongVersion verifies that non-2.0 JSON-RPC versions are rejected
 func TestReadRequest_WrongVersion(t *testing.T) {
 	input := `{"jsonrpc":"1.0","id":1,"method":"test","params":{}}`
@@ -133,3 +198,87 @@ func TestWriteResponse_Error(t *testing.T) {
 		t.Error("Response missing error message")
 	}
 }
+
+func FuzzReadRequest(f *testing.F) {
+	f.Add(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}` + "\n")
+	f.Add(`{"jsonrpc":"2.0","id":"abc","method":"tools/call","params":{"name":"godot_ping"}}` + "\n")
+	f.Add(`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n")
+	f.Add(`{"jsonrpc":"1.0","id":1}` + "\n")
+	f.Add(`{"jsonrpc":"2.0","id":1,INVALID}` + "\n")
+	f.Add(`[{"jsonrpc":"2.0","id":1,"method":"tools/list"}]` + "\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		transport := NewTransportWithStreams(strings.NewReader(input), io.Discard)
+
+		// Every call must consume at least one line, so the stream must reach
+		// EOF within (lines + 1) reads regardless of how malformed it is
+		maxReads := strings.Count(input, "\n") + 1
+		for i := 0; i < maxReads; i++ {
+			if _, err := transport.ReadFrame(); err == io.EOF {
+				return
+			}
+		}
+		if _, err := transport.ReadFrame(); err != io.EOF {
+			t.Fatalf("expected EOF after %d reads, got %v", maxReads, err)
+		}
+	})
+}
+
+// TestReadFrame_Batch verifies that batch arrays are split into requests
+// and that invalid elements are reported without failing the frame
+func TestReadFrame_Batch(t *testing.T) {
+	input := `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},{"jsonrpc":"1.0","id":2,"method":"x"},42,{"jsonrpc":"2.0","method":"notifications/initialized"}]` + "\n"
+	transport := NewTransportWithStreams(strings.NewReader(input), &bytes.Buffer{})
+
+	frame, err := transport.ReadFrame()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !frame.Batch {
+		t.Error("Expected frame to be a batch")
+	}
+	if len(frame.Requests) != 2 {
+		t.Fatalf("Expected 2 valid requests, got %d", len(frame.Requests))
+	}
+	if frame.Requests[0].Method != "tools/list" || frame.Requests[1].Method != "notifications/initialized" {
+		t.Errorf("Unexpected requests: %+v, %+v", frame.Requests[0], frame.Requests[1])
+	}
+	if len(frame.Invalid) != 2 {
+		t.Errorf("Expected 2 invalid elements, got %d", len(frame.Invalid))
+	}
+}
+
+// TestReadFrame_Single verifies that non-array frames are read as one request
+func TestReadFrame_Single(t *testing.T) {
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}` + "\n"
+	transport := NewTransportWithStreams(strings.NewReader(input), &bytes.Buffer{})
+
+	frame, err := transport.ReadFrame()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if frame.Batch || len(frame.Requests) != 1 {
+		t.Errorf("Expected single request frame, got %+v", frame)
+	}
+}
+
+// TestWriteNotification verifies that notifications are written without an id
+func TestWriteNotification(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	server := NewServerWithTransport(NewTransportWithStreams(strings.NewReader(""), stdout))
+
+	if err := server.Notify("notifications/message", map[string]interface{}{"level": "info", "data": "hi"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var msg map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &msg); err != nil {
+		t.Fatalf("Notification is not valid JSON: %v", err)
+	}
+	if _, ok := msg["id"]; ok {
+		t.Error("Notification must not have an id field")
+	}
+	if msg["jsonrpc"] != "2.0" || msg["method"] != "notifications/message" {
+		t.Errorf("Unexpected notification: %v", msg)
+	}
+}
@@ -1,11 +1,27 @@
 package mcp
 
+import (
+	"encoding/json"
+	"regexp"
+)
+
 // MCPRequest represents an incoming JSON-RPC 2.0 request from the MCP client
 type MCPRequest struct {
 	JSONRPC string                 `json:"jsonrpc"` // Always "2.0"
 	ID      *interface{}           `json:"id"`      // Request ID (number or string)
 	Method  string                 `json:"method"`  // Method name (e.g., "tools/list", "tools/call")
 	Params  map[string]interface{} `json:"params"`  // Method parameters
+
+	// Result and Error are only set when the client is answering a
+	// server-initiated request (e.g. roots/list); Method is empty then
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *MCPError       `json:"error,omitempty"`
+}
+
+// isResponse reports whether the message is the client's response to a
+// server-initiated request rather than a request of its own
+func (r *MCPRequest) isResponse() bool {
+	return r.Method == "" && r.ID != nil && (r.Result != nil || r.Error != nil)
 }
 
 // MCPResponse represents an outgoing JSON-RPC 2.0 response to the MCP client
@@ -16,6 +32,14 @@ type MCPResponse struct {
 	Error   *MCPError   `json:"error,omitempty"`  // Error details (mutually exclusive with Result)
 }
 
+// MCPNotification represents an outgoing JSON-RPC 2.0 notification to the MCP client.
+// Unlike MCPRequest it has no id field at all, since "id": null is not a notification.
+type MCPNotification struct {
+	JSONRPC string                 `json:"jsonrpc"`          // Always "2.0"
+	Method  string                 `json:"method"`           // Notification method (e.g., "notifications/message")
+	Params  map[string]interface{} `json:"params,omitempty"` // Notification parameters
+}
+
 // MCPError represents a JSON-RPC 2.0 error
 type MCPError struct {
 	Code    int         `json:"code"`           // Error code
@@ -38,6 +62,23 @@ type Parameter struct {
 	Required    bool        // Whether parameter is required
 	Description string      // Human-readable description
 	Default     interface{} // Default value if not provided
+	Enum        []string    // Allowed values for string parameters (optional)
+	Items       *Parameter  // Element schema for "array" parameters (Name is unused)
+	Properties  []Parameter // Nested fields for "object" parameters
+
+	// Validation, checked by the server before the handler runs.
+	// Violations are answered with a -32602 (invalid params) error.
+	Minimum  *float64                // Smallest allowed value for numbers
+	Maximum  *float64                // Largest allowed value for numbers
+	Pattern  string                  // Regular expression strings must match
+	Validate func(interface{}) error // Custom check for anything the schema can't express
+
+	pattern *regexp.Regexp // Pattern, compiled by RegisterTool
+}
+
+// Float64 returns a pointer to v, for Parameter.Minimum and Maximum
+func Float64(v float64) *float64 {
+	return &v
 }
 
 // ToolListResult represents the response to tools/list
@@ -52,18 +93,30 @@ type ToolMetadata struct {
 	InputSchema ToolInputSchema `json:"inputSchema"`
 }
 
+// jsonSchemaDialect is the JSON Schema version tool input schemas follow
+const jsonSchemaDialect = "http://json-schema.org/draft-07/schema#"
+
 // ToolInputSchema defines the JSON schema for tool parameters
 type ToolInputSchema struct {
-	Type       string                        `json:"type"`       // Always "object"
-	Properties map[string]PropertyDefinition `json:"properties"` // Parameter definitions
-	Required   []string                      `json:"required"`   // Required parameter names
+	Schema               string                        `json:"$schema"`              // JSON Schema dialect
+	Type                 string                        `json:"type"`                 // Always "object"
+	Properties           map[string]PropertyDefinition `json:"properties"`           // Parameter definitions
+	Required             []string                      `json:"required"`             // Required parameter names
+	AdditionalProperties bool                          `json:"additionalProperties"` // Always false: tools take only their declared parameters
 }
 
 // PropertyDefinition defines a single parameter's schema
 type PropertyDefinition struct {
-	Type        string      `json:"type,omitempty"`    // Parameter type (omit for any type)
-	Description string      `json:"description"`       // Parameter description
-	Default     interface{} `json:"default,omitempty"` // Default value
+	Type        string                        `json:"type,omitempty"`        // Parameter type (omit for any type)
+	Description string                        `json:"description,omitempty"` // Parameter description
+	Default     interface{}                   `json:"default,omitempty"`     // Default value
+	Enum        []string                      `json:"enum,omitempty"`        // Allowed values
+	Items       *PropertyDefinition           `json:"items,omitempty"`       // Array element schema
+	Properties  map[string]PropertyDefinition `json:"properties,omitempty"`  // Object field schemas
+	Required    []string                      `json:"required,omitempty"`    // Required object fields
+	Minimum     *float64                      `json:"minimum,omitempty"`     // Smallest allowed number
+	Maximum     *float64                      `json:"maximum,omitempty"`     // Largest allowed number
+	Pattern     string                        `json:"pattern,omitempty"`     // Regular expression for strings
 }
 
 // ToolCallResult represents the response to tools/call
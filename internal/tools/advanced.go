@@ -56,6 +56,9 @@ godot_pause(thread_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requireGameActive(session, "pause execution"); err != nil {
+				return nil, err
+			}
 
 			// Get thread ID parameter
 			threadId := 1 // default
@@ -161,13 +164,19 @@ Returns: Variable name, new value, and type`,
 	})
 }
 
+// Precompiled patterns used for variable validation and string escaping
+var (
+	variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	backslashPattern    = regexp.MustCompile(`\\`)
+	quotePattern        = regexp.MustCompile(`"`)
+)
+
 // isValidVariableName validates that a variable name is a valid GDScript identifier
 // Pattern: ^[a-zA-Z_][a-zA-Z0-9_]*$
 // This prevents code injection by rejecting expressions with operators, spaces, etc.
 func isValidVariableName(name string) bool {
 	// Must start with letter or underscore, followed by letters, numbers, or underscores
-	matched, _ := regexp.MatchString(`^[a-zA-Z_][a-zA-Z0-9_]*$`, name)
-	return matched
+	return variableNamePattern.MatchString(name)
 }
 
 // formatValueForGDScript formats a value for use in a GDScript expression
@@ -191,7 +200,7 @@ func formatValueForGDScript(value interface{}) string {
 // escapeString escapes quotes and backslashes in a string for GDScript
 func escapeString(s string) string {
 	// Replace backslashes first, then quotes
-	s = regexp.MustCompile(`\\`).ReplaceAllString(s, `\\`)
-	s = regexp.MustCompile(`"`).ReplaceAllString(s, `\"`)
+	s = backslashPattern.ReplaceAllString(s, `\\`)
+	s = quotePattern.ReplaceAllString(s, `\"`)
 	return s
 }
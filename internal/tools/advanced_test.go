@@ -115,3 +115,17 @@ func TestEscapeString(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkIsValidVariableName(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		isValidVariableName("player_health")
+	}
+}
+
+func BenchmarkEscapeString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		escapeString(`path\to\"file\"`)
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// allowedDirsEnv names the environment variable that restricts which
+// directories tools may touch. It holds a list of absolute directories
+// separated by the OS path list separator (":" on Unix, ";" on Windows).
+// When unset, every path is allowed.
+const allowedDirsEnv = "GODOT_MCP_ALLOWED_DIRS"
+
+// allowedDirs is the parsed allowlist; nil means unrestricted
+var allowedDirs = parseAllowedDirs(os.Getenv(allowedDirsEnv))
+
+// parseAllowedDirs parses an allowlist value into cleaned, symlink-resolved
+// absolute directories. Relative entries are ignored.
+func parseAllowedDirs(value string) []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(value) {
+		if dir == "" || !filepath.IsAbs(dir) {
+			continue
+		}
+		dirs = append(dirs, resolveSymlinks(dir))
+	}
+	return dirs
+}
+
+// resolveSymlinks resolves symlinks in path so a link inside an allowed
+// directory can't point outside it. Paths that don't exist yet are resolved
+// up to their deepest existing parent.
+func resolveSymlinks(path string) string {
+	path = filepath.Clean(path)
+	var rest []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		rest = append([]string{filepath.Base(dir)}, rest...)
+	}
+}
+
+// isPathAllowed reports whether path lies inside one of dirs.
+// An empty allowlist allows everything.
+func isPathAllowed(path string, dirs []string) bool {
+	if len(dirs) == 0 {
+		return true
+	}
+	resolved := resolveSymlinks(path)
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPathAllowed returns an error if path is outside the configured allowlist
+func checkPathAllowed(path string) error {
+	if isPathAllowed(path, allowedDirs) {
+		return nil
+	}
+	return ErrPathNotAllowed(path)
+}
+
+func ErrPathNotAllowed(path string) error {
+	return FormatError(
+		"Path is outside the allowed directories",
+		path,
+		[]string{
+			fmt.Sprintf("Use a path inside one of: %s", strings.Join(allowedDirs, ", ")),
+			fmt.Sprintf("Ask the server operator to add the directory to %s", allowedDirsEnv),
+		},
+		nil,
+	)
+}
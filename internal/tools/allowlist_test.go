@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAllowedDirs(t *testing.T) {
+	dir := t.TempDir()
+	value := strings.Join([]string{dir, "relative/dir", ""}, string(os.PathListSeparator))
+
+	dirs := parseAllowedDirs(value)
+	if len(dirs) != 1 {
+		t.Fatalf("expected only the absolute entry, got %v", dirs)
+	}
+	if parseAllowedDirs("") != nil {
+		t.Error("empty value should mean no allowlist")
+	}
+}
+
+func TestIsPathAllowed(t *testing.T) {
+	allowed := t.TempDir()
+	outside := t.TempDir()
+	dirs := parseAllowedDirs(allowed)
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"allowed dir itself", allowed, true},
+		{"file inside", filepath.Join(allowed, "scripts", "player.gd"), true},
+		{"outside dir", filepath.Join(outside, "player.gd"), false},
+		{"traversal out", filepath.Join(allowed, "..", filepath.Base(outside)), false},
+		{"sibling with shared prefix", allowed + "-other", false},
+	}
+	for _, tt := range tests {
+		if got := isPathAllowed(tt.path, dirs); got != tt.want {
+			t.Errorf("%s: isPathAllowed(%q) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+
+	if !isPathAllowed(outside, nil) {
+		t.Error("an empty allowlist should allow everything")
+	}
+}
+
+func TestIsPathAllowed_Symlink(t *testing.T) {
+	allowed := t.TempDir()
+	outside := t.TempDir()
+	link := filepath.Join(allowed, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if isPathAllowed(filepath.Join(link, "secret.gd"), parseAllowedDirs(allowed)) {
+		t.Error("a symlink pointing outside the allowlist should be rejected")
+	}
+}
+
+func TestCheckPathAllowed_Tools(t *testing.T) {
+	allowed := t.TempDir()
+	outside := t.TempDir()
+
+	saved := allowedDirs
+	allowedDirs = parseAllowedDirs(allowed)
+	defer func() { allowedDirs = saved }()
+
+	if err := validateProjectPath(outside); err == nil || !strings.Contains(err.Error(), "outside the allowed directories") {
+		t.Errorf("validateProjectPath should reject paths outside the allowlist, got %v", err)
+	}
+	if _, err := resolveGodotPath("res://../outside.gd", allowed); err == nil {
+		t.Error("res:// paths escaping the project should be rejected")
+	}
+	if _, err := resolveGodotPath(filepath.Join(allowed, "player.gd"), ""); err != nil {
+		t.Errorf("paths inside the allowlist should resolve, got %v", err)
+	}
+}
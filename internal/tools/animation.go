@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// stateMachinePlayback is the AnimationTree parameter holding the root state
+// machine's AnimationNodeStateMachinePlayback
+const stateMachinePlayback = `get("parameters/playback")`
+
+// unquoteGodotName strips quotes from a String, StringName (&"name"), or
+// NodePath (^"path") value
+func unquoteGodotName(value string) string {
+	return unquoteGodotString(strings.TrimLeft(strings.TrimSpace(value), "&^"))
+}
+
+// parseGodotStringList parses the str() of an Array or PackedStringArray of
+// names, e.g. ["idle", "run"] or [idle, run]
+func parseGodotStringList(value string) []string {
+	inner := strings.TrimSpace(value)
+	inner = strings.TrimSuffix(strings.TrimPrefix(inner, "["), "]")
+	names := []string{}
+	if strings.TrimSpace(inner) == "" {
+		return names
+	}
+	for _, name := range strings.Split(inner, ", ") {
+		names = append(names, unquoteGodotName(name))
+	}
+	return names
+}
+
+// parseGodotFloat parses a float result, returning nil if it isn't one
+func parseGodotFloat(value string) interface{} {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// readAnimationPlayer reads the playback state of an AnimationPlayer
+func readAnimationPlayer(r memberReader, result map[string]interface{}) error {
+	playing, err := r.eval("is_playing()")
+	if err != nil {
+		return err
+	}
+	result["playing"] = playing == "true"
+
+	current, _ := r.eval("current_animation")
+	current = unquoteGodotName(current)
+	result["current_animation"] = current
+	assigned, _ := r.eval("assigned_animation")
+	result["assigned_animation"] = unquoteGodotName(assigned)
+	if current != "" {
+		if position, err := r.eval("current_animation_position"); err == nil {
+			result["position"] = parseGodotFloat(position)
+		}
+		if length, err := r.eval("current_animation_length"); err == nil {
+			result["length"] = parseGodotFloat(length)
+		}
+	}
+	if speed, err := r.eval("get_playing_speed()"); err == nil {
+		result["playing_speed"] = parseGodotFloat(speed)
+	}
+	if queue, err := r.eval("get_queue()"); err == nil {
+		result["queue"] = parseGodotStringList(queue)
+	}
+	if list, err := r.eval("get_animation_list()"); err == nil {
+		result["animations"] = parseGodotStringList(list)
+	}
+	return nil
+}
+
+// readAnimationTree reads an AnimationTree and, if its root is a state
+// machine, the active state and the path being traveled
+func readAnimationTree(r memberReader, result map[string]interface{}) error {
+	active, err := r.eval("active")
+	if err != nil {
+		return err
+	}
+	result["active"] = active == "true"
+	if player, err := r.eval("anim_player"); err == nil {
+		result["anim_player"] = unquoteGodotName(player)
+	}
+
+	root, err := r.eval("tree_root.get_class()")
+	if err != nil {
+		result["tree_root"] = ""
+		return nil
+	}
+	root = unquoteGodotName(root)
+	result["tree_root"] = root
+	if root != "AnimationNodeStateMachine" {
+		return nil
+	}
+
+	playback := memberReader{ctx: r.ctx, client: r.client, frameId: r.frameId, node: r.node + "." + stateMachinePlayback}
+	stateMachine := map[string]interface{}{}
+	current, err := playback.eval("get_current_node()")
+	if err != nil {
+		return err
+	}
+	stateMachine["current_node"] = unquoteGodotName(current)
+	if playing, err := playback.eval("is_playing()"); err == nil {
+		stateMachine["playing"] = playing == "true"
+	}
+	if position, err := playback.eval("get_current_play_position()"); err == nil {
+		stateMachine["position"] = parseGodotFloat(position)
+	}
+	if length, err := playback.eval("get_current_length()"); err == nil {
+		stateMachine["length"] = parseGodotFloat(length)
+	}
+	if path, err := playback.eval("get_travel_path()"); err == nil {
+		stateMachine["travel_path"] = parseGodotStringList(path)
+	}
+	result["state_machine"] = stateMachine
+	return nil
+}
+
+// readAnimationState inspects the AnimationPlayer or AnimationTree at node
+func readAnimationState(ctx context.Context, client expressionEvaluator, frameId int, node string) (map[string]interface{}, error) {
+	r := memberReader{ctx: ctx, client: client, frameId: frameId, node: node}
+	result := map[string]interface{}{"status": "success"}
+
+	isPlayer, err := r.eval(`is_class("AnimationPlayer")`)
+	if err != nil {
+		return nil, err
+	}
+	if isPlayer == "true" {
+		result["kind"] = "AnimationPlayer"
+		return result, readAnimationPlayer(r, result)
+	}
+	isTree, err := r.eval(`is_class("AnimationTree")`)
+	if err != nil {
+		return nil, err
+	}
+	if isTree == "true" {
+		result["kind"] = "AnimationTree"
+		return result, readAnimationTree(r, result)
+	}
+	return nil, fmt.Errorf("%s is neither an AnimationPlayer nor an AnimationTree", node)
+}
+
+// RegisterAnimationTools registers godot_get_animation_state
+func RegisterAnimationTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_animation_state",
+		Description: `Get the playback state of an AnimationPlayer or AnimationTree.
+
+For an AnimationPlayer: the current and assigned animation, position and
+length (seconds), whether it is playing, playing speed, queued animations,
+and the animation list.
+
+For an AnimationTree: whether it is active, its AnimationPlayer, and the root
+node's class. If the root is a state machine, also the active state, its
+position and length, and the travel path (states still to pass through).
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Absolute paths ("/root/Main/Player/AnimationTree") start at the scene tree's
+root; other paths are relative to the current scene.
+
+Example: Why is the character stuck in "jump"?
+godot_get_animation_state(node_path="Player/AnimationTree")
+→ {"kind": "AnimationTree", "active": true,
+   "state_machine": {"current_node": "jump", "travel_path": ["fall", "land"], ...}}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "node_path",
+				Type:        "string",
+				Required:    true,
+				Description: "Node path of the AnimationPlayer or AnimationTree (absolute /root/... or relative to the current scene)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get animation state"); err != nil {
+				return nil, err
+			}
+
+			path, ok := params["node_path"].(string)
+			if !ok || strings.TrimSpace(path) == "" {
+				return nil, fmt.Errorf("node_path is required and must be a non-empty string")
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := readAnimationState(ctx, session.GetClient(), 0, nodePathExpression(path))
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read animation state",
+					path,
+					[]string{
+						"The path must point to an AnimationPlayer or AnimationTree node",
+						"Relative paths start at the current scene; use /root/... for an absolute path",
+						"Check the path with godot_evaluate, e.g. get_tree().current_scene.get_node_or_null(\"...\")",
+					},
+					err,
+				)
+			}
+			result["node_path"] = path
+			return result, nil
+		},
+	})
+}
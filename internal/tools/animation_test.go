@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseGodotStringList(t *testing.T) {
+	if got := parseGodotStringList(`["idle", "run"]`); !reflect.DeepEqual(got, []string{"idle", "run"}) {
+		t.Errorf("unexpected quoted list: %v", got)
+	}
+	if got := parseGodotStringList(`[&"idle", walk]`); !reflect.DeepEqual(got, []string{"idle", "walk"}) {
+		t.Errorf("unexpected mixed list: %v", got)
+	}
+	if got := parseGodotStringList("[]"); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty list, got %v", got)
+	}
+}
+
+func TestReadAnimationState_Player(t *testing.T) {
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		`$Anim.is_class("AnimationPlayer")`: "true",
+		"$Anim.is_playing()":                "true",
+		"$Anim.current_animation":           `"walk"`,
+		"$Anim.assigned_animation":          `"walk"`,
+		"$Anim.current_animation_position":  "0.25",
+		"$Anim.current_animation_length":    "1",
+		"$Anim.get_playing_speed()":         "1.5",
+		"$Anim.get_queue()":                 `["idle"]`,
+		"$Anim.get_animation_list()":        `["idle", "walk"]`,
+	}}
+
+	result, err := readAnimationState(context.Background(), eval, 0, "$Anim")
+	if err != nil {
+		t.Fatalf("readAnimationState failed: %v", err)
+	}
+	if result["kind"] != "AnimationPlayer" || result["playing"] != true || result["current_animation"] != "walk" {
+		t.Errorf("unexpected player state: %v", result)
+	}
+	if result["position"] != 0.25 || result["length"] != 1.0 || result["playing_speed"] != 1.5 {
+		t.Errorf("unexpected timing: %v", result)
+	}
+	if !reflect.DeepEqual(result["queue"], []string{"idle"}) || !reflect.DeepEqual(result["animations"], []string{"idle", "walk"}) {
+		t.Errorf("unexpected lists: %v", result)
+	}
+}
+
+func TestReadAnimationState_StateMachine(t *testing.T) {
+	playback := "$Tree." + stateMachinePlayback
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		`$Tree.is_class("AnimationPlayer")`:       "false",
+		`$Tree.is_class("AnimationTree")`:         "true",
+		"$Tree.active":                            "true",
+		"$Tree.anim_player":                       `"../AnimationPlayer"`,
+		"$Tree.tree_root.get_class()":             `"AnimationNodeStateMachine"`,
+		playback + ".get_current_node()":          `&"jump"`,
+		playback + ".is_playing()":                "true",
+		playback + ".get_current_play_position()": "0.1",
+		playback + ".get_current_length()":        "0.5",
+		playback + ".get_travel_path()":           `[&"fall", &"land"]`,
+	}}
+
+	result, err := readAnimationState(context.Background(), eval, 0, "$Tree")
+	if err != nil {
+		t.Fatalf("readAnimationState failed: %v", err)
+	}
+	if result["kind"] != "AnimationTree" || result["active"] != true || result["anim_player"] != "../AnimationPlayer" {
+		t.Errorf("unexpected tree state: %v", result)
+	}
+	sm := result["state_machine"].(map[string]interface{})
+	if sm["current_node"] != "jump" || !reflect.DeepEqual(sm["travel_path"], []string{"fall", "land"}) {
+		t.Errorf("unexpected state machine: %v", sm)
+	}
+}
+
+func TestReadAnimationState_NotAnimation(t *testing.T) {
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		`$Label.is_class("AnimationPlayer")`: "false",
+		`$Label.is_class("AnimationTree")`:   "false",
+	}}
+	if _, err := readAnimationState(context.Background(), eval, 0, "$Label"); err == nil {
+		t.Error("expected an error for a node that isn't animated")
+	}
+}
@@ -35,9 +35,36 @@ Attach Flow:
 3. Debugger attaches to the running game session
 
 Example: Attach to running game
-godot_attach()`,
+godot_attach()
 
-		Parameters: []mcp.Parameter{}, // No parameters required for attach
+Example: Attach to a game on another device
+godot_attach(address="192.168.1.20", port=6007)
+
+Godot's attach request currently takes no arguments, so address, port, and args
+only matter for adapter versions that read them.`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "address",
+				Type:        "string",
+				Required:    false,
+				Description: "Host the game's remote debugger runs on (default: 127.0.0.1 when port is set)",
+			},
+			{
+				Name:        "port",
+				Type:        "number",
+				Required:    false,
+				Description: "The game's remote debug port (default: adapter's choice, usually 6007)",
+				Minimum:     mcp.Float64(1),
+				Maximum:     mcp.Float64(65535),
+			},
+			{
+				Name:        "args",
+				Type:        "object",
+				Required:    false,
+				Description: "Additional adapter-specific attach arguments (override address and port)",
+			},
+		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
 			// Get active session
@@ -46,11 +73,22 @@ godot_attach()`,
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
 
+			config := &dap.AttachConfig{}
+			if address, ok := params["address"].(string); ok {
+				config.Address = address
+			}
+			if port, ok := params["port"].(float64); ok {
+				config.Port = int(port)
+			}
+			if args, ok := params["args"].(map[string]interface{}); ok {
+				config.Args = args
+			}
+
 			// Attach to game
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
-			if _, err := session.AttachGodot(ctx); err != nil {
+			if _, err := session.AttachGodot(ctx, config); err != nil {
 				return nil, FormatError(
 					"Failed to attach to running game",
 					"command=attach",
@@ -58,14 +96,17 @@ godot_attach()`,
 						"Ensure the game is running",
 						"Ensure the game was started with --remote-debug",
 						"Check that the game is connecting to the correct port (default 6007)",
+						describeTimeline(session.GetClient().LaunchTimeline()),
 					},
 					err,
 				)
 			}
 
 			return map[string]interface{}{
-				"status":  "attached",
-				"message": "Successfully attached to running game",
+				"status":   "attached",
+				"message":  "Successfully attached to running game",
+				"state":    session.GetState().String(),
+				"timeline": formatTimeline(session.GetClient().LaunchTimeline()),
 			}, nil
 		},
 	})
@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// Caps on what godot_get_audio_state reads, since every value is an evaluate
+// request
+const (
+	maxAudioBuses   = 32
+	maxAudioPlayers = 64
+)
+
+// silentVolumeDb is the volume below which a bus or player is inaudible
+const silentVolumeDb = -60.0
+
+// audioPlayerClasses are the stream player classes found in the scene tree
+var audioPlayerClasses = []string{"AudioStreamPlayer", "AudioStreamPlayer2D", "AudioStreamPlayer3D"}
+
+// audioBus is one AudioServer bus
+type audioBus struct {
+	Index    int      `json:"index"`
+	Name     string   `json:"name"`
+	VolumeDb *float64 `json:"volume_db"`
+	Mute     bool     `json:"mute"`
+	Solo     bool     `json:"solo"`
+	Bypass   bool     `json:"bypass_effects"`
+	Send     string   `json:"send,omitempty"`
+	Effects  int      `json:"effect_count"`
+}
+
+// audioPlayer is one AudioStreamPlayer(2D/3D) in the scene tree
+type audioPlayer struct {
+	trackedNode
+	Path     string   `json:"path"`
+	Playing  bool     `json:"playing"`
+	Paused   bool     `json:"stream_paused"`
+	Bus      string   `json:"bus"`
+	VolumeDb *float64 `json:"volume_db"`
+	Stream   string   `json:"stream"`
+	Position *float64 `json:"position,omitempty"`
+}
+
+// audioReader evaluates expressions in one frame
+type audioReader struct {
+	ctx     context.Context
+	client  expressionEvaluator
+	frameId int
+}
+
+// eval evaluates expression, returning its result
+func (r audioReader) eval(expression string) (string, error) {
+	resp, err := r.client.Evaluate(r.ctx, expression, r.frameId, "watch")
+	if err != nil {
+		return "", err
+	}
+	return resp.Body.Result, nil
+}
+
+// float evaluates expression as a float; nil if it fails or isn't one
+func (r audioReader) float(expression string) *float64 {
+	value, err := r.eval(expression)
+	if err != nil {
+		return nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// flag evaluates expression as a bool; false if it fails
+func (r audioReader) flag(expression string) bool {
+	value, err := r.eval(expression)
+	return err == nil && value == "true"
+}
+
+// readBuses reads the AudioServer bus layout
+func (r audioReader) readBuses() ([]audioBus, error) {
+	countText, err := r.eval("AudioServer.bus_count")
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countText))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected bus count %q", countText)
+	}
+	if count > maxAudioBuses {
+		count = maxAudioBuses
+	}
+
+	buses := make([]audioBus, 0, count)
+	for i := 0; i < count; i++ {
+		bus := audioBus{Index: i}
+		name, _ := r.eval(fmt.Sprintf("AudioServer.get_bus_name(%d)", i))
+		bus.Name = unquoteGodotName(name)
+		bus.VolumeDb = r.float(fmt.Sprintf("AudioServer.get_bus_volume_db(%d)", i))
+		bus.Mute = r.flag(fmt.Sprintf("AudioServer.is_bus_mute(%d)", i))
+		bus.Solo = r.flag(fmt.Sprintf("AudioServer.is_bus_solo(%d)", i))
+		bus.Bypass = r.flag(fmt.Sprintf("AudioServer.is_bus_bypassing_effects(%d)", i))
+		if i > 0 {
+			send, _ := r.eval(fmt.Sprintf("AudioServer.get_bus_send(%d)", i))
+			bus.Send = unquoteGodotName(send)
+		}
+		if effects, err := r.eval(fmt.Sprintf("AudioServer.get_bus_effect_count(%d)", i)); err == nil {
+			bus.Effects, _ = strconv.Atoi(strings.TrimSpace(effects))
+		}
+		buses = append(buses, bus)
+	}
+	return buses, nil
+}
+
+// readPlayers finds the stream players in the scene tree and reads their
+// state. Returns the players (up to maxAudioPlayers) and how many were found.
+func (r audioReader) readPlayers() ([]audioPlayer, int, error) {
+	var nodes []trackedNode
+	for _, class := range audioPlayerClasses {
+		value, err := r.eval(fmt.Sprintf(`str(Engine.get_main_loop().root.find_children("*", %q, true, false))`, class))
+		if err != nil {
+			return nil, 0, err
+		}
+		nodes = append(nodes, parseNodeList(value)...)
+	}
+	found := len(nodes)
+	if len(nodes) > maxAudioPlayers {
+		nodes = nodes[:maxAudioPlayers]
+	}
+
+	players := make([]audioPlayer, 0, len(nodes))
+	for _, node := range nodes {
+		self := fmt.Sprintf("instance_from_id(%d)", node.ID)
+		player := audioPlayer{trackedNode: node}
+		path, _ := r.eval(self + ".get_path()")
+		player.Path = unquoteGodotName(path)
+		player.Playing = r.flag(self + ".playing")
+		player.Paused = r.flag(self + ".stream_paused")
+		bus, _ := r.eval(self + ".bus")
+		player.Bus = unquoteGodotName(bus)
+		player.VolumeDb = r.float(self + ".volume_db")
+		if stream, err := r.eval(fmt.Sprintf("str(%s.stream)", self)); err == nil && stream != "<null>" {
+			// Streams created in code have no resource path; show the object instead
+			path, _ := r.eval(self + ".stream.resource_path")
+			if player.Stream = unquoteGodotName(path); player.Stream == "" {
+				player.Stream = stream
+			}
+		}
+		if player.Playing {
+			player.Position = r.float(self + ".get_playback_position()")
+		}
+		players = append(players, player)
+	}
+	return players, found, nil
+}
+
+// audioHints explains why players might not be heard: muted, silent, or
+// soloed buses, unknown buses, missing streams, or nothing playing
+func audioHints(buses []audioBus, players []audioPlayer) []string {
+	hints := []string{}
+	byName := map[string]audioBus{}
+	solo := false
+	for _, bus := range buses {
+		byName[bus.Name] = bus
+		solo = solo || bus.Solo
+		if bus.Mute {
+			hints = append(hints, fmt.Sprintf("Bus %s is muted", bus.Name))
+		} else if bus.VolumeDb != nil && *bus.VolumeDb <= silentVolumeDb {
+			hints = append(hints, fmt.Sprintf("Bus %s is at %.1f dB, which is inaudible", bus.Name, *bus.VolumeDb))
+		}
+	}
+	if solo {
+		hints = append(hints, "A bus is soloed, so buses that aren't soloed are silent")
+	}
+
+	playing := 0
+	for _, player := range players {
+		if player.Stream == "" {
+			hints = append(hints, fmt.Sprintf("%s has no stream", player.Path))
+		}
+		if !player.Playing {
+			continue
+		}
+		playing++
+		if _, ok := byName[player.Bus]; !ok && player.Bus != "" {
+			hints = append(hints, fmt.Sprintf("%s plays on bus %s, which doesn't exist (Master is used instead)", player.Path, player.Bus))
+		}
+		if player.Paused {
+			hints = append(hints, fmt.Sprintf("%s is playing but stream_paused is set", player.Path))
+		}
+		if player.VolumeDb != nil && *player.VolumeDb <= silentVolumeDb {
+			hints = append(hints, fmt.Sprintf("%s is at %.1f dB, which is inaudible", player.Path, *player.VolumeDb))
+		}
+	}
+	if playing == 0 {
+		hints = append(hints, "No stream player is playing")
+	}
+	return hints
+}
+
+// readAudioState reads the bus layout and the stream players in the scene tree
+func readAudioState(ctx context.Context, client expressionEvaluator, frameId int) (map[string]interface{}, error) {
+	r := audioReader{ctx: ctx, client: client, frameId: frameId}
+	buses, err := r.readBuses()
+	if err != nil {
+		return nil, err
+	}
+	players, found, err := r.readPlayers()
+	if err != nil {
+		return nil, err
+	}
+
+	playing := 0
+	for _, player := range players {
+		if player.Playing {
+			playing++
+		}
+	}
+	result := map[string]interface{}{
+		"status":        "success",
+		"buses":         buses,
+		"players":       players,
+		"playing_count": playing,
+		"hints":         audioHints(buses, players),
+	}
+	if device, err := r.eval("AudioServer.output_device"); err == nil {
+		result["output_device"] = unquoteGodotName(device)
+	}
+	if found > len(players) {
+		result["players_truncated"] = true
+		result["player_count"] = found
+	}
+	return result, nil
+}
+
+// RegisterAudioTools registers godot_get_audio_state
+func RegisterAudioTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_audio_state",
+		Description: `Get the audio bus layout and the stream players in the scene tree.
+
+Returns the AudioServer buses (volume, mute, solo, effects bypass, send bus)
+and every AudioStreamPlayer, AudioStreamPlayer2D, and AudioStreamPlayer3D in
+the scene tree (playing, bus, volume, stream, playback position). Hints
+point out common causes of missing sound: muted or inaudible buses, a soloed
+bus, a player on a bus that doesn't exist, a missing stream, or nothing
+playing at all.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Every value is read with a separate evaluate request, so large scenes take a
+moment; at most 32 buses and 64 players are read.
+
+Example: Why is there no sound?
+godot_get_audio_state()
+→ {"buses": [{"name": "Master", "volume_db": 0, ...}, {"name": "SFX", "mute": true, ...}],
+   "players": [{"path": "/root/Main/Jump", "playing": true, "bus": "SFX", ...}],
+   "hints": ["Bus SFX is muted"]}`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get audio state"); err != nil {
+				return nil, err
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := readAudioState(ctx, session.GetClient(), 0)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read audio state",
+					"AudioServer",
+					[]string{
+						"The game might have resumed; pause it and try again",
+						"Check that the game is running a scene tree (Engine.get_main_loop())",
+					},
+					err,
+				)
+			}
+			return result, nil
+		},
+	})
+}
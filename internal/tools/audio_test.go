@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestReadAudioState(t *testing.T) {
+	jump := "instance_from_id(501)"
+	music := "instance_from_id(502)"
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		"AudioServer.bus_count":               "2",
+		"AudioServer.get_bus_name(0)":         `"Master"`,
+		"AudioServer.get_bus_volume_db(0)":    "0",
+		"AudioServer.is_bus_mute(0)":          "false",
+		"AudioServer.get_bus_name(1)":         `&"SFX"`,
+		"AudioServer.get_bus_volume_db(1)":    "-6",
+		"AudioServer.is_bus_mute(1)":          "true",
+		"AudioServer.get_bus_send(1)":         `&"Master"`,
+		"AudioServer.get_bus_effect_count(1)": "2",
+		"AudioServer.output_device":           `"Default"`,
+		`str(Engine.get_main_loop().root.find_children("*", "AudioStreamPlayer", true, false))`:   "[Music:<AudioStreamPlayer#502>]",
+		`str(Engine.get_main_loop().root.find_children("*", "AudioStreamPlayer2D", true, false))`: "[Jump:<AudioStreamPlayer2D#501>]",
+		`str(Engine.get_main_loop().root.find_children("*", "AudioStreamPlayer3D", true, false))`: "[]",
+		jump + ".get_path()":              `^"/root/Main/Jump"`,
+		jump + ".playing":                 "true",
+		jump + ".bus":                     `&"SFX"`,
+		jump + ".volume_db":               "0",
+		"str(" + jump + ".stream)":        "<AudioStreamWAV#-9223372>",
+		jump + ".stream.resource_path":    `"res://sfx/jump.wav"`,
+		jump + ".get_playback_position()": "0.2",
+		music + ".get_path()":             `^"/root/Main/Music"`,
+		music + ".playing":                "false",
+		music + ".bus":                    `&"Music"`,
+		"str(" + music + ".stream)":       "<null>",
+	}}
+
+	result, err := readAudioState(context.Background(), eval, 0)
+	if err != nil {
+		t.Fatalf("readAudioState failed: %v", err)
+	}
+	buses := result["buses"].([]audioBus)
+	if len(buses) != 2 || buses[1].Name != "SFX" || !buses[1].Mute || buses[1].Send != "Master" || buses[1].Effects != 2 {
+		t.Errorf("unexpected buses: %+v", buses)
+	}
+	players := result["players"].([]audioPlayer)
+	if len(players) != 2 || result["playing_count"] != 1 {
+		t.Fatalf("unexpected players: %+v", players)
+	}
+	if players[1].Path != "/root/Main/Jump" || players[1].Stream != "res://sfx/jump.wav" || *players[1].Position != 0.2 {
+		t.Errorf("unexpected jump player: %+v", players[1])
+	}
+	want := []string{"Bus SFX is muted", "/root/Main/Music has no stream"}
+	if !reflect.DeepEqual(result["hints"], want) {
+		t.Errorf("expected hints %v, got %v", want, result["hints"])
+	}
+}
+
+func TestAudioHints_NothingPlaying(t *testing.T) {
+	volume := -80.0
+	hints := audioHints([]audioBus{{Name: "Master", VolumeDb: &volume}}, nil)
+	want := []string{"Bus Master is at -80.0 dB, which is inaudible", "No stream player is playing"}
+	if !reflect.DeepEqual(hints, want) {
+		t.Errorf("expected hints %v, got %v", want, hints)
+	}
+}
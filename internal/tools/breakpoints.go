@@ -3,11 +3,189 @@ package tools
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
 )
 
+// Outcomes of a requested breakpoint, as reported by diagnoseBreakpoints
+const (
+	breakpointSet        = "set"        // Verified at the requested line
+	breakpointMoved      = "moved"      // Verified at a different line
+	breakpointUnverified = "unverified" // Acknowledged but not verified
+	breakpointRejected   = "rejected"   // Not returned at all
+)
+
+// breakpointDiagnosis explains what Godot did with one requested breakpoint
+type breakpointDiagnosis struct {
+	RequestedLine int    `json:"requested_line"`
+	ActualLine    int    `json:"actual_line,omitempty"`
+	Status        string `json:"status"` // One of the breakpoint* outcomes
+	Diagnosis     string `json:"diagnosis"`
+}
+
+// diagnoseBreakpoints compares the lines requested for path with the
+// breakpoints Godot returned, which DAP returns in request order
+func diagnoseBreakpoints(path string, requested []int, returned []godap.Breakpoint) []breakpointDiagnosis {
+	diagnoses := make([]breakpointDiagnosis, len(requested))
+	for i, line := range requested {
+		d := breakpointDiagnosis{RequestedLine: line}
+		switch {
+		case i >= len(returned):
+			d.Status = breakpointRejected
+			d.Diagnosis = fmt.Sprintf("line %d rejected: Godot returned no breakpoint for it", line)
+		case !returned[i].Verified:
+			d.Status = breakpointUnverified
+			d.ActualLine = returned[i].Line
+			d.Diagnosis = unverifiedReason(path, line, returned[i])
+		case returned[i].Line != line:
+			d.Status = breakpointMoved
+			d.ActualLine = returned[i].Line
+			d.Diagnosis = fmt.Sprintf("line %d not executable, moved to %d", line, returned[i].Line)
+		default:
+			d.Status = breakpointSet
+			d.ActualLine = line
+			d.Diagnosis = fmt.Sprintf("set at line %d", line)
+		}
+		diagnoses[i] = d
+	}
+	return diagnoses
+}
+
+// unverifiedReason explains an unverified breakpoint, preferring Godot's own
+// message. Godot doesn't say why, so a missing file is checked here.
+func unverifiedReason(path string, line int, bp godap.Breakpoint) string {
+	if bp.Message != "" {
+		return fmt.Sprintf("line %d not verified: %s", line, bp.Message)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Sprintf("line %d not verified: file not found", line)
+	}
+	return fmt.Sprintf("line %d not verified: file not loaded or line not executable (Godot verifies it when the script loads)", line)
+}
+
+// breakpointWarnings returns the diagnoses of breakpoints that weren't set
+// exactly as requested
+func breakpointWarnings(diagnoses []breakpointDiagnosis) []string {
+	var warnings []string
+	for _, d := range diagnoses {
+		if d.Status != breakpointSet {
+			warnings = append(warnings, d.Diagnosis)
+		}
+	}
+	return warnings
+}
+
+// withoutLine returns the requested lines of a file minus the breakpoint at
+// line, matched by requested line or by the line the adapter moved it to
+// (acked). Reports whether a breakpoint was removed.
+func withoutLine(requested []int, acked []dap.BreakpointState, line int) ([]int, bool) {
+	var kept []int
+	removed := false
+	for _, l := range requested {
+		if l == line {
+			removed = true
+			continue
+		}
+		if state, ok := ackedState(acked, l); ok && state.Line == line {
+			removed = true
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept, removed
+}
+
+// ackedState finds the adapter's state of the breakpoint requested at line
+func ackedState(acked []dap.BreakpointState, line int) (dap.BreakpointState, bool) {
+	for _, state := range acked {
+		if state.RequestedLine == line {
+			return state, true
+		}
+	}
+	return dap.BreakpointState{}, false
+}
+
+// listedBreakpoint is one breakpoint reported by godot_list_breakpoints
+type listedBreakpoint struct {
+	File          string `json:"file"`
+	RequestedLine int    `json:"requested_line,omitempty"` // 0 for breakpoints from the editor
+	ActualLine    int    `json:"actual_line,omitempty"`
+	Verified      bool   `json:"verified"`
+	Condition     string `json:"condition,omitempty"`
+	HitCondition  string `json:"hit_condition,omitempty"`
+	LogMessage    string `json:"log_message,omitempty"`
+	Function      string `json:"function,omitempty"` // Function of a breakpoint set by godot_set_function_breakpoint
+	Origin        string `json:"origin,omitempty"`   // "editor" for breakpoints Godot announced itself
+}
+
+// line returns the line a listed breakpoint is sorted by
+func (b listedBreakpoint) line() int {
+	if b.RequestedLine == 0 {
+		return b.ActualLine
+	}
+	return b.RequestedLine
+}
+
+// listBreakpoints returns the requested breakpoints sorted by file and line,
+// with the state the adapter last reported for each (acked), followed in the
+// same order by breakpoints Godot announced itself, such as ones set in the
+// editor
+func listBreakpoints(requested []dap.RequestedBreakpoint, acked map[string][]dap.BreakpointState) []listedBreakpoint {
+	var listed []listedBreakpoint
+	for path, states := range acked {
+		for _, state := range states {
+			if state.RequestedLine == 0 {
+				listed = append(listed, listedBreakpoint{File: path, ActualLine: state.Line, Verified: state.Verified, Origin: "editor"})
+			}
+		}
+	}
+	for _, r := range requested {
+		bp := listedBreakpoint{
+			File:          r.File,
+			RequestedLine: r.Line,
+			Condition:     r.Condition,
+			HitCondition:  r.HitCondition,
+			LogMessage:    r.LogMessage,
+			Function:      r.Function,
+		}
+		if state, ok := ackedState(acked[r.File], r.Line); ok {
+			bp.ActualLine = state.Line
+			bp.Verified = state.Verified
+		}
+		listed = append(listed, bp)
+	}
+	sort.Slice(listed, func(i, j int) bool {
+		if listed[i].File != listed[j].File {
+			return listed[i].File < listed[j].File
+		}
+		if listed[i].Origin != listed[j].Origin {
+			return listed[i].Origin == ""
+		}
+		return listed[i].line() < listed[j].line()
+	})
+	return listed
+}
+
+// withLineValue returns a copy of a file's per-line values with line set to
+// value, or removed if value is empty
+func withLineValue(values map[int]string, line int, value string) map[int]string {
+	updated := make(map[int]string, len(values)+1)
+	for l, v := range values {
+		if l != line {
+			updated[l] = v
+		}
+	}
+	if value != "" {
+		updated[line] = value
+	}
+	return updated
+}
+
 // RegisterBreakpointTools registers breakpoint management tools
 func RegisterBreakpointTools(server *mcp.Server) {
 	// godot_set_breakpoint - Set a breakpoint
@@ -16,7 +194,9 @@ func RegisterBreakpointTools(server *mcp.Server) {
 		Description: `Set a breakpoint in a GDScript file at the specified line.
 
 This tool sets a breakpoint that will pause game execution when that line is reached.
-The breakpoint will be active for all subsequent runs until cleared.
+The breakpoint will be active for all subsequent runs until cleared. The file's
+other breakpoints, logpoints, and function breakpoints are kept; a logpoint at
+the same line becomes a plain breakpoint.
 
 Prerequisites:
 - Must be connected to Godot DAP server (call godot_connect first)
@@ -29,17 +209,40 @@ Use this tool:
 - To inspect variables at specific locations
 
 Godot will verify the breakpoint and may adjust the line number if the specified
-line is not executable (e.g., blank line, comment). The verified breakpoint
-location will be returned.
+line is not executable (e.g., blank line, comment). The result's diagnosis
+explains what happened, e.g. "line 12 not executable, moved to 14"; unverified
+breakpoints carry a reason such as "file not found" or "file not loaded".
 
 File path requirements:
 - Can be absolute path: /path/to/project/scripts/player.gd
 - Can be res:// path: res://scripts/player.gd (Requires 'project' arg in godot_connect)
 - Must point to a .gd (GDScript) file
 
+C# (.cs) scripts are rejected with guidance: in Godot .NET projects, C# code is
+debugged via the .NET debugger, not Godot's DAP server.
+
+Conditional breakpoints: pass condition, a GDScript expression evaluated in
+the breakpoint's frame, to pause only when it is true. This cuts the noise of
+breakpoints in loops or per-frame code. Godot's DAP server doesn't evaluate
+conditions itself, so the server checks the condition at each hit and resumes
+the game immediately when it is false; a condition that fails to evaluate
+leaves the game paused.
+
+Hit-count breakpoints: pass hit_condition to pause only on some hits, e.g.
+">= 10" to skip the first nine. It accepts "N" (the Nth hit only), "== N",
+">= N", "> N", "<= N", "< N", and "% N" (every Nth hit). With a condition,
+only hits where the condition is true are counted. Counts restart when the
+breakpoint is set again and when the game is relaunched.
+
 Example: Set breakpoint in player script
 godot_set_breakpoint(file="res://scripts/player.gd", line=45)
 
+Example: Pause only on the iteration that matters
+godot_set_breakpoint(file="res://scripts/spawner.gd", line=30, condition="i == 997")
+
+Example: Pause from the 500th frame on
+godot_set_breakpoint(file="res://scripts/enemy.gd", line=88, hit_condition=">= 500")
+
 Example: Set breakpoint with absolute path
 godot_set_breakpoint(file="/Users/dev/myproject/player.gd", line=12)`,
 
@@ -49,12 +252,26 @@ godot_set_breakpoint(file="/Users/dev/myproject/player.gd", line=12)`,
 				Type:        "string",
 				Required:    true,
 				Description: "Path to GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
 			},
 			{
 				Name:        "line",
 				Type:        "number",
 				Required:    true,
 				Description: "Line number where breakpoint should be set (1-indexed)",
+				Minimum:     mcp.Float64(1),
+			},
+			{
+				Name:        "condition",
+				Type:        "string",
+				Required:    false,
+				Description: "GDScript expression; the game only pauses at this line when it evaluates true (e.g. \"health <= 0\")",
+			},
+			{
+				Name:        "hit_condition",
+				Type:        "string",
+				Required:    false,
+				Description: "Pause only on matching hits: \"N\" (Nth hit only), \">= N\", \"> N\", \"<= N\", \"< N\", or \"% N\" (every Nth hit)",
 			},
 		},
 
@@ -77,6 +294,28 @@ godot_set_breakpoint(file="/Users/dev/myproject/player.gd", line=12)`,
 				return nil, fmt.Errorf("line parameter is required and must be a positive integer")
 			}
 			line := int(lineFloat)
+			condition, _ := params["condition"].(string)
+			condition = strings.TrimSpace(condition)
+			hitCondition, _ := params["hit_condition"].(string)
+			hitCondition = strings.TrimSpace(hitCondition)
+			if hitCondition != "" {
+				if _, err := parseHitCondition(hitCondition); err != nil {
+					return nil, FormatError(
+						"Invalid hit_condition",
+						hitCondition,
+						[]string{
+							"Use \">= 10\" to pause from the 10th hit on",
+							"Use \"10\" to pause on the 10th hit only, or \"% 10\" for every 10th hit",
+						},
+						err,
+					)
+				}
+			}
+
+			// C# breakpoints belong to the .NET debugger, not Godot's DAP
+			if isCSharpScript(file) {
+				return nil, ErrCSharpBreakpoint(file, session.GetProjectRoot())
+			}
 
 			// Resolve file path
 			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
@@ -84,48 +323,227 @@ godot_set_breakpoint(file="/Users/dev/myproject/player.gd", line=12)`,
 				return nil, err
 			}
 
+			// setBreakpoints replaces the file's list, so resend the others
+			// with what was recorded about them. This line becomes a plain
+			// breakpoint with only the given conditions.
+			breakpoints := session.Breakpoints()
+			lines := []int{line}
+			for _, l := range breakpoints.Lines(normalizedFile) {
+				if l != line {
+					lines = append(lines, l)
+				}
+			}
+			conditions := breakpoints.AllConditions()[normalizedFile]
+			hitConditions := breakpoints.AllHitConditions()[normalizedFile]
+			logMessages := breakpoints.AllLogMessages()[normalizedFile]
+			functions := breakpoints.AllFunctions()[normalizedFile]
+			breakpoints.SetConditions(normalizedFile, withLineValue(conditions, line, condition))
+			breakpoints.SetHitConditions(normalizedFile, withLineValue(hitConditions, line, hitCondition))
+			breakpoints.SetLogMessages(normalizedFile, withLineValue(logMessages, line, ""))
+			breakpoints.SetFunctions(normalizedFile, withLineValue(functions, line, ""))
+
 			// Send setBreakpoints request
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
 			client := session.GetClient()
-			resp, err := client.SetBreakpoints(ctx, normalizedFile, []int{line})
+			resp, err := client.SetSourceBreakpoints(ctx, normalizedFile, breakpoints.SourceBreakpoints(normalizedFile, lines))
 			if err != nil {
+				breakpoints.SetConditions(normalizedFile, conditions)
+				breakpoints.SetHitConditions(normalizedFile, hitConditions)
+				breakpoints.SetLogMessages(normalizedFile, logMessages)
+				breakpoints.SetFunctions(normalizedFile, functions)
 				return nil, fmt.Errorf("failed to set breakpoint: %w", err)
 			}
+			breakpoints.Set(normalizedFile, lines)
+			d := diagnoseBreakpoints(normalizedFile, lines, resp.Body.Breakpoints)[0]
+			breakpointConditions.resetHit(normalizedFile, line)
+			enforceConditions(session)
+			autosaveSession()
+
+			switch d.Status {
+			case breakpointRejected:
+				return nil, fmt.Errorf("no breakpoints were set: %s", d.Diagnosis)
+			case breakpointUnverified:
+				result := map[string]interface{}{
+					"status":         "unverified",
+					"message":        "Breakpoint set but not verified by Godot",
+					"file":           file,
+					"requested_line": line,
+					"actual_line":    d.ActualLine,
+					"reason":         d.Diagnosis,
+				}
+				if isCSharpProject(session.GetProjectRoot()) {
+					result["csharp_project"] = true
+					result["note"] = "This is a C# project: only GDScript code is debuggable through Godot's DAP. C# code needs the .NET debugger."
+				}
+				return result, nil
+			}
+
+			result := map[string]interface{}{
+				"status":         "verified",
+				"message":        fmt.Sprintf("Breakpoint set at %s:%d", file, d.ActualLine),
+				"file":           file,
+				"requested_line": line,
+				"actual_line":    d.ActualLine,
+				"id":             resp.Body.Breakpoints[0].Id,
+				"diagnosis":      d.Diagnosis,
+			}
+			var pausesWhen []string
+			if condition != "" {
+				result["condition"] = condition
+				pausesWhen = append(pausesWhen, condition)
+			}
+			if hitCondition != "" {
+				result["hit_condition"] = hitCondition
+				pausesWhen = append(pausesWhen, "hit count "+hitCondition)
+			}
+			if len(pausesWhen) > 0 {
+				result["message"] = fmt.Sprintf("Conditional breakpoint set at %s:%d (pauses when %s)", file, d.ActualLine, strings.Join(pausesWhen, " and "))
+			}
+
+			// Add message if line was adjusted
+			if d.Status == breakpointMoved {
+				result["adjusted"] = true
+				result["message"] = fmt.Sprintf("Breakpoint set at %s:%d (line %d is not executable)", file, d.ActualLine, line)
+			}
+			if len(pausesWhen) > 0 && d.Status == breakpointMoved {
+				result["warning"] = fmt.Sprintf("The condition is checked at line %d; set the breakpoint at line %d for it to apply", line, d.ActualLine)
+			}
+
+			return result, nil
+		},
+	})
+
+	// godot_set_logpoint - Print a message instead of pausing
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_set_logpoint",
+		Description: `Set a logpoint: a breakpoint that prints a message instead of pausing.
+
+Each time the line runs, the message is added to the game's output (see
+godot_get_output) and the game keeps running. Expressions in braces are
+evaluated in the line's frame, so "hp={health} at {position}" prints the
+current values; write {{ and }} for literal braces.
+
+Like godot_set_breakpoint, this keeps the file's other breakpoints and
+logpoints. Godot's DAP server doesn't print log messages itself, so the server
+briefly pauses at the line, evaluates the message, and continues; expect the
+overhead of a pause per hit, as with tracepoints.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To trace values in per-frame code without stopping the game
+- To add print() debugging without editing and reloading scripts
+
+Example: Log the player's health when hit
+godot_set_logpoint(file="res://scripts/player.gd", line=45, message="took {damage}, hp={health}")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "file",
+				Type:        "string",
+				Required:    true,
+				Description: "Path to GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
+			},
+			{
+				Name:        "line",
+				Type:        "number",
+				Required:    true,
+				Description: "Line number of the logpoint (1-indexed)",
+				Minimum:     mcp.Float64(1),
+			},
+			{
+				Name:        "message",
+				Type:        "string",
+				Required:    true,
+				Description: "Message to print; {expression} is replaced with its value (e.g. \"hp={health}\")",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
 
-			// Check if breakpoint was verified
-			if len(resp.Body.Breakpoints) == 0 {
-				return nil, fmt.Errorf("no breakpoints were set (file may not exist or line may be invalid)")
+			file, _ := params["file"].(string)
+			lineFloat, ok := params["line"].(float64)
+			if !ok || lineFloat < 1 {
+				return nil, fmt.Errorf("line parameter is required and must be a positive integer")
+			}
+			line := int(lineFloat)
+			message, _ := params["message"].(string)
+			if strings.TrimSpace(message) == "" {
+				return nil, fmt.Errorf("message parameter is required and must be a non-empty string")
 			}
 
-			bp := resp.Body.Breakpoints[0]
-			if !bp.Verified {
+			if isCSharpScript(file) {
+				return nil, ErrCSharpBreakpoint(file, session.GetProjectRoot())
+			}
+			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
+			if err != nil {
+				return nil, err
+			}
+
+			// setBreakpoints replaces the file's list, so resend the others
+			lines := []int{line}
+			for _, l := range session.Breakpoints().Lines(normalizedFile) {
+				if l != line {
+					lines = append(lines, l)
+				}
+			}
+			previous := session.Breakpoints().AllLogMessages()[normalizedFile]
+			messages := map[int]string{line: message}
+			for l, m := range previous {
+				if l != line {
+					messages[l] = m
+				}
+			}
+			session.Breakpoints().SetLogMessages(normalizedFile, messages)
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			resp, err := client.SetSourceBreakpoints(ctx, normalizedFile, session.Breakpoints().SourceBreakpoints(normalizedFile, lines))
+			if err != nil {
+				session.Breakpoints().SetLogMessages(normalizedFile, previous)
+				return nil, fmt.Errorf("failed to set logpoint: %w", err)
+			}
+			session.Breakpoints().Set(normalizedFile, lines)
+			enforceConditions(session)
+			autosaveSession()
+
+			d := diagnoseBreakpoints(normalizedFile, lines, resp.Body.Breakpoints)[0]
+			switch d.Status {
+			case breakpointRejected:
+				return nil, fmt.Errorf("no logpoint was set: %s", d.Diagnosis)
+			case breakpointUnverified:
 				return map[string]interface{}{
 					"status":         "unverified",
-					"message":        "Breakpoint set but not verified by Godot",
+					"message":        "Logpoint set but not verified by Godot",
 					"file":           file,
 					"requested_line": line,
-					"actual_line":    bp.Line,
-					"reason":         "File may not be loaded or line may not be executable",
+					"reason":         d.Diagnosis,
 				}, nil
 			}
 
 			result := map[string]interface{}{
 				"status":         "verified",
-				"message":        fmt.Sprintf("Breakpoint set at %s:%d", file, bp.Line),
+				"message":        fmt.Sprintf("Logpoint set at %s:%d", file, d.ActualLine),
 				"file":           file,
 				"requested_line": line,
-				"actual_line":    bp.Line,
-				"id":             bp.Id,
+				"actual_line":    d.ActualLine,
+				"log_message":    message,
+				"diagnosis":      d.Diagnosis,
 			}
-
-			// Add message if line was adjusted
-			if bp.Line != line {
+			if d.Status == breakpointMoved {
 				result["adjusted"] = true
-				result["message"] = fmt.Sprintf("Breakpoint set at %s:%d (adjusted from line %d)", file, bp.Line, line)
+				result["warning"] = fmt.Sprintf("Godot moved the logpoint to line %d, where it pauses instead of printing; set it at line %d", d.ActualLine, d.ActualLine)
 			}
-
 			return result, nil
 		},
 	})
@@ -133,26 +551,30 @@ godot_set_breakpoint(file="/Users/dev/myproject/player.gd", line=12)`,
 	// godot_clear_breakpoint - Clear a breakpoint
 	server.RegisterTool(mcp.Tool{
 		Name: "godot_clear_breakpoint",
-		Description: `Clear a breakpoint from a GDScript file.
+		Description: `Clear breakpoints from a GDScript file.
+
+With line, this removes only the breakpoint or logpoint at that line and keeps
+the file's others. Without it, every breakpoint in the file is cleared.
 
-This tool removes the breakpoint at the specified line in the given file.
-Technically, this sets an empty breakpoint list for the file, which clears
-all breakpoints in that file.
+DAP has no request to remove one breakpoint: each setBreakpoints request
+replaces the file's whole list. The server remembers the breakpoints set
+through godot_set_breakpoint and godot_set_logpoint and resends the ones to
+keep, with their conditions. The line may be the one requested or the one
+Godot moved the breakpoint to.
 
 Prerequisites:
 - Must be connected to Godot DAP server (call godot_connect first)
-- Breakpoint must have been previously set at the specified location
+- For line, a breakpoint must have been set at that line (see godot_list_breakpoints)
 
 Use this tool:
 - When you no longer need a breakpoint
 - To disable debugging at a specific location
 - To clean up breakpoints after debugging
 
-Note: Due to DAP protocol design, this clears ALL breakpoints in the specified file.
-If you want to keep some breakpoints and remove others, you'll need to set
-breakpoints again for the lines you want to keep.
+Example: Clear one breakpoint in the player script
+godot_clear_breakpoint(file="res://scripts/player.gd", line=45)
 
-Example: Clear breakpoint in player script
+Example: Clear every breakpoint in the file
 godot_clear_breakpoint(file="res://scripts/player.gd")
 
 Example: Clear with absolute path
@@ -164,6 +586,14 @@ godot_clear_breakpoint(file="/Users/dev/myproject/player.gd")`,
 				Type:        "string",
 				Required:    true,
 				Description: "Path to GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
+			},
+			{
+				Name:        "line",
+				Type:        "number",
+				Required:    false,
+				Description: "Line of the breakpoint to clear (default: clear all breakpoints in the file)",
+				Minimum:     mcp.Float64(1),
 			},
 		},
 
@@ -180,26 +610,115 @@ godot_clear_breakpoint(file="/Users/dev/myproject/player.gd")`,
 				return nil, fmt.Errorf("file parameter is required and must be a non-empty string")
 			}
 
+			// C# breakpoints belong to the .NET debugger, not Godot's DAP
+			if isCSharpScript(file) {
+				return nil, ErrCSharpBreakpoint(file, session.GetProjectRoot())
+			}
+
 			// Resolve file path
 			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
 			if err != nil {
 				return nil, err
 			}
 
-			// Send setBreakpoints with empty list to clear all breakpoints
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
 			client := session.GetClient()
-			_, err = client.SetBreakpoints(ctx, normalizedFile, []int{})
+			lineFloat, hasLine := params["line"].(float64)
+			if !hasLine {
+				// Send setBreakpoints with empty list to clear all breakpoints
+				_, err = client.SetBreakpoints(ctx, normalizedFile, []int{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to clear breakpoints: %w", err)
+				}
+				session.Breakpoints().Set(normalizedFile, nil)
+				autosaveSession()
+
+				return map[string]interface{}{
+					"status":  "cleared",
+					"message": fmt.Sprintf("All breakpoints cleared in %s", file),
+					"file":    file,
+				}, nil
+			}
+
+			line := int(lineFloat)
+			requested := session.Breakpoints().Lines(normalizedFile)
+			kept, removed := withoutLine(requested, client.AcknowledgedBreakpoints()[normalizedFile], line)
+			if !removed {
+				return nil, FormatError(
+					"No breakpoint at this line",
+					fmt.Sprintf("%s:%d (breakpoints in file: %v)", file, line, requested),
+					[]string{
+						"Call godot_list_breakpoints to see the active breakpoints",
+						"Omit line to clear every breakpoint in the file",
+					},
+					nil,
+				)
+			}
+
+			resp, err := client.SetSourceBreakpoints(ctx, normalizedFile, session.Breakpoints().SourceBreakpoints(normalizedFile, kept))
+			if err != nil {
+				return nil, fmt.Errorf("failed to clear breakpoint: %w", err)
+			}
+			session.Breakpoints().Set(normalizedFile, kept)
+			autosaveSession()
+
+			result := map[string]interface{}{
+				"status":    "cleared",
+				"message":   fmt.Sprintf("Breakpoint cleared at %s:%d", file, line),
+				"file":      file,
+				"line":      line,
+				"remaining": kept,
+			}
+			if warnings := breakpointWarnings(diagnoseBreakpoints(normalizedFile, kept, resp.Body.Breakpoints)); len(warnings) > 0 {
+				result["warnings"] = warnings
+			}
+			return result, nil
+		},
+	})
+
+	// godot_list_breakpoints - List breakpoints across the project
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_list_breakpoints",
+		Description: `List the breakpoints and logpoints set in every file.
+
+Each entry gives the file, the requested line, the line Godot placed the
+breakpoint at, whether Godot verified it, and its condition, hit condition,
+or log message. Breakpoints are unverified until their script loads.
+
+Godot reports later changes with breakpoint events: a breakpoint verified or
+moved once its script loads shows its new line here. Breakpoints toggled in
+the Godot editor are listed with origin "editor" and no requested line.
+
+Tracepoints are listed by godot_get_tracepoint_stats instead.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To check which breakpoints are active before launching
+- To find the line to pass to godot_clear_breakpoint
+
+Example:
+godot_list_breakpoints()`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
 			if err != nil {
-				return nil, fmt.Errorf("failed to clear breakpoints: %w", err)
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
 
+			breakpoints := listBreakpoints(session.Breakpoints().Requested(), session.GetClient().AcknowledgedBreakpoints())
+			if breakpoints == nil {
+				breakpoints = []listedBreakpoint{}
+			}
 			return map[string]interface{}{
-				"status":  "cleared",
-				"message": fmt.Sprintf("All breakpoints cleared in %s", file),
-				"file":    file,
+				"status":      "success",
+				"count":       len(breakpoints),
+				"breakpoints": breakpoints,
 			}, nil
 		},
 	})
@@ -1,9 +1,17 @@
 package tools
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
 )
 
 func TestBreakpointTools_Registration(t *testing.T) {
@@ -82,3 +90,228 @@ func TestBreakpointTools_RequireSession(t *testing.T) {
 		t.Error("Breakpoint tools should require an active session")
 	}
 }
+
+func TestPathParamValidators(t *testing.T) {
+	if err := validateGodotPathParam("res://player.gd"); err != nil {
+		t.Errorf("res:// path should be valid: %v", err)
+	}
+	if err := validateGodotPathParam("/abs/player.gd"); err != nil {
+		t.Errorf("absolute path should be valid: %v", err)
+	}
+	if err := validateGodotPathParam("player.gd"); err == nil {
+		t.Error("relative path should be rejected")
+	}
+	if err := validateGodotPathParam(42.0); err == nil {
+		t.Error("non-string should be rejected")
+	}
+
+	if err := validateAbsolutePathParam("/abs"); err != nil {
+		t.Errorf("absolute dir should be valid: %v", err)
+	}
+	if err := validateAbsolutePathParam("res://scenes"); err == nil {
+		t.Error("res:// is not a directory path")
+	}
+}
+
+func TestShortenProjectPaths(t *testing.T) {
+	if currentSession() != nil {
+		t.Skip("a session's project root would take precedence")
+	}
+	saved := getDiscoveredProjectRoot()
+	defer setDiscoveredProjectRoot(saved)
+
+	setDiscoveredProjectRoot("")
+	text := `{"path":"/games/demo/player.gd"}`
+	if got := shortenProjectPaths("godot_where", text, false); got != text {
+		t.Errorf("without a project root nothing should change, got %s", got)
+	}
+
+	setDiscoveredProjectRoot("/games/demo")
+	if got := shortenProjectPaths("godot_where", text, false); got != `{"path":"res://player.gd"}` {
+		t.Errorf("expected a res:// path, got %s", got)
+	}
+	if got := shortenProjectPaths("godot_where", "cannot open /games/demo2/x.gd or /games/demo", true); got != "cannot open /games/demo2/x.gd or /games/demo" {
+		t.Errorf("paths outside the project and the root itself should stay, got %s", got)
+	}
+	if got := shortenProjectPaths("godot_save_session", `{"path":"/games/demo/.godot/session.json"}`, false); !strings.Contains(got, "/games/demo/.godot") {
+		t.Errorf("tools returning paths to pass back should keep them absolute, got %s", got)
+	}
+
+	// Only path fields are rewritten; data holding the root stays as it is
+	result := `{"files":["/games/demo/a.gd","/games/demo/b.gd"],"message":"Breakpoint set at /games/demo/a.gd:3","value":"\"/games/demo/save.dat\""}`
+	want := `{"files":["res://a.gd","res://b.gd"],"message":"Breakpoint set at res://a.gd:3","value":"\"/games/demo/save.dat\""}`
+	if got := shortenProjectPaths("godot_evaluate", result, false); got != want {
+		t.Errorf("expected only path fields rewritten:\n got %s\nwant %s", got, want)
+	}
+	if got := shortenProjectPaths("godot_get_string_chunk", `{"chunk":"/games/demo/player.gd"}`, false); got != `{"chunk":"/games/demo/player.gd"}` {
+		t.Errorf("string data containing the root should come back unchanged, got %s", got)
+	}
+	if got := shortenProjectPaths("godot_where", "tool execution failed: cannot read /games/demo/player.gd", true); got != "tool execution failed: cannot read res://player.gd" {
+		t.Errorf("error messages should be rewritten, got %s", got)
+	}
+	if got := shortenProjectPaths("godot_get_variables", "| value | /games/demo/save.dat |", false); got != "| value | /games/demo/save.dat |" {
+		t.Errorf("markdown results should be left alone, got %s", got)
+	}
+}
+
+func TestDiagnoseBreakpoints(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "player.gd")
+	if err := os.WriteFile(script, []byte("extends Node\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	returned := []godap.Breakpoint{
+		{Line: 5, Verified: true},
+		{Line: 14, Verified: true},
+		{Line: 20, Verified: false},
+		{Line: 30, Verified: false, Message: "script has errors"},
+	}
+	diagnoses := diagnoseBreakpoints(script, []int{5, 12, 20, 30, 40}, returned)
+
+	want := []struct {
+		status    string
+		diagnosis string
+	}{
+		{breakpointSet, "set at line 5"},
+		{breakpointMoved, "line 12 not executable, moved to 14"},
+		{breakpointUnverified, "file not loaded or line not executable"},
+		{breakpointUnverified, "script has errors"},
+		{breakpointRejected, "line 40 rejected"},
+	}
+	for i, w := range want {
+		d := diagnoses[i]
+		if d.Status != w.status || !strings.Contains(d.Diagnosis, w.diagnosis) {
+			t.Errorf("breakpoint %d: got %s %q, want %s containing %q", i, d.Status, d.Diagnosis, w.status, w.diagnosis)
+		}
+	}
+
+	warnings := breakpointWarnings(diagnoses)
+	if len(warnings) != 4 {
+		t.Errorf("expected warnings for all but the exact breakpoint, got %v", warnings)
+	}
+
+	missing := diagnoseBreakpoints(filepath.Join(t.TempDir(), "gone.gd"), []int{3}, []godap.Breakpoint{{Line: 3}})
+	if !strings.Contains(missing[0].Diagnosis, "file not found") {
+		t.Errorf("expected a missing file diagnosis, got %q", missing[0].Diagnosis)
+	}
+}
+
+func TestWithoutLine(t *testing.T) {
+	acked := []dap.BreakpointState{
+		{Breakpoint: godap.Breakpoint{Line: 5}, RequestedLine: 5},
+		{Breakpoint: godap.Breakpoint{Line: 14}, RequestedLine: 12},
+		{Breakpoint: godap.Breakpoint{Line: 20}, RequestedLine: 20},
+	}
+
+	kept, removed := withoutLine([]int{5, 12, 20}, acked, 20)
+	if !removed || len(kept) != 2 || kept[0] != 5 || kept[1] != 12 {
+		t.Errorf("removing by requested line: got %v, %v", kept, removed)
+	}
+	// Godot moved line 12 to 14
+	kept, removed = withoutLine([]int{5, 12, 20}, acked, 14)
+	if !removed || len(kept) != 2 || kept[0] != 5 || kept[1] != 20 {
+		t.Errorf("removing by actual line: got %v, %v", kept, removed)
+	}
+	if _, removed := withoutLine([]int{5, 12, 20}, acked, 7); removed {
+		t.Error("no breakpoint at line 7 should be removed")
+	}
+	if kept, removed := withoutLine([]int{5}, nil, 5); !removed || kept != nil {
+		t.Errorf("removing the last breakpoint: got %v, %v", kept, removed)
+	}
+}
+
+func TestListBreakpoints(t *testing.T) {
+	var registry dap.BreakpointRegistry
+	registry.Set("/p/player.gd", []int{30, 12})
+	registry.Set("/p/enemy.gd", []int{8})
+	registry.SetConditions("/p/player.gd", map[int]string{12: "health <= 0"})
+	registry.SetLogMessages("/p/player.gd", map[int]string{30: "hp={health}"})
+	acked := map[string][]dap.BreakpointState{
+		"/p/player.gd": {
+			{Breakpoint: godap.Breakpoint{Line: 30, Verified: true}, RequestedLine: 30},
+			{Breakpoint: godap.Breakpoint{Line: 14, Verified: true}, RequestedLine: 12},
+			{Breakpoint: godap.Breakpoint{Line: 2, Verified: true}}, // Set in the editor
+		},
+	}
+
+	listed := listBreakpoints(registry.Requested(), acked)
+	if len(listed) != 4 {
+		t.Fatalf("expected 4 breakpoints, got %+v", listed)
+	}
+	if got := listed[3]; got.Origin != "editor" || got.ActualLine != 2 || got.RequestedLine != 0 {
+		t.Errorf("editor breakpoint should follow the file's requested ones: got %+v", got)
+	}
+	if listed[0].File != "/p/enemy.gd" || listed[0].Verified || listed[0].ActualLine != 0 {
+		t.Errorf("unacknowledged breakpoint: got %+v", listed[0])
+	}
+	if got := listed[1]; got.RequestedLine != 12 || got.ActualLine != 14 || !got.Verified || got.Condition != "health <= 0" {
+		t.Errorf("moved conditional breakpoint: got %+v", got)
+	}
+	if got := listed[2]; got.RequestedLine != 30 || got.LogMessage != "hp={health}" {
+		t.Errorf("logpoint: got %+v", got)
+	}
+	if listBreakpoints(nil, nil) != nil {
+		t.Error("an empty registry should list nothing")
+	}
+}
+
+func TestSetBreakpoint_KeepsFileBreakpoints(t *testing.T) {
+	fixture := daptest.NewProject(t, daptest.Script{Path: "scripts/player.gd", Source: "extends Node\n"})
+	script := fixture.Path("scripts/player.gd")
+
+	var mu sync.Mutex
+	var sent []godap.SourceBreakpoint // Breakpoints of the last setBreakpoints request
+	mock := daptest.NewServer(t)
+	defer mock.Close()
+	go mock.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{mock.Success(req, nil), mock.NewEvent("initialized", nil)}
+		case "setBreakpoints":
+			args := req.(*godap.SetBreakpointsRequest).Arguments
+			mu.Lock()
+			sent = args.Breakpoints
+			mu.Unlock()
+			breakpoints := []map[string]interface{}{}
+			for i, bp := range args.Breakpoints {
+				breakpoints = append(breakpoints, map[string]interface{}{"id": i + 1, "verified": true, "line": bp.Line})
+			}
+			return []godap.Message{mock.Success(req, map[string]interface{}{"breakpoints": breakpoints})}
+		}
+		return nil
+	})
+
+	session := connectMockSession(t, mock)
+	t.Cleanup(breakpointConditions.detach)
+
+	server := mcp.NewServer()
+	RegisterBreakpointTools(server)
+	call := func(tool string, args map[string]interface{}) {
+		t.Helper()
+		args["file"] = script
+		if _, err := server.CallTool(tool, args); err != nil {
+			t.Fatalf("%s failed: %v", tool, err)
+		}
+	}
+	call("godot_set_logpoint", map[string]interface{}{"line": float64(5), "message": "hp={health}"})
+	call("godot_set_breakpoint", map[string]interface{}{"line": float64(10), "condition": "hp < 10"})
+	call("godot_set_breakpoint", map[string]interface{}{"line": float64(20)})
+	call("godot_clear_breakpoint", map[string]interface{}{"line": float64(20)})
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []godap.SourceBreakpoint{{Line: 10, Condition: "hp < 10"}, {Line: 5, LogMessage: "hp={health}"}}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Godot should keep the other breakpoint and the logpoint, got %+v", sent)
+	}
+	breakpoints := session.Breakpoints()
+	if lines := breakpoints.Lines(script); !reflect.DeepEqual(lines, []int{10, 5}) {
+		t.Errorf("registry lines: got %v", lines)
+	}
+	if got := breakpoints.Condition(script, 10); got != "hp < 10" {
+		t.Errorf("the breakpoint's condition should be kept, got %q", got)
+	}
+	if got := breakpoints.LogMessage(script, 5); got != "hp={health}" {
+		t.Errorf("the logpoint should be kept, got %q", got)
+	}
+}
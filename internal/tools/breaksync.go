@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// breakpointMove is where godot_sync_breakpoints moves one breakpoint
+type breakpointMove struct {
+	From     int
+	To       int
+	Function string // Function the breakpoint was set on, if any
+	Resolved bool   // To was found by looking the function up again
+}
+
+// planBreakpointSync works out where a file's breakpoints go: function
+// breakpoints to their function's current body line if reresolve is set,
+// and the other breakpoints at or after fromLine by shift lines. Returns
+// warnings for functions that can no longer be found, which are shifted
+// like plain breakpoints.
+func planBreakpointSync(path string, lines []int, functions map[int]string, shift int, fromLine int, reresolve bool) ([]breakpointMove, []string, error) {
+	var moves []breakpointMove
+	var warnings []string
+	for _, line := range lines {
+		m := breakpointMove{From: line, To: line, Function: functions[line]}
+		if reresolve && m.Function != "" {
+			if def, ok := findFunctionInScript(path, m.Function); ok {
+				m.To = def.BodyLine
+				m.Resolved = true
+				moves = append(moves, m)
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("func %s no longer found; its breakpoint at line %d was shifted like the others", m.Function, line))
+		}
+		if line >= fromLine {
+			m.To = line + shift
+		}
+		if m.To < 1 {
+			return nil, nil, fmt.Errorf("shift %d moves the breakpoint at line %d to line %d", shift, line, m.To)
+		}
+		moves = append(moves, m)
+	}
+	return moves, warnings, nil
+}
+
+// RegisterBreakpointSyncTools registers godot_sync_breakpoints
+func RegisterBreakpointSyncTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_sync_breakpoints",
+		Description: `Resend a file's breakpoints after the script was edited, moving them to follow the code.
+
+Breakpoints stay on the line numbers they were set at, so inserting or
+deleting lines above them leaves them on the wrong statements. This tool
+moves the breakpoints the server recorded for the file and sends them to
+Godot again, with their conditions, hit conditions, and log messages:
+- shift moves breakpoints by a number of lines (negative for deleted
+  lines), optionally only those at or after from_line
+- functions=true looks up breakpoints set by godot_set_function_breakpoint
+  again and moves them to the first line of their function's body, wherever
+  it is now; these aren't shifted
+
+With neither, the breakpoints are resent unchanged, which also re-verifies
+them after the script was reloaded. Hit counts restart.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Breakpoints must have been set in the file through this server
+
+Example: Three lines were inserted at line 20
+godot_sync_breakpoints(file="res://scripts/player.gd", shift=3, from_line=20)
+
+Example: Follow function breakpoints after a refactor
+godot_sync_breakpoints(file="res://scripts/player.gd", functions=true)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "file",
+				Type:        "string",
+				Required:    true,
+				Description: "Path to GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
+			},
+			{
+				Name:        "shift",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Lines to move breakpoints by; negative moves them up (default: 0)",
+			},
+			{
+				Name:        "from_line",
+				Type:        "number",
+				Required:    false,
+				Default:     1,
+				Description: "Only shift breakpoints at or after this line (default: 1, all)",
+				Minimum:     mcp.Float64(1),
+			},
+			{
+				Name:        "functions",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, move function breakpoints to where their function's body is now",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			file, ok := params["file"].(string)
+			if !ok || file == "" {
+				return nil, fmt.Errorf("file parameter is required and must be a non-empty string")
+			}
+			if isCSharpScript(file) {
+				return nil, ErrCSharpBreakpoint(file, session.GetProjectRoot())
+			}
+			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
+			if err != nil {
+				return nil, err
+			}
+
+			shift := 0
+			if s, ok := params["shift"].(float64); ok {
+				shift = int(s)
+			}
+			fromLine := 1
+			if f, ok := params["from_line"].(float64); ok && f > 1 {
+				fromLine = int(f)
+			}
+			reresolve := getBoolParam(params, "functions")
+
+			lines := session.Breakpoints().Lines(normalizedFile)
+			if len(lines) == 0 {
+				return nil, FormatError(
+					"No breakpoints recorded for this file",
+					file,
+					[]string{
+						"Call godot_list_breakpoints to see the files with breakpoints",
+						"Breakpoints toggled in the Godot editor are kept in sync by the editor itself",
+					},
+					nil,
+				)
+			}
+			moves, warnings, err := planBreakpointSync(normalizedFile, lines, session.Breakpoints().AllFunctions()[normalizedFile], shift, fromLine, reresolve)
+			if err != nil {
+				return nil, FormatError("Invalid shift", file, []string{"Use a smaller negative shift, or from_line to shift only the breakpoints below the deleted lines"}, err)
+			}
+			destination := make(map[int]int, len(moves))
+			for _, m := range moves {
+				destination[m.From] = m.To
+			}
+			move := func(line int) int {
+				if to, ok := destination[line]; ok {
+					return to
+				}
+				return line
+			}
+
+			// Send first and update the registry only once Godot accepted the
+			// new lines, so a failed request leaves the breakpoints as they were
+			newLines := dap.MovedLines(lines, move)
+			sources := make([]godap.SourceBreakpoint, 0, len(newLines))
+			for _, bp := range session.Breakpoints().SourceBreakpoints(normalizedFile, lines) {
+				bp.Line = move(bp.Line)
+				if len(sources) < len(newLines) && bp.Line == newLines[len(sources)] {
+					sources = append(sources, bp)
+				}
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+			client := session.GetClient()
+			resp, err := client.SetSourceBreakpoints(ctx, normalizedFile, sources)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sync breakpoints: %w", err)
+			}
+			session.Breakpoints().Remap(normalizedFile, move)
+			breakpointConditions.resetHits(normalizedFile)
+			enforceConditions(session)
+			autosaveSession()
+
+			diagnoses := diagnoseBreakpoints(normalizedFile, newLines, resp.Body.Breakpoints)
+			entries := make([]map[string]interface{}, 0, len(moves))
+			moved := 0
+			for _, m := range moves {
+				entry := map[string]interface{}{
+					"line":     m.From,
+					"new_line": m.To,
+				}
+				if m.Function != "" {
+					entry["function"] = m.Function
+					entry["resolved"] = m.Resolved
+				}
+				if m.To != m.From {
+					moved++
+				}
+				for _, d := range diagnoses {
+					if d.RequestedLine == m.To {
+						entry["status"] = d.Status
+						entry["diagnosis"] = d.Diagnosis
+						if d.ActualLine != 0 {
+							entry["actual_line"] = d.ActualLine
+						}
+						break
+					}
+				}
+				entries = append(entries, entry)
+			}
+			if merged := len(lines) - len(newLines); merged > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d breakpoint(s) moved onto a line that already had one and were merged into it", merged))
+			}
+			warnings = append(warnings, breakpointWarnings(diagnoses)...)
+
+			result := map[string]interface{}{
+				"status":      "synced",
+				"message":     fmt.Sprintf("Resent %d breakpoint(s) in %s, %d moved", len(newLines), file, moved),
+				"file":        file,
+				"breakpoints": entries,
+			}
+			if len(warnings) > 0 {
+				result["warnings"] = warnings
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanBreakpointSync(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "player.gd", `extends Node
+
+# take_damage moved down after an edit
+
+
+func take_damage(amount):
+	health -= amount
+`)
+	path := filepath.Join(root, "player.gd")
+	lines := []int{2, 4, 10}
+	functions := map[int]string{4: "take_damage", 10: "heal"}
+
+	moves, warnings, err := planBreakpointSync(path, lines, functions, 2, 3, true)
+	if err != nil {
+		t.Fatalf("planBreakpointSync failed: %v", err)
+	}
+	want := []breakpointMove{
+		{From: 2, To: 2}, // Before from_line
+		{From: 4, To: 7, Function: "take_damage", Resolved: true}, // Looked up again, not shifted
+		{From: 10, To: 12, Function: "heal"},                      // Not found, shifted
+	}
+	if !reflect.DeepEqual(moves, want) {
+		t.Errorf("unexpected moves:\n got %+v\nwant %+v", moves, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "func heal no longer found") {
+		t.Errorf("expected a warning for heal, got %v", warnings)
+	}
+
+	// Without reresolve, function breakpoints shift like the others
+	moves, _, _ = planBreakpointSync(path, lines, functions, -1, 1, false)
+	if moves[1].To != 3 || moves[1].Resolved {
+		t.Errorf("expected take_damage's breakpoint shifted to 3, got %+v", moves[1])
+	}
+
+	if _, _, err := planBreakpointSync(path, lines, nil, -2, 1, false); err == nil || !strings.Contains(err.Error(), "line 2 to line 0") {
+		t.Errorf("shifting before line 1 should fail, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+// TestToolCatalog_Snapshot guards the tools/list output against accidental
+// churn. After an intentional change, regenerate with:
+//
+//	go test ./internal/tools -run TestToolCatalog_Snapshot -update
+func TestToolCatalog_Snapshot(t *testing.T) {
+	server := mcp.NewServer()
+	RegisterAll(server)
+
+	got, err := json.MarshalIndent(mcp.ToolListResult{Tools: server.ToolCatalog()}, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal catalog: %v", err)
+	}
+	got = append(got, '\n')
+
+	golden := filepath.Join("testdata", "tool_catalog.golden.json")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("tool catalog changed; if intended, rerun with -update and review the diff")
+	}
+}
+
+func TestToolCatalog_Sorted(t *testing.T) {
+	server := mcp.NewServer()
+	RegisterAll(server)
+
+	catalog := server.ToolCatalog()
+	for i := 1; i < len(catalog); i++ {
+		if catalog[i-1].Name >= catalog[i].Name {
+			t.Errorf("tools not sorted: %s before %s", catalog[i-1].Name, catalog[i].Name)
+		}
+	}
+}
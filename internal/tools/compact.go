@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// Output formats for inspection tools. JSON is the default; markdown_compact
+// renders the same data as terse, aligned markdown tables that take far less
+// of an LLM's context than the JSON equivalent.
+const (
+	inspectFormatJSON            = "json"
+	inspectFormatMarkdownCompact = "markdown_compact"
+)
+
+// maxCompactValueLength caps variable values in compact output; the full value
+// is still available through godot_get_variable or the JSON format
+const maxCompactValueLength = 48
+
+// inspectFormatParam is the format parameter shared by inspection tools
+func inspectFormatParam() mcp.Parameter {
+	return mcp.Parameter{
+		Name:        "format",
+		Type:        "string",
+		Required:    false,
+		Default:     inspectFormatJSON,
+		Description: "Response format: 'json' (default) or 'markdown_compact' (terse markdown tables, values truncated)",
+		Enum:        []string{inspectFormatJSON, inspectFormatMarkdownCompact},
+	}
+}
+
+// wantsCompact reports whether the caller asked for markdown_compact output
+func wantsCompact(params map[string]interface{}) bool {
+	format, _ := params["format"].(string)
+	return format == inspectFormatMarkdownCompact
+}
+
+// compactValue shortens a value for a table cell: Godot types use their
+// formatted form, newlines are flattened, and long values are truncated
+func compactValue(typeName string, value string) string {
+	if formatted := formatGodotType(typeName, value); formatted != "" {
+		value = formatted
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) > maxCompactValueLength {
+		runes := []rune(value)
+		value = string(runes[:maxCompactValueLength-1]) + "…"
+	}
+	return value
+}
+
+// markdownTable renders rows as a markdown table with columns padded to a
+// common width, so the table also reads well as plain text
+func markdownTable(headers []string, rows [][]string) string {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	escaped := make([][]string, len(rows))
+	for r, row := range rows {
+		escaped[r] = make([]string, len(headers))
+		for i := range headers {
+			cell := ""
+			if i < len(row) {
+				cell = strings.ReplaceAll(row[i], "|", `\|`)
+			}
+			escaped[r][i] = cell
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for i, cell := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(cell)
+			sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(headers)
+	separators := make([]string, len(headers))
+	for i := range headers {
+		separators[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(separators)
+	for _, row := range escaped {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// compactLocation renders a frame's location as "res://path.gd:line",
+// falling back to the absolute path, or "native" for frames without source
+func compactLocation(frame godap.StackFrame, projectRoot string) string {
+	if isNativeFrame(frame) {
+		return "native"
+	}
+	path := frame.Source.Path
+	if resPath := toResPath(path, projectRoot); resPath != "" {
+		path = resPath
+	}
+	return fmt.Sprintf("%s:%d", path, frame.Line)
+}
+
+// renderStackCompact renders a stack trace as a markdown table. The entered
+// column marks frames the engine entered directly (see calledFrom).
+func renderStackCompact(frames []godap.StackFrame, projectRoot string, complete bool) string {
+	rows := make([][]string, len(frames))
+	for i, frame := range frames {
+		rows[i] = []string{
+			fmt.Sprint(frame.Id),
+			frame.Name,
+			compactLocation(frame, projectRoot),
+			calledFrom(frames, i, complete),
+		}
+	}
+	return fmt.Sprintf("**Stack** (%d frames)\n\n", len(frames)) + markdownTable([]string{"id", "function", "location", "entered"}, rows)
+}
+
+// renderVariablesCompact renders variables as a markdown table. The ref
+// column holds the variables_reference of expandable values.
+func renderVariablesCompact(variables []godap.Variable) string {
+	if len(variables) == 0 {
+		return "_(no variables)_\n"
+	}
+	rows := make([][]string, len(variables))
+	for i, v := range variables {
+		ref := ""
+		if v.VariablesReference > 0 {
+			ref = fmt.Sprint(v.VariablesReference)
+		}
+		rows[i] = []string{v.Name, v.Type, compactValue(v.Type, v.Value), ref}
+	}
+	return markdownTable([]string{"name", "type", "value", "ref"}, rows)
+}
+
+// renderStopContextCompact renders a frame and its scopes' variables as a
+// location header followed by one table per scope
+func renderStopContextCompact(frame godap.StackFrame, projectRoot string, scopes []scopeVariables) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** at %s (frame %d)\n", frame.Name, compactLocation(frame, projectRoot), frame.Id))
+	for _, sv := range scopes {
+		sb.WriteString(fmt.Sprintf("\n**%s**\n\n", sv.Scope.Name))
+		if sv.Err != nil {
+			sb.WriteString(fmt.Sprintf("_error: %v_\n", sv.Err))
+			continue
+		}
+		sb.WriteString(renderVariablesCompact(sv.Variables))
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+func TestMarkdownTable_AlignsAndEscapes(t *testing.T) {
+	table := markdownTable([]string{"name", "value"}, [][]string{
+		{"a", "1"},
+		{"long_name", "x | y"},
+	})
+	lines := strings.Split(strings.TrimRight(table, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, separator, and 2 rows, got:\n%s", table)
+	}
+	for _, line := range lines[1:] {
+		if len(line) != len(lines[0]) {
+			t.Errorf("columns not aligned:\n%s", table)
+			break
+		}
+	}
+	if !strings.Contains(table, `x \| y`) {
+		t.Errorf("pipes in cells should be escaped:\n%s", table)
+	}
+}
+
+func TestCompactValue(t *testing.T) {
+	if got := compactValue("Vector2", "(1, 2)"); got != formatGodotType("Vector2", "(1, 2)") {
+		t.Errorf("Godot types should use their formatted value, got %q", got)
+	}
+	if got := compactValue("String", "line one\nline two"); got != "line one line two" {
+		t.Errorf("newlines should be flattened, got %q", got)
+	}
+	long := compactValue("String", strings.Repeat("x", 100))
+	if len([]rune(long)) != maxCompactValueLength || !strings.HasSuffix(long, "…") {
+		t.Errorf("long values should be truncated to %d runes, got %q", maxCompactValueLength, long)
+	}
+}
+
+func TestRenderStackCompact(t *testing.T) {
+	frames := []godap.StackFrame{
+		{Id: 0, Name: "_process", Line: 12, Source: &godap.Source{Path: "/game/player.gd"}},
+		{Id: 1, Name: "call", Line: 0},
+	}
+	out := renderStackCompact(frames, "/game", true)
+	if !strings.Contains(out, "res://player.gd:12") || !strings.Contains(out, "native") {
+		t.Errorf("unexpected compact stack:\n%s", out)
+	}
+}
+
+func TestRenderVariablesCompact(t *testing.T) {
+	out := renderVariablesCompact([]godap.Variable{
+		{Name: "speed", Type: "int", Value: "10"},
+		{Name: "target", Type: "Node2D", Value: "<Node2D#42>", VariablesReference: 7},
+	})
+	if !strings.Contains(out, "| speed ") || !strings.Contains(out, "| 7   |") {
+		t.Errorf("unexpected compact variables:\n%s", out)
+	}
+	if renderVariablesCompact(nil) != "_(no variables)_\n" {
+		t.Error("empty variable list should render a placeholder")
+	}
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// fieldDifference is one field whose value differs between two expansions
+type fieldDifference struct {
+	Name  string `json:"name"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+	TypeA string `json:"type_a,omitempty"` // Types are only set when they differ
+	TypeB string `json:"type_b,omitempty"`
+}
+
+// variableComparison is the field-by-field difference of two expansions
+type variableComparison struct {
+	Differences []fieldDifference `json:"differences"`
+	OnlyInA     []string          `json:"only_in_a"`
+	OnlyInB     []string          `json:"only_in_b"`
+	Equal       int               `json:"equal"`
+}
+
+// compareVariables matches the fields of a and b by name and reports those
+// whose type or value differ, in a's order, plus the fields only one has
+func compareVariables(a, b []godap.Variable) variableComparison {
+	comparison := variableComparison{
+		Differences: []fieldDifference{},
+		OnlyInA:     []string{},
+		OnlyInB:     []string{},
+	}
+	byName := make(map[string]godap.Variable, len(b))
+	for _, variable := range b {
+		byName[variable.Name] = variable
+	}
+	inA := make(map[string]bool, len(a))
+
+	for _, va := range a {
+		inA[va.Name] = true
+		vb, ok := byName[va.Name]
+		switch {
+		case !ok:
+			comparison.OnlyInA = append(comparison.OnlyInA, va.Name)
+		case va.Value == vb.Value && va.Type == vb.Type:
+			comparison.Equal++
+		default:
+			difference := fieldDifference{Name: va.Name, A: va.Value, B: vb.Value}
+			if va.Type != vb.Type {
+				difference.TypeA = va.Type
+				difference.TypeB = vb.Type
+			}
+			comparison.Differences = append(comparison.Differences, difference)
+		}
+	}
+	for _, vb := range b {
+		if !inA[vb.Name] {
+			comparison.OnlyInB = append(comparison.OnlyInB, vb.Name)
+		}
+	}
+	return comparison
+}
+
+// RegisterCompareTools registers godot_compare
+func RegisterCompareTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_compare",
+		Description: `Compare two objects field by field.
+
+Expands two variables references (e.g. two enemy instances, or the same
+object's state captured through two references) and reports every field whose
+value or type differs, plus fields only one of them has. Use it to answer
+"why does this instance behave differently from that one" without reading
+two full property listings side by side.
+
+Fields are matched by name (e.g. "Members/health", "Node2D/position"), and
+values are compared as Godot formats them. Fields holding objects differ
+whenever they point to different instances.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- Both references must come from the current stop (godot_get_variables,
+  godot_get_scopes, or godot_evaluate results)
+
+Example: Compare two enemies, script variables only
+godot_compare(ref_a=2051, ref_b=2064, categories=["Members"])
+→ {"differences": [{"name": "Members/state", "a": "1", "b": "3"}],
+   "only_in_a": [], "only_in_b": [], "equal": 11}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "ref_a",
+				Type:        "number",
+				Required:    true,
+				Description: "Variables reference of the first object",
+			},
+			{
+				Name:        "ref_b",
+				Type:        "number",
+				Required:    true,
+				Description: "Variables reference of the second object",
+			},
+			{
+				Name:        "categories",
+				Type:        "array",
+				Required:    false,
+				Items:       &mcp.Parameter{Type: "string"},
+				Description: "Only compare properties in these categories (e.g. [\"Members\"]); case-insensitive",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "compare variables"); err != nil {
+				return nil, err
+			}
+
+			refA, okA := params["ref_a"].(float64)
+			refB, okB := params["ref_b"].(float64)
+			if !okA || !okB {
+				return nil, fmt.Errorf("ref_a and ref_b are required and must be numbers")
+			}
+			categories, err := getStringListParam(params, "categories")
+			if err != nil {
+				return nil, err
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			var expanded [2][]godap.Variable
+			for i, ref := range []int{int(refA), int(refB)} {
+				resp, err := client.Variables(ctx, ref)
+				if err != nil {
+					return nil, FormatError(
+						"Failed to expand variables",
+						fmt.Sprintf("ref=%d", ref),
+						[]string{
+							"Variables reference might be stale (get fresh scopes)",
+							"Game might not be paused",
+						},
+						err,
+					)
+				}
+				expanded[i], _ = filterPropertyCategories(resp.Body.Variables, categories)
+			}
+
+			comparison := compareVariables(expanded[0], expanded[1])
+			return map[string]interface{}{
+				"status":      "success",
+				"ref_a":       int(refA),
+				"ref_b":       int(refB),
+				"differences": comparison.Differences,
+				"only_in_a":   comparison.OnlyInA,
+				"only_in_b":   comparison.OnlyInB,
+				"equal":       comparison.Equal,
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+func TestCompareVariables(t *testing.T) {
+	a := []godap.Variable{
+		{Name: "Members/health", Type: "int", Value: "100"},
+		{Name: "Members/state", Type: "int", Value: "1"},
+		{Name: "Members/target", Type: "Object", Value: "<null>"},
+		{Name: "Node2D/position", Type: "Vector2", Value: "(10, 20)"},
+		{Name: "Members/patrol_route", Type: "Array", Value: "[]"},
+	}
+	b := []godap.Variable{
+		{Name: "Members/health", Type: "int", Value: "100"},
+		{Name: "Members/state", Type: "int", Value: "3"},
+		{Name: "Members/target", Type: "Object", Value: "CharacterBody2D:<CharacterBody2D#101>"},
+		{Name: "Node2D/position", Type: "Vector2", Value: "(10, 20)"},
+		{Name: "Members/alerted", Type: "bool", Value: "true"},
+	}
+
+	c := compareVariables(a, b)
+	if c.Equal != 2 {
+		t.Errorf("expected 2 equal fields, got %d", c.Equal)
+	}
+	if len(c.Differences) != 2 || c.Differences[0].Name != "Members/state" || c.Differences[0].B != "3" {
+		t.Errorf("unexpected differences: %+v", c.Differences)
+	}
+	if c.Differences[1].TypeA != "" {
+		t.Errorf("types should only be reported when they differ, got %+v", c.Differences[1])
+	}
+	if len(c.OnlyInA) != 1 || c.OnlyInA[0] != "Members/patrol_route" || len(c.OnlyInB) != 1 || c.OnlyInB[0] != "Members/alerted" {
+		t.Errorf("unexpected one-sided fields: %v / %v", c.OnlyInA, c.OnlyInB)
+	}
+
+	c = compareVariables([]godap.Variable{{Name: "x", Type: "int", Value: "1"}}, []godap.Variable{{Name: "x", Type: "float", Value: "1"}})
+	if len(c.Differences) != 1 || c.Differences[0].TypeA != "int" || c.Differences[0].TypeB != "float" {
+		t.Errorf("a type change should be a difference, got %+v", c.Differences)
+	}
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	godap "github.com/google/go-dap"
+)
+
+// conditionClient is the subset of *dap.Client used to check breakpoint
+// conditions at a stop
+type conditionClient interface {
+	StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*godap.StackTraceResponse, error)
+	Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error)
+	Continue(ctx context.Context, threadId int) (*godap.ContinueResponse, error)
+	SupportsConditionalBreakpoints() bool
+	SupportsHitConditionalBreakpoints() bool
+	SupportsLogPoints() bool
+	RecordOutput(category, output string)
+}
+
+// conditionGate enforces breakpoint conditions, hit conditions, and
+// logpoints for adapters that don't (Godot ignores SourceBreakpoint.Condition,
+// HitCondition, and LogMessage): when such a breakpoint is hit, its condition
+// is evaluated in the top frame and its hit count checked, and execution
+// continues right away unless both hold. A logpoint whose conditions hold
+// adds its message to the game's output and continues too.
+type conditionGate struct {
+	mu          sync.Mutex
+	stop        chan struct{}
+	client      *dap.Client             // Client the gate is attached to
+	breakpoints *dap.BreakpointRegistry // Breakpoints of the attached session
+	hits        map[breakpointLine]int  // Hits counted toward hit conditions
+	decisions   stopDecisions           // Whether each breakpoint stop was continued
+}
+
+// breakpointLine identifies a breakpoint by file and line
+type breakpointLine struct {
+	path string
+	line int
+}
+
+// Enforces the conditions of godot_set_breakpoint(condition=..., hit_condition=...)
+// and the messages of godot_set_logpoint
+var breakpointConditions = &conditionGate{}
+
+// isFalsy reports whether an evaluate result is false in GDScript terms
+func isFalsy(result string) bool {
+	switch strings.TrimSpace(result) {
+	case "false", "0", "0.0", "null", "<null>", "", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// hitCondition is a parsed breakpoint hit condition: pause on the hits whose
+// count compares to n with op
+type hitCondition struct {
+	op string // ==, >=, >, <=, <, or % (every nth hit)
+	n  int
+}
+
+// parseHitCondition parses a hit condition: "N" (the Nth hit only, same as
+// "== N"), ">= N", "> N", "<= N", "< N", or "% N" (every Nth hit)
+func parseHitCondition(s string) (hitCondition, error) {
+	s = strings.TrimSpace(s)
+	op := "=="
+	for _, candidate := range []string{"==", ">=", "<=", ">", "<", "%"} {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(strings.TrimPrefix(s, candidate))
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return hitCondition{}, fmt.Errorf("expected a hit count of 1 or more, got %q", s)
+	}
+	return hitCondition{op: op, n: n}, nil
+}
+
+// matches reports whether the game should pause on hit number hits
+func (h hitCondition) matches(hits int) bool {
+	switch h.op {
+	case ">=":
+		return hits >= h.n
+	case ">":
+		return hits > h.n
+	case "<=":
+		return hits <= h.n
+	case "<":
+		return hits < h.n
+	case "%":
+		return hits%h.n == 0
+	}
+	return hits == h.n
+}
+
+// hit counts a hit of the breakpoint at path:line and returns the count
+func (g *conditionGate) hit(path string, line int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.hits == nil {
+		g.hits = map[breakpointLine]int{}
+	}
+	key := breakpointLine{path: path, line: line}
+	g.hits[key]++
+	return g.hits[key]
+}
+
+// resetHits restarts the hit counts of a file's breakpoints, or of every
+// breakpoint if path is empty
+func (g *conditionGate) resetHits(path string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key := range g.hits {
+		if path == "" || key.path == path {
+			delete(g.hits, key)
+		}
+	}
+}
+
+// resetHit restarts the hit count of the breakpoint at path:line
+func (g *conditionGate) resetHit(path string, line int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.hits, breakpointLine{path: path, line: line})
+}
+
+// handleStop continues execution if the thread stopped at a conditional
+// breakpoint whose condition is false or whose hit condition doesn't match.
+// Hits only count while the condition holds. A condition that fails to
+// evaluate keeps the game paused, so a typo can't hide the stop. Returns true
+// if execution was continued.
+func (g *conditionGate) handleStop(client conditionClient, threadId int) bool {
+	g.mu.Lock()
+	breakpoints := g.breakpoints
+	g.mu.Unlock()
+	if breakpoints == nil {
+		return false
+	}
+
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	resp, err := client.StackTrace(ctx, threadId, 0, 1)
+	if err != nil || len(resp.Body.StackFrames) == 0 {
+		return false
+	}
+	frame := resp.Body.StackFrames[0]
+	if isNativeFrame(frame) {
+		return false
+	}
+	path := filepath.Clean(frame.Source.Path)
+	condition := breakpoints.Condition(path, frame.Line)
+	if client.SupportsConditionalBreakpoints() {
+		condition = ""
+	}
+	hitCond := breakpoints.HitCondition(path, frame.Line)
+	if client.SupportsHitConditionalBreakpoints() {
+		hitCond = ""
+	}
+
+	message := breakpoints.LogMessage(path, frame.Line)
+	if client.SupportsLogPoints() {
+		message = ""
+	}
+	if condition == "" && hitCond == "" && message == "" {
+		return false
+	}
+	if condition != "" {
+		result, err := client.Evaluate(ctx, condition, frame.Id, "watch")
+		if err != nil {
+			log.Printf("Breakpoint condition %q at %s:%d failed to evaluate, staying paused: %v", condition, frame.Source.Path, frame.Line, err)
+			return false
+		}
+		if isFalsy(result.Body.Result) {
+			return resumePastBreakpoint(ctx, client, threadId)
+		}
+	}
+	if hitCond != "" {
+		parsed, err := parseHitCondition(hitCond)
+		if err != nil {
+			log.Printf("Breakpoint hit condition %q at %s:%d is invalid, staying paused: %v", hitCond, frame.Source.Path, frame.Line, err)
+			return false
+		}
+		if !parsed.matches(g.hit(path, frame.Line)) {
+			return resumePastBreakpoint(ctx, client, threadId)
+		}
+	}
+	if message != "" {
+		client.RecordOutput("console", formatLogMessage(ctx, client, frame.Id, message)+"\n")
+		return resumePastBreakpoint(ctx, client, threadId)
+	}
+	return false
+}
+
+// formatLogMessage replaces each {expression} of a logpoint message with its
+// value in the frame; {{ and }} stand for literal braces. Expressions that
+// fail to evaluate are replaced with the error.
+func formatLogMessage(ctx context.Context, client expressionEvaluator, frameId int, message string) string {
+	var out strings.Builder
+	for i := 0; i < len(message); i++ {
+		switch {
+		case strings.HasPrefix(message[i:], "{{"), strings.HasPrefix(message[i:], "}}"):
+			out.WriteByte(message[i])
+			i++
+		case message[i] == '{':
+			end := strings.IndexByte(message[i:], '}')
+			if end < 0 {
+				out.WriteString(message[i:])
+				return out.String()
+			}
+			expression := strings.TrimSpace(message[i+1 : i+end])
+			if result, err := client.Evaluate(ctx, expression, frameId, "watch"); err != nil {
+				fmt.Fprintf(&out, "<error: %v>", err)
+			} else {
+				out.WriteString(result.Body.Result)
+			}
+			i += end
+		default:
+			out.WriteByte(message[i])
+		}
+	}
+	return out.String()
+}
+
+// resumePastBreakpoint continues past a breakpoint whose conditions don't hold
+func resumePastBreakpoint(ctx context.Context, client conditionClient, threadId int) bool {
+	if _, err := client.Continue(ctx, threadId); err != nil {
+		log.Printf("Failed to continue past a breakpoint condition: %v", err)
+		return false
+	}
+	return true
+}
+
+// attach starts checking the conditions of a session's breakpoints at
+// breakpoint stops, replacing any previous attachment. Hit counts restart
+// with each attachment and each run of the game.
+func (g *conditionGate) attach(session *dap.Session) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stop != nil {
+		close(g.stop)
+	}
+	stop := make(chan struct{})
+	client := session.GetClient()
+	g.stop = stop
+	g.client = client
+	g.breakpoints = session.Breakpoints()
+	g.hits = nil
+
+	// Stops are handled on a queue so the listener keeps draining events
+	// while a condition is evaluated
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("breakpoint conditions"), dap.WithBlockWhenFull())
+	g.decisions.begin(client)
+	queue := newWorkQueue(stop)
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				switch e := msg.(type) {
+				case *godap.StoppedEvent:
+					if e.Body.Reason != "breakpoint" {
+						continue
+					}
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					queue.push(func() { g.decisions.record(e, g.handleStop(client, threadId)) })
+				case *godap.TerminatedEvent, *godap.ExitedEvent:
+					queue.push(func() { g.resetHits("") })
+				}
+			}
+		}
+	}()
+}
+
+// detach stops checking conditions
+func (g *conditionGate) detach() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+		g.client = nil
+		g.breakpoints = nil
+		g.decisions.end()
+	}
+}
+
+// attachedTo reports whether conditions are being checked on client
+func (g *conditionGate) attachedTo(client *dap.Client) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stop != nil && g.client == client
+}
+
+// enforceConditions starts checking conditions on a session if any of its
+// breakpoints has a condition, hit condition, or log message the adapter
+// won't handle itself
+func enforceConditions(session *dap.Session) {
+	client := session.GetClient()
+	breakpoints := session.Breakpoints()
+	conditions := breakpoints.AllConditions() != nil && !client.SupportsConditionalBreakpoints()
+	hitConditions := breakpoints.AllHitConditions() != nil && !client.SupportsHitConditionalBreakpoints()
+	logMessages := breakpoints.AllLogMessages() != nil && !client.SupportsLogPoints()
+	if !conditions && !hitConditions && !logMessages {
+		return
+	}
+	if !breakpointConditions.attachedTo(client) {
+		breakpointConditions.attach(session)
+	}
+}
@@ -0,0 +1,252 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
+)
+
+// fakeConditionClient is stopped at a fixed location and answers conditions
+// from a table; unknown conditions fail to evaluate
+type fakeConditionClient struct {
+	fakeTracepointClient
+	results map[string]string
+	output  []string
+}
+
+func (f *fakeConditionClient) Evaluate(ctx context.Context, expression string, frameId int, evalContext string) (*godap.EvaluateResponse, error) {
+	result, ok := f.results[expression]
+	if !ok {
+		return nil, fmt.Errorf("Invalid named index '%s'", expression)
+	}
+	resp := &godap.EvaluateResponse{}
+	resp.Body.Result = result
+	return resp, nil
+}
+
+func (f *fakeConditionClient) SupportsConditionalBreakpoints() bool    { return false }
+func (f *fakeConditionClient) SupportsHitConditionalBreakpoints() bool { return false }
+func (f *fakeConditionClient) SupportsLogPoints() bool                 { return false }
+func (f *fakeConditionClient) RecordOutput(category, output string) {
+	f.output = append(f.output, output)
+}
+
+func TestConditionGate_HandleStop(t *testing.T) {
+	breakpoints := &dap.BreakpointRegistry{}
+	breakpoints.Set("/game/spawner.gd", []int{30, 40})
+
+	client := &fakeConditionClient{
+		fakeTracepointClient: fakeTracepointClient{path: "/game/spawner.gd", line: 30},
+		results:              map[string]string{"i == 997": "false", "wave > 2": "true"},
+	}
+	gate := &conditionGate{breakpoints: breakpoints}
+
+	// Unconditional breakpoints stay paused
+	if gate.handleStop(client, 1) {
+		t.Fatal("a breakpoint without a condition should not continue")
+	}
+
+	breakpoints.SetConditions("/game/spawner.gd", map[int]string{30: "i == 997", 40: "wave > 2"})
+	if !gate.handleStop(client, 1) || client.continues != 1 {
+		t.Errorf("a false condition should continue, got %d continues", client.continues)
+	}
+
+	client.line = 40
+	if gate.handleStop(client, 1) || client.continues != 1 {
+		t.Error("a true condition should stay paused")
+	}
+
+	breakpoints.SetConditions("/game/spawner.gd", map[int]string{40: "undefined_var"})
+	if gate.handleStop(client, 1) || client.continues != 1 {
+		t.Error("a condition that fails to evaluate should stay paused")
+	}
+}
+
+func TestIsFalsy(t *testing.T) {
+	for _, result := range []string{"false", "0", "0.0", "null", "<null>", "", "[]"} {
+		if !isFalsy(result) {
+			t.Errorf("%q should be falsy", result)
+		}
+	}
+	for _, result := range []string{"true", "1", "-1", "[0]", "Player:<CharacterBody2D#12>"} {
+		if isFalsy(result) {
+			t.Errorf("%q should be truthy", result)
+		}
+	}
+}
+
+func TestConditionGate_HitConditions(t *testing.T) {
+	breakpoints := &dap.BreakpointRegistry{}
+	breakpoints.Set("/game/enemy.gd", []int{88})
+	breakpoints.SetHitConditions("/game/enemy.gd", map[int]string{88: ">= 3"})
+
+	client := &fakeConditionClient{fakeTracepointClient: fakeTracepointClient{path: "/game/enemy.gd", line: 88}}
+	gate := &conditionGate{breakpoints: breakpoints}
+
+	var paused []int
+	for hit := 1; hit <= 4; hit++ {
+		if !gate.handleStop(client, 1) {
+			paused = append(paused, hit)
+		}
+	}
+	if fmt.Sprint(paused) != "[3 4]" {
+		t.Errorf("\">= 3\" should pause from the third hit on, paused on %v", paused)
+	}
+
+	// Only hits where the condition holds are counted
+	gate.resetHits("/game/enemy.gd")
+	breakpoints.SetHitConditions("/game/enemy.gd", map[int]string{88: "2"})
+	breakpoints.SetConditions("/game/enemy.gd", map[int]string{88: "armed"})
+	client.results = map[string]string{"armed": "false"}
+	gate.handleStop(client, 1)
+	gate.handleStop(client, 1)
+	client.results["armed"] = "true"
+	if !gate.handleStop(client, 1) {
+		t.Error("the first hit with a true condition should not pause at hit count 2")
+	}
+	if gate.handleStop(client, 1) {
+		t.Error("the second hit with a true condition should pause")
+	}
+	if !gate.handleStop(client, 1) {
+		t.Error("\"2\" should pause on the second hit only")
+	}
+}
+
+func TestConditionGate_DrainsEventsWhileEvaluating(t *testing.T) {
+	mock := daptest.NewServer(t)
+	defer mock.Close()
+	continued := make(chan struct{}, 1)
+	go mock.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{mock.Success(req, nil), mock.NewEvent("initialized", nil)}
+		case "stackTrace":
+			return []godap.Message{mock.Success(req, map[string]interface{}{
+				"stackFrames": []map[string]interface{}{
+					{"id": 0, "name": "spawn", "line": 30, "column": 1, "source": map[string]interface{}{"path": "/game/spawner.gd"}},
+				},
+			})}
+		case "evaluate":
+			// Flood the client, well past the gate's event buffer, while the
+			// condition's response is still pending
+			for i := 0; i < 1000; i++ {
+				mock.Send(mock.NewEvent("output", map[string]interface{}{"category": "stdout", "output": "tick\n"}))
+			}
+			return []godap.Message{mock.Success(req, map[string]interface{}{"result": "false", "variablesReference": 0})}
+		case "continue":
+			continued <- struct{}{}
+		}
+		return nil
+	})
+
+	session := dap.NewSession("localhost", mock.Port())
+	defer session.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	session.Breakpoints().Set("/game/spawner.gd", []int{30})
+	session.Breakpoints().SetConditions("/game/spawner.gd", map[int]string{30: "i == 997"})
+
+	gate := &conditionGate{}
+	gate.attach(session)
+	defer gate.detach()
+	mock.Send(mock.NewEvent("stopped", map[string]interface{}{"reason": "breakpoint", "threadId": 1}))
+
+	// A listener blocked on the evaluate response would stall the reader
+	// until the command timeout
+	select {
+	case <-continued:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the gate should continue past the false condition while events keep arriving")
+	}
+}
+
+func TestParseHitCondition(t *testing.T) {
+	tests := []struct {
+		input  string
+		paused []int // Hits 1-6 that pause
+	}{
+		{"3", []int{3}},
+		{"== 3", []int{3}},
+		{">= 5", []int{5, 6}},
+		{">4", []int{5, 6}},
+		{"<= 2", []int{1, 2}},
+		{"< 2", []int{1}},
+		{"% 2", []int{2, 4, 6}},
+	}
+	for _, tt := range tests {
+		h, err := parseHitCondition(tt.input)
+		if err != nil {
+			t.Errorf("parseHitCondition(%q) failed: %v", tt.input, err)
+			continue
+		}
+		var paused []int
+		for hit := 1; hit <= 6; hit++ {
+			if h.matches(hit) {
+				paused = append(paused, hit)
+			}
+		}
+		if fmt.Sprint(paused) != fmt.Sprint(tt.paused) {
+			t.Errorf("%q pauses on %v, want %v", tt.input, paused, tt.paused)
+		}
+	}
+
+	for _, input := range []string{"", "ten", ">= 0", "% -2", "!= 3"} {
+		if _, err := parseHitCondition(input); err == nil {
+			t.Errorf("parseHitCondition(%q) should fail", input)
+		}
+	}
+}
+
+func TestConditionGate_LogPoints(t *testing.T) {
+	breakpoints := &dap.BreakpointRegistry{}
+	breakpoints.Set("/game/player.gd", []int{45})
+	breakpoints.SetLogMessages("/game/player.gd", map[int]string{45: "hp={health}"})
+
+	client := &fakeConditionClient{
+		fakeTracepointClient: fakeTracepointClient{path: "/game/player.gd", line: 45},
+		results:              map[string]string{"health": "80"},
+	}
+	gate := &conditionGate{breakpoints: breakpoints}
+
+	if !gate.handleStop(client, 1) || client.continues != 1 {
+		t.Error("a logpoint should continue")
+	}
+	if fmt.Sprint(client.output) != "[hp=80\n]" {
+		t.Errorf("unexpected output: %q", client.output)
+	}
+
+	// Conditions and hit conditions decide whether the message is printed
+	breakpoints.SetHitConditions("/game/player.gd", map[int]string{45: "% 2"})
+	gate.handleStop(client, 1)
+	gate.handleStop(client, 1)
+	if len(client.output) != 2 || client.continues != 3 {
+		t.Errorf("only every second hit should print, got %q after %d continues", client.output, client.continues)
+	}
+}
+
+func TestFormatLogMessage(t *testing.T) {
+	client := &fakeConditionClient{results: map[string]string{"health": "80", "position": "(1, 2)"}}
+	tests := map[string]string{
+		"hp={health} at { position }": "hp=80 at (1, 2)",
+		"{{literal}} {health}":        "{literal} 80",
+		"{missing}":                   "<error: Invalid named index 'missing'>",
+		"unclosed {health":            "unclosed {health",
+		"plain":                       "plain",
+	}
+	for message, want := range tests {
+		if got := formatLogMessage(context.Background(), client, 0, message); got != want {
+			t.Errorf("formatLogMessage(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
@@ -3,22 +3,268 @@ package tools
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap/quirks"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
 )
 
 // Global DAP session (single session design)
-// All debugging tools share this session
-var globalSession *dap.Session
+// All debugging tools share this session. The server handles tool calls
+// concurrently, so access goes through sessionMu. The only transport is
+// stdio, which serves exactly one MCP client per process, so there is no
+// need for per-client session namespaces.
+var (
+	globalSession *dap.Session
+	sessionEnded  chan struct{} // Closed when globalSession is cleared or replaced
+	sessionMu     sync.RWMutex
+)
 
 // GetSession returns the global DAP session
 // Returns error if no session is active
 func GetSession() (*dap.Session, error) {
-	if globalSession == nil {
+	session := currentSession()
+	if session == nil {
 		return nil, ErrNotConnected()
 	}
-	return globalSession, nil
+	// Every tool that uses the session counts as activity
+	idle.touch()
+	return session, nil
+}
+
+// currentSession returns the global session, or nil if not connected
+func currentSession() *dap.Session {
+	sessionMu.RLock()
+	defer sessionMu.RUnlock()
+	return globalSession
+}
+
+// swapSession replaces the global session and returns the previous one
+func swapSession(session *dap.Session) *dap.Session {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	prev := globalSession
+	globalSession = session
+	if sessionEnded != nil {
+		close(sessionEnded)
+		sessionEnded = nil
+	}
+	if session != nil {
+		sessionEnded = make(chan struct{})
+	}
+	return prev
+}
+
+// clearSession detaches session if it is still the global session.
+// Returns false if another session replaced it in the meantime.
+func clearSession(session *dap.Session) bool {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	if globalSession != session {
+		return false
+	}
+	globalSession = nil
+	close(sessionEnded)
+	sessionEnded = nil
+	return true
+}
+
+// sessionDone returns a channel closed once session is no longer the global
+// session (disconnected, idle, or replaced by a new connection), so waiting
+// tools can stop early
+func sessionDone(session *dap.Session) <-chan struct{} {
+	sessionMu.RLock()
+	defer sessionMu.RUnlock()
+	if globalSession != session || sessionEnded == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return sessionEnded
+}
+
+// shutdownSession stops the background event consumers of a detached
+// session and closes it. With terminate, the game is ended first if it is
+// still running. Returns how the game ended, if it did.
+func shutdownSession(session *dap.Session, terminate bool) (map[string]interface{}, error) {
+	idle.stop()
+	follower.stopFollowing()
+	watches.detach()
+	tracepoints.detach()
+	breakpointConditions.detach()
+	scenes.detach()
+	nodeTracking.detach()
+	seeding.detach()
+
+	if terminate && session.GetExitStatus() == nil && session.GetClient().RunState() != dap.RunStateNotLaunched {
+		ctx, cancel := dap.WithCommandTimeout(context.Background())
+		if err := session.GetClient().Terminate(ctx); err != nil {
+			log.Printf("Failed to terminate the game: %v", err)
+		}
+		cancel()
+	}
+
+	// Capture how the game ended before the session is discarded
+	exit := formatExitStatus(session.GetExitStatus())
+
+	if err := session.Close(); err != nil {
+		return exit, fmt.Errorf("failed to disconnect: %w", err)
+	}
+	return exit, nil
+}
+
+// formatExitStatus converts a run's exit status for tool responses.
+// Returns nil if the run hasn't ended.
+func formatExitStatus(status *dap.ExitStatus) map[string]interface{} {
+	if status == nil {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"reason":     status.Reason,
+		"terminated": status.Terminated,
+		"time":       status.Time.Format(time.RFC3339),
+	}
+	if status.Exited {
+		result["exit_code"] = status.ExitCode
+		result["success"] = status.ExitCode == 0
+	}
+	if status.Restart {
+		result["restart_requested"] = true
+	}
+	return result
+}
+
+// formatViolations converts malformed adapter messages for tool responses
+func formatViolations(violations []dap.ProtocolViolation) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(violations))
+	for i, v := range violations {
+		entry := map[string]interface{}{
+			"kind":     v.Kind,
+			"problems": v.Problems,
+			"time":     v.Time.Format(time.RFC3339),
+		}
+		if v.Name != "" {
+			entry["name"] = v.Name
+		}
+		result[i] = entry
+	}
+	return result
+}
+
+// eventBufferSizeEnv sets the event channel capacity of each subscriber;
+// output-heavy games can overflow the default and lose events
+const eventBufferSizeEnv = "GODOT_MCP_EVENT_BUFFER_SIZE"
+
+// eventBufferSize reads eventBufferSizeEnv, returning 0 (the client default)
+// if it is unset or invalid
+func eventBufferSize() int {
+	value := os.Getenv(eventBufferSizeEnv)
+	if value == "" {
+		return 0
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		log.Printf("Ignoring invalid %s=%q", eventBufferSizeEnv, value)
+		return 0
+	}
+	return size
+}
+
+// connectQuirks returns the quirks to connect with: the profile's, with
+// request padding switched only if the caller passed pad_requests
+func connectQuirks(profile dap.Profile, params map[string]interface{}) quirks.Set {
+	set := profile.Quirks
+	if pad, ok := params["pad_requests"].(bool); ok {
+		set.ReadsOptionalFieldsUnsafely = pad
+	}
+	return set
+}
+
+// formatEventStats converts event delivery statistics for tool responses
+func formatEventStats(stats dap.EventStats) map[string]interface{} {
+	listeners := make([]map[string]interface{}, len(stats.Listeners))
+	for i, l := range stats.Listeners {
+		entry := map[string]interface{}{
+			"buffered": l.Buffered,
+			"capacity": l.Capacity,
+			"dropped":  l.Dropped,
+		}
+		if l.Name != "" {
+			entry["name"] = l.Name
+		}
+		if l.Blocking {
+			entry["blocking"] = true
+		}
+		listeners[i] = entry
+	}
+	return map[string]interface{}{
+		"dropped":   stats.Dropped,
+		"listeners": listeners,
+	}
+}
+
+// formatRequestStats converts request statistics for tool responses
+func formatRequestStats(stats dap.RequestStats) map[string]interface{} {
+	result := map[string]interface{}{
+		"sent":       stats.Sent,
+		"answered":   stats.Answered,
+		"unanswered": stats.Unanswered,
+		"abandoned":  stats.Abandoned,
+		"late":       stats.Late,
+		"pending":    stats.Pending,
+	}
+	if stats.Pending > 0 {
+		result["oldest_pending_ms"] = stats.OldestPending.Milliseconds()
+	}
+	if len(stats.UnansweredByCommand) > 0 {
+		result["unanswered_by_command"] = stats.UnansweredByCommand
+	}
+	return result
+}
+
+// formatProcessInfo converts the debuggee process for tool responses
+func formatProcessInfo(process *dap.ProcessInfo) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":     process.Name,
+		"is_local": process.IsLocalProcess,
+		"time":     process.Time.Format(time.RFC3339),
+	}
+	if process.PID != 0 {
+		result["pid"] = process.PID
+	}
+	if process.StartMethod != "" {
+		result["start_method"] = process.StartMethod
+	}
+	if process.PointerSize != 0 {
+		result["pointer_size"] = process.PointerSize
+	}
+	return result
+}
+
+// reportStateChanges returns a session state observer that logs each
+// transition and sends it to the MCP client as a notifications/message log
+// notification, so clients see launches and disconnects without polling.
+func reportStateChanges(n notifier) dap.StateChangeHandler {
+	return func(change dap.StateChange) {
+		log.Printf("DAP session state: %s -> %s", change.From, change.To)
+		err := n.Notify("notifications/message", map[string]interface{}{
+			"level":  "info",
+			"logger": "session",
+			"data": map[string]interface{}{
+				"from": change.From.String(),
+				"to":   change.To.String(),
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to report session state change: %v", err)
+		}
+	}
 }
 
 // RegisterConnectionTools registers godot_connect and godot_disconnect tools
@@ -48,7 +294,31 @@ Example: Connect to default port
 godot_connect()
 
 Example: Connect with project path (enables res:// path resolution)
-godot_connect(project="/path/to/my/project")`,
+godot_connect(project="/path/to/my/project")
+
+Example: Debug Godot on a remote machine through an SSH tunnel
+godot_connect(ssh="me@devbox", project="/home/me/my-game")
+
+With ssh, the port is the DAP port on the remote machine and project is the
+project path there. The system ssh client is used, so keys, agents, and
+~/.ssh/config apply; authentication must not prompt for a password.
+
+Example: Connect through a local unix socket (or \\.\pipe\name on Windows)
+godot_connect(socket="/tmp/godot-dap.sock")
+
+Example: Connect to an older Godot release
+godot_connect(profile="godot-4.2")
+
+The profile bundles what the server assumes about the debug adapter: which
+Godot protocol deviations to work around, which advertised capabilities to
+ignore, and timeouts. "generic" speaks plain DAP and trusts the adapter, for
+comparing against other debug adapters.
+
+Example: Disconnect automatically after 30 minutes without tool calls, ending the game
+godot_connect(idle_timeout=30, idle_terminate=true)
+
+The idle timeout defaults to GODOT_MCP_IDLE_TIMEOUT_MINUTES and
+GODOT_MCP_IDLE_TERMINATE from the server's environment (disabled if unset).`,
 
 		Parameters: []mcp.Parameter{
 			{
@@ -57,18 +327,66 @@ godot_connect(project="/path/to/my/project")`,
 				Required:    false,
 				Default:     6006,
 				Description: "DAP server port number (default: 6006)",
+				Minimum:     mcp.Float64(1),
+				Maximum:     mcp.Float64(65535),
 			},
 			{
 				Name:        "project",
 				Type:        "string",
 				Required:    false,
-				Description: "Absolute path to project root (optional, enables res:// path resolution)",
+				Description: "Absolute path to project root, or a name from godot_list_projects (optional, enables res:// path resolution; defaults to a project discovered in the client's workspace roots)",
+			},
+			{
+				Name:        "ssh",
+				Type:        "string",
+				Required:    false,
+				Description: "Reach the DAP server through an SSH tunnel to this target (\"user@host\" or an ~/.ssh/config alias)",
+			},
+			{
+				Name:        "socket",
+				Type:        "string",
+				Required:    false,
+				Description: "Connect over a unix domain socket path or Windows named pipe (\\\\.\\pipe\\name) instead of TCP; port is ignored",
+			},
+			{
+				Name:        "keepalive",
+				Type:        "number",
+				Required:    false,
+				Default:     30,
+				Description: "TCP keepalive period in seconds, so idle sessions survive NAT and firewalls (default: 30, 0 disables)",
+			},
+			{
+				Name:        "profile",
+				Type:        "string",
+				Required:    false,
+				Default:     dap.DefaultProfile,
+				Description: "Adapter profile: the Godot release the editor runs, or generic for other DAP adapters (default: " + dap.DefaultProfile + ")",
+				Enum:        dap.ProfileNames(),
+			},
+			{
+				Name:        "pad_requests",
+				Type:        "boolean",
+				Required:    false,
+				Description: "Send every request field Godot reads, even spec-optional ones, so the editor console stays free of Dictionary errors (default: the profile's, on for Godot profiles; disable to reproduce them)",
+			},
+			{
+				Name:        "idle_timeout",
+				Type:        "number",
+				Required:    false,
+				Description: "Disconnect after this many minutes without tool calls (0 disables; default: GODOT_MCP_IDLE_TIMEOUT_MINUTES)",
+				Minimum:     mcp.Float64(0),
+			},
+			{
+				Name:        "idle_terminate",
+				Type:        "boolean",
+				Required:    false,
+				Description: "Also end the running game on idle disconnect (default: GODOT_MCP_IDLE_TERMINATE)",
 			},
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
 			// Check if already connected
-			if globalSession != nil && globalSession.IsReady() {
+			if session := currentSession(); session != nil && session.IsReady() {
 				return map[string]interface{}{
 					"status":  "already_connected",
 					"message": "Already connected to Godot DAP server",
@@ -81,26 +399,72 @@ godot_connect(project="/path/to/my/project")`,
 				port = int(p)
 			}
 
-			// Create new session
-			session := dap.NewSession("localhost", port)
+			profileName, _ := params["profile"].(string)
+			profile, err := dap.LookupProfile(profileName)
+			if err != nil {
+				return nil, err
+			}
 
-			// Set project root if provided
+			// Create new session, tunnelling through ssh or using a local
+			// socket if requested
+			opts := []dap.ClientOption{dap.WithProfile(profile)}
+			if k, ok := params["keepalive"].(float64); ok {
+				if k <= 0 {
+					opts = append(opts, dap.WithKeepAlive(-1))
+				} else {
+					opts = append(opts, dap.WithKeepAlive(time.Duration(k*float64(time.Second))))
+				}
+			}
+			if set := connectQuirks(profile, params); set != profile.Quirks {
+				opts = append(opts, dap.WithQuirks(set))
+			}
+			if size := eventBufferSize(); size > 0 {
+				opts = append(opts, dap.WithEventBufferSize(size))
+			}
+			address := fmt.Sprintf("localhost:%d", port)
+			target, _ := params["ssh"].(string)
+			socket, _ := params["socket"].(string)
+			switch {
+			case target != "" && socket != "":
+				return nil, fmt.Errorf("ssh and socket cannot be combined; choose one transport")
+			case target != "":
+				opts = append(opts, dap.WithDialer(&dap.SSHDialer{Target: target}))
+				address = fmt.Sprintf("%s via ssh %s", address, target)
+			case socket != "":
+				opts = append(opts, dap.WithDialer(&dap.SocketDialer{Path: socket}))
+				address = socket
+			}
+			session := dap.NewSession("localhost", port, opts...)
+			session.OnStateChange(reportStateChanges(server))
+
+			// Set project root if provided, otherwise fall back to the
+			// project discovered from the client's workspace roots
 			if proj, ok := params["project"].(string); ok && proj != "" {
+				proj, err := resolveProject(proj)
+				if err != nil {
+					return nil, err
+				}
+				if err := checkPathAllowed(proj); err != nil {
+					return nil, err
+				}
+				session.SetProjectRoot(proj)
+			} else if proj := getDiscoveredProjectRoot(); proj != "" {
 				session.SetProjectRoot(proj)
 			}
 
-			// Connect with timeout
-			ctx, cancel := dap.WithConnectTimeout(context.Background())
+			// Connect with the profile's timeout
+			ctx, cancel := dap.WithTimeout(context.Background(), profile.ConnectTimeout)
 			defer cancel()
 
 			if err := session.Connect(ctx); err != nil {
 				return nil, FormatError(
 					"Failed to connect to Godot DAP server",
-					fmt.Sprintf("localhost:%d", port),
+					address,
 					[]string{
 						"Launch Godot editor",
 						"Enable DAP in Editor → Editor Settings → Network → Debug Adapter",
 						fmt.Sprintf("Check port setting (default: 6006, tried: %d)", port),
+						"If using ssh, check that `ssh <target>` works without a password prompt",
 					},
 					err,
 				)
@@ -116,14 +480,55 @@ godot_connect(project="/path/to/my/project")`,
 			// It must be sent AFTER the launch request.
 			// The session remains in 'initialized' state until a launch tool is called.
 
-			// Session is now ready for debugging
-			globalSession = session
+			// Session is now ready for debugging. A session left over from an
+			// earlier connect is closed so its connection doesn't leak; its
+			// breakpoints end with it.
+			if restored := takeRestoredBreakpoints(); restored != nil {
+				session.ReplaceBreakpoints(restored)
+			}
+			if prev := swapSession(session); prev != nil {
+				prev.Close()
+			}
+
+			idleTimeout, idleTerminate := defaultIdleSettings()
+			if minutes, ok := params["idle_timeout"].(float64); ok {
+				idleTimeout = time.Duration(minutes * float64(time.Minute))
+			}
+			if terminate, ok := params["idle_terminate"].(bool); ok {
+				idleTerminate = terminate
+			}
+			idle.watch(session, idleTimeout, idleTerminate)
+			scenes.attach(session.GetClient(), server)
 
-			return map[string]interface{}{
+			// Resume watches, node tracking, and seeding set up before a reconnect
+			if len(watches.list()) > 0 {
+				watches.attach(session.GetClient())
+			}
+			if len(nodeTracking.list()) > 0 {
+				nodeTracking.attach(session.GetClient())
+			}
+			if seeding.enabled() {
+				seeding.newRun()
+				seeding.attach(session.GetClient())
+			}
+
+			result := map[string]interface{}{
 				"status":  "connected",
-				"message": fmt.Sprintf("Connected to Godot DAP server at localhost:%d. Ready to launch.", port),
+				"message": fmt.Sprintf("Connected to Godot DAP server at %s. Ready to launch.", address),
 				"state":   session.GetState().String(),
-			}, nil
+				"profile": profile.Name,
+			}
+
+			// Set breakpoints restored from a snapshot before connecting
+			if files := session.Breakpoints().All(); len(files) > 0 {
+				result["breakpoints"] = applyBreakpoints(ctx, session.GetClient(), session.Breakpoints(), files)
+				enforceConditions(session)
+			}
+			if proj := session.GetProjectRoot(); proj != "" {
+				result["project"] = proj
+			}
+			autosaveSession()
+			return result, nil
 		},
 	})
 
@@ -143,29 +548,184 @@ After disconnecting, you'll need to call godot_connect again before
 performing any debugging operations.
 
 Example: Disconnect from DAP server
-godot_disconnect()`,
+godot_disconnect()
 
-		Parameters: []mcp.Parameter{},
+Example: End the running game, then disconnect
+godot_disconnect(terminate=true)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "terminate",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, end the running game before disconnecting",
+			},
+		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
-			// Check if connected
-			if globalSession == nil {
+			// Detach the session first so concurrent tool calls stop using it
+			session := swapSession(nil)
+			if session == nil {
 				return map[string]interface{}{
 					"status":  "not_connected",
 					"message": "Not currently connected to Godot DAP server",
 				}, nil
 			}
 
-			// Close the session
-			if err := globalSession.Close(); err != nil {
-				return nil, fmt.Errorf("failed to disconnect: %w", err)
+			exit, err := shutdownSession(session, getBoolParam(params, "terminate"))
+			if err != nil {
+				return nil, err
 			}
 
-			globalSession = nil
-
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"status":  "disconnected",
 				"message": "Disconnected from Godot DAP server",
+			}
+			if exit != nil {
+				result["exit"] = exit
+			}
+			return result, nil
+		},
+	})
+
+	// godot_get_session_state - Report session state and how the game ended
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_session_state",
+		Description: `Get the state of the DAP session and the outcome of the last run.
+
+This tool reports the session state (connected, initialized, launched, ...) and,
+once the game has ended, how it ended: the exit code from the "exited" event,
+whether a "terminated" event was received, and the termination reason
+("exited", "terminated", or "connection_lost").
+
+run_state tracks the game itself: "not_launched", "running", "paused", or
+"ended". Inspection and stepping tools require "paused".
+
+It also includes the timeline of the last launch or attach, including
+milestones reached after the launch tool returned (process start, first stop).
+
+protocol_violations, when present, lists recent messages from Godot that were
+malformed (a response without its body, a field of the wrong type) and were
+dropped. A malformed response also fails the tool call that sent the request.
+
+requests counts DAP requests by outcome. Requests that keep going unanswered
+(unanswered, or abandoned after 10 minutes without a response) point to a
+deadlocked or overloaded Godot.
+
+events lists the event subscribers (background trackers and waiting tools)
+with their buffer use and the events dropped because a buffer was full.
+Subscribers that resume the game (tracepoints, breakpoint conditions) wait
+for room instead of dropping stops.
+
+Use this tool:
+- After a test run, to check whether the game exited successfully (exit_code 0)
+- To find out whether the game is still running
+- To check the connection before issuing other commands
+
+Example: Check the outcome of a test run
+godot_get_session_state()`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session := currentSession()
+			if session == nil {
+				return map[string]interface{}{
+					"status":    "success",
+					"state":     dap.StateDisconnected.String(),
+					"run_state": string(dap.RunStateNotLaunched),
+					"connected": false,
+				}, nil
+			}
+
+			result := map[string]interface{}{
+				"status":    "success",
+				"state":     session.GetState().String(),
+				"run_state": string(session.GetClient().RunState()),
+				"connected": session.GetClient().IsConnected(),
+			}
+			if profile := session.GetClient().Profile(); profile != "" {
+				result["profile"] = profile
+			}
+			if proj := session.GetProjectRoot(); proj != "" {
+				result["project"] = proj
+			}
+			if exit := formatExitStatus(session.GetExitStatus()); exit != nil {
+				result["exit"] = exit
+			}
+			if milestones := session.GetClient().LaunchTimeline(); len(milestones) > 0 {
+				result["launch_timeline"] = formatTimeline(milestones)
+			}
+			result["requests"] = formatRequestStats(session.GetClient().RequestStats())
+			result["events"] = formatEventStats(session.GetClient().EventStats())
+			if violations := session.GetClient().ProtocolViolations(); len(violations) > 0 {
+				result["protocol_violations"] = formatViolations(violations)
+			}
+			if status := idle.status(); status != nil {
+				result["idle_timeout"] = status
+			}
+			return result, nil
+		},
+	})
+
+	// godot_get_process_info - Report the debuggee process
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_process_info",
+		Description: `Get the debuggee process reported by Godot (name and OS process ID).
+
+Godot sends a DAP "process" event once the game starts. This tool returns what
+it reported, plus any modules announced through "module" events. Use the PID to
+correlate the game with OS-level tools (top, perf, a native debugger, crash logs).
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- The game must have been launched or attached; process info is cleared at the
+  start of each run
+
+Fields the adapter doesn't report (e.g. pid) are omitted.
+
+Example: Find the game's PID
+godot_get_process_info()`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			client := session.GetClient()
+			process := client.ProcessInfo()
+			if process == nil {
+				return map[string]interface{}{
+					"status":    "unknown",
+					"message":   "No process event received yet. Launch the game with a launch tool (or godot_attach) first.",
+					"run_state": string(client.RunState()),
+				}, nil
+			}
+
+			modules := make([]map[string]interface{}, 0)
+			for _, m := range client.Modules() {
+				module := map[string]interface{}{
+					"id":   m.ID,
+					"name": m.Name,
+				}
+				if m.Path != "" {
+					module["path"] = m.Path
+				}
+				if m.Version != "" {
+					module["version"] = m.Version
+				}
+				modules = append(modules, module)
+			}
+
+			return map[string]interface{}{
+				"status":    "success",
+				"process":   formatProcessInfo(process),
+				"modules":   modules,
+				"run_state": string(client.RunState()),
 			}, nil
 		},
 	})
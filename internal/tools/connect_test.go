@@ -2,8 +2,11 @@ package tools
 
 import (
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
 )
 
@@ -30,6 +33,35 @@ func TestGetSession_NoSession(t *testing.T) {
 	}
 }
 
+func TestSessionAccess_Concurrent(t *testing.T) {
+	defer swapSession(nil)
+
+	// Run with -race: readers must not race with the session being replaced
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			swapSession(dap.NewSession("localhost", 6006))
+		}()
+		go func() {
+			defer wg.Done()
+			if session, err := GetSession(); err == nil {
+				session.SetProjectRoot("/tmp/project")
+				_ = session.GetState()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if prev := swapSession(nil); prev == nil {
+		t.Error("swapSession should return the session it replaced")
+	}
+	if _, err := GetSession(); err == nil {
+		t.Error("GetSession should error after the session is cleared")
+	}
+}
+
 func TestGodotConnect_ToolMetadata(t *testing.T) {
 	server := mcp.NewServer()
 	RegisterConnectionTools(server)
@@ -43,6 +75,34 @@ func TestGodotConnect_ToolMetadata(t *testing.T) {
 	// For unit tests, we verify registration succeeds
 }
 
+func TestConnectQuirks(t *testing.T) {
+	generic, _ := dap.LookupProfile("generic")
+	godot, _ := dap.LookupProfile("godot-4.4")
+
+	// pad_requests must have no default, or the server would pass it on
+	// every call and override the profile
+	server := mcp.NewServer()
+	RegisterConnectionTools(server)
+	for _, tool := range server.ToolCatalog() {
+		if tool.Name == "godot_connect" && tool.InputSchema.Properties["pad_requests"].Default != nil {
+			t.Errorf("pad_requests should have no default, got %v", tool.InputSchema.Properties["pad_requests"].Default)
+		}
+	}
+
+	if connectQuirks(generic, map[string]interface{}{}).ReadsOptionalFieldsUnsafely {
+		t.Error("the generic profile should stay unpadded")
+	}
+	if !connectQuirks(godot, map[string]interface{}{}).ReadsOptionalFieldsUnsafely {
+		t.Error("Godot profiles should pad requests")
+	}
+	if connectQuirks(godot, map[string]interface{}{"pad_requests": false}).ReadsOptionalFieldsUnsafely {
+		t.Error("pad_requests=false should turn padding off")
+	}
+	if set := connectQuirks(generic, map[string]interface{}{"pad_requests": true}); !set.ReadsOptionalFieldsUnsafely || set.LaunchResponseAfterConfigurationDone {
+		t.Errorf("pad_requests=true should only turn padding on, got %+v", set)
+	}
+}
+
 func TestGodotDisconnect_NoConnection(t *testing.T) {
 	// Reset global session
 	globalSession = nil
@@ -60,3 +120,97 @@ func TestGodotDisconnect_NoConnection(t *testing.T) {
 		t.Error("Should return nil session when disconnected")
 	}
 }
+
+func TestFormatExitStatus(t *testing.T) {
+	if formatExitStatus(nil) != nil {
+		t.Error("nil exit status should format as nil")
+	}
+
+	exited := formatExitStatus(&dap.ExitStatus{Reason: dap.ExitReasonExited, Exited: true, ExitCode: 2, Terminated: true, Time: time.Now()})
+	if exited["exit_code"] != 2 || exited["success"] != false || exited["reason"] != "exited" {
+		t.Errorf("unexpected exited status: %v", exited)
+	}
+
+	terminated := formatExitStatus(&dap.ExitStatus{Reason: dap.ExitReasonTerminated, Terminated: true, Time: time.Now()})
+	if _, ok := terminated["exit_code"]; ok {
+		t.Error("exit_code should be omitted when no exited event was received")
+	}
+}
+
+func TestFormatViolations(t *testing.T) {
+	violations := formatViolations([]dap.ProtocolViolation{
+		{Kind: "response", Name: "stackTrace", RequestSeq: 4, Problems: []string{"body: missing"}, Time: time.Now()},
+		{Problems: []string{"not a JSON object"}, Time: time.Now()},
+	})
+	if len(violations) != 2 || violations[0]["name"] != "stackTrace" || violations[0]["kind"] != "response" {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+	if _, ok := violations[1]["name"]; ok {
+		t.Error("name should be omitted for messages that aren't JSON objects")
+	}
+}
+
+func TestFormatRequestStats(t *testing.T) {
+	idle := formatRequestStats(dap.RequestStats{Sent: 4, Answered: 4})
+	if idle["answered"] != 4 || idle["oldest_pending_ms"] != nil || idle["unanswered_by_command"] != nil {
+		t.Errorf("unexpected stats: %v", idle)
+	}
+	stuck := formatRequestStats(dap.RequestStats{Sent: 3, Abandoned: 1, Pending: 1, OldestPending: 2 * time.Second, UnansweredByCommand: map[string]int{"evaluate": 1}})
+	if stuck["oldest_pending_ms"] != int64(2000) || stuck["unanswered_by_command"] == nil {
+		t.Errorf("unexpected stats: %v", stuck)
+	}
+}
+
+func TestFormatEventStats(t *testing.T) {
+	stats := formatEventStats(dap.EventStats{Dropped: 2, Listeners: []dap.ListenerStats{
+		{Name: "tracepoints", Capacity: 100, Blocking: true},
+		{Capacity: 100, Buffered: 100, Dropped: 2},
+	}})
+	listeners := stats["listeners"].([]map[string]interface{})
+	if stats["dropped"] != 2 || len(listeners) != 2 || listeners[0]["blocking"] != true || listeners[0]["name"] != "tracepoints" {
+		t.Errorf("unexpected event stats: %v", stats)
+	}
+	if _, ok := listeners[1]["name"]; ok {
+		t.Error("name should be omitted for unnamed listeners")
+	}
+}
+
+func TestEventBufferSize(t *testing.T) {
+	for value, want := range map[string]int{"": 0, "500": 500, "0": 0, "-3": 0, "lots": 0} {
+		t.Setenv(eventBufferSizeEnv, value)
+		if got := eventBufferSize(); got != want {
+			t.Errorf("%s=%q: got %d, want %d", eventBufferSizeEnv, value, got, want)
+		}
+	}
+}
+
+func TestFormatProcessInfo(t *testing.T) {
+	process := formatProcessInfo(&dap.ProcessInfo{Name: "my-game", PID: 4242, StartMethod: "launch", Time: time.Now()})
+	if process["name"] != "my-game" || process["pid"] != 4242 || process["start_method"] != "launch" {
+		t.Errorf("unexpected process info: %v", process)
+	}
+
+	unknownPID := formatProcessInfo(&dap.ProcessInfo{Name: "my-game", Time: time.Now()})
+	if _, ok := unknownPID["pid"]; ok {
+		t.Error("pid should be omitted when the adapter didn't report one")
+	}
+}
+
+func TestReportStateChanges(t *testing.T) {
+	n := &recordingNotifier{}
+	report := reportStateChanges(n)
+
+	report(dap.StateChange{From: dap.StateInitialized, To: dap.StateLaunched, Time: time.Now()})
+
+	if len(n.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(n.notifications))
+	}
+	params := n.notifications[0]
+	if params["logger"] != "session" || params["level"] != "info" {
+		t.Errorf("unexpected notification params: %v", params)
+	}
+	data := params["data"].(map[string]interface{})
+	if data["from"] != "initialized" || data["to"] != "launched" {
+		t.Errorf("expected initialized -> launched, got %v", data)
+	}
+}
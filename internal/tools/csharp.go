@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxListedScripts caps how many GDScript/C# files are listed in guidance errors
+const maxListedScripts = 10
+
+// scriptFiles lists a project's scripts by language, as res:// paths
+type scriptFiles struct {
+	GDScript []string
+	CSharp   []string
+}
+
+// isCSharpProject reports whether the project at projectDir uses C# scripts.
+// Godot's .NET build generates a .csproj (and usually a .sln) next to
+// project.godot, and lists "C#" in application/config/features.
+func isCSharpProject(projectDir string) bool {
+	if projectDir == "" {
+		return false
+	}
+
+	entries, err := os.ReadDir(projectDir)
+	if err == nil {
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if !e.IsDir() && (ext == ".csproj" || ext == ".sln") {
+				return true
+			}
+		}
+	}
+
+	info, err := parseProjectFile(projectDir)
+	if err != nil {
+		return false
+	}
+	for _, feature := range info.Features {
+		if feature == "C#" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCSharpScript reports whether path refers to a C# source file
+func isCSharpScript(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".cs")
+}
+
+// findScriptFiles walks a project and collects its .gd and .cs files.
+// Hidden directories and the .NET build output (bin, obj) are skipped.
+func findScriptFiles(projectDir string) scriptFiles {
+	var files scriptFiles
+	projectDir = filepath.Clean(projectDir)
+
+	filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != projectDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != projectDir && (strings.HasPrefix(name, ".") || name == "bin" || name == "obj") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return nil
+		}
+		resPath := "res://" + filepath.ToSlash(rel)
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".gd":
+			files.GDScript = append(files.GDScript, resPath)
+		case ".cs":
+			files.CSharp = append(files.CSharp, resPath)
+		}
+		return nil
+	})
+
+	return files
+}
+
+// summarizeScripts formats a bounded list of script paths for an error message
+func summarizeScripts(paths []string) string {
+	if len(paths) == 0 {
+		return "none"
+	}
+	if len(paths) <= maxListedScripts {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s, ... (%d more)", strings.Join(paths[:maxListedScripts], ", "), len(paths)-maxListedScripts)
+}
+
+// ErrCSharpBreakpoint explains that C# breakpoints can't be set through Godot's DAP.
+// If the project root is known, the project's GDScript and C# files are listed
+// so the caller can pick a file that can be debugged here.
+func ErrCSharpBreakpoint(file string, projectRoot string) error {
+	solutions := []string{
+		"C# scripts are debugged via the .NET debugger, not Godot's DAP: attach Visual Studio, Rider, or VS Code (C# Dev Kit) to the running game",
+		"Set breakpoints in GDScript (.gd) files with this tool instead",
+	}
+
+	if projectRoot != "" {
+		scripts := findScriptFiles(projectRoot)
+		solutions = append(solutions,
+			fmt.Sprintf("GDScript files in this project (%d): %s", len(scripts.GDScript), summarizeScripts(scripts.GDScript)),
+			fmt.Sprintf("C# files in this project (%d): %s", len(scripts.CSharp), summarizeScripts(scripts.CSharp)),
+		)
+	}
+
+	return FormatError(
+		"Cannot set breakpoints in C# scripts through Godot's DAP server",
+		file,
+		solutions,
+		nil,
+	)
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile creates a file (and its parent directories) under root
+func writeFile(t *testing.T, root string, rel string, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestIsCSharpProject(t *testing.T) {
+	gdProject := t.TempDir()
+	makeProject(t, gdProject)
+	if isCSharpProject(gdProject) {
+		t.Error("plain GDScript project should not be detected as C#")
+	}
+
+	csprojProject := t.TempDir()
+	makeProject(t, csprojProject)
+	writeFile(t, csprojProject, "MyGame.csproj", "<Project Sdk=\"Godot.NET.Sdk/4.3.0\"></Project>")
+	if !isCSharpProject(csprojProject) {
+		t.Error("project with a .csproj should be detected as C#")
+	}
+
+	featureProject := t.TempDir()
+	writeFile(t, featureProject, "project.godot", "[application]\nconfig/features=PackedStringArray(\"4.3\", \"C#\", \"Forward Plus\")\n")
+	if !isCSharpProject(featureProject) {
+		t.Error("project with the C# feature should be detected as C#")
+	}
+
+	if isCSharpProject("") {
+		t.Error("empty project root should not be detected as C#")
+	}
+}
+
+func TestFindScriptFiles(t *testing.T) {
+	root := t.TempDir()
+	makeProject(t, root)
+	writeFile(t, root, "player.gd", "")
+	writeFile(t, root, "scripts/Enemy.cs", "")
+	writeFile(t, root, ".godot/mono/Generated.cs", "") // hidden, skipped
+	writeFile(t, root, "obj/Debug/Build.cs", "")       // build output, skipped
+
+	scripts := findScriptFiles(root)
+	if len(scripts.GDScript) != 1 || scripts.GDScript[0] != "res://player.gd" {
+		t.Errorf("unexpected GDScript files: %v", scripts.GDScript)
+	}
+	if len(scripts.CSharp) != 1 || scripts.CSharp[0] != "res://scripts/Enemy.cs" {
+		t.Errorf("unexpected C# files: %v", scripts.CSharp)
+	}
+}
+
+func TestErrCSharpBreakpoint(t *testing.T) {
+	root := t.TempDir()
+	makeProject(t, root)
+	writeFile(t, root, "player.gd", "")
+	writeFile(t, root, "Player.cs", "")
+
+	msg := ErrCSharpBreakpoint("res://Player.cs", root).Error()
+	for _, want := range []string{".NET debugger", "res://player.gd", "res://Player.cs"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error should mention %q, got:\n%s", want, msg)
+		}
+	}
+
+	if !isCSharpScript("res://Player.CS") || isCSharpScript("res://player.gd") {
+		t.Error("isCSharpScript should match .cs files case-insensitively")
+	}
+}
+
+func TestSummarizeScripts(t *testing.T) {
+	if got := summarizeScripts(nil); got != "none" {
+		t.Errorf("expected none, got %q", got)
+	}
+
+	var paths []string
+	for i := 0; i < maxListedScripts+3; i++ {
+		paths = append(paths, "res://a.gd")
+	}
+	if got := summarizeScripts(paths); !strings.HasSuffix(got, "(3 more)") {
+		t.Errorf("expected truncated list, got %q", got)
+	}
+}
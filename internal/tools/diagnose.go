@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// expressionEvaluator is the part of the DAP client diagnoseExpression needs
+type expressionEvaluator interface {
+	Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error)
+}
+
+// Outcomes of evaluating one prefix of an expression
+const (
+	segmentOK    = "ok"
+	segmentNull  = "null"
+	segmentFreed = "freed"
+	segmentError = "error"
+)
+
+// segmentResult is the evaluation of one prefix of a dotted expression
+type segmentResult struct {
+	Expression string `json:"expression"`
+	Status     string `json:"status"` // One of the segment* outcomes
+	Result     string `json:"result,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// splitAccessChain returns the prefixes of a dotted expression that end
+// before each member access: "a.b(c.d).e" gives "a", "a.b(c.d)", and
+// "a.b(c.d).e". Dots inside brackets, strings, and number literals don't
+// split.
+func splitAccessChain(expression string) []string {
+	var prefixes []string
+	depth := 0
+	var quote rune
+	escaped := false
+	runes := []rune(expression)
+	for i, r := range runes {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == '.' && depth == 0:
+			prefix := strings.TrimSpace(string(runes[:i]))
+			if prefix != "" && !isNumberLiteral(prefix) {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return append(prefixes, strings.TrimSpace(expression))
+}
+
+// isNumberLiteral reports whether the text after the last operator is a
+// number, so "x + 1.5" doesn't split at the decimal point
+func isNumberLiteral(prefix string) bool {
+	last := prefix[strings.LastIndexAny(prefix, " +-*/%(,[<>=!&|")+1:]
+	if last == "" {
+		return false
+	}
+	for _, r := range last {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// classifyEvaluation turns an evaluate result into a segment outcome
+func classifyEvaluation(resp *godap.EvaluateResponse, err error) segmentResult {
+	if err != nil {
+		return segmentResult{Status: segmentError, Error: err.Error()}
+	}
+	result := segmentResult{Status: segmentOK, Result: resp.Body.Result, Type: resp.Body.Type}
+	switch {
+	case strings.Contains(resp.Body.Result, "Freed Object") || strings.Contains(resp.Body.Result, "previously freed"):
+		result.Status = segmentFreed
+	case resp.Body.Result == "null" || resp.Body.Type == "Nil":
+		result.Status = segmentNull
+	}
+	return result
+}
+
+// diagnoseExpression evaluates each prefix of expression in turn and stops at
+// the first one that fails, is null, or is a freed object, explaining which
+// access broke
+func diagnoseExpression(ctx context.Context, client expressionEvaluator, expression string, frameId int) map[string]interface{} {
+	prefixes := splitAccessChain(expression)
+	var segments []segmentResult
+	for i, prefix := range prefixes {
+		resp, err := client.Evaluate(ctx, prefix, frameId, "watch")
+		segment := classifyEvaluation(resp, err)
+		segment.Expression = prefix
+		segments = append(segments, segment)
+
+		last := i == len(prefixes)-1
+		if segment.Status == segmentOK || (last && segment.Status == segmentNull) {
+			continue
+		}
+
+		// The member accessed after this prefix, e.g. ".c" for "a.b" in "a.b.c"
+		next := ""
+		if !last {
+			next = strings.TrimPrefix(prefixes[i+1], prefix)
+		}
+		var diagnosis string
+		switch segment.Status {
+		case segmentNull:
+			diagnosis = fmt.Sprintf("%s is null, so %s can't be accessed", prefix, next)
+		case segmentFreed:
+			diagnosis = fmt.Sprintf("%s refers to a freed object (queue_free() or free() was called on it)", prefix)
+		case segmentError:
+			if i == 0 {
+				diagnosis = fmt.Sprintf("%s can't be evaluated in this frame: %s", prefix, segment.Error)
+			} else {
+				parent := segments[i-1]
+				member := strings.TrimPrefix(strings.TrimPrefix(prefix, parent.Expression), ".")
+				owner := parent.Expression
+				if parent.Type != "" {
+					owner += " (" + parent.Type + ")"
+				}
+				diagnosis = fmt.Sprintf("%s has no accessible %s: %s", owner, member, segment.Error)
+			}
+		}
+		return map[string]interface{}{
+			"status":          "failed",
+			"expression":      expression,
+			"failing_segment": prefix,
+			"diagnosis":       diagnosis,
+			"segments":        segments,
+		}
+	}
+
+	final := segments[len(segments)-1]
+	return map[string]interface{}{
+		"status":     "ok",
+		"expression": expression,
+		"result":     final.Result,
+		"type":       final.Type,
+		"diagnosis":  fmt.Sprintf("every access in %s succeeded", expression),
+		"segments":   segments,
+	}
+}
+
+// RegisterDiagnoseTools registers godot_diagnose_expression
+func RegisterDiagnoseTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_diagnose_expression",
+		Description: `Find which part of a dotted expression is null or invalid.
+
+When an expression like "player.weapon.sprite.texture" fails, Godot only
+reports a generic error. This tool evaluates each prefix in turn ("player",
+"player.weapon", "player.weapon.sprite", ...) and stops at the first one that
+errors, is null, or refers to a freed object, naming that segment.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Use this tool:
+- After godot_evaluate fails on a chain of member accesses
+- To find out which node or reference is null at a crash site
+
+Each prefix is evaluated, so prefixes with side effects (e.g. method calls)
+run again.
+
+Example: Find the null link in a chain
+godot_diagnose_expression(expression="player.weapon.sprite.texture")
+→ {"status": "failed", "failing_segment": "player.weapon",
+   "diagnosis": "player.weapon is null, so .sprite can't be accessed", ...}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "expression",
+				Type:        "string",
+				Required:    true,
+				Description: "Dotted GDScript expression to diagnose (e.g. player.weapon.sprite)",
+			},
+			{
+				Name:        "frame_id",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Stack frame ID for evaluation context (default: 0 = top frame)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "diagnose expression"); err != nil {
+				return nil, err
+			}
+
+			expression, ok := params["expression"].(string)
+			if !ok || strings.TrimSpace(expression) == "" {
+				return nil, fmt.Errorf("expression is required and must be a non-empty string")
+			}
+			frameId := 0
+			if fid, ok := params["frame_id"].(float64); ok {
+				frameId = int(fid)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			return diagnoseExpression(ctx, session.GetClient(), expression, frameId), nil
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+// fakeDiagnoseEvaluator answers from a table of expression → result and type;
+// unknown expressions fail
+type fakeDiagnoseEvaluator struct {
+	values    map[string][2]string
+	evaluated []string
+}
+
+func (f *fakeDiagnoseEvaluator) Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error) {
+	f.evaluated = append(f.evaluated, expression)
+	value, ok := f.values[expression]
+	if !ok {
+		return nil, fmt.Errorf("Invalid get index '%s'", expression)
+	}
+	resp := &godap.EvaluateResponse{}
+	resp.Body.Result = value[0]
+	resp.Body.Type = value[1]
+	return resp, nil
+}
+
+func TestSplitAccessChain(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"player", []string{"player"}},
+		{"a.b.c", []string{"a", "a.b", "a.b.c"}},
+		{"a.get_node(\"x.y\").z", []string{"a", "a.get_node(\"x.y\")", "a.get_node(\"x.y\").z"}},
+		{"items[i.index].name", []string{"items[i.index]", "items[i.index].name"}},
+		{"$Player/Sprite.texture", []string{"$Player/Sprite", "$Player/Sprite.texture"}},
+		{"speed * 1.5", []string{"speed * 1.5"}},
+		{"'a\\'.b'.length()", []string{"'a\\'.b'", "'a\\'.b'.length()"}},
+	}
+	for _, tt := range tests {
+		if got := splitAccessChain(tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAccessChain(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestDiagnoseExpression(t *testing.T) {
+	values := map[string][2]string{
+		"player":              {"Player:<CharacterBody2D#123>", "CharacterBody2D"},
+		"player.weapon":       {"null", "Nil"},
+		"player.target":       {"<Freed Object>", "Object"},
+		"player.stats":        {"Stats:<Resource#9>", "Resource"},
+		"player.stats.health": {"100", "int"},
+	}
+
+	tests := []struct {
+		expr      string
+		status    string
+		failing   string
+		diagnosis string
+		evaluated int
+	}{
+		{"player.weapon.sprite.texture", "failed", "player.weapon", "player.weapon is null, so .sprite can't be accessed", 2},
+		{"player.target.position", "failed", "player.target", "freed object", 2},
+		{"player.speed", "failed", "player.speed", "player (CharacterBody2D) has no accessible speed", 2},
+		{"enemy.health", "failed", "enemy", "enemy can't be evaluated in this frame", 1},
+		{"player.stats.health", "ok", "", "every access", 3},
+		{"player.weapon", "ok", "", "every access", 2}, // A null final value is a valid result
+	}
+	for _, tt := range tests {
+		eval := &fakeDiagnoseEvaluator{values: values}
+		result := diagnoseExpression(context.Background(), eval, tt.expr, 0)
+		if result["status"] != tt.status {
+			t.Errorf("%s: status = %v, want %s", tt.expr, result["status"], tt.status)
+		}
+		if tt.failing != "" && result["failing_segment"] != tt.failing {
+			t.Errorf("%s: failing_segment = %v, want %s", tt.expr, result["failing_segment"], tt.failing)
+		}
+		if d, _ := result["diagnosis"].(string); !strings.Contains(d, tt.diagnosis) {
+			t.Errorf("%s: diagnosis = %q, want it to contain %q", tt.expr, d, tt.diagnosis)
+		}
+		if len(eval.evaluated) != tt.evaluated {
+			t.Errorf("%s: evaluated %v, want %d prefixes", tt.expr, eval.evaluated, tt.evaluated)
+		}
+	}
+}
@@ -72,3 +72,51 @@ func ErrSessionNotReady(state string) error {
 		nil,
 	)
 }
+
+func ErrGameNotLaunched(action string) error {
+	return FormatError(
+		fmt.Sprintf("Cannot %s: game not launched", action),
+		"",
+		[]string{
+			"Call godot_launch_main_scene() (or another launch tool) to start the game",
+			"Or call godot_attach() to debug a game that is already running",
+		},
+		nil,
+	)
+}
+
+func ErrGameRunning(action string) error {
+	return FormatError(
+		fmt.Sprintf("Cannot %s: game is running, pause first", action),
+		"",
+		[]string{
+			"Call godot_pause() to pause execution",
+			"Or set a breakpoint and wait for it with godot_wait_for_event(types=[\"stopped\"])",
+		},
+		nil,
+	)
+}
+
+func ErrGameEnded(action string, reason string) error {
+	return FormatError(
+		fmt.Sprintf("Cannot %s: game has ended", action),
+		fmt.Sprintf("reason: %s", reason),
+		[]string{
+			"Call godot_get_session_state() to see how the game ended",
+			"Launch the game again with godot_launch_main_scene() (or another launch tool)",
+		},
+		nil,
+	)
+}
+
+func ErrNotSupportedByAdapter(action string, capability string) error {
+	return FormatError(
+		fmt.Sprintf("Cannot %s: not supported by the debug adapter", action),
+		fmt.Sprintf("capability %s not advertised", capability),
+		[]string{
+			"Update Godot; this feature needs engine support in its DAP server",
+			"Use breakpoints with godot_continue, or godot_step_over, to move execution instead",
+		},
+		nil,
+	)
+}
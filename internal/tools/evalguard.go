@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	godap "github.com/google/go-dap"
+)
+
+// Bounds of godot_evaluate's timeout_seconds
+const maxEvaluateSeconds = 30
+
+// evalProbeTimeout bounds each step of the recovery after an evaluate times
+// out, so a hung game costs a few seconds rather than another full timeout
+const evalProbeTimeout = 2 * time.Second
+
+// evalRecoverer is the part of the DAP client used to recover from an
+// evaluate that timed out
+type evalRecoverer interface {
+	Pause(ctx context.Context, threadId int) (*godap.PauseResponse, error)
+	Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error)
+}
+
+// evalRecovery is the outcome of recoverFromEvaluateTimeout
+type evalRecovery struct {
+	PauseSent  bool
+	Responsive bool // A trivial evaluate succeeded after the pause
+}
+
+// recoverFromEvaluateTimeout runs after an expression exceeded its timeout.
+// The expression is still running on the game's main thread (a heavy getter
+// or an endless loop), so it sends a pause to get the debugger back in
+// control, then evaluates a constant to see whether the game answers again.
+func recoverFromEvaluateTimeout(client evalRecoverer, frameId int) evalRecovery {
+	var recovery evalRecovery
+
+	ctx, cancel := context.WithTimeout(context.Background(), evalProbeTimeout)
+	_, err := client.Pause(ctx, 1)
+	cancel()
+	recovery.PauseSent = err == nil
+
+	ctx, cancel = context.WithTimeout(context.Background(), evalProbeTimeout)
+	_, err = client.Evaluate(ctx, "1", frameId, "repl")
+	cancel()
+	recovery.Responsive = err == nil
+
+	return recovery
+}
+
+// isEvaluateTimeout reports whether err is an evaluate that ran out of time
+func isEvaluateTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// evaluateTimeoutError explains a timed-out evaluate and what to do next,
+// depending on whether the game recovered
+func evaluateTimeoutError(expression string, timeout time.Duration, recovery evalRecovery, cause error) error {
+	problem := fmt.Sprintf("Expression did not finish within %s", timeout)
+	var solutions []string
+	if recovery.Responsive {
+		problem += "; the game is responding again"
+		solutions = []string{
+			"The expression is expensive (a heavy getter, a large loop, or a blocking call); avoid evaluating it while paused",
+			"Inspect the individual properties it reads with godot_get_variables or smaller expressions",
+			"If the expression is expected to be slow, retry with a larger timeout_seconds (max 30)",
+		}
+	} else {
+		problem += "; the game is not responding"
+		solutions = []string{
+			"The expression might loop forever or block the main thread; the game can't answer the debugger until it returns",
+			"Wait a few seconds and call godot_get_threads to check whether it recovered",
+			"Otherwise end the run with godot_disconnect(terminate=true) and relaunch the game",
+		}
+	}
+	if !recovery.PauseSent {
+		solutions = append(solutions, "The automatic pause request was not acknowledged; call godot_pause once the game responds")
+	}
+	return FormatError(problem, fmt.Sprintf("expr='%s'", expression), solutions, cause)
+}
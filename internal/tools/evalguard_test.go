@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	godap "github.com/google/go-dap"
+)
+
+// fakeRecoverer answers pause and the probe evaluate, or blocks until the
+// context expires like a game stuck in an expression
+type fakeRecoverer struct {
+	hung   bool
+	paused bool
+}
+
+func (f *fakeRecoverer) Pause(ctx context.Context, threadId int) (*godap.PauseResponse, error) {
+	f.paused = true
+	return &godap.PauseResponse{}, nil
+}
+
+func (f *fakeRecoverer) Evaluate(ctx context.Context, expression string, frameId int, evalContext string) (*godap.EvaluateResponse, error) {
+	if f.hung {
+		<-ctx.Done()
+		return nil, fmt.Errorf("request timed out: %w", ctx.Err())
+	}
+	resp := &godap.EvaluateResponse{}
+	resp.Body.Result = expression
+	return resp, nil
+}
+
+func TestRecoverFromEvaluateTimeout(t *testing.T) {
+	client := &fakeRecoverer{}
+	recovery := recoverFromEvaluateTimeout(client, 0)
+	if !client.paused || !recovery.PauseSent || !recovery.Responsive {
+		t.Errorf("expected a pause and a responsive game, got %+v", recovery)
+	}
+
+	timedOut := fmt.Errorf("request timed out: %w", context.DeadlineExceeded)
+	if !isEvaluateTimeout(timedOut) || isEvaluateTimeout(errors.New("invalid expression")) {
+		t.Error("only deadline errors should count as timeouts")
+	}
+
+	err := evaluateTimeoutError("level.total_weight", 10*time.Second, recovery, timedOut)
+	if !strings.Contains(err.Error(), "responding again") || !strings.Contains(err.Error(), "godot_get_variables") {
+		t.Errorf("expected guidance for a slow expression, got %v", err)
+	}
+}
+
+func TestRecoverFromEvaluateTimeout_Hung(t *testing.T) {
+	recovery := recoverFromEvaluateTimeout(&fakeRecoverer{hung: true}, 0)
+	if recovery.Responsive {
+		t.Fatal("a game that doesn't answer the probe should not be reported responsive")
+	}
+
+	err := evaluateTimeoutError("while true: pass", 10*time.Second, recovery, context.DeadlineExceeded)
+	if !strings.Contains(err.Error(), "not responding") || !strings.Contains(err.Error(), "terminate=true") {
+		t.Errorf("expected guidance to end the run, got %v", err)
+	}
+}
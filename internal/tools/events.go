@@ -0,0 +1,451 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+const (
+	// defaultEventWaitSeconds is how long godot_wait_for_event waits by default
+	defaultEventWaitSeconds = 30
+
+	// maxEventWaitSeconds caps the wait so a tool call can't block indefinitely
+	maxEventWaitSeconds = 600
+
+	// defaultEventHistoryLimit is how many events godot_get_events returns by
+	// default; maxEventHistoryLimit matches the client's history size
+	defaultEventHistoryLimit = 100
+	maxEventHistoryLimit     = 500
+)
+
+// defaultWaitEventTypes are the events godot_wait_for_event waits for when
+// no types are given: anything that ends or pauses the run
+var defaultWaitEventTypes = []string{"stopped", "terminated", "exited"}
+
+// getStringListParam reads a parameter given as a JSON array of strings.
+// A single string is accepted as a one-element list.
+func getStringListParam(params map[string]interface{}, name string) ([]string, error) {
+	switch v := params[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []string:
+		// A parameter default, applied as is
+		return v, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings (got element %v)", name, item)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", name)
+	}
+}
+
+// waitTimeoutParameter is the timeout parameter of the tools that wait for events
+var waitTimeoutParameter = mcp.Parameter{
+	Name:        "timeout",
+	Type:        "number",
+	Required:    false,
+	Default:     defaultEventWaitSeconds,
+	Description: fmt.Sprintf("Maximum time to wait in seconds (default: %d, max: %d)", defaultEventWaitSeconds, maxEventWaitSeconds),
+}
+
+// waitTimeoutParam reads waitTimeoutParameter in seconds, clamped to 1 to
+// maxEventWaitSeconds
+func waitTimeoutParam(params map[string]interface{}) int {
+	timeoutSec := defaultEventWaitSeconds
+	if t, ok := params["timeout"].(float64); ok {
+		timeoutSec = int(t)
+	}
+	if timeoutSec < 1 {
+		timeoutSec = 1
+	}
+	if timeoutSec > maxEventWaitSeconds {
+		timeoutSec = maxEventWaitSeconds
+	}
+	return timeoutSec
+}
+
+// isClosed reports whether ch is closed
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitDisconnectedResult is the result of a wait cut short because the
+// session was closed (godot_disconnect or a new godot_connect)
+func waitDisconnectedResult(waitedMs int64) map[string]interface{} {
+	return map[string]interface{}{
+		"status":    "disconnected",
+		"message":   "The DAP session was closed while waiting; call godot_connect to start a new one",
+		"waited_ms": waitedMs,
+	}
+}
+
+// formatEvent converts a DAP event to a generic map for the tool response.
+// The body is round-tripped through JSON so every event type is handled.
+func formatEvent(event godap.EventMessage) map[string]interface{} {
+	result := map[string]interface{}{
+		"event": event.GetEvent().Event,
+		"seq":   event.GetSeq(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return result
+	}
+	var raw struct {
+		Body interface{} `json:"body"`
+	}
+	if err := json.Unmarshal(data, &raw); err == nil && raw.Body != nil {
+		result["body"] = raw.Body
+	}
+	return result
+}
+
+// RegisterEventTools registers tools that wait on asynchronous DAP events
+func RegisterEventTools(server *mcp.Server) {
+	// godot_wait_for_event - Block until a specific DAP event arrives
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_wait_for_event",
+		Description: `Wait until one of the specified DAP events arrives and return it.
+
+This tool blocks until Godot sends an event whose type is in the list, or until
+the timeout expires. Only events that arrive after the call starts are
+considered. Breakpoint stops that a breakpoint condition or tracepoint resumes
+right away are skipped. If the session is closed while waiting, the tool
+returns status "disconnected".
+
+Common event types:
+- stopped: Game paused (breakpoint, step, pause)
+- terminated: Debug session ended
+- exited: Game process exited (body includes exitCode)
+- output: Game printed something
+- continued, thread, breakpoint, process
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To wait for the game to exit after a test run
+- To wait for a breakpoint hit after continuing
+- To orchestrate flows that depend on asynchronous game events
+
+Example: Wait for the game to stop or exit (default types)
+godot_wait_for_event()
+
+Example: Wait up to 2 minutes for the game to exit
+godot_wait_for_event(types=["terminated", "exited"], timeout=120)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "types",
+				Type:        "array",
+				Required:    false,
+				Default:     defaultWaitEventTypes,
+				Items:       &mcp.Parameter{Type: "string"},
+				Description: "Event types to wait for (default: [\"stopped\", \"terminated\", \"exited\"])",
+			},
+			waitTimeoutParameter,
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			types := defaultWaitEventTypes
+			if list, err := getStringListParam(params, "types"); err != nil {
+				return nil, err
+			} else if len(list) > 0 {
+				types = list
+			}
+
+			timeoutSec := waitTimeoutParam(params)
+
+			client := session.GetClient()
+			events, cleanup := client.SubscribeToEvents(dap.WithListenerName("godot_wait_for_event"), dap.WithBlockWhenFull())
+			defer cleanup()
+
+			start := time.Now()
+			ended := sessionDone(session)
+			msg := awaitReportedEvent(client, events, types, time.After(time.Duration(timeoutSec)*time.Second), ended)
+			event, ok := msg.(godap.EventMessage)
+			if !ok && isClosed(ended) {
+				return waitDisconnectedResult(time.Since(start).Milliseconds()), nil
+			}
+			if !ok {
+				return map[string]interface{}{
+					"status":  "timeout",
+					"message": fmt.Sprintf("No %v event within %d seconds", types, timeoutSec),
+					"types":   types,
+				}, nil
+			}
+
+			result := formatEvent(event)
+			result["status"] = "received"
+			result["waited_ms"] = time.Since(start).Milliseconds()
+			return result, nil
+		},
+	})
+
+	// godot_wait_for_stop - Block until the game pauses
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_wait_for_stop",
+		Description: `Wait until the game pauses and return where it stopped.
+
+This tool blocks until Godot sends a stopped event (breakpoint, step, pause,
+exception), then returns the stop reason, the thread, and the top stack frame
+with a source snippet. If a thread is already paused when the call starts (e.g.
+the breakpoint was hit right after launch), that stop is returned immediately,
+so launching and then waiting never misses a stop. Breakpoint stops that a
+breakpoint condition or tracepoint resumes right away are skipped. If the game
+ends while waiting, the tool returns at once with how it ended; if the session
+is closed, it returns status "disconnected".
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- After launching with breakpoints set, instead of sleeping
+- After godot_continue, to wait for the next breakpoint hit
+
+Example: Launch and wait for the first breakpoint
+godot_launch_main_scene(project="/path/to/project")
+godot_wait_for_stop(timeout=60)`,
+
+		Parameters: []mcp.Parameter{
+			waitTimeoutParameter,
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			client := session.GetClient()
+
+			timeoutSec := waitTimeoutParam(params)
+
+			// Subscribe before looking at the current state so a stop in
+			// between isn't missed
+			events, cleanup := client.SubscribeToEvents(dap.WithListenerName("godot_wait_for_stop"), dap.WithBlockWhenFull())
+			defer cleanup()
+
+			start := time.Now()
+			threadId, reason, already := currentStop(client.ThreadStates())
+
+			// A breakpoint stop a condition or tracepoint resumes (or is
+			// still deciding on) isn't reported as already paused
+			var pending []godap.Message
+			if stop := lastStop(client); already && stop != nil {
+				if resumed, decided := resumedAtOnce(client, stop); resumed {
+					already = false
+				} else if !decided {
+					already = false
+					pending = append(pending, stop)
+				}
+			}
+
+			if !already {
+				if client.RunState() == dap.RunStateEnded {
+					return waitEndedResult(session, 0), nil
+				}
+				ended := sessionDone(session)
+				event := awaitReportedEvent(client, events, defaultWaitEventTypes, time.After(time.Duration(timeoutSec)*time.Second), ended, pending...)
+				if event == nil && isClosed(ended) {
+					return waitDisconnectedResult(time.Since(start).Milliseconds()), nil
+				}
+				if event == nil {
+					return map[string]interface{}{
+						"status":    "timeout",
+						"message":   fmt.Sprintf("The game didn't stop within %d seconds", timeoutSec),
+						"run_state": string(client.RunState()),
+					}, nil
+				}
+				stopped, ok := event.(*godap.StoppedEvent)
+				if !ok {
+					return waitEndedResult(session, time.Since(start).Milliseconds()), nil
+				}
+				threadId, reason = stopped.Body.ThreadId, stopped.Body.Reason
+				if threadId == 0 {
+					threadId = 1
+				}
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+			location, err := currentLocation(ctx, session, threadId)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"status":         "paused",
+				"stop_reason":    reason,
+				"thread_id":      threadId,
+				"already_paused": already,
+				"waited_ms":      time.Since(start).Milliseconds(),
+				"location":       location,
+			}, nil
+		},
+	})
+
+	// godot_get_events - Poll the event history
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_events",
+		Description: `Get the DAP events received recently, optionally only those since a previous call.
+
+The server keeps the last 500 events Godot sent (stopped, continued, output,
+breakpoint, process, terminated, exited, ...) with their bodies. Each event has
+a sequence number; pass the next_since of the previous call as since to get
+only what happened in between, without blocking the way godot_wait_for_event
+does.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To check what happened while you were doing something else
+- To see whether a breakpoint was hit or the game exited since the last check
+- To review the order of events when debugging the debug session itself
+
+Example: Get the recent stop and exit events
+godot_get_events(types=["stopped", "terminated", "exited"])
+
+Example: Poll for anything new since the last call
+godot_get_events(since=42)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "types",
+				Type:        "array",
+				Required:    false,
+				Items:       &mcp.Parameter{Type: "string"},
+				Description: "Event types to return (default: all)",
+			},
+			{
+				Name:        "since",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Return only events with a sequence number above this (default: 0, all buffered events)",
+			},
+			{
+				Name:        "limit",
+				Type:        "number",
+				Required:    false,
+				Default:     defaultEventHistoryLimit,
+				Description: fmt.Sprintf("Maximum number of events to return, oldest first (default: %d, max: %d)", defaultEventHistoryLimit, maxEventHistoryLimit),
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			types, err := getStringListParam(params, "types")
+			if err != nil {
+				return nil, err
+			}
+			since := 0
+			if s, ok := params["since"].(float64); ok && s > 0 {
+				since = int(s)
+			}
+			limit := defaultEventHistoryLimit
+			if l, ok := params["limit"].(float64); ok {
+				limit = int(l)
+			}
+			if limit < 1 {
+				limit = 1
+			}
+			if limit > maxEventHistoryLimit {
+				limit = maxEventHistoryLimit
+			}
+
+			return formatEventHistory(session.GetClient().Events(since, types), limit), nil
+		},
+	})
+}
+
+// formatEventHistory converts a poll of the event history to the tool
+// response, returning at most limit events. When events are left over,
+// next_since points after the last one returned so the next call picks up
+// the rest.
+func formatEventHistory(history dap.EventHistory, limit int) map[string]interface{} {
+	records := history.Events
+	more := len(records) > limit
+	if more {
+		records = records[:limit]
+	}
+	nextSince := history.LastSeq
+	if more {
+		nextSince = records[len(records)-1].Seq
+	}
+
+	events := make([]map[string]interface{}, len(records))
+	for i, r := range records {
+		event := formatEvent(r.Message)
+		event["event"] = r.Event
+		event["seq"] = r.Seq
+		event["time"] = r.Time.Format(time.RFC3339)
+		events[i] = event
+	}
+
+	result := map[string]interface{}{
+		"status":     "success",
+		"events":     events,
+		"count":      len(events),
+		"more":       more,
+		"next_since": nextSince,
+	}
+	if history.Missed > 0 {
+		result["missed"] = history.Missed
+		result["warning"] = fmt.Sprintf("%d event(s) after the requested sequence number were dropped from the history before this call; poll more often", history.Missed)
+	}
+	return result
+}
+
+// currentStop returns the lowest-numbered paused thread and its stop reason,
+// if any thread is paused
+func currentStop(states map[int]dap.ThreadState) (threadId int, reason string, ok bool) {
+	for id, st := range states {
+		if st.Stopped && (!ok || id < threadId) {
+			threadId, reason, ok = id, st.Reason, true
+		}
+	}
+	return threadId, reason, ok
+}
+
+// waitEndedResult reports that the game ended instead of stopping
+func waitEndedResult(session *dap.Session, waitedMs int64) map[string]interface{} {
+	result := map[string]interface{}{
+		"status":    "ended",
+		"message":   "The game ended without stopping",
+		"waited_ms": waitedMs,
+	}
+	if exit := formatExitStatus(session.GetExitStatus()); exit != nil {
+		result["exit"] = exit
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
+)
+
+func TestGetStringListParam(t *testing.T) {
+	list, err := getStringListParam(map[string]interface{}{"types": []interface{}{"stopped", "exited"}}, "types")
+	if err != nil || len(list) != 2 || list[1] != "exited" {
+		t.Errorf("unexpected list %v (err=%v)", list, err)
+	}
+
+	list, err = getStringListParam(map[string]interface{}{"types": "terminated"}, "types")
+	if err != nil || len(list) != 1 || list[0] != "terminated" {
+		t.Errorf("single string should become a one-element list, got %v (err=%v)", list, err)
+	}
+
+	list, err = getStringListParam(map[string]interface{}{"types": defaultWaitEventTypes}, "types")
+	if err != nil || len(list) != len(defaultWaitEventTypes) {
+		t.Errorf("a []string default should be accepted, got %v (err=%v)", list, err)
+	}
+
+	list, err = getStringListParam(map[string]interface{}{}, "types")
+	if err != nil || list != nil {
+		t.Errorf("missing parameter should return nil, got %v (err=%v)", list, err)
+	}
+
+	if _, err := getStringListParam(map[string]interface{}{"types": []interface{}{"stopped", 3.0}}, "types"); err == nil {
+		t.Error("non-string elements should be rejected")
+	}
+}
+
+func TestWaitTimeoutParam(t *testing.T) {
+	tests := []struct {
+		params map[string]interface{}
+		want   int
+	}{
+		{map[string]interface{}{}, defaultEventWaitSeconds},
+		{map[string]interface{}{"timeout": float64(90)}, 90},
+		{map[string]interface{}{"timeout": float64(0)}, 1},
+		{map[string]interface{}{"timeout": float64(5000)}, maxEventWaitSeconds},
+	}
+	for _, tt := range tests {
+		if got := waitTimeoutParam(tt.params); got != tt.want {
+			t.Errorf("waitTimeoutParam(%v) = %d, want %d", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	event := &godap.ExitedEvent{
+		Event: godap.Event{ProtocolMessage: godap.ProtocolMessage{Seq: 42, Type: "event"}, Event: "exited"},
+		Body:  godap.ExitedEventBody{ExitCode: 3},
+	}
+
+	result := formatEvent(event)
+	if result["event"] != "exited" || result["seq"] != 42 {
+		t.Errorf("unexpected event fields: %v", result)
+	}
+	body, ok := result["body"].(map[string]interface{})
+	if !ok || body["exitCode"] != 3.0 {
+		t.Errorf("unexpected body: %v", result["body"])
+	}
+}
+
+func TestCurrentStop(t *testing.T) {
+	if _, _, ok := currentStop(map[int]dap.ThreadState{1: {Stopped: false}}); ok {
+		t.Error("no paused thread should report no stop")
+	}
+
+	states := map[int]dap.ThreadState{
+		1: {Stopped: false},
+		3: {Stopped: true, Reason: "step", Time: time.Now()},
+		2: {Stopped: true, Reason: "breakpoint", Time: time.Now()},
+	}
+	threadId, reason, ok := currentStop(states)
+	if !ok || threadId != 2 || reason != "breakpoint" {
+		t.Errorf("expected the lowest paused thread 2 (breakpoint), got %d (%q, ok=%v)", threadId, reason, ok)
+	}
+}
+
+func TestFormatEventHistory(t *testing.T) {
+	history := dap.EventHistory{LastSeq: 9, Missed: 2}
+	for seq := 7; seq <= 9; seq++ {
+		history.Events = append(history.Events, dap.EventRecord{
+			Seq:     seq,
+			Event:   "output",
+			Time:    time.Now(),
+			Message: &godap.OutputEvent{Event: godap.Event{ProtocolMessage: godap.ProtocolMessage{Seq: 100 + seq}, Event: "output"}, Body: godap.OutputEventBody{Output: "hi\n"}},
+		})
+	}
+
+	result := formatEventHistory(history, 2)
+	events := result["events"].([]map[string]interface{})
+	if len(events) != 2 || result["more"] != true || result["next_since"] != 8 {
+		t.Fatalf("expected 2 events with more after seq 8, got %v", result)
+	}
+	if events[0]["seq"] != 7 || events[0]["event"] != "output" {
+		t.Errorf("events should carry the history's sequence numbers, got %v", events[0])
+	}
+	if result["missed"] != 2 || result["warning"] == nil {
+		t.Errorf("missed events should be reported, got %v", result)
+	}
+
+	result = formatEventHistory(history, 10)
+	if result["count"] != 3 || result["more"] != false || result["next_since"] != 9 {
+		t.Errorf("expected all events with next_since 9, got %v", result)
+	}
+}
+
+func TestWaitTools_SkipStopsResumedByConditions(t *testing.T) {
+	var condition atomic.Value
+	condition.Store("false")
+	continued := make(chan struct{}, 1)
+	mock := daptest.NewServer(t)
+	defer mock.Close()
+	go mock.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{mock.Success(req, nil), mock.NewEvent("initialized", nil)}
+		case "stackTrace":
+			return []godap.Message{mock.Success(req, map[string]interface{}{
+				"stackFrames": []map[string]interface{}{
+					{"id": 0, "name": "spawn", "line": 30, "column": 1, "source": map[string]interface{}{"path": "/game/spawner.gd"}},
+				},
+			})}
+		case "evaluate":
+			return []godap.Message{mock.Success(req, map[string]interface{}{"result": condition.Load(), "variablesReference": 0})}
+		case "continue":
+			continued <- struct{}{}
+			return []godap.Message{mock.Success(req, map[string]interface{}{"allThreadsContinued": true})}
+		}
+		return nil
+	})
+
+	session := connectMockSession(t, mock)
+	session.Breakpoints().Set("/game/spawner.gd", []int{30})
+	session.Breakpoints().SetConditions("/game/spawner.gd", map[int]string{30: "hp < 10"})
+	breakpointConditions.attach(session)
+	defer breakpointConditions.detach()
+
+	server := mcp.NewServer()
+	RegisterEventTools(server)
+	wait := func(tool string, args map[string]interface{}) <-chan interface{} {
+		result := make(chan interface{}, 1)
+		go func() {
+			r, err := server.CallTool(tool, args)
+			if err != nil {
+				t.Errorf("%s failed: %v", tool, err)
+			}
+			result <- r
+		}()
+		return result
+	}
+	status := func(result <-chan interface{}) interface{} {
+		r, _ := (<-result).(map[string]interface{})
+		return r["status"]
+	}
+
+	// A stop the false condition resumes is reported by neither tool
+	stop := wait("godot_wait_for_stop", map[string]interface{}{"timeout": float64(1)})
+	event := wait("godot_wait_for_event", map[string]interface{}{"types": []interface{}{"stopped"}, "timeout": float64(1)})
+	time.Sleep(100 * time.Millisecond)
+	mock.Send(mock.NewEvent("stopped", map[string]interface{}{"reason": "breakpoint", "threadId": 1}))
+	select {
+	case <-continued:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the gate should continue past the false condition")
+	}
+	if got := status(stop); got != "timeout" {
+		t.Errorf("godot_wait_for_stop should skip the resumed stop, got status %v", got)
+	}
+	if got := status(event); got != "timeout" {
+		t.Errorf("godot_wait_for_event should skip the resumed stop, got status %v", got)
+	}
+
+	// A stop the condition keeps is reported
+	condition.Store("true")
+	stop = wait("godot_wait_for_stop", map[string]interface{}{"timeout": float64(5)})
+	time.Sleep(100 * time.Millisecond)
+	mock.Send(mock.NewEvent("stopped", map[string]interface{}{"reason": "breakpoint", "threadId": 1}))
+	if got := status(stop); got != "paused" {
+		t.Errorf("godot_wait_for_stop should report the kept stop, got status %v", got)
+	}
+}
+
+func TestWaitTools_ReturnWhenSessionCloses(t *testing.T) {
+	for _, tool := range []string{"godot_wait_for_event", "godot_wait_for_stop"} {
+		t.Run(tool, func(t *testing.T) {
+			mock := daptest.NewServer(t)
+			defer mock.Close()
+			go mock.Serve(func(req godap.RequestMessage) []godap.Message {
+				if req.GetRequest().Command == "initialize" {
+					return []godap.Message{mock.Success(req, nil), mock.NewEvent("initialized", nil)}
+				}
+				return nil
+			})
+			session := connectMockSession(t, mock)
+			server := mcp.NewServer()
+			RegisterEventTools(server)
+
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				clearSession(session)
+			}()
+			start := time.Now()
+			result, err := server.CallTool(tool, map[string]interface{}{"timeout": float64(10)})
+			if err != nil {
+				t.Fatalf("wait failed: %v", err)
+			}
+			if status := result.(map[string]interface{})["status"]; status != "disconnected" {
+				t.Errorf("expected status disconnected, got %v", status)
+			}
+			if waited := time.Since(start); waited > 3*time.Second {
+				t.Errorf("the wait should end with the session, took %v", waited)
+			}
+		})
+	}
+}
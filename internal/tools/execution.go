@@ -3,11 +3,61 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
 )
 
+// singleThreadRequested reports whether an execution request should be limited
+// to the requested thread. Godot runs GDScript on a single thread and doesn't
+// advertise supportsSingleThreadExecutionRequests, so this is only true once a
+// multithreaded adapter does and the caller didn't pass all_threads=true.
+func singleThreadRequested(params map[string]interface{}, client *dap.Client) bool {
+	return !getBoolParam(params, "all_threads") && client.SupportsSingleThreadExecution()
+}
+
+// resumedThreadIds lists the threads an execution request resumed: just
+// threadId for a single-thread request, otherwise every known thread.
+// Call it after the request, once the client has marked threads as running.
+func resumedThreadIds(client *dap.Client, threadId int, allThreads bool) []int {
+	if !allThreads {
+		return []int{threadId}
+	}
+
+	seen := map[int]bool{threadId: true}
+	ids := []int{threadId}
+	for id := range client.ThreadStates() {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// maxContinueForMs caps how long godot_continue_for lets the game run
+const maxContinueForMs = 60000
+
+// awaitStopOrEnd waits for a stopped, terminated, or exited event until
+// deadline fires. Returns nil if the deadline came first.
+func awaitStopOrEnd(events <-chan godap.Message, deadline <-chan time.Time) godap.Message {
+	for {
+		select {
+		case <-deadline:
+			return nil
+		case msg := <-events:
+			switch msg.(type) {
+			case *godap.StoppedEvent, *godap.TerminatedEvent, *godap.ExitedEvent:
+				return msg
+			}
+		}
+	}
+}
+
 // RegisterExecutionTools registers execution control tools (continue, step-over, step-into)
 func RegisterExecutionTools(server *mcp.Server) {
 	// godot_continue - Resume execution
@@ -30,11 +80,17 @@ Use this tool:
 The tool will wait for the continue operation to complete. You'll receive a
 "stopped" event when the game hits the next breakpoint.
 
+The result reports all_threads_continued and resumed_thread_ids, the threads
+that are now running.
+
 Example: Continue execution
 godot_continue()
 
 Example: Continue specific thread (Godot uses thread ID 1)
-godot_continue(thread_id=1)`,
+godot_continue(thread_id=1)
+
+Example: Resume every thread (multithreaded GDScript)
+godot_continue(all_threads=true)`,
 
 		Parameters: []mcp.Parameter{
 			{
@@ -44,6 +100,13 @@ godot_continue(thread_id=1)`,
 				Default:     1,
 				Description: "Thread ID to continue (default: 1, Godot typically uses single thread)",
 			},
+			{
+				Name:        "all_threads",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, resume every thread; otherwise only thread_id is resumed when the adapter supports single-thread execution",
+			},
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -52,6 +115,9 @@ godot_continue(thread_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requireGameActive(session, "continue execution"); err != nil {
+				return nil, err
+			}
 
 			// Get thread ID parameter
 			threadId := 1 // default
@@ -64,7 +130,8 @@ godot_continue(thread_id=1)`,
 			defer cancel()
 
 			client := session.GetClient()
-			resp, err := client.Continue(ctx, threadId)
+			singleThread := singleThreadRequested(params, client)
+			resp, err := client.ContinueThread(ctx, threadId, singleThread)
 			if err != nil {
 				return nil, FormatError(
 					"Failed to continue execution",
@@ -77,10 +144,14 @@ godot_continue(thread_id=1)`,
 				)
 			}
 
+			allContinued := resp.Body.AllThreadsContinued || !singleThread
 			return map[string]interface{}{
 				"status":                "continued",
 				"message":               "Execution resumed",
-				"all_threads_continued": resp.Body.AllThreadsContinued,
+				"thread_id":             threadId,
+				"single_thread":         singleThread,
+				"all_threads_continued": allContinued,
+				"resumed_thread_ids":    resumedThreadIds(client, threadId, allContinued),
 			}, nil
 		},
 	})
@@ -109,7 +180,10 @@ Example: Step over current line
 godot_step_over()
 
 Example: Step over with specific thread ID
-godot_step_over(thread_id=1)`,
+godot_step_over(thread_id=1)
+
+Example: Step thread 2 while letting other threads run
+godot_step_over(thread_id=2, all_threads=true)`,
 
 		Parameters: []mcp.Parameter{
 			{
@@ -119,6 +193,13 @@ godot_step_over(thread_id=1)`,
 				Default:     1,
 				Description: "Thread ID to step (default: 1, Godot typically uses single thread)",
 			},
+			{
+				Name:        "all_threads",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, let other threads run while stepping; otherwise they stay paused when the adapter supports single-thread execution",
+			},
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -127,6 +208,9 @@ godot_step_over(thread_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "step over"); err != nil {
+				return nil, err
+			}
 
 			// Get thread ID parameter
 			threadId := 1 // default
@@ -139,7 +223,8 @@ godot_step_over(thread_id=1)`,
 			defer cancel()
 
 			client := session.GetClient()
-			_, err = client.Next(ctx, threadId)
+			singleThread := singleThreadRequested(params, client)
+			_, err = client.NextThread(ctx, threadId, singleThread)
 			if err != nil {
 				return nil, FormatError(
 					"Failed to step over",
@@ -153,8 +238,11 @@ godot_step_over(thread_id=1)`,
 			}
 
 			return map[string]interface{}{
-				"status":  "stepped_over",
-				"message": "Stepped over current line",
+				"status":             "stepped_over",
+				"message":            "Stepped over current line",
+				"thread_id":          threadId,
+				"single_thread":      singleThread,
+				"resumed_thread_ids": resumedThreadIds(client, threadId, !singleThread),
 			}, nil
 		},
 	})
@@ -185,7 +273,10 @@ Example: Step into function
 godot_step_into()
 
 Example: Step into with specific thread ID
-godot_step_into(thread_id=1)`,
+godot_step_into(thread_id=1)
+
+Example: Step into on thread 2 while letting other threads run
+godot_step_into(thread_id=2, all_threads=true)`,
 
 		Parameters: []mcp.Parameter{
 			{
@@ -195,6 +286,13 @@ godot_step_into(thread_id=1)`,
 				Default:     1,
 				Description: "Thread ID to step (default: 1, Godot typically uses single thread)",
 			},
+			{
+				Name:        "all_threads",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, let other threads run while stepping; otherwise they stay paused when the adapter supports single-thread execution",
+			},
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -203,6 +301,9 @@ godot_step_into(thread_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "step into"); err != nil {
+				return nil, err
+			}
 
 			// Get thread ID parameter
 			threadId := 1 // default
@@ -215,7 +316,8 @@ godot_step_into(thread_id=1)`,
 			defer cancel()
 
 			client := session.GetClient()
-			_, err = client.StepIn(ctx, threadId)
+			singleThread := singleThreadRequested(params, client)
+			_, err = client.StepInThread(ctx, threadId, singleThread)
 			if err != nil {
 				return nil, FormatError(
 					"Failed to step into",
@@ -230,9 +332,322 @@ godot_step_into(thread_id=1)`,
 			}
 
 			return map[string]interface{}{
-				"status":  "stepped_in",
-				"message": "Stepped into function",
+				"status":             "stepped_in",
+				"message":            "Stepped into function",
+				"thread_id":          threadId,
+				"single_thread":      singleThread,
+				"resumed_thread_ids": resumedThreadIds(client, threadId, !singleThread),
+			}, nil
+		},
+	})
+
+	// godot_continue_for - Run for a while, then pause
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_continue_for",
+		Description: `Let the game run for a fixed time, then pause it and report where it is.
+
+This tool resumes execution (if paused), waits duration_ms, sends a pause, and
+returns the new location like godot_where. It answers "let the game run for a
+second and show me where it is" in one call.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be launched (paused or running)
+
+If the game stops on its own during the wait (breakpoint, error), that stop is
+reported instead with stopped_early=true. If the game ends, its exit status is
+returned.
+
+Note: Godot spends most of its time in engine code between frames, so the pause
+may land in a native frame; use godot_get_stack_trace to find the nearest
+GDScript frame.
+
+Example: Run for one second
+godot_continue_for(duration_ms=1000)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "duration_ms",
+				Type:        "number",
+				Required:    true,
+				Description: fmt.Sprintf("How long to let the game run before pausing, in milliseconds (max: %d)", maxContinueForMs),
+				Minimum:     mcp.Float64(1),
+				Maximum:     mcp.Float64(maxContinueForMs),
+			},
+			{
+				Name:        "thread_id",
+				Type:        "number",
+				Required:    false,
+				Default:     1,
+				Description: "Thread ID to resume and pause (default: 1, Godot typically uses single thread)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requireGameActive(session, "continue"); err != nil {
+				return nil, err
+			}
+
+			durationMs, ok := params["duration_ms"].(float64)
+			if !ok || durationMs < 1 {
+				return nil, fmt.Errorf("duration_ms is required and must be a positive number")
+			}
+			if durationMs > maxContinueForMs {
+				durationMs = maxContinueForMs
+			}
+			duration := time.Duration(durationMs * float64(time.Millisecond))
+
+			threadId := 1 // default
+			if tid, ok := params["thread_id"].(float64); ok {
+				threadId = int(tid)
+			}
+
+			client := session.GetClient()
+
+			// Subscribe first so a stop during the wait isn't missed
+			events, cleanup := client.SubscribeToEvents(dap.WithListenerName("continue_for"))
+			defer cleanup()
+
+			start := time.Now()
+			if client.RunState() == dap.RunStatePaused {
+				ctx, cancel := dap.WithCommandTimeout(context.Background())
+				_, err := client.Continue(ctx, threadId)
+				cancel()
+				if err != nil {
+					return nil, FormatError(
+						"Failed to continue execution",
+						"",
+						[]string{
+							"Thread ID might be invalid",
+							"Connection might be lost",
+						},
+						err,
+					)
+				}
+			}
+
+			timer := time.NewTimer(duration)
+			defer timer.Stop()
+			event := awaitStopOrEnd(events, timer.C)
+
+			stoppedEarly := event != nil
+			if event == nil {
+				ctx, cancel := dap.WithCommandTimeout(context.Background())
+				_, err := client.Pause(ctx, threadId)
+				cancel()
+				if err != nil {
+					return nil, FormatError(
+						"Failed to pause after running",
+						fmt.Sprintf("ran %dms", time.Since(start).Milliseconds()),
+						[]string{
+							"The game might have exited (check godot_get_session_state)",
+							"Call godot_pause to try again",
+						},
+						err,
+					)
+				}
+				pauseTimer := time.NewTimer(dap.DefaultCommandTimeout)
+				defer pauseTimer.Stop()
+				if event = awaitStopOrEnd(events, pauseTimer.C); event == nil {
+					return nil, FormatError(
+						"Game did not report a stop after pause",
+						"",
+						[]string{
+							"Call godot_get_session_state to check the run state",
+							"Call godot_pause again",
+						},
+						nil,
+					)
+				}
+			}
+			ranMs := time.Since(start).Milliseconds()
+
+			stopped, ok := event.(*godap.StoppedEvent)
+			if !ok {
+				result := map[string]interface{}{
+					"status":  "ended",
+					"message": "The game ended while running",
+					"ran_ms":  ranMs,
+				}
+				if exit := formatExitStatus(session.GetExitStatus()); exit != nil {
+					result["exit"] = exit
+				}
+				return result, nil
+			}
+
+			stoppedThread := stopped.Body.ThreadId
+			if stoppedThread == 0 {
+				stoppedThread = threadId
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+			location, err := currentLocation(ctx, session, stoppedThread)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{
+				"status":        "paused",
+				"stop_reason":   stopped.Body.Reason,
+				"stopped_early": stoppedEarly,
+				"ran_ms":        ranMs,
+				"requested_ms":  int64(durationMs),
+				"thread_id":     stoppedThread,
+				"location":      location,
+			}, nil
+		},
+	})
+
+	// godot_jump_to_line - Set the next statement (goto)
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_jump_to_line",
+		Description: `Move execution of the paused game to another line without running the code in between.
+
+This tool sets the next statement: the thread stays paused, but resumes from the
+given line. Use it to skip over problematic code or re-run a block after
+inspecting it. Skipped code is not executed, so variables it would have set keep
+their current values.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- The debug adapter must support goto (supportsGotoTargetsRequest); the tool
+  fails with an explanation if it doesn't. Current Godot versions don't.
+
+The target line should usually be in the current function; adapters may refuse
+jumps into other functions.
+
+Example: Skip the rest of a loop body
+godot_jump_to_line(file="res://scripts/player.gd", line=52)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "file",
+				Type:        "string",
+				Required:    true,
+				Description: "Path to the GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
+			},
+			{
+				Name:        "line",
+				Type:        "number",
+				Required:    true,
+				Description: "Line to continue from (1-indexed)",
+				Minimum:     mcp.Float64(1),
+			},
+			{
+				Name:        "thread_id",
+				Type:        "number",
+				Required:    false,
+				Default:     1,
+				Description: "Thread ID to move (default: 1, Godot typically uses single thread)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "jump to line"); err != nil {
+				return nil, err
+			}
+
+			client := session.GetClient()
+			if !client.SupportsGotoTargets() {
+				return nil, ErrNotSupportedByAdapter("jump to line", "supportsGotoTargetsRequest")
+			}
+
+			file, ok := params["file"].(string)
+			if !ok || file == "" {
+				return nil, fmt.Errorf("file parameter is required and must be a non-empty string")
+			}
+			lineFloat, ok := params["line"].(float64)
+			if !ok || lineFloat < 1 {
+				return nil, fmt.Errorf("line parameter is required and must be a positive integer")
+			}
+			line := int(lineFloat)
+
+			threadId := 1 // default
+			if tid, ok := params["thread_id"].(float64); ok {
+				threadId = int(tid)
+			}
+
+			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
+			if err != nil {
+				return nil, err
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			targetsResp, err := client.GotoTargets(ctx, normalizedFile, line)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to find jump targets",
+					fmt.Sprintf("%s:%d", file, line),
+					[]string{
+						"Check that the file is loaded by the running game",
+						"Pick an executable line (not blank or a comment)",
+					},
+					err,
+				)
+			}
+
+			target, ok := pickGotoTarget(targetsResp.Body.Targets, line)
+			if !ok {
+				return nil, FormatError(
+					"Cannot jump to this line",
+					fmt.Sprintf("%s:%d", file, line),
+					[]string{
+						"Pick an executable line in the current function",
+						"Use godot_get_stack_trace to see the current location",
+					},
+					nil,
+				)
+			}
+
+			if _, err := client.Goto(ctx, threadId, target.Id); err != nil {
+				return nil, FormatError(
+					"Failed to jump to line",
+					fmt.Sprintf("%s:%d", file, target.Line),
+					[]string{
+						"The adapter may refuse jumps out of the current function",
+						"Thread ID might be invalid",
+					},
+					err,
+				)
+			}
+
+			return map[string]interface{}{
+				"status":         "jumped",
+				"message":        fmt.Sprintf("Execution will continue from line %d. The game is still paused; use godot_step_over or godot_continue to resume.", target.Line),
+				"file":           file,
+				"requested_line": line,
+				"actual_line":    target.Line,
+				"thread_id":      threadId,
 			}, nil
 		},
 	})
 }
+
+// pickGotoTarget chooses the goto target for a requested line: an exact line
+// match if the adapter offers one, otherwise its first suggestion
+func pickGotoTarget(targets []godap.GotoTarget, line int) (godap.GotoTarget, bool) {
+	if len(targets) == 0 {
+		return godap.GotoTarget{}, false
+	}
+	for _, target := range targets {
+		if target.Line == line {
+			return target, true
+		}
+	}
+	return targets[0], true
+}
@@ -2,8 +2,11 @@ package tools
 
 import (
 	"testing"
+	"time"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
 )
 
 func TestExecutionTools_Registration(t *testing.T) {
@@ -35,3 +38,64 @@ func TestExecutionTools_DefaultThreadID(t *testing.T) {
 	server := mcp.NewServer()
 	RegisterExecutionTools(server)
 }
+
+func TestSingleThreadRequested(t *testing.T) {
+	// Godot doesn't advertise single-thread execution, so requests are never
+	// limited to one thread regardless of all_threads
+	client := dap.NewClient("localhost", 6006)
+	if singleThreadRequested(map[string]interface{}{}, client) {
+		t.Error("single-thread execution should not be requested without adapter support")
+	}
+	if singleThreadRequested(map[string]interface{}{"all_threads": true}, client) {
+		t.Error("all_threads=true should never request single-thread execution")
+	}
+}
+
+func TestResumedThreadIds(t *testing.T) {
+	client := dap.NewClient("localhost", 6006)
+
+	if got := resumedThreadIds(client, 3, false); len(got) != 1 || got[0] != 3 {
+		t.Errorf("single-thread request should resume only thread 3, got %v", got)
+	}
+	if got := resumedThreadIds(client, 1, true); len(got) != 1 || got[0] != 1 {
+		t.Errorf("with no known threads, only the requested thread is listed, got %v", got)
+	}
+}
+
+func TestPickGotoTarget(t *testing.T) {
+	if _, ok := pickGotoTarget(nil, 10); ok {
+		t.Error("no targets should mean the line can't be jumped to")
+	}
+
+	targets := []godap.GotoTarget{
+		{Id: 1, Label: "line 11", Line: 11},
+		{Id: 2, Label: "line 10", Line: 10},
+	}
+	if target, _ := pickGotoTarget(targets, 10); target.Id != 2 {
+		t.Errorf("expected exact line match (id 2), got id %d", target.Id)
+	}
+	if target, _ := pickGotoTarget(targets, 9); target.Id != 1 {
+		t.Errorf("expected first target when no line matches, got id %d", target.Id)
+	}
+}
+
+func TestAwaitStopOrEnd(t *testing.T) {
+	events := make(chan godap.Message, 3)
+	events <- &godap.OutputEvent{}
+	events <- &godap.StoppedEvent{Body: godap.StoppedEventBody{Reason: "breakpoint"}}
+
+	msg := awaitStopOrEnd(events, time.After(time.Second))
+	if stopped, ok := msg.(*godap.StoppedEvent); !ok || stopped.Body.Reason != "breakpoint" {
+		t.Errorf("expected the stopped event, got %T", msg)
+	}
+
+	events <- &godap.OutputEvent{}
+	if msg := awaitStopOrEnd(events, time.After(10*time.Millisecond)); msg != nil {
+		t.Errorf("expected nil at the deadline, got %T", msg)
+	}
+
+	events <- &godap.ExitedEvent{}
+	if _, ok := awaitStopOrEnd(events, time.After(time.Second)).(*godap.ExitedEvent); !ok {
+		t.Error("an exited event should end the wait")
+	}
+}
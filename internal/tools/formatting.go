@@ -11,6 +11,22 @@ import (
 // Godot type formatters for DAP variables
 // These functions detect and pretty-print common Godot types for better readability
 
+// Precompiled patterns for Godot's string representations.
+// Compiled once at package init since formatting runs for every variable.
+var (
+	// Godot format: "[P: (x, y), S: (w, h)]"
+	rect2Pattern = regexp.MustCompile(`\[P:\s*\(([^,]+),\s*([^)]+)\),\s*S:\s*\(([^,]+),\s*([^)]+)\)\]`)
+
+	// Godot format: "[P: (x, y, z), S: (w, h, d)]"
+	aabbPattern = regexp.MustCompile(`\[P:\s*\(([^,]+),\s*([^,]+),\s*([^)]+)\),\s*S:\s*\(([^,]+),\s*([^,]+),\s*([^)]+)\)\]`)
+
+	// Godot format: "[X: (xx, xy), Y: (yx, yy), O: (ox, oy)]"
+	transform2DPattern = regexp.MustCompile(`\[X:\s*\(([^)]+)\),\s*Y:\s*\(([^)]+)\),\s*O:\s*\(([^)]+)\)\]`)
+
+	// Object instance: "<ClassName#123>"
+	nodeInstancePattern = regexp.MustCompile(`<([^#]+)#(\d+)>`)
+)
+
 // formatVariable enhances a DAP variable with Godot-specific formatting
 func formatVariable(variable dap.Variable) map[string]interface{} {
 	result := map[string]interface{}{
@@ -116,9 +132,7 @@ func formatColor(value string) string {
 
 // formatRect2 formats Rect2 as "Rect2(pos=(x, y), size=(w, h))"
 func formatRect2(value string) string {
-	// Godot format: "[P: (x, y), S: (w, h)]"
-	re := regexp.MustCompile(`\[P:\s*\(([^,]+),\s*([^)]+)\),\s*S:\s*\(([^,]+),\s*([^)]+)\)\]`)
-	matches := re.FindStringSubmatch(value)
+	matches := rect2Pattern.FindStringSubmatch(value)
 	if len(matches) == 5 {
 		return fmt.Sprintf("Rect2(pos=(%s, %s), size=(%s, %s))",
 			strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2]),
@@ -130,8 +144,7 @@ func formatRect2(value string) string {
 // formatAABB formats AABB (3D bounding box) as "AABB(pos=(x, y, z), size=(w, h, d))"
 func formatAABB(value string) string {
 	// Similar to Rect2 but 3D
-	re := regexp.MustCompile(`\[P:\s*\(([^,]+),\s*([^,]+),\s*([^)]+)\),\s*S:\s*\(([^,]+),\s*([^,]+),\s*([^)]+)\)\]`)
-	matches := re.FindStringSubmatch(value)
+	matches := aabbPattern.FindStringSubmatch(value)
 	if len(matches) == 7 {
 		return fmt.Sprintf("AABB(pos=(%s, %s, %s), size=(%s, %s, %s))",
 			strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2]), strings.TrimSpace(matches[3]),
@@ -160,9 +173,7 @@ func formatQuaternion(value string) string {
 
 // formatTransform2D formats Transform2D with origin and rotation hint
 func formatTransform2D(value string) string {
-	// Godot format: "[X: (xx, xy), Y: (yx, yy), O: (ox, oy)]"
-	re := regexp.MustCompile(`\[X:\s*\(([^)]+)\),\s*Y:\s*\(([^)]+)\),\s*O:\s*\(([^)]+)\)\]`)
-	matches := re.FindStringSubmatch(value)
+	matches := transform2DPattern.FindStringSubmatch(value)
 	if len(matches) == 4 {
 		return fmt.Sprintf("Transform2D(x=%s, y=%s, origin=%s)",
 			strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2]), strings.TrimSpace(matches[3]))
@@ -237,8 +248,7 @@ func formatNode(typeName string, value string) string {
 	}
 
 	// Extract instance ID if present
-	re := regexp.MustCompile(`<([^#]+)#(\d+)>`)
-	matches := re.FindStringSubmatch(value)
+	matches := nodeInstancePattern.FindStringSubmatch(value)
 	if len(matches) == 3 {
 		className := matches[1]
 		instanceID := matches[2]
@@ -417,3 +417,45 @@ func TestFormatVariableList(t *testing.T) {
 		t.Error("Second variable should not have 'formatted' field for int")
 	}
 }
+
+// benchmarkVariables builds a large, mixed variable list resembling an expanded Node
+func benchmarkVariables(n int) []dap.Variable {
+	samples := []dap.Variable{
+		{Name: "position", Value: "(10, 20)", Type: "Vector2"},
+		{Name: "rect", Value: "[P: (0, 0), S: (100, 50)]", Type: "Rect2"},
+		{Name: "bounds", Value: "[P: (0, 0, 0), S: (1, 2, 3)]", Type: "AABB"},
+		{Name: "transform", Value: "[X: (1, 0), Y: (0, 1), O: (5, 5)]", Type: "Transform2D"},
+		{Name: "player", Value: "<CharacterBody2D#12345>", Type: "CharacterBody2D"},
+		{Name: "health", Value: "100", Type: "int"},
+	}
+
+	variables := make([]dap.Variable, n)
+	for i := range variables {
+		variables[i] = samples[i%len(samples)]
+	}
+	return variables
+}
+
+func BenchmarkFormatVariableList(b *testing.B) {
+	variables := benchmarkVariables(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		formatVariableList(variables)
+	}
+}
+
+func BenchmarkFormatRect2(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		formatRect2("[P: (0, 0), S: (100, 50)]")
+	}
+}
+
+func BenchmarkFormatNode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		formatNode("CharacterBody2D", "<CharacterBody2D#12345>")
+	}
+}
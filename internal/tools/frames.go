@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"strings"
+	"sync"
+
+	godap "github.com/google/go-dap"
+)
+
+// nativeFrameMessage explains why a frame has no source or variables
+const nativeFrameMessage = "Frame is in native code (engine or GDExtension) and has no GDScript source or variables. Select a GDScript frame from godot_get_stack_trace instead."
+
+// Frame IDs of native frames from the most recent stack trace.
+// Frame IDs are only valid while the game stays paused, so the set is
+// replaced on every stack trace rather than accumulated.
+var (
+	nativeFrameIDs   = map[int]bool{}
+	nativeFrameIDsMu sync.Mutex
+)
+
+// isNativeFrame reports whether a stack frame has no usable source.
+// Frames from C++ engine code or GDExtension libraries come back without a
+// Source, or with a Source that has neither a path nor a sourceReference.
+func isNativeFrame(frame godap.StackFrame) bool {
+	if frame.Source == nil {
+		return true
+	}
+	return frame.Source.Path == "" && frame.Source.SourceReference == 0
+}
+
+// addFrameSource adds the frame's source to frameData, or marks it native
+func addFrameSource(frameData map[string]interface{}, frame godap.StackFrame) {
+	if isNativeFrame(frame) {
+		frameData["native"] = true
+		if frame.Source != nil && frame.Source.Name != "" {
+			frameData["source"] = map[string]interface{}{
+				"name": frame.Source.Name,
+			}
+		}
+		return
+	}
+
+	frameData["source"] = map[string]interface{}{
+		"name": frame.Source.Name,
+		"path": frame.Source.Path,
+	}
+}
+
+// recordNativeFrames remembers which frames of a stack trace are native, so
+// godot_get_scopes can answer for them without a failing DAP request
+func recordNativeFrames(frames []godap.StackFrame) {
+	nativeFrameIDsMu.Lock()
+	defer nativeFrameIDsMu.Unlock()
+
+	nativeFrameIDs = map[int]bool{}
+	for _, frame := range frames {
+		if isNativeFrame(frame) {
+			nativeFrameIDs[frame.Id] = true
+		}
+	}
+}
+
+// isKnownNativeFrame reports whether frameId was native in the last stack trace
+func isKnownNativeFrame(frameId int) bool {
+	nativeFrameIDsMu.Lock()
+	defer nativeFrameIDsMu.Unlock()
+	return nativeFrameIDs[frameId]
+}
+
+// How a frame was entered when its caller isn't GDScript. Deferred calls,
+// signal emissions, and resumptions after await all start a fresh GDScript
+// stack, so the code that caused them isn't on the stack.
+const (
+	calledFromEngine      = "engine_callback" // _ready, _process, _input, ...
+	calledFromSignal      = "signal"
+	calledFromDeferred    = "deferred"
+	calledFromAwaitResume = "await_resume"
+	calledFromEngineOther = "deferred_or_signal" // Not an engine callback; the engine called it some other way
+)
+
+// calledFromNotes explains each called_from marker
+var calledFromNotes = map[string]string{
+	calledFromEngine:      "Engine callback; the engine called it as part of the frame loop or a notification",
+	calledFromSignal:      "Called from a signal emission; the code that emitted the signal is not on this stack",
+	calledFromDeferred:    "Called deferred (call_deferred or a deferred connection) at the end of the frame; the code that queued it is not on this stack",
+	calledFromAwaitResume: "Resumed after await; the code before the await ran on an earlier stack",
+	calledFromEngineOther: "Called by the engine outside the frame loop: a signal connection, call_deferred, a Timer or Tween callback, or a resumption after await",
+}
+
+// engineCallbacks are the virtual methods the engine calls directly
+var engineCallbacks = map[string]bool{
+	"_init": true, "_ready": true, "_enter_tree": true, "_exit_tree": true,
+	"_process": true, "_physics_process": true, "_notification": true,
+	"_input": true, "_unhandled_input": true, "_unhandled_key_input": true, "_shortcut_input": true, "_gui_input": true,
+	"_draw": true, "_integrate_forces": true, "_get": true, "_set": true, "_get_property_list": true, "_to_string": true,
+	"_run": true, "_initialize": true, "_finalize": true,
+}
+
+// nativeCallerKind classifies a native frame that called into GDScript by
+// its name, e.g. "emit_signal" or "_call_deferred"
+func nativeCallerKind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "emit"):
+		return calledFromSignal
+	case strings.Contains(lower, "deferred") || strings.Contains(lower, "messagequeue") || strings.Contains(lower, "flush"):
+		return calledFromDeferred
+	case strings.Contains(lower, "resume"):
+		return calledFromAwaitResume
+	}
+	return ""
+}
+
+// entryKind classifies the oldest frame of a complete stack by its name:
+// engine callbacks, signal handlers named by the editor's _on_<node>_<signal>
+// convention, and anything else the engine called
+func entryKind(name string) string {
+	switch {
+	case engineCallbacks[name]:
+		return calledFromEngine
+	case strings.HasPrefix(name, "_on_"):
+		return calledFromSignal
+	}
+	return calledFromEngineOther
+}
+
+// calledFrom returns how the GDScript frame at i was entered if its caller
+// isn't GDScript: from a native caller's name, or, for the oldest frame of a
+// complete stack, from its own name. Returns "" for frames called from
+// GDScript, and for native frames.
+func calledFrom(frames []godap.StackFrame, i int, complete bool) string {
+	if isNativeFrame(frames[i]) {
+		return ""
+	}
+	if i+1 < len(frames) {
+		caller := frames[i+1]
+		if !isNativeFrame(caller) {
+			return ""
+		}
+		if kind := nativeCallerKind(caller.Name); kind != "" {
+			return kind
+		}
+		return entryKind(frames[i].Name)
+	}
+	if !complete {
+		return ""
+	}
+	return entryKind(frames[i].Name)
+}
+
+// isCompleteStack reports whether a stack trace response holds every frame,
+// so its oldest frame is where the stack began
+func isCompleteStack(resp *godap.StackTraceResponse, maxFrames int) bool {
+	n := len(resp.Body.StackFrames)
+	if resp.Body.TotalFrames > 0 {
+		return resp.Body.TotalFrames <= n
+	}
+	return n < maxFrames
+}
+
+// addCalledFrom marks a frame entered from outside GDScript with called_from
+// and an explanatory note
+func addCalledFrom(frameData map[string]interface{}, frames []godap.StackFrame, i int, complete bool) {
+	if kind := calledFrom(frames, i, complete); kind != "" {
+		frameData["called_from"] = kind
+		frameData["called_from_note"] = calledFromNotes[kind]
+	}
+}
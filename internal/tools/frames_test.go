@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+func TestIsNativeFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame godap.StackFrame
+		want  bool
+	}{
+		{"no source", godap.StackFrame{Id: 1}, true},
+		{"empty source", godap.StackFrame{Id: 1, Source: &godap.Source{Name: "native"}}, true},
+		{"path", godap.StackFrame{Id: 1, Source: &godap.Source{Path: "/game/player.gd"}}, false},
+		{"source reference", godap.StackFrame{Id: 1, Source: &godap.Source{SourceReference: 7}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNativeFrame(tt.frame); got != tt.want {
+				t.Errorf("isNativeFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFrameSource(t *testing.T) {
+	native := map[string]interface{}{}
+	addFrameSource(native, godap.StackFrame{Id: 1})
+	if native["native"] != true {
+		t.Error("frame without source should be marked native")
+	}
+	if _, ok := native["source"]; ok {
+		t.Error("frame without source should not have a source entry")
+	}
+
+	script := map[string]interface{}{}
+	addFrameSource(script, godap.StackFrame{Id: 2, Source: &godap.Source{Name: "player.gd", Path: "/game/player.gd"}})
+	if _, ok := script["native"]; ok {
+		t.Error("GDScript frame should not be marked native")
+	}
+	if src, ok := script["source"].(map[string]interface{}); !ok || src["path"] != "/game/player.gd" {
+		t.Errorf("unexpected source: %v", script["source"])
+	}
+}
+
+func TestRecordNativeFrames(t *testing.T) {
+	recordNativeFrames([]godap.StackFrame{
+		{Id: 1, Source: &godap.Source{Path: "/game/player.gd"}},
+		{Id: 2},
+	})
+	if isKnownNativeFrame(1) || !isKnownNativeFrame(2) {
+		t.Error("only frame 2 should be recorded as native")
+	}
+
+	// A new stack trace replaces the previous set
+	recordNativeFrames([]godap.StackFrame{{Id: 3}})
+	if isKnownNativeFrame(2) || !isKnownNativeFrame(3) {
+		t.Error("native frames should be replaced on each stack trace")
+	}
+}
+
+func TestCalledFrom(t *testing.T) {
+	src := &godap.Source{Path: "/game/player.gd"}
+	frames := []godap.StackFrame{
+		{Id: 0, Name: "take_damage", Source: src},
+		{Id: 1, Name: "_on_hitbox_area_entered", Source: src},
+		{Id: 2, Name: "emit_signal"},
+		{Id: 3, Name: "spawn", Source: src},
+		{Id: 4, Name: "_call_deferred"},
+		{Id: 5, Name: "_physics_process", Source: src},
+	}
+	want := []string{"", calledFromSignal, "", calledFromDeferred, "", calledFromEngine}
+	for i := range frames {
+		if got := calledFrom(frames, i, true); got != want[i] {
+			t.Errorf("frame %d (%s): expected %q, got %q", i, frames[i].Name, want[i], got)
+		}
+	}
+
+	// The oldest frame of a truncated stack isn't where the stack began
+	if got := calledFrom(frames[:2], 1, false); got != "" {
+		t.Errorf("expected no marker for a truncated stack, got %q", got)
+	}
+	if got := calledFrom([]godap.StackFrame{{Name: "spawn_wave", Source: src}}, 0, true); got != calledFromEngineOther {
+		t.Errorf("expected %q for a plain method at the bottom, got %q", calledFromEngineOther, got)
+	}
+
+	frameData := map[string]interface{}{}
+	addCalledFrom(frameData, frames, 1, true)
+	if frameData["called_from"] != calledFromSignal || frameData["called_from_note"] == "" {
+		t.Errorf("unexpected frame data: %v", frameData)
+	}
+}
+
+func TestIsCompleteStack(t *testing.T) {
+	resp := &godap.StackTraceResponse{}
+	resp.Body.StackFrames = make([]godap.StackFrame, 3)
+	if !isCompleteStack(resp, 20) {
+		t.Error("fewer frames than requested should be complete")
+	}
+	resp.Body.TotalFrames = 5
+	if isCompleteStack(resp, 3) {
+		t.Error("a total above the frames returned should be incomplete")
+	}
+}
@@ -0,0 +1,326 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// maxFunctionDefinitions caps how many scripts one function breakpoint may
+// cover; a name defined in more scripts (e.g. _process) needs a file
+const maxFunctionDefinitions = 20
+
+// functionDefinition is where a GDScript function is defined
+type functionDefinition struct {
+	Path     string // Absolute path of the script
+	FuncLine int    // Line of the func declaration
+	BodyLine int    // First line of the body, where the breakpoint goes
+}
+
+// funcDeclarationPattern matches a func declaration and captures its
+// indentation, name, and whatever follows the parameter list's "("
+var funcDeclarationPattern = regexp.MustCompile(`^(\s*)(?:static\s+)?func\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*\((.*)$`)
+
+// indentWidth returns the width of a line's leading whitespace
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// stripComment returns a GDScript line without its comment and surrounding
+// whitespace. A # inside a string literal is mistaken for a comment, which
+// only matters for the rare declaration that has one.
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// headerColon returns the index of the colon that ends a one-line func
+// declaration, the first one outside the parameter list, or -1
+func headerColon(declaration string) int {
+	depth := 0
+	for i, r := range declaration {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ':':
+			if depth == 0 && strings.Contains(declaration[:i], "(") {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findFunctionInScript returns the definition of function name in a GDScript
+// source file, if it has one. Functions of inner classes are found too.
+func findFunctionInScript(path string, name string) (functionDefinition, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return functionDefinition{}, false
+	}
+	defer file.Close()
+
+	def := functionDefinition{Path: path}
+	indent := 0
+	inHeader := false // Inside a parameter list split over lines
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := stripComment(text)
+		if def.FuncLine == 0 {
+			m := funcDeclarationPattern.FindStringSubmatch(text)
+			if m == nil || m[2] != name {
+				continue
+			}
+			def.FuncLine = line
+			indent = indentWidth(text)
+			// A one-line function has a statement after the colon:
+			// func f() -> int: return 1
+			if colon := headerColon(trimmed); colon >= 0 && strings.TrimSpace(trimmed[colon+1:]) != "" {
+				def.BodyLine = line
+				return def, true
+			}
+			inHeader = !strings.HasSuffix(trimmed, ":")
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		if inHeader {
+			inHeader = !strings.HasSuffix(trimmed, ":")
+			continue
+		}
+		// The body is the first statement indented deeper than the func
+		if indentWidth(text) > indent {
+			def.BodyLine = line
+			return def, true
+		}
+		break
+	}
+	if def.FuncLine == 0 {
+		return functionDefinition{}, false
+	}
+	// No body found: break on the line after the func and let Godot move it
+	def.BodyLine = def.FuncLine + 1
+	return def, true
+}
+
+// findFunctionDefinitions returns the definitions of function name in the
+// project's GDScript files, or in file alone if it is given
+func findFunctionDefinitions(projectRoot string, file string, name string) []functionDefinition {
+	var paths []string
+	if file != "" {
+		paths = []string{file}
+	} else {
+		for _, resPath := range findScriptFiles(projectRoot).GDScript {
+			paths = append(paths, filepath.Join(projectRoot, filepath.FromSlash(strings.TrimPrefix(resPath, "res://"))))
+		}
+	}
+	var defs []functionDefinition
+	for _, path := range paths {
+		if def, ok := findFunctionInScript(path, name); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// Function names set through godot_set_function_breakpoint on adapters that
+// support function breakpoints; setFunctionBreakpoints replaces the list
+var (
+	requestedFunctions   []string
+	requestedFunctionsMu sync.Mutex
+)
+
+// RegisterFunctionBreakpointTools registers godot_set_function_breakpoint
+func RegisterFunctionBreakpointTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_set_function_breakpoint",
+		Description: `Set a breakpoint that pauses when a named GDScript function is entered.
+
+Use it to break in a function without looking up its file and line, e.g.
+_physics_process or take_damage. Without file, every script in the project
+that defines the function gets a breakpoint; pass file to pick one script.
+
+Godot's DAP server doesn't support function breakpoints, so the server finds
+the function's definition in the project's .gd files and sets a regular
+breakpoint on the first line of its body. Like godot_set_logpoint, it keeps
+the file's other breakpoints, and the breakpoints show up in
+godot_list_breakpoints and are cleared with godot_clear_breakpoint. A
+function added after the call isn't covered; call the tool again.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Without file, the project path must be known (godot_connect(project=...))
+
+Example: Pause whenever the player takes damage
+godot_set_function_breakpoint(name="take_damage", file="res://scripts/player.gd")
+
+Example: Pause in every script's _ready
+godot_set_function_breakpoint(name="_ready")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "name",
+				Type:        "string",
+				Required:    true,
+				Description: "Name of the GDScript function (e.g. \"_physics_process\")",
+			},
+			{
+				Name:        "file",
+				Type:        "string",
+				Required:    false,
+				Description: "Script defining the function (absolute or res:// path; default: search the project)",
+				Validate:    validateGodotPathParam,
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			name, _ := params["name"].(string)
+			name = strings.TrimSpace(name)
+			if !isValidVariableName(name) {
+				return nil, fmt.Errorf("name must be a GDScript function name (got %q)", name)
+			}
+			file, _ := params["file"].(string)
+			projectRoot := session.GetProjectRoot()
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+			client := session.GetClient()
+
+			if client.SupportsFunctionBreakpoints() && file == "" {
+				requestedFunctionsMu.Lock()
+				defer requestedFunctionsMu.Unlock()
+				names := requestedFunctions
+				if !slices.Contains(names, name) {
+					names = append(names[:len(names):len(names)], name)
+				}
+				resp, err := client.SetFunctionBreakpoints(ctx, names)
+				if err != nil {
+					return nil, fmt.Errorf("failed to set function breakpoint: %w", err)
+				}
+				requestedFunctions = names
+				verified := false
+				for i, bp := range resp.Body.Breakpoints {
+					if i < len(names) && names[i] == name {
+						verified = bp.Verified
+					}
+				}
+				return map[string]interface{}{
+					"status":   "set",
+					"message":  fmt.Sprintf("Function breakpoint set on %s", name),
+					"function": name,
+					"verified": verified,
+				}, nil
+			}
+
+			if file != "" {
+				if isCSharpScript(file) {
+					return nil, ErrCSharpBreakpoint(file, projectRoot)
+				}
+				if file, err = resolveGodotPath(file, projectRoot); err != nil {
+					return nil, err
+				}
+			} else if projectRoot == "" {
+				return nil, FormatError(
+					"Project path unknown",
+					name,
+					[]string{
+						"Pass file to name the script that defines the function",
+						"Or reconnect with godot_connect(project=...) to search the project",
+					},
+					nil,
+				)
+			}
+
+			defs := findFunctionDefinitions(projectRoot, file, name)
+			switch {
+			case len(defs) == 0:
+				where := "the project's .gd files"
+				if file != "" {
+					where = file
+				}
+				return nil, FormatError(
+					"Function not found",
+					fmt.Sprintf("func %s in %s", name, where),
+					[]string{
+						"Check the spelling; GDScript names are case-sensitive",
+						"Engine methods and functions of C# scripts can't be found; set a breakpoint in a GDScript caller instead",
+					},
+					nil,
+				)
+			case len(defs) > maxFunctionDefinitions:
+				return nil, FormatError(
+					"Function defined in too many scripts",
+					fmt.Sprintf("func %s is defined in %d scripts", name, len(defs)),
+					[]string{"Pass file to pick the script to break in"},
+					nil,
+				)
+			}
+
+			var breakpoints []map[string]interface{}
+			var warnings []string
+			for _, def := range defs {
+				lines := []int{def.BodyLine}
+				for _, l := range session.Breakpoints().Lines(def.Path) {
+					if l != def.BodyLine {
+						lines = append(lines, l)
+					}
+				}
+				resp, err := client.SetSourceBreakpoints(ctx, def.Path, session.Breakpoints().SourceBreakpoints(def.Path, lines))
+				if err != nil {
+					return nil, fmt.Errorf("failed to set breakpoint in %s: %w", def.Path, err)
+				}
+				session.Breakpoints().Set(def.Path, lines)
+				session.Breakpoints().SetFunction(def.Path, def.BodyLine, name)
+
+				d := diagnoseBreakpoints(def.Path, lines, resp.Body.Breakpoints)[0]
+				entry := map[string]interface{}{
+					"file":          def.Path,
+					"function_line": def.FuncLine,
+					"line":          def.BodyLine,
+					"status":        d.Status,
+					"diagnosis":     d.Diagnosis,
+				}
+				if d.ActualLine != 0 {
+					entry["actual_line"] = d.ActualLine
+				}
+				if d.Status != breakpointSet && d.Status != breakpointMoved {
+					warnings = append(warnings, fmt.Sprintf("%s: %s", def.Path, d.Diagnosis))
+				}
+				breakpoints = append(breakpoints, entry)
+			}
+			enforceConditions(session)
+			autosaveSession()
+
+			result := map[string]interface{}{
+				"status":      "set",
+				"message":     fmt.Sprintf("Breakpoint set on entry to %s in %d script(s)", name, len(defs)),
+				"function":    name,
+				"breakpoints": breakpoints,
+			}
+			if len(warnings) > 0 {
+				result["warnings"] = warnings
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindFunctionInScript(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "player.gd", `extends CharacterBody2D
+
+var health := 100
+
+func _ready():
+	# Comments and blank lines are skipped
+
+	health = 100
+
+func take_damage(amount: int,
+		source: Node = null) -> void:
+	health -= amount
+
+static func clamp_health(value): return clampi(value, 0, 100)
+
+class Inventory:
+	func add(item):
+		pass
+
+func empty():
+`)
+	path := filepath.Join(root, "player.gd")
+
+	tests := []struct {
+		name     string
+		funcLine int
+		bodyLine int
+	}{
+		{"_ready", 5, 8},
+		{"take_damage", 10, 12},
+		{"clamp_health", 14, 14},
+		{"add", 17, 18},
+		{"empty", 20, 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, ok := findFunctionInScript(path, tt.name)
+			if !ok {
+				t.Fatalf("%s not found", tt.name)
+			}
+			if def.FuncLine != tt.funcLine || def.BodyLine != tt.bodyLine {
+				t.Errorf("got func line %d, body line %d; want %d, %d", def.FuncLine, def.BodyLine, tt.funcLine, tt.bodyLine)
+			}
+		})
+	}
+
+	if _, ok := findFunctionInScript(path, "take"); ok {
+		t.Error("a name prefix should not match")
+	}
+	if _, ok := findFunctionInScript(filepath.Join(root, "missing.gd"), "_ready"); ok {
+		t.Error("a missing script should not match")
+	}
+}
+
+func TestFindFunctionDefinitions(t *testing.T) {
+	root := t.TempDir()
+	makeProject(t, root)
+	writeFile(t, root, "player.gd", "func _ready():\n\tpass\n")
+	writeFile(t, root, "enemies/enemy.gd", "func _ready():\n\tprint(\"enemy\")\n")
+	writeFile(t, root, "hud.gd", "func _process(delta):\n\tpass\n")
+
+	if defs := findFunctionDefinitions(root, "", "_ready"); len(defs) != 2 {
+		t.Errorf("expected _ready in 2 scripts, got %v", defs)
+	}
+	defs := findFunctionDefinitions(root, filepath.Join(root, "hud.gd"), "_process")
+	if len(defs) != 1 || defs[0].BodyLine != 2 {
+		t.Errorf("expected _process at line 2 of hud.gd, got %v", defs)
+	}
+	if defs := findFunctionDefinitions(root, "", "jump"); len(defs) != 0 {
+		t.Errorf("expected no definitions of jump, got %v", defs)
+	}
+}
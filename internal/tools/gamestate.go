@@ -0,0 +1,287 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// defaultGameStateSlot is the slot passed to the project's save and load
+// functions when none is given and nothing was saved yet
+const defaultGameStateSlot = "mcp_repro"
+
+// gameStateHelpers names the project's own save and load functions: methods
+// on a node (usually an autoload) that take a slot name
+type gameStateHelpers struct {
+	NodePath   string `json:"node_path"`
+	SaveMethod string `json:"save_method"`
+	LoadMethod string `json:"load_method"`
+	LastSlot   string `json:"last_slot,omitempty"` // Slot of the last successful save
+}
+
+// defaultGameStateHelpers are used until a project's own names are given
+var defaultGameStateHelpers = gameStateHelpers{
+	NodePath:   "/root/SaveManager",
+	SaveMethod: "save_state",
+	LoadMethod: "load_state",
+}
+
+// Helpers used by godot_save_game_state and godot_load_game_state. Names
+// passed to either tool are remembered and saved in the session snapshot.
+var (
+	gameState   = defaultGameStateHelpers
+	gameStateMu sync.Mutex
+)
+
+// getGameStateHelpers returns the configured helpers
+func getGameStateHelpers() gameStateHelpers {
+	gameStateMu.Lock()
+	defer gameStateMu.Unlock()
+	return gameState
+}
+
+// setGameStateHelpers replaces the configured helpers; empty names keep
+// the current ones
+func setGameStateHelpers(h gameStateHelpers) {
+	gameStateMu.Lock()
+	if h.NodePath != "" {
+		gameState.NodePath = h.NodePath
+	}
+	if h.SaveMethod != "" {
+		gameState.SaveMethod = h.SaveMethod
+	}
+	if h.LoadMethod != "" {
+		gameState.LoadMethod = h.LoadMethod
+	}
+	if h.LastSlot != "" {
+		gameState.LastSlot = h.LastSlot
+	}
+	gameStateMu.Unlock()
+	autosaveSession()
+}
+
+// callGameStateHelper calls node_path.method(slot) in the paused game and
+// returns the method's result. The node and method are checked first, so a
+// misconfigured helper gets a clear error instead of a failed evaluate.
+func callGameStateHelper(ctx context.Context, client expressionEvaluator, frameId int, nodePath, method, slot string) (string, error) {
+	node := nodePathExpression(nodePath)
+	r := memberReader{ctx: ctx, client: client, frameId: frameId, node: node}
+
+	resp, err := client.Evaluate(ctx, fmt.Sprintf("str(%s)", node), frameId, "watch")
+	if err != nil {
+		return "", err
+	}
+	if resp.Body.Result == "<null>" {
+		return "", fmt.Errorf("no node at %s", nodePath)
+	}
+	has, err := r.eval(fmt.Sprintf("has_method(%s)", strconv.Quote(method)))
+	if err != nil {
+		return "", err
+	}
+	if has != "true" {
+		return "", fmt.Errorf("%s has no method %s", nodePath, method)
+	}
+	return r.eval(fmt.Sprintf("%s(%s)", method, strconv.Quote(slot)))
+}
+
+// gameStateParams reads the slot, node_path, and method parameters, falling
+// back to the configured helpers. method names the load method if load is set,
+// the save method otherwise.
+func gameStateParams(params map[string]interface{}, load bool) (gameStateHelpers, string) {
+	h := getGameStateHelpers()
+	override := gameStateHelpers{}
+	if p, ok := params["node_path"].(string); ok && p != "" {
+		override.NodePath = p
+	}
+	if m, ok := params["method"].(string); ok && m != "" {
+		if load {
+			override.LoadMethod = m
+		} else {
+			override.SaveMethod = m
+		}
+	}
+	if override != (gameStateHelpers{}) {
+		setGameStateHelpers(override)
+		h = getGameStateHelpers()
+	}
+
+	slot, _ := params["slot"].(string)
+	if slot == "" {
+		slot = h.LastSlot
+	}
+	if slot == "" {
+		slot = defaultGameStateSlot
+	}
+	return h, slot
+}
+
+// gameStateError wraps a failed save or load with hints about the helper setup
+func gameStateError(action, nodePath, method string, err error) error {
+	return FormatError(
+		fmt.Sprintf("Failed to %s game state", action),
+		fmt.Sprintf("%s.%s", nodePath, method),
+		[]string{
+			"The project must define the method on a node, usually an autoload (e.g. /root/SaveManager)",
+			"Pass node_path and method to point at the project's own functions; they are remembered",
+			"The method is called with the slot name as its only argument",
+		},
+		err,
+	)
+}
+
+// RegisterGameStateTools registers godot_save_game_state and godot_load_game_state
+func RegisterGameStateTools(server *mcp.Server) {
+	// godot_save_game_state - Call the project's save function at a breakpoint
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_save_game_state",
+		Description: `Save the game's state with the project's own save function.
+
+Calls <node_path>.<method>(slot) in the paused game, so a bug state reached at
+a breakpoint can be persisted and loaded again on the next launch with
+godot_load_game_state. The project provides the function; this tool only
+calls it. Defaults: node_path "/root/SaveManager", method "save_state".
+node_path and method are remembered for later calls (and saved in the session
+snapshot), so they only need to be given once.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- The project defines a save method taking a slot name, e.g. on an autoload:
+  func save_state(slot: String) -> bool
+
+Example: Persist the state that triggers a bug
+godot_save_game_state(slot="boss_softlock", node_path="/root/GameState", method="save_to_slot")
+→ {"status": "saved", "slot": "boss_softlock", "result": "true", ...}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "slot",
+				Type:        "string",
+				Required:    false,
+				Description: "Slot name passed to the save method (default: the last saved slot, or \"mcp_repro\")",
+			},
+			{
+				Name:        "node_path",
+				Type:        "string",
+				Required:    false,
+				Description: "Node with the save method, absolute /root/... or relative to the current scene (default: remembered, initially /root/SaveManager)",
+			},
+			{
+				Name:        "method",
+				Type:        "string",
+				Required:    false,
+				Description: "Name of the save method (default: remembered, initially save_state)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "save game state"); err != nil {
+				return nil, err
+			}
+
+			h, slot := gameStateParams(params, false)
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := callGameStateHelper(ctx, session.GetClient(), 0, h.NodePath, h.SaveMethod, slot)
+			if err != nil {
+				return nil, gameStateError("save", h.NodePath, h.SaveMethod, err)
+			}
+			setGameStateHelpers(gameStateHelpers{LastSlot: slot})
+
+			return map[string]interface{}{
+				"status":    "saved",
+				"slot":      slot,
+				"node_path": h.NodePath,
+				"method":    h.SaveMethod,
+				"result":    result,
+				"message":   fmt.Sprintf("On the next launch, pause early (e.g. a breakpoint in _ready) and call godot_load_game_state(slot=%q)", slot),
+			}, nil
+		},
+	})
+
+	// godot_load_game_state - Call the project's load function at a breakpoint
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_load_game_state",
+		Description: `Load a game state with the project's own load function.
+
+Calls <node_path>.<method>(slot) in the paused game to restore a state saved
+with godot_save_game_state, usually at a breakpoint early in a fresh launch,
+so a bug can be reproduced from the same state every time. Defaults:
+node_path "/root/SaveManager", method "load_state", slot the last one saved.
+node_path and method are remembered like those of godot_save_game_state.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- The project defines a load method taking a slot name
+
+The state is applied when the game resumes; loading while paused in the
+middle of a frame may need the project's load function to defer its work.
+
+Example: Reproduce from the saved state on the next launch
+godot_set_breakpoint(file="res://main.gd", line=5)
+godot_launch_main_scene(project="/path/to/project")
+godot_load_game_state(slot="boss_softlock")
+godot_continue()`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "slot",
+				Type:        "string",
+				Required:    false,
+				Description: "Slot name passed to the load method (default: the last saved slot, or \"mcp_repro\")",
+			},
+			{
+				Name:        "node_path",
+				Type:        "string",
+				Required:    false,
+				Description: "Node with the load method, absolute /root/... or relative to the current scene (default: remembered, initially /root/SaveManager)",
+			},
+			{
+				Name:        "method",
+				Type:        "string",
+				Required:    false,
+				Description: "Name of the load method (default: remembered, initially load_state)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "load game state"); err != nil {
+				return nil, err
+			}
+
+			h, slot := gameStateParams(params, true)
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := callGameStateHelper(ctx, session.GetClient(), 0, h.NodePath, h.LoadMethod, slot)
+			if err != nil {
+				return nil, gameStateError("load", h.NodePath, h.LoadMethod, err)
+			}
+
+			return map[string]interface{}{
+				"status":    "loaded",
+				"slot":      slot,
+				"node_path": h.NodePath,
+				"method":    h.LoadMethod,
+				"result":    result,
+				"message":   "Resume the game (godot_continue) to run from the loaded state",
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCallGameStateHelper(t *testing.T) {
+	node := `Engine.get_main_loop().root.get_node_or_null("/root/SaveManager")`
+	values := map[string][2]string{
+		"str(" + node + ")":                                               {"SaveManager:<Node#123>", "String"},
+		node + `.has_method("save_state")`:                                {"true", "bool"},
+		node + `.has_method("missing")`:                                   {"false", "bool"},
+		node + `.save_state("boss_softlock")`:                             {"true", "bool"},
+		`str(Engine.get_main_loop().root.get_node_or_null("/root/Nope"))`: {"<null>", "String"},
+	}
+	eval := &fakeDiagnoseEvaluator{values: values}
+	ctx := context.Background()
+
+	result, err := callGameStateHelper(ctx, eval, 0, "/root/SaveManager", "save_state", "boss_softlock")
+	if err != nil || result != "true" {
+		t.Errorf("expected the save method's result, got %q, %v", result, err)
+	}
+
+	if _, err := callGameStateHelper(ctx, eval, 0, "/root/SaveManager", "missing", "boss_softlock"); err == nil || !strings.Contains(err.Error(), "has no method missing") {
+		t.Errorf("expected a missing method error, got %v", err)
+	}
+	if _, err := callGameStateHelper(ctx, eval, 0, "/root/Nope", "save_state", "boss_softlock"); err == nil || !strings.Contains(err.Error(), "no node at /root/Nope") {
+		t.Errorf("expected a missing node error, got %v", err)
+	}
+}
+
+func TestGameStateParams(t *testing.T) {
+	saved := getGameStateHelpers()
+	defer func() {
+		gameStateMu.Lock()
+		gameState = saved
+		gameStateMu.Unlock()
+	}()
+	gameStateMu.Lock()
+	gameState = defaultGameStateHelpers
+	gameStateMu.Unlock()
+
+	h, slot := gameStateParams(map[string]interface{}{}, false)
+	if h != defaultGameStateHelpers || slot != defaultGameStateSlot {
+		t.Errorf("expected the defaults, got %+v slot %q", h, slot)
+	}
+
+	// Names given once are remembered; method applies to the tool's side only
+	h, _ = gameStateParams(map[string]interface{}{"node_path": "/root/GameState", "method": "load_slot"}, true)
+	if h.NodePath != "/root/GameState" || h.LoadMethod != "load_slot" || h.SaveMethod != "save_state" {
+		t.Errorf("unexpected helpers after override: %+v", h)
+	}
+	setGameStateHelpers(gameStateHelpers{LastSlot: "boss"})
+	h, slot = gameStateParams(map[string]interface{}{}, false)
+	if h.NodePath != "/root/GameState" || slot != "boss" {
+		t.Errorf("expected remembered node path and last slot, got %+v slot %q", h, slot)
+	}
+}
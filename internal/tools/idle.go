@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+)
+
+// Environment variables for the default idle timeout, overridable per
+// session with godot_connect(idle_timeout=..., idle_terminate=...)
+const (
+	// idleTimeoutEnv is the idle timeout in minutes (unset or 0 disables it)
+	idleTimeoutEnv = "GODOT_MCP_IDLE_TIMEOUT_MINUTES"
+
+	// idleTerminateEnv, when "true" or "1", also ends the game on idle disconnect
+	idleTerminateEnv = "GODOT_MCP_IDLE_TERMINATE"
+)
+
+// idleMonitor disconnects a session after a period without tool activity,
+// so a forgotten session doesn't keep the game paused or the editor's
+// debug adapter occupied. A running tool call (e.g. a long
+// godot_wait_for_stop) is activity for as long as it runs.
+type idleMonitor struct {
+	mu           sync.Mutex
+	session      *dap.Session
+	timeout      time.Duration
+	terminate    bool
+	lastActivity time.Time
+	timer        *time.Timer
+	calls        int // Tool calls running
+
+	// onIdle replaces disconnectIdleSession in tests
+	onIdle func(session *dap.Session, terminate bool)
+}
+
+// Idle monitor of the global session
+var idle idleMonitor
+
+// defaultIdleSettings reads the idle timeout defaults from the environment
+func defaultIdleSettings() (time.Duration, bool) {
+	var timeout time.Duration
+	if value := os.Getenv(idleTimeoutEnv); value != "" {
+		minutes, err := strconv.ParseFloat(value, 64)
+		if err != nil || minutes < 0 {
+			log.Printf("Ignoring invalid %s=%q", idleTimeoutEnv, value)
+		} else {
+			timeout = time.Duration(minutes * float64(time.Minute))
+		}
+	}
+	terminate, _ := strconv.ParseBool(os.Getenv(idleTerminateEnv))
+	return timeout, terminate
+}
+
+// watch starts monitoring session, replacing any previous session.
+// A zero timeout disables the monitor.
+func (m *idleMonitor) watch(session *dap.Session, timeout time.Duration, terminate bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopLocked()
+	if timeout <= 0 {
+		return
+	}
+	m.session = session
+	m.timeout = timeout
+	m.terminate = terminate
+	m.lastActivity = time.Now()
+	m.timer = time.AfterFunc(timeout, m.expire)
+}
+
+// touch records tool activity, restarting the idle countdown
+func (m *idleMonitor) touch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastActivity = time.Now()
+	if m.timer != nil {
+		m.timer.Reset(m.timeout)
+	}
+}
+
+// begin records that a tool call started; the session doesn't idle until
+// the returned function records its end. Installed as the server's call hook.
+func (m *idleMonitor) begin(tool string) func() {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.calls--
+			m.mu.Unlock()
+			m.touch()
+		})
+	}
+}
+
+// stop ends monitoring without disconnecting
+func (m *idleMonitor) stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopLocked()
+}
+
+func (m *idleMonitor) stopLocked() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.session = nil
+}
+
+// status describes the monitor for godot_get_session_state; nil if disabled
+func (m *idleMonitor) status() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.timer == nil {
+		return nil
+	}
+	remaining := m.timeout - time.Since(m.lastActivity)
+	if m.calls > 0 {
+		remaining = m.timeout
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return map[string]interface{}{
+		"timeout_minutes":   m.timeout.Minutes(),
+		"terminate":         m.terminate,
+		"remaining_seconds": int(remaining.Seconds()),
+	}
+}
+
+// expire runs when the timer fires. Activity may have raced the timer, so
+// the idle period is checked again before disconnecting. While a tool call
+// runs the countdown starts over; the call's end restarts it too.
+func (m *idleMonitor) expire() {
+	m.mu.Lock()
+	if m.timer == nil {
+		m.mu.Unlock()
+		return
+	}
+	if m.calls > 0 {
+		m.timer.Reset(m.timeout)
+		m.mu.Unlock()
+		return
+	}
+	if idleFor := time.Since(m.lastActivity); idleFor < m.timeout {
+		m.timer.Reset(m.timeout - idleFor)
+		m.mu.Unlock()
+		return
+	}
+	session, terminate := m.session, m.terminate
+	onIdle := m.onIdle
+	m.timer = nil
+	m.session = nil
+	m.mu.Unlock()
+
+	if onIdle == nil {
+		onIdle = disconnectIdleSession
+	}
+	onIdle(session, terminate)
+}
+
+// disconnectIdleSession closes session if it is still the active one
+func disconnectIdleSession(session *dap.Session, terminate bool) {
+	if !clearSession(session) {
+		return
+	}
+	log.Printf("Disconnecting DAP session after idle timeout (terminate game: %v)", terminate)
+	if _, err := shutdownSession(session, terminate); err != nil {
+		log.Printf("Idle disconnect failed: %v", err)
+	}
+}
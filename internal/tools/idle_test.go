@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
+)
+
+func TestIdleMonitor_Expires(t *testing.T) {
+	fired := make(chan bool, 1)
+	m := &idleMonitor{onIdle: func(session *dap.Session, terminate bool) {
+		fired <- terminate
+	}}
+
+	m.watch(dap.NewSession("localhost", 6006), 30*time.Millisecond, true)
+	if m.status() == nil {
+		t.Fatal("status should be reported while monitoring")
+	}
+
+	select {
+	case terminate := <-fired:
+		if !terminate {
+			t.Error("terminate setting should be passed to onIdle")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle timeout did not fire")
+	}
+	if m.status() != nil {
+		t.Error("monitor should be disabled after it fired")
+	}
+}
+
+func TestIdleMonitor_TouchDelaysExpiry(t *testing.T) {
+	fired := make(chan time.Time, 1)
+	m := &idleMonitor{onIdle: func(session *dap.Session, terminate bool) {
+		fired <- time.Now()
+	}}
+
+	start := time.Now()
+	m.watch(dap.NewSession("localhost", 6006), 60*time.Millisecond, false)
+	for i := 0; i < 4; i++ {
+		time.Sleep(30 * time.Millisecond)
+		m.touch()
+	}
+
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(start); elapsed < 180*time.Millisecond {
+			t.Errorf("fired after %v despite activity", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle timeout did not fire after activity stopped")
+	}
+}
+
+func TestIdleMonitor_RunningCallKeepsSession(t *testing.T) {
+	fired := make(chan time.Time, 1)
+	m := &idleMonitor{onIdle: func(session *dap.Session, terminate bool) {
+		fired <- time.Now()
+	}}
+
+	m.watch(dap.NewSession("localhost", 6006), 30*time.Millisecond, false)
+	end := m.begin("godot_wait_for_stop")
+	select {
+	case <-fired:
+		t.Fatal("the session should not idle while a tool call runs")
+	case <-time.After(120 * time.Millisecond):
+	}
+
+	ended := time.Now()
+	end()
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(ended); elapsed < 30*time.Millisecond {
+			t.Errorf("fired %v after the call ended, before the timeout", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle timeout did not fire after the call ended")
+	}
+}
+
+func TestIdleTimeout_LongWait(t *testing.T) {
+	mock := daptest.NewServer(t)
+	defer mock.Close()
+	go mock.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{mock.Success(req, nil), mock.NewEvent("initialized", nil)}
+		case "disconnect":
+			return []godap.Message{mock.Success(req, nil)}
+		}
+		return nil
+	})
+	session := connectMockSession(t, mock)
+	server := mcp.NewServer()
+	RegisterAll(server)
+
+	// The wait outlasts the idle timeout several times over
+	idle.watch(session, 200*time.Millisecond, false)
+	t.Cleanup(idle.stop)
+	result, err := server.CallTool("godot_wait_for_event", map[string]interface{}{"types": []interface{}{"terminated"}, "timeout": float64(1)})
+	if err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if status := result.(map[string]interface{})["status"]; status != "timeout" {
+		t.Errorf("the wait should run to its own timeout, got status %v", status)
+	}
+	if currentSession() != session {
+		t.Fatal("the session should not idle while the wait runs")
+	}
+
+	// Once the call ends, the countdown starts over
+	deadline := time.Now().Add(2 * time.Second)
+	for currentSession() == session && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if currentSession() == session {
+		t.Error("the session should idle after the wait ends")
+	}
+}
+
+func TestIdleMonitor_StopAndDisabled(t *testing.T) {
+	fired := make(chan struct{}, 1)
+	m := &idleMonitor{onIdle: func(session *dap.Session, terminate bool) {
+		fired <- struct{}{}
+	}}
+
+	m.watch(dap.NewSession("localhost", 6006), 20*time.Millisecond, false)
+	m.stop()
+
+	m.watch(dap.NewSession("localhost", 6006), 0, false)
+	if m.status() != nil {
+		t.Error("zero timeout should disable the monitor")
+	}
+	m.touch() // must not panic without a timer
+
+	select {
+	case <-fired:
+		t.Error("stopped monitor should not fire")
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestDefaultIdleSettings(t *testing.T) {
+	t.Setenv(idleTimeoutEnv, "")
+	t.Setenv(idleTerminateEnv, "")
+	if timeout, terminate := defaultIdleSettings(); timeout != 0 || terminate {
+		t.Errorf("expected disabled defaults, got %v, %v", timeout, terminate)
+	}
+
+	t.Setenv(idleTimeoutEnv, "1.5")
+	t.Setenv(idleTerminateEnv, "true")
+	if timeout, terminate := defaultIdleSettings(); timeout != 90*time.Second || !terminate {
+		t.Errorf("expected 90s with terminate, got %v, %v", timeout, terminate)
+	}
+
+	t.Setenv(idleTimeoutEnv, "soon")
+	if timeout, _ := defaultIdleSettings(); timeout != 0 {
+		t.Errorf("invalid value should be ignored, got %v", timeout)
+	}
+}
+
+func TestClearSession(t *testing.T) {
+	session := dap.NewSession("localhost", 6006)
+	other := dap.NewSession("localhost", 6007)
+	swapSession(session)
+	t.Cleanup(func() { swapSession(nil) })
+
+	if clearSession(other) {
+		t.Error("clearing a session that isn't active should fail")
+	}
+	if currentSession() != session {
+		t.Fatal("active session should be untouched")
+	}
+	if !clearSession(session) || currentSession() != nil {
+		t.Error("active session should be cleared")
+	}
+}
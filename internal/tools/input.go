@@ -0,0 +1,317 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// inputAction is an action from the [input] section of project.godot
+type inputAction struct {
+	Name     string   `json:"name"`
+	Deadzone *float64 `json:"deadzone,omitempty"`
+	Events   []string `json:"events"` // Readable event descriptions
+}
+
+// inputEventProperty matches the "key":value pairs of an Object(InputEvent...) value
+var inputEventProperty = regexp.MustCompile(`"(\w+)":([^,)]+)`)
+
+// inputDeadzone matches the deadzone of an action
+var inputDeadzone = regexp.MustCompile(`"deadzone":\s*([0-9.]+)`)
+
+// specialKeys names the Key values with the KEY_SPECIAL bit (1 << 22) set
+// that actions are commonly bound to
+var specialKeys = map[int]string{
+	4194305: "Escape",
+	4194306: "Tab",
+	4194308: "Backspace",
+	4194309: "Enter",
+	4194310: "Kp Enter",
+	4194312: "Delete",
+	4194317: "Home",
+	4194318: "End",
+	4194319: "Left",
+	4194320: "Up",
+	4194321: "Right",
+	4194322: "Down",
+	4194323: "PageUp",
+	4194324: "PageDown",
+	4194325: "Shift",
+	4194326: "Ctrl",
+	4194327: "Meta",
+	4194328: "Alt",
+}
+
+// mouseButtons names the MouseButton values
+var mouseButtons = map[string]string{
+	"1": "Left Mouse Button",
+	"2": "Right Mouse Button",
+	"3": "Middle Mouse Button",
+	"4": "Mouse Wheel Up",
+	"5": "Mouse Wheel Down",
+}
+
+// keyName names a Key value: letters, digits, and symbols by their
+// character, common special keys by name, the rest by number
+func keyName(code int) string {
+	if name, ok := specialKeys[code]; ok {
+		return name
+	}
+	if code == 32 {
+		return "Space"
+	}
+	if code > 32 && code < 127 {
+		return string(rune(code))
+	}
+	if code >= 4194332 && code <= 4194343 {
+		return fmt.Sprintf("F%d", code-4194331)
+	}
+	return fmt.Sprintf("Key %d", code)
+}
+
+// describeInputEvent turns one Object(InputEvent...) value into a readable
+// binding, e.g. "Ctrl+S (physical)" or "Joypad axis 0 -"
+func describeInputEvent(object string) string {
+	class, rest, _ := strings.Cut(object, ",")
+	class = strings.TrimSpace(class)
+	props := map[string]string{}
+	for _, m := range inputEventProperty.FindAllStringSubmatch(rest, -1) {
+		props[m[1]] = strings.TrimSpace(m[2])
+	}
+
+	switch class {
+	case "InputEventKey":
+		var mods []string
+		for _, mod := range []struct{ prop, name string }{
+			{"ctrl_pressed", "Ctrl"}, {"shift_pressed", "Shift"}, {"alt_pressed", "Alt"}, {"meta_pressed", "Meta"},
+		} {
+			if props[mod.prop] == "true" {
+				mods = append(mods, mod.name)
+			}
+		}
+		suffix := ""
+		code, _ := strconv.Atoi(props["keycode"])
+		if physical, _ := strconv.Atoi(props["physical_keycode"]); physical != 0 {
+			code = physical
+			suffix = " (physical)"
+		}
+		return strings.Join(append(mods, keyName(code)), "+") + suffix
+	case "InputEventMouseButton":
+		if name, ok := mouseButtons[props["button_index"]]; ok {
+			return name
+		}
+		return "Mouse button " + props["button_index"]
+	case "InputEventJoypadButton":
+		return "Joypad button " + props["button_index"]
+	case "InputEventJoypadMotion":
+		direction := "+"
+		if strings.HasPrefix(props["axis_value"], "-") {
+			direction = "-"
+		}
+		return fmt.Sprintf("Joypad axis %s %s", props["axis"], direction)
+	}
+	return class
+}
+
+// parseInputActions reads the actions from the [input] section of a
+// project's project.godot, in file order. Each action's value spans lines:
+//
+//	move_left={
+//	"deadzone": 0.5,
+//	"events": [Object(InputEventKey,...,"physical_keycode":65,...)
+//	]
+//	}
+func parseInputActions(projectDir string) ([]inputAction, error) {
+	f, err := os.Open(filepath.Join(projectDir, "project.godot"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	actions := []inputAction{}
+	section := ""
+	var current *inputAction
+	var body strings.Builder
+
+	finish := func() {
+		value := body.String()
+		if m := inputDeadzone.FindStringSubmatch(value); m != nil {
+			if deadzone, err := strconv.ParseFloat(m[1], 64); err == nil {
+				current.Deadzone = &deadzone
+			}
+		}
+		current.Events = []string{}
+		for _, object := range strings.Split(value, "Object(")[1:] {
+			current.Events = append(current.Events, describeInputEvent(object))
+		}
+		actions = append(actions, *current)
+		current = nil
+		body.Reset()
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if current != nil {
+			if line == "}" {
+				finish()
+			} else {
+				body.WriteString(line)
+				body.WriteString("\n")
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		if section != "input" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		current = &inputAction{Name: strings.TrimSpace(key)}
+		value = strings.TrimSpace(value)
+		if value != "{" {
+			// Whole value on one line
+			body.WriteString(value)
+			finish()
+		}
+	}
+	if current != nil {
+		finish()
+	}
+	return actions, scanner.Err()
+}
+
+// readPressedActions evaluates Input.is_action_pressed() for each action,
+// with the action strength of those that are pressed
+func readPressedActions(ctx context.Context, client expressionEvaluator, frameId int, actions []inputAction) (map[string]interface{}, error) {
+	pressed := map[string]interface{}{}
+	for _, action := range actions {
+		name := strconv.Quote(action.Name)
+		resp, err := client.Evaluate(ctx, fmt.Sprintf("Input.is_action_pressed(%s)", name), frameId, "watch")
+		if err != nil {
+			return nil, err
+		}
+		if resp.Body.Result != "true" {
+			continue
+		}
+		strength := interface{}(true)
+		if resp, err := client.Evaluate(ctx, fmt.Sprintf("Input.get_action_strength(%s)", name), frameId, "watch"); err == nil {
+			if value := parseGodotFloat(resp.Body.Result); value != nil {
+				strength = value
+			}
+		}
+		pressed[action.Name] = strength
+	}
+	return pressed, nil
+}
+
+// RegisterInputTools registers godot_get_input_state
+func RegisterInputTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_input_state",
+		Description: `Get the project's input actions and which of them are pressed.
+
+Lists the actions from the [input] section of project.godot with their
+deadzone and bindings ("A (physical)", "Ctrl+S", "Joypad axis 0 -", ...).
+While the game is paused, also reports which actions are pressed
+(Input.is_action_pressed) and how strongly (Input.get_action_strength).
+
+Built-in ui_* actions only appear if the project overrides them.
+
+Prerequisites:
+- A project path: the project parameter, the connected session's project,
+  or the project found in the client's workspace
+- For pressed actions: connected, with the game paused
+
+Input is polled when frames are processed, so while paused the pressed
+state is the one of the frame that was interrupted.
+
+Example: Is the jump action held at this breakpoint?
+godot_get_input_state()
+→ {"actions": [{"name": "jump", "events": ["Space (physical)", "Joypad button 0"]}, ...],
+   "pressed": {"jump": 1}, "pressed_checked": true}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "project",
+				Type:        "string",
+				Required:    false,
+				Description: "Absolute path to the Godot project directory, or a name from godot_list_projects (default: the session's project)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session := currentSession()
+			project, _ := params["project"].(string)
+			project, err := resolveProject(project)
+			if err != nil {
+				return nil, err
+			}
+			if project == "" && session != nil {
+				project = session.GetProjectRoot()
+			}
+			if project == "" {
+				project = getDiscoveredProjectRoot()
+			}
+			if project == "" {
+				return nil, fmt.Errorf("no project known: pass project, or call godot_connect with a project first")
+			}
+			if err := checkPathAllowed(project); err != nil {
+				return nil, err
+			}
+
+			actions, err := parseInputActions(project)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read input actions",
+					filepath.Join(project, "project.godot"),
+					[]string{
+						"Check that project points at a Godot project directory",
+						"Use godot_find_projects to locate projects",
+					},
+					err,
+				)
+			}
+
+			result := map[string]interface{}{
+				"status":          "success",
+				"project":         project,
+				"actions":         actions,
+				"pressed_checked": false,
+			}
+			if session == nil || session.GetClient().RunState() != dap.RunStatePaused {
+				result["message"] = "Pause the game (godot_pause or a breakpoint) to see which actions are pressed"
+				return result, nil
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+			pressed, err := readPressedActions(ctx, session.GetClient(), 0, actions)
+			if err != nil {
+				result["pressed_error"] = err.Error()
+				return result, nil
+			}
+			result["pressed"] = pressed
+			result["pressed_checked"] = true
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const inputProject = `config_version=5
+
+[application]
+
+config/name="Input Test"
+
+[input]
+
+move_left={
+"deadzone": 0.5,
+"events": [Object(InputEventKey,"resource_local_to_scene":false,"resource_name":"","device":-1,"window_id":0,"alt_pressed":false,"shift_pressed":false,"ctrl_pressed":false,"meta_pressed":false,"pressed":false,"keycode":0,"physical_keycode":65,"key_label":0,"unicode":97,"location":0,"echo":false,"script":null)
+, Object(InputEventJoypadMotion,"resource_local_to_scene":false,"resource_name":"","device":-1,"axis":0,"axis_value":-1.0,"script":null)
+]
+}
+save={
+"deadzone": 0.5,
+"events": [Object(InputEventKey,"resource_local_to_scene":false,"device":-1,"ctrl_pressed":true,"keycode":83,"physical_keycode":0,"script":null)
+, Object(InputEventMouseButton,"resource_local_to_scene":false,"device":-1,"button_index":2,"pressed":true,"script":null)
+]
+}
+pause={"deadzone": 0.5, "events": []}
+
+[rendering]
+
+renderer/rendering_method="mobile"
+`
+
+func TestParseInputActions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "project.godot"), []byte(inputProject), 0644); err != nil {
+		t.Fatalf("failed to write project.godot: %v", err)
+	}
+
+	actions, err := parseInputActions(dir)
+	if err != nil {
+		t.Fatalf("parseInputActions failed: %v", err)
+	}
+	if len(actions) != 3 {
+		t.Fatalf("expected 3 actions, got %+v", actions)
+	}
+	if actions[0].Name != "move_left" || actions[0].Deadzone == nil || *actions[0].Deadzone != 0.5 {
+		t.Errorf("unexpected first action: %+v", actions[0])
+	}
+	if want := []string{"A (physical)", "Joypad axis 0 -"}; !reflect.DeepEqual(actions[0].Events, want) {
+		t.Errorf("expected events %v, got %v", want, actions[0].Events)
+	}
+	if want := []string{"Ctrl+S", "Right Mouse Button"}; !reflect.DeepEqual(actions[1].Events, want) {
+		t.Errorf("expected events %v, got %v", want, actions[1].Events)
+	}
+	if actions[2].Name != "pause" || len(actions[2].Events) != 0 {
+		t.Errorf("unexpected one-line action: %+v", actions[2])
+	}
+}
+
+func TestKeyName(t *testing.T) {
+	tests := map[int]string{32: "Space", 65: "A", 4194319: "Left", 4194332: "F1", 4194343: "F12", 9999999: "Key 9999999"}
+	for code, want := range tests {
+		if got := keyName(code); got != want {
+			t.Errorf("keyName(%d) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestReadPressedActions(t *testing.T) {
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`Input.is_action_pressed("jump")`:   {"true", "bool"},
+		`Input.get_action_strength("jump")`: {"0.75", "float"},
+		`Input.is_action_pressed("fire")`:   {"false", "bool"},
+	}}
+	pressed, err := readPressedActions(context.Background(), eval, 0, []inputAction{{Name: "jump"}, {Name: "fire"}})
+	if err != nil {
+		t.Fatalf("readPressedActions failed: %v", err)
+	}
+	if !reflect.DeepEqual(pressed, map[string]interface{}{"jump": 0.75}) {
+		t.Errorf("unexpected pressed actions: %v", pressed)
+	}
+}
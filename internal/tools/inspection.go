@@ -3,9 +3,11 @@ package tools
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
 )
 
 // RegisterInspectionTools registers all runtime inspection MCP tools.
@@ -27,12 +29,26 @@ Use this tool:
 - To verify the game is running and responsive
 - Before inspecting variables or evaluating expressions
 
-The response includes thread ID and name for each active thread.
+The response includes thread ID, name, and execution state ("stopped" with a
+stop_reason, "running", or "unknown" before the first stop) for each active
+thread, plus recent thread start/exit events. The list is cached and kept current from thread events;
+pass refresh=true to force a new threads request.
 
 Example: Get all threads
-godot_get_threads()`,
+godot_get_threads()
 
-		Parameters: []mcp.Parameter{},
+Example: Bypass the cache
+godot_get_threads(refresh=true)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "refresh",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, always request the threads list from Godot instead of using the cache",
+			},
+		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
 			// Get active session
@@ -46,7 +62,17 @@ godot_get_threads()`,
 			defer cancel()
 
 			client := session.GetClient()
-			resp, err := client.Threads(ctx)
+
+			var threadList []godap.Thread
+			if getBoolParam(params, "refresh") {
+				var resp *godap.ThreadsResponse
+				resp, err = client.Threads(ctx)
+				if resp != nil {
+					threadList = resp.Body.Threads
+				}
+			} else {
+				threadList, err = client.CachedThreads(ctx)
+			}
 			if err != nil {
 				return nil, FormatError(
 					"Failed to get threads",
@@ -59,20 +85,45 @@ godot_get_threads()`,
 				)
 			}
 
-			// Format response
-			threads := make([]map[string]interface{}, len(resp.Body.Threads))
-			for i, thread := range resp.Body.Threads {
-				threads[i] = map[string]interface{}{
-					"id":   thread.Id,
-					"name": thread.Name,
+			// Format response, with each thread's last known execution state
+			states := client.ThreadStates()
+			threads := make([]map[string]interface{}, len(threadList))
+			for i, thread := range threadList {
+				entry := map[string]interface{}{
+					"id":    thread.Id,
+					"name":  thread.Name,
+					"state": "unknown",
+				}
+				if st, ok := states[thread.Id]; ok {
+					if st.Stopped {
+						entry["state"] = "stopped"
+						entry["stop_reason"] = st.Reason
+					} else {
+						entry["state"] = "running"
+					}
 				}
+				threads[i] = entry
 			}
 
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"status":  "success",
 				"threads": threads,
 				"count":   len(threads),
-			}, nil
+			}
+
+			if records := client.ThreadEvents(); len(records) > 0 {
+				events := make([]map[string]interface{}, len(records))
+				for i, r := range records {
+					events[i] = map[string]interface{}{
+						"reason":    r.Reason,
+						"thread_id": r.ThreadId,
+						"time":      r.Time.Format(time.RFC3339),
+					}
+				}
+				result["thread_events"] = events
+			}
+
+			return result, nil
 		},
 	})
 
@@ -94,13 +145,28 @@ Use this tool:
 - To see which function called the current function
 - To get frame IDs for inspecting variables in different stack frames
 
-The response includes frames from most recent (index 0) to oldest.
+The response includes frames from most recent (index 0) to oldest. Frames in
+native engine or GDExtension code have no source and are marked native=true;
+they have no GDScript variables to inspect.
+
+Frames the engine entered directly are marked with called_from, since the
+code that caused them isn't on the stack:
+- engine_callback: _ready, _process, _input, ...
+- signal: a signal emission (or an _on_<node>_<signal> handler)
+- deferred: call_deferred or a deferred connection, run at the end of the frame
+- await_resume: a function resuming after await
+- deferred_or_signal: called by the engine some other way (signal connection
+  to another method, call_deferred, Timer/Tween callback, await)
+The markers are inferred from frame names.
 
 Example: Get full stack trace
 godot_get_stack_trace(thread_id=1)
 
 Example: Get top 5 frames only
-godot_get_stack_trace(thread_id=1, max_frames=5)`,
+godot_get_stack_trace(thread_id=1, max_frames=5)
+
+Example: Get a compact markdown table instead of JSON
+godot_get_stack_trace(format="markdown_compact")`,
 
 		Parameters: []mcp.Parameter{
 			{
@@ -116,7 +182,9 @@ godot_get_stack_trace(thread_id=1, max_frames=5)`,
 				Required:    false,
 				Default:     20,
 				Description: "Maximum number of stack frames to return (default: 20)",
+				Minimum:     mcp.Float64(1),
 			},
+			inspectFormatParam(),
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -125,6 +193,9 @@ godot_get_stack_trace(thread_id=1, max_frames=5)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "get stack trace"); err != nil {
+				return nil, err
+			}
 
 			// Get parameters
 			threadId := 1
@@ -156,6 +227,7 @@ godot_get_stack_trace(thread_id=1, max_frames=5)`,
 			}
 
 			// Format stack frames
+			complete := isCompleteStack(resp, maxFrames)
 			frames := make([]map[string]interface{}, len(resp.Body.StackFrames))
 			for i, frame := range resp.Body.StackFrames {
 				frameData := map[string]interface{}{
@@ -165,16 +237,19 @@ godot_get_stack_trace(thread_id=1, max_frames=5)`,
 					"column": frame.Column,
 				}
 
-				// Add source file if available
-				if frame.Source != nil {
-					frameData["source"] = map[string]interface{}{
-						"name": frame.Source.Name,
-						"path": frame.Source.Path,
-					}
-				}
+				// Add source file, or mark native frames that have none
+				addFrameSource(frameData, frame)
+
+				// Mark frames entered by a signal, deferred call, or await
+				addCalledFrom(frameData, resp.Body.StackFrames, i, complete)
 
 				frames[i] = frameData
 			}
+			recordNativeFrames(resp.Body.StackFrames)
+
+			if wantsCompact(params) {
+				return renderStackCompact(resp.Body.StackFrames, session.GetProjectRoot(), complete), nil
+			}
 
 			return map[string]interface{}{
 				"status":       "success",
@@ -208,6 +283,9 @@ Godot always returns three scopes:
 - Members: Instance/class member variables (if in a method)
 - Globals: Global variables and autoloads
 
+Frames marked native=true in the stack trace (engine or GDExtension code) have
+no scopes; for those this tool returns an explanatory message instead of an error.
+
 Example: Get scopes for top frame
 godot_get_scopes(frame_id=1)`,
 
@@ -226,6 +304,9 @@ godot_get_scopes(frame_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "get scopes"); err != nil {
+				return nil, err
+			}
 
 			// Get frame ID parameter
 			frameIdFloat, ok := params["frame_id"].(float64)
@@ -234,6 +315,17 @@ godot_get_scopes(frame_id=1)`,
 			}
 			frameId := int(frameIdFloat)
 
+			// Native frames have no scopes; Godot would answer with an error
+			if isKnownNativeFrame(frameId) {
+				return map[string]interface{}{
+					"status":  "native_frame",
+					"message": nativeFrameMessage,
+					"native":  true,
+					"scopes":  []map[string]interface{}{},
+					"count":   0,
+				}, nil
+			}
+
 			// Request scopes
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
@@ -313,9 +405,24 @@ When expanding a Node object, properties are categorized:
 - Transform2D/* - Position, rotation, scale (for 2D nodes)
 - Other categories based on node type (CanvasItem, Control, etc.)
 
+Pass categories to keep only some of an expanded object's property categories,
+e.g. categories=["Members", "Node"] to drop engine internals like CanvasItem/*.
+The response lists every category present so you can refine the filter.
+Uncategorized variables (scope contents, array elements) are never filtered.
+
+Variables holding nodes in the scene tree get a node_path, and their
+formatted value reads "Player (CharacterBody2D) @ /root/Main/Player" instead
+of only the instance ID.
+
 Example: Get all local variables
 godot_get_variables(variables_reference=1000)
 
+Example: Show only a node's script members and Node properties
+godot_get_variables(variables_reference=2000, categories=["Members", "Node"])
+
+Example: Get a compact markdown table (values truncated, ref column for expansion)
+godot_get_variables(variables_reference=1000, format="markdown_compact")
+
 Example: Expand a Vector2 variable
 godot_get_variables(variables_reference=2000)
 
@@ -336,6 +443,14 @@ Example: Scene tree navigation workflow
 				Required:    true,
 				Description: "Variables reference ID (from godot_get_scopes or a complex variable)",
 			},
+			{
+				Name:        "categories",
+				Type:        "array",
+				Required:    false,
+				Items:       &mcp.Parameter{Type: "string"},
+				Description: "Only return properties in these categories of an expanded object (e.g. [\"Members\", \"Node\"]); case-insensitive",
+			},
+			inspectFormatParam(),
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -344,6 +459,9 @@ Example: Scene tree navigation workflow
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "get variables"); err != nil {
+				return nil, err
+			}
 
 			// Get variables reference parameter
 			varRefFloat, ok := params["variables_reference"].(float64)
@@ -352,6 +470,11 @@ Example: Scene tree navigation workflow
 			}
 			varRef := int(varRefFloat)
 
+			categories, err := getStringListParam(params, "categories")
+			if err != nil {
+				return nil, err
+			}
+
 			// Request variables
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
@@ -371,13 +494,26 @@ Example: Scene tree navigation workflow
 			}
 
 			// Format variables with Godot-specific formatting
-			variables := formatVariableList(resp.Body.Variables)
+			kept, filtered := filterPropertyCategories(resp.Body.Variables, categories)
+			if wantsCompact(params) {
+				return renderVariablesCompact(kept), nil
+			}
+			variables := formatVariableList(kept)
+			addNodeBreadcrumbs(ctx, client, kept, variables)
+			addSummaries(ctx, client, kept, variables)
 
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"status":    "success",
 				"variables": variables,
 				"count":     len(variables),
-			}, nil
+			}
+			if available := propertyCategories(resp.Body.Variables); len(available) > 0 {
+				result["categories"] = available
+			}
+			if len(categories) > 0 {
+				result["filtered_out"] = filtered
+			}
+			return result, nil
 		},
 	})
 
@@ -405,6 +541,11 @@ WARNING: The expression CAN modify game state. For example, evaluating
 "player.health = 0" will actually change the player's health. Use
 godot_set_variable for intentional modifications.
 
+The expression runs on the game's main thread, so a heavy getter or an
+endless loop blocks the game. Evaluation stops waiting after timeout_seconds
+(default 10); the server then sends a pause, checks whether the game answers
+again, and returns guidance instead of holding the session.
+
 Example: Evaluate simple expression
 godot_evaluate(expression="player.health * 2", frame_id=1)
 
@@ -434,6 +575,14 @@ godot_evaluate(expression="$Player/Sprite.texture.get_size()", frame_id=1)`,
 				Required:    false,
 				Default:     "repl",
 				Description: "Evaluation context: 'watch', 'repl', or 'hover' (default: 'repl')",
+				Enum:        []string{"watch", "repl", "hover"},
+			},
+			{
+				Name:        "timeout_seconds",
+				Type:        "number",
+				Required:    false,
+				Default:     10,
+				Description: "Seconds to wait for the result before attempting recovery (default: 10, max: 30)",
 			},
 		},
 
@@ -443,6 +592,9 @@ godot_evaluate(expression="$Player/Sprite.texture.get_size()", frame_id=1)`,
 			if err != nil {
 				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
 			}
+			if err := requirePaused(session, "evaluate expression"); err != nil {
+				return nil, err
+			}
 
 			// Get expression parameter
 			expression, ok := params["expression"].(string)
@@ -461,13 +613,25 @@ godot_evaluate(expression="$Player/Sprite.texture.get_size()", frame_id=1)`,
 				evalContext = ctx
 			}
 
+			timeout := dap.DefaultEvaluateTimeout
+			if t, ok := params["timeout_seconds"].(float64); ok && t > 0 {
+				timeout = time.Duration(t * float64(time.Second))
+			}
+			if timeout > maxEvaluateSeconds*time.Second {
+				timeout = maxEvaluateSeconds * time.Second
+			}
+
 			// Evaluate expression
-			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			ctx, cancel := dap.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
 			client := session.GetClient()
 			resp, err := client.Evaluate(ctx, expression, frameId, evalContext)
 			if err != nil {
+				if isEvaluateTimeout(err) {
+					recovery := recoverFromEvaluateTimeout(client, frameId)
+					return nil, evaluateTimeoutError(expression, timeout, recovery, err)
+				}
 				return nil, FormatError(
 					"Failed to evaluate expression",
 					fmt.Sprintf("expr='%s'", expression),
@@ -2,14 +2,106 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
 )
 
+// entryStopWait is how long a stop_on_entry launch waits for the adapter's
+// own entry stop before pausing the game itself
+const entryStopWait = 2 * time.Second
+
+// stopOnEntryParam is the stop_on_entry parameter shared by the launch tools
+func stopOnEntryParam() mcp.Parameter {
+	return mcp.Parameter{
+		Name:        "stop_on_entry",
+		Type:        "boolean",
+		Required:    false,
+		Default:     false,
+		Description: "If true, pause the game right after startup so breakpoints and watches can be configured before gameplay runs",
+	}
+}
+
+// waitForBreakpointsParam is the wait_for_breakpoints parameter shared by the launch tools
+func waitForBreakpointsParam() mcp.Parameter {
+	return mcp.Parameter{
+		Name:        "wait_for_breakpoints",
+		Type:        "boolean",
+		Required:    false,
+		Default:     true,
+		Description: "Hold configurationDone, which starts the game, until Godot has acknowledged every breakpoint set so far, so breakpoints in autoload scripts are hit (default: true)",
+	}
+}
+
+// addUnverifiedBreakpoints lists breakpoints Godot acknowledged without
+// verifying; they won't be hit until Godot verifies them
+func addUnverifiedBreakpoints(result map[string]interface{}, session *dap.Session) {
+	if unverified := session.GetClient().UnverifiedBreakpoints(); len(unverified) > 0 {
+		result["unverified_breakpoints"] = unverified
+	}
+}
+
+// launchScene launches config and, with stopOnEntry, pauses the game right
+// after startup. Adapters that honor stopOnEntry stop by themselves; if no
+// stop arrives within entryStopWait (Godot ignores the flag), the game is
+// paused with a pause request. Returns the entry stop details, or nil if
+// stopOnEntry is false. Failing to pause is reported in the details rather
+// than as an error, since the game did launch.
+func launchScene(ctx context.Context, session *dap.Session, config *dap.GodotLaunchConfig, stopOnEntry bool) (map[string]interface{}, error) {
+	config.StopOnEntry = stopOnEntry
+	if !stopOnEntry || config.NoDebug {
+		if _, err := session.LaunchGodotScene(ctx, config); err != nil {
+			return nil, err
+		}
+		recordLaunch(config)
+		return nil, nil
+	}
+
+	// Subscribe before launching so an immediate entry stop isn't missed
+	client := session.GetClient()
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("launch entry stop"))
+	defer cleanup()
+
+	if _, err := session.LaunchGodotScene(ctx, config); err != nil {
+		return nil, err
+	}
+	recordLaunch(config)
+
+	method := "adapter"
+	event := awaitStopOrEnd(events, time.After(entryStopWait))
+	if event == nil {
+		method = "pause"
+		if _, err := client.Pause(ctx, 1); err != nil {
+			return map[string]interface{}{
+				"stopped": false,
+				"error":   fmt.Sprintf("pause failed: %v", err),
+			}, nil
+		}
+		event = awaitStopOrEnd(events, time.After(dap.DefaultReadTimeout))
+	}
+
+	stopped, ok := event.(*godap.StoppedEvent)
+	if !ok {
+		return map[string]interface{}{
+			"stopped": false,
+			"error":   "the game ended or did not report a stop before pausing",
+		}, nil
+	}
+	return map[string]interface{}{
+		"stopped":   true,
+		"method":    method,
+		"reason":    stopped.Body.Reason,
+		"thread_id": stopped.Body.ThreadId,
+	}, nil
+}
+
 // RegisterLaunchTools registers scene launching tools (main, custom, current)
 func RegisterLaunchTools(server *mcp.Server) {
 	// godot_launch_main_scene - Launch project's main scene
@@ -35,6 +127,11 @@ Launch Flow:
 2. Sends configurationDone to trigger actual launch
 3. Game starts and runs until breakpoint/pause/exit
 
+The result includes a timeline of launch milestones (request sent,
+configurationDone acknowledged, launch response, process start) with elapsed
+milliseconds. Later milestones such as the first stop are reported by
+godot_get_session_state.
+
 Example: Launch main scene with default settings
 godot_launch_main_scene(project="/path/to/godot/project")
 
@@ -42,14 +139,30 @@ Example: Launch with debugging disabled
 godot_launch_main_scene(project="/path/to/project", no_debug=true)
 
 Example: Launch with profiling enabled
-godot_launch_main_scene(project="/path/to/project", profiling=true)`,
+godot_launch_main_scene(project="/path/to/project", profiling=true)
+
+Example: Pause right after startup to set up breakpoints and watches
+godot_launch_main_scene(project="/path/to/project", stop_on_entry=true)
+
+With stop_on_entry, the result's entry_stop reports whether the game paused
+and how: "adapter" if the debug adapter stopped on entry itself, or "pause" if
+it didn't (Godot currently doesn't) and the game was paused once running.
+
+Godot starts the game on configurationDone, and autoload scripts run right
+away, so by default configurationDone waits until Godot has acknowledged every
+breakpoint set so far, even ones set concurrently with the launch. Breakpoints
+Godot acknowledged without verifying are listed in unverified_breakpoints.
+
+Example: Break in an autoload's _ready
+godot_set_breakpoint(file="res://autoload/game_state.gd", line=8)
+godot_launch_main_scene(project="/path/to/project")`,
 
 		Parameters: []mcp.Parameter{
 			{
 				Name:        "project",
 				Type:        "string",
 				Required:    true,
-				Description: "Absolute path to Godot project directory (must contain project.godot)",
+				Description: "Absolute path to Godot project directory (must contain project.godot), or a name from godot_list_projects",
 			},
 			{
 				Name:        "no_debug",
@@ -79,6 +192,8 @@ godot_launch_main_scene(project="/path/to/project", profiling=true)`,
 				Default:     false,
 				Description: "Show navigation mesh",
 			},
+			stopOnEntryParam(),
+			waitForBreakpointsParam(),
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -93,6 +208,10 @@ godot_launch_main_scene(project="/path/to/project", profiling=true)`,
 			if !ok || projectPath == "" {
 				return nil, fmt.Errorf("project parameter is required and must be a string")
 			}
+			projectPath, err = resolveProject(projectPath)
+			if err != nil {
+				return nil, err
+			}
 
 			// Validate project path
 			if err := validateProjectPath(projectPath); err != nil {
@@ -101,38 +220,46 @@ godot_launch_main_scene(project="/path/to/project", profiling=true)`,
 
 			// Build launch configuration
 			config := &dap.GodotLaunchConfig{
-				Project:         projectPath,
-				Scene:           dap.SceneLaunchMain,
-				Platform:        dap.PlatformHost,
-				NoDebug:         getBoolParam(params, "no_debug"),
-				Profiling:       getBoolParam(params, "profiling"),
-				DebugCollisions: getBoolParam(params, "debug_collisions"),
-				DebugNavigation: getBoolParam(params, "debug_navigation"),
+				Project:            projectPath,
+				Scene:              dap.SceneLaunchMain,
+				Platform:           dap.PlatformHost,
+				NoDebug:            getBoolParam(params, "no_debug"),
+				Profiling:          getBoolParam(params, "profiling"),
+				DebugCollisions:    getBoolParam(params, "debug_collisions"),
+				DebugNavigation:    getBoolParam(params, "debug_navigation"),
+				WaitForBreakpoints: getBoolParam(params, "wait_for_breakpoints"),
 			}
 
 			// Launch scene
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
-			if _, err := session.LaunchGodotScene(ctx, config); err != nil {
+			entry, err := launchScene(ctx, session, config, getBoolParam(params, "stop_on_entry"))
+			if err != nil {
 				return nil, FormatError(
 					"Failed to launch main scene",
 					fmt.Sprintf("project=%s", projectPath),
-					[]string{
+					launchSuggestions(err, session,
 						"Godot editor might be busy or not responding",
 						"Project path might be incorrect",
 						"DAP connection might be unstable",
-					},
+					),
 					err,
 				)
 			}
 
-			return map[string]interface{}{
-				"status":  "launched",
-				"message": "Main scene launched successfully",
-				"project": projectPath,
-				"scene":   "main",
-			}, nil
+			result := map[string]interface{}{
+				"status":   "launched",
+				"message":  "Main scene launched successfully",
+				"project":  projectPath,
+				"scene":    "main",
+				"timeline": formatTimeline(session.GetClient().LaunchTimeline()),
+			}
+			if entry != nil {
+				result["entry_stop"] = entry
+			}
+			addUnverifiedBreakpoints(result, session)
+			return result, nil
 		},
 	})
 
@@ -178,7 +305,7 @@ godot_launch_scene(project="/path/to/project", scene="res://test.tscn", debug_co
 				Name:        "project",
 				Type:        "string",
 				Required:    true,
-				Description: "Absolute path to Godot project directory (must contain project.godot)",
+				Description: "Absolute path to Godot project directory (must contain project.godot), or a name from godot_list_projects",
 			},
 			{
 				Name:        "scene",
@@ -214,6 +341,8 @@ godot_launch_scene(project="/path/to/project", scene="res://test.tscn", debug_co
 				Default:     false,
 				Description: "Show navigation mesh",
 			},
+			stopOnEntryParam(),
+			waitForBreakpointsParam(),
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -228,6 +357,10 @@ godot_launch_scene(project="/path/to/project", scene="res://test.tscn", debug_co
 			if !ok || projectPath == "" {
 				return nil, fmt.Errorf("project parameter is required and must be a string")
 			}
+			projectPath, err = resolveProject(projectPath)
+			if err != nil {
+				return nil, err
+			}
 
 			// Validate project path
 			if err := validateProjectPath(projectPath); err != nil {
@@ -242,39 +375,47 @@ godot_launch_scene(project="/path/to/project", scene="res://test.tscn", debug_co
 
 			// Build launch configuration
 			config := &dap.GodotLaunchConfig{
-				Project:         projectPath,
-				Scene:           dap.SceneLaunchCustom,
-				ScenePath:       scenePath,
-				Platform:        dap.PlatformHost,
-				NoDebug:         getBoolParam(params, "no_debug"),
-				Profiling:       getBoolParam(params, "profiling"),
-				DebugCollisions: getBoolParam(params, "debug_collisions"),
-				DebugNavigation: getBoolParam(params, "debug_navigation"),
+				Project:            projectPath,
+				Scene:              dap.SceneLaunchCustom,
+				ScenePath:          scenePath,
+				Platform:           dap.PlatformHost,
+				NoDebug:            getBoolParam(params, "no_debug"),
+				Profiling:          getBoolParam(params, "profiling"),
+				DebugCollisions:    getBoolParam(params, "debug_collisions"),
+				DebugNavigation:    getBoolParam(params, "debug_navigation"),
+				WaitForBreakpoints: getBoolParam(params, "wait_for_breakpoints"),
 			}
 
 			// Launch scene
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
-			if _, err := session.LaunchGodotScene(ctx, config); err != nil {
+			entry, err := launchScene(ctx, session, config, getBoolParam(params, "stop_on_entry"))
+			if err != nil {
 				return nil, FormatError(
 					fmt.Sprintf("Failed to launch scene %s", scenePath),
 					fmt.Sprintf("project=%s", projectPath),
-					[]string{
+					launchSuggestions(err, session,
 						"Scene file might not exist",
 						"Scene path format might be incorrect (use res://...)",
 						"Godot editor might be busy",
-					},
+					),
 					err,
 				)
 			}
 
-			return map[string]interface{}{
-				"status":  "launched",
-				"message": fmt.Sprintf("Scene %s launched successfully", scenePath),
-				"project": projectPath,
-				"scene":   scenePath,
-			}, nil
+			result := map[string]interface{}{
+				"status":   "launched",
+				"message":  fmt.Sprintf("Scene %s launched successfully", scenePath),
+				"project":  projectPath,
+				"scene":    scenePath,
+				"timeline": formatTimeline(session.GetClient().LaunchTimeline()),
+			}
+			if entry != nil {
+				result["entry_stop"] = entry
+			}
+			addUnverifiedBreakpoints(result, session)
+			return result, nil
 		},
 	})
 
@@ -316,7 +457,7 @@ godot_launch_current_scene(project="/path/to/project", profiling=true, debug_col
 				Name:        "project",
 				Type:        "string",
 				Required:    true,
-				Description: "Absolute path to Godot project directory (must contain project.godot)",
+				Description: "Absolute path to Godot project directory (must contain project.godot), or a name from godot_list_projects",
 			},
 			{
 				Name:        "no_debug",
@@ -346,6 +487,8 @@ godot_launch_current_scene(project="/path/to/project", profiling=true, debug_col
 				Default:     false,
 				Description: "Show navigation mesh",
 			},
+			stopOnEntryParam(),
+			waitForBreakpointsParam(),
 		},
 
 		Handler: func(params map[string]interface{}) (interface{}, error) {
@@ -360,6 +503,10 @@ godot_launch_current_scene(project="/path/to/project", profiling=true, debug_col
 			if !ok || projectPath == "" {
 				return nil, fmt.Errorf("project parameter is required and must be a string")
 			}
+			projectPath, err = resolveProject(projectPath)
+			if err != nil {
+				return nil, err
+			}
 
 			// Validate project path
 			if err := validateProjectPath(projectPath); err != nil {
@@ -368,43 +515,95 @@ godot_launch_current_scene(project="/path/to/project", profiling=true, debug_col
 
 			// Build launch configuration
 			config := &dap.GodotLaunchConfig{
-				Project:         projectPath,
-				Scene:           dap.SceneLaunchCurrent,
-				Platform:        dap.PlatformHost,
-				NoDebug:         getBoolParam(params, "no_debug"),
-				Profiling:       getBoolParam(params, "profiling"),
-				DebugCollisions: getBoolParam(params, "debug_collisions"),
-				DebugNavigation: getBoolParam(params, "debug_navigation"),
+				Project:            projectPath,
+				Scene:              dap.SceneLaunchCurrent,
+				Platform:           dap.PlatformHost,
+				NoDebug:            getBoolParam(params, "no_debug"),
+				Profiling:          getBoolParam(params, "profiling"),
+				DebugCollisions:    getBoolParam(params, "debug_collisions"),
+				DebugNavigation:    getBoolParam(params, "debug_navigation"),
+				WaitForBreakpoints: getBoolParam(params, "wait_for_breakpoints"),
 			}
 
 			// Launch scene
 			ctx, cancel := dap.WithCommandTimeout(context.Background())
 			defer cancel()
 
-			if _, err := session.LaunchGodotScene(ctx, config); err != nil {
+			entry, err := launchScene(ctx, session, config, getBoolParam(params, "stop_on_entry"))
+			if err != nil {
 				return nil, FormatError(
 					"Failed to launch current scene",
 					fmt.Sprintf("project=%s", projectPath),
-					[]string{
+					launchSuggestions(err, session,
 						"No scene might be open in the editor",
 						"Godot editor might be busy",
-					},
+					),
 					err,
 				)
 			}
 
-			return map[string]interface{}{
-				"status":  "launched",
-				"message": "Current scene launched successfully",
-				"project": projectPath,
-				"scene":   "current",
-			}, nil
+			result := map[string]interface{}{
+				"status":   "launched",
+				"message":  "Current scene launched successfully",
+				"project":  projectPath,
+				"scene":    "current",
+				"timeline": formatTimeline(session.GetClient().LaunchTimeline()),
+			}
+			if entry != nil {
+				result["entry_stop"] = entry
+			}
+			addUnverifiedBreakpoints(result, session)
+			return result, nil
 		},
 	})
 }
 
+// formatTimeline converts launch milestones for tool responses
+func formatTimeline(milestones []dap.LaunchMilestone) []map[string]interface{} {
+	timeline := make([]map[string]interface{}, len(milestones))
+	for i, m := range milestones {
+		entry := map[string]interface{}{
+			"milestone":  m.Name,
+			"elapsed_ms": m.Elapsed.Milliseconds(),
+		}
+		if m.Detail != "" {
+			entry["detail"] = m.Detail
+		}
+		timeline[i] = entry
+	}
+	return timeline
+}
+
+// launchSuggestions returns the suggestions for a failed launch followed by
+// its progress: the watchdog's workarounds if the launch stalled, otherwise
+// the tool's usual suspects
+func launchSuggestions(err error, session *dap.Session, usual ...string) []string {
+	suggestions := usual
+	var stall *dap.LaunchStallError
+	if errors.As(err, &stall) {
+		suggestions = stall.Suggestions
+	}
+	return append(suggestions[:len(suggestions):len(suggestions)], describeTimeline(session.GetClient().LaunchTimeline()))
+}
+
+// describeTimeline summarizes how far a launch got, for error messages
+func describeTimeline(milestones []dap.LaunchMilestone) string {
+	if len(milestones) == 0 {
+		return "Launch progress: no milestones reached (request was not sent)"
+	}
+	steps := make([]string, len(milestones))
+	for i, m := range milestones {
+		steps[i] = fmt.Sprintf("%s (+%dms)", m.Name, m.Elapsed.Milliseconds())
+	}
+	return "Launch progress before failure: " + strings.Join(steps, " → ")
+}
+
 // validateProjectPath checks if the project path is valid and contains project.godot
 func validateProjectPath(path string) error {
+	if err := checkPathAllowed(path); err != nil {
+		return err
+	}
+
 	projectFile := filepath.Join(path, "project.godot")
 	if _, err := os.Stat(projectFile); os.IsNotExist(err) {
 		return fmt.Errorf(`Invalid project path: project.godot not found at %s
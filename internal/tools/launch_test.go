@@ -1,10 +1,15 @@
 package tools
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
 )
 
@@ -156,3 +161,47 @@ func TestLaunchTools_RequireSession(t *testing.T) {
 		t.Error("Launch tools should require an active session")
 	}
 }
+
+func TestFormatTimeline(t *testing.T) {
+	milestones := []dap.LaunchMilestone{
+		{Name: dap.MilestoneRequestSent, Detail: "launch", Elapsed: 2 * time.Millisecond},
+		{Name: dap.MilestoneConfigDoneSent, Elapsed: 5 * time.Millisecond},
+	}
+
+	timeline := formatTimeline(milestones)
+	if len(timeline) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(timeline))
+	}
+	if timeline[0]["milestone"] != dap.MilestoneRequestSent || timeline[0]["detail"] != "launch" || timeline[0]["elapsed_ms"] != int64(2) {
+		t.Errorf("unexpected first entry: %v", timeline[0])
+	}
+	if _, ok := timeline[1]["detail"]; ok {
+		t.Error("empty detail should be omitted")
+	}
+
+	desc := describeTimeline(milestones)
+	if !strings.Contains(desc, "request_sent (+2ms) → configuration_done_sent (+5ms)") {
+		t.Errorf("unexpected description: %s", desc)
+	}
+	if !strings.Contains(describeTimeline(nil), "no milestones") {
+		t.Error("empty timeline should say no milestones were reached")
+	}
+}
+
+func TestLaunchSuggestions(t *testing.T) {
+	session := dap.NewSession("localhost", 6006)
+
+	usual := launchSuggestions(errors.New("launch failed"), session, "Godot editor might be busy")
+	if len(usual) != 2 || usual[0] != "Godot editor might be busy" || !strings.Contains(usual[1], "no milestones") {
+		t.Errorf("expected the usual suggestion and the timeline, got %v", usual)
+	}
+
+	stall := &dap.LaunchStallError{Command: "launch", Suggestions: []string{"Restart the Godot editor"}}
+	stalled := launchSuggestions(fmt.Errorf("launch: %w", stall), session, "Godot editor might be busy")
+	if len(stalled) != 2 || stalled[0] != "Restart the Godot editor" {
+		t.Errorf("expected the watchdog's suggestion instead of the usual one, got %v", stalled)
+	}
+	if len(stall.Suggestions) != 1 {
+		t.Error("the stall's suggestions should not be modified")
+	}
+}
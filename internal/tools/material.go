@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// maxShaderParams caps the shader parameters read from one material
+const maxShaderParams = 64
+
+// materialProperties are the properties read from built-in materials, which
+// have no shader parameters of their own. Checked in order with is_class().
+var materialProperties = []struct {
+	class      string
+	properties []string
+}{
+	{"BaseMaterial3D", []string{"albedo_color", "albedo_texture", "metallic", "roughness", "emission_enabled", "emission", "transparency", "shading_mode", "cull_mode"}},
+	{"CanvasItemMaterial", []string{"blend_mode", "light_mode"}},
+	{"ParticleProcessMaterial", []string{"color", "emission_shape", "direction", "spread", "gravity", "initial_velocity_min", "initial_velocity_max"}},
+}
+
+// shaderUniformName matches the names in the str() of Shader.get_shader_uniform_list()
+var shaderUniformName = regexp.MustCompile(`"name": "([^"]+)"`)
+
+// parseShaderUniforms returns the uniform names from the str() of
+// Shader.get_shader_uniform_list(), in declaration order
+func parseShaderUniforms(value string) []string {
+	names := []string{}
+	for _, m := range shaderUniformName.FindAllStringSubmatch(value, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// typed evaluates node.member, returning the result as a DAP variable named
+// name so it can go through formatVariable
+func (r memberReader) typed(name, member string) (godap.Variable, error) {
+	resp, err := r.client.Evaluate(r.ctx, fmt.Sprintf("%s.%s", r.node, member), r.frameId, "watch")
+	if err != nil {
+		return godap.Variable{}, err
+	}
+	return godap.Variable{Name: name, Value: resp.Body.Result, Type: resp.Body.Type}, nil
+}
+
+// materialExpression finds where the node's material lives: the active
+// material of a mesh surface, a geometry's material_override, or a canvas
+// item's material. Returns "" if the node has none of these.
+func materialExpression(r memberReader, surface int) (string, error) {
+	candidates := []struct{ class, member string }{
+		{"MeshInstance3D", fmt.Sprintf("get_active_material(%d)", surface)},
+		{"GeometryInstance3D", "material_override"},
+		{"CanvasItem", "material"},
+	}
+	for _, c := range candidates {
+		is, err := r.eval(fmt.Sprintf("is_class(%q)", c.class))
+		if err != nil {
+			return "", err
+		}
+		if is == "true" {
+			return r.node + "." + c.member, nil
+		}
+	}
+	return "", nil
+}
+
+// readMaterialParams reads the parameters of a node's material: shader
+// uniforms for a ShaderMaterial, common properties for built-in materials.
+// Values are formatted like variables (Colors, Vectors, ...).
+func readMaterialParams(ctx context.Context, client expressionEvaluator, frameId int, node string, surface int) (map[string]interface{}, error) {
+	material, err := materialExpression(memberReader{ctx: ctx, client: client, frameId: frameId, node: node}, surface)
+	if err != nil {
+		return nil, err
+	}
+	if material == "" {
+		return nil, fmt.Errorf("%s has no material (expected a CanvasItem, GeometryInstance3D, or MeshInstance3D)", node)
+	}
+
+	m := memberReader{ctx: ctx, client: client, frameId: frameId, node: material}
+	resp, err := client.Evaluate(ctx, fmt.Sprintf("str(%s)", material), frameId, "watch")
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{"status": "success", "parameters": []map[string]interface{}{}}
+	if resp.Body.Result == "<null>" {
+		result["status"] = "no_material"
+		result["message"] = "The node has no material set"
+		return result, nil
+	}
+
+	class, err := m.eval("get_class()")
+	if err != nil {
+		return nil, err
+	}
+	class = unquoteGodotName(class)
+	result["material_class"] = class
+	if path, err := m.eval("resource_path"); err == nil && unquoteGodotName(path) != "" {
+		result["resource_path"] = unquoteGodotName(path)
+	}
+
+	var names []string
+	var read func(name string) (godap.Variable, error)
+	if class == "ShaderMaterial" {
+		shaderPath, err := m.eval("shader.resource_path")
+		if err != nil {
+			result["status"] = "no_shader"
+			result["message"] = "The ShaderMaterial has no shader"
+			return result, nil
+		}
+		result["shader"] = unquoteGodotName(shaderPath)
+		uniforms, err := m.eval("shader.get_shader_uniform_list()")
+		if err != nil {
+			return nil, err
+		}
+		names = parseShaderUniforms(uniforms)
+		read = func(name string) (godap.Variable, error) {
+			return m.typed(name, fmt.Sprintf("get_shader_parameter(%q)", name))
+		}
+	} else {
+		for _, builtin := range materialProperties {
+			if is, err := m.eval(fmt.Sprintf("is_class(%q)", builtin.class)); err == nil && is == "true" {
+				names = builtin.properties
+				break
+			}
+		}
+		read = func(name string) (godap.Variable, error) {
+			return m.typed(name, name)
+		}
+	}
+
+	if len(names) > maxShaderParams {
+		result["parameters_truncated"] = true
+		result["parameter_count"] = len(names)
+		names = names[:maxShaderParams]
+	}
+	params := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		variable, err := read(name)
+		if err != nil {
+			params = append(params, map[string]interface{}{"name": name, "error": err.Error()})
+			continue
+		}
+		params = append(params, formatVariable(variable))
+	}
+	result["parameters"] = params
+	return result, nil
+}
+
+// RegisterMaterialTools registers godot_get_material_params
+func RegisterMaterialTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_material_params",
+		Description: `Get the shader parameters of a node's material.
+
+Finds the node's material (a mesh surface's active material, a geometry's
+material_override, or a CanvasItem's material) and reads its parameters:
+- ShaderMaterial: every shader uniform, via get_shader_parameter()
+- StandardMaterial3D/ORMMaterial3D, CanvasItemMaterial,
+  ParticleProcessMaterial: their most commonly scripted properties
+
+Values are formatted like godot_get_variables results (Colors as
+Color(r=..., g=..., b=..., a=...), Vectors with named components).
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Absolute paths ("/root/Main/Player/Sprite2D") start at the scene tree's root;
+other paths are relative to the current scene.
+
+Example: Is the hit flash uniform set?
+godot_get_material_params(node_path="Player/Sprite2D")
+→ {"material_class": "ShaderMaterial", "shader": "res://shaders/flash.gdshader",
+   "parameters": [{"name": "flash_color", "type": "Color",
+                   "formatted": "Color(r=1, g=1, b=1, a=1)", ...}, ...]}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "node_path",
+				Type:        "string",
+				Required:    true,
+				Description: "Node path of the node (absolute /root/... or relative to the current scene)",
+			},
+			{
+				Name:        "surface",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Surface index for MeshInstance3D nodes (default: 0)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get material parameters"); err != nil {
+				return nil, err
+			}
+
+			path, ok := params["node_path"].(string)
+			if !ok || strings.TrimSpace(path) == "" {
+				return nil, fmt.Errorf("node_path is required and must be a non-empty string")
+			}
+			surface := 0
+			if s, ok := params["surface"].(float64); ok {
+				surface = int(s)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := readMaterialParams(ctx, session.GetClient(), 0, nodePathExpression(path), surface)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read material parameters",
+					path,
+					[]string{
+						"The path must point to a CanvasItem, GeometryInstance3D, or MeshInstance3D node",
+						"Relative paths start at the current scene; use /root/... for an absolute path",
+						"For a mesh, check the surface index (the mesh's surface count)",
+					},
+					err,
+				)
+			}
+			result["node_path"] = path
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseShaderUniforms(t *testing.T) {
+	value := `[{ "name": "flash_color", "type": 20, "hint": 0 }, { "name": "amount", "type": 3, "hint": 1 }]`
+	if got := parseShaderUniforms(value); !reflect.DeepEqual(got, []string{"flash_color", "amount"}) {
+		t.Errorf("unexpected uniforms: %v", got)
+	}
+}
+
+func TestReadMaterialParams_Shader(t *testing.T) {
+	material := "$Sprite.material"
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`$Sprite.is_class("MeshInstance3D")`:     {"false", "bool"},
+		`$Sprite.is_class("GeometryInstance3D")`: {"false", "bool"},
+		`$Sprite.is_class("CanvasItem")`:         {"true", "bool"},
+		"str(" + material + ")":                  {"<ShaderMaterial#-9223>", "String"},
+		material + ".get_class()":                {`"ShaderMaterial"`, "String"},
+		material + ".resource_path":              {`""`, "String"},
+		material + ".shader.resource_path":       {`"res://flash.gdshader"`, "String"},
+		material + ".shader.get_shader_uniform_list()": {
+			`[{ "name": "flash_color", "type": 20 }, { "name": "amount", "type": 3 }]`, "Array",
+		},
+		material + `.get_shader_parameter("flash_color")`: {"(1, 1, 1, 1)", "Color"},
+	}}
+
+	result, err := readMaterialParams(context.Background(), eval, 0, "$Sprite", 0)
+	if err != nil {
+		t.Fatalf("readMaterialParams failed: %v", err)
+	}
+	if result["material_class"] != "ShaderMaterial" || result["shader"] != "res://flash.gdshader" {
+		t.Errorf("unexpected material: %v", result)
+	}
+	if _, ok := result["resource_path"]; ok {
+		t.Error("an embedded material has no resource_path")
+	}
+	params := result["parameters"].([]map[string]interface{})
+	if len(params) != 2 || params[0]["formatted"] != "Color(r=1, g=1, b=1, a=1)" {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+	if params[1]["name"] != "amount" || params[1]["error"] == nil {
+		t.Errorf("a failed parameter should be reported with its error: %v", params[1])
+	}
+}
+
+func TestReadMaterialParams_Mesh(t *testing.T) {
+	material := "$Mesh.get_active_material(1)"
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`$Mesh.is_class("MeshInstance3D")`:       {"true", "bool"},
+		"str(" + material + ")":                  {"<StandardMaterial3D#-9224>", "String"},
+		material + ".get_class()":                {`"StandardMaterial3D"`, "String"},
+		material + ".resource_path":              {`"res://metal.tres"`, "String"},
+		material + `.is_class("BaseMaterial3D")`: {"true", "bool"},
+		material + ".albedo_color":               {"(0.5, 0.5, 0.5, 1)", "Color"},
+	}}
+
+	result, err := readMaterialParams(context.Background(), eval, 0, "$Mesh", 1)
+	if err != nil {
+		t.Fatalf("readMaterialParams failed: %v", err)
+	}
+	if result["resource_path"] != "res://metal.tres" {
+		t.Errorf("unexpected resource path: %v", result["resource_path"])
+	}
+	params := result["parameters"].([]map[string]interface{})
+	if len(params) != len(materialProperties[0].properties) || params[0]["formatted"] != "Color(r=0.5, g=0.5, b=0.5, a=1)" {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+}
+
+func TestReadMaterialParams_NoMaterial(t *testing.T) {
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`$Sprite.is_class("MeshInstance3D")`:     {"false", "bool"},
+		`$Sprite.is_class("GeometryInstance3D")`: {"false", "bool"},
+		`$Sprite.is_class("CanvasItem")`:         {"true", "bool"},
+		"str($Sprite.material)":                  {"<null>", "String"},
+	}}
+	result, err := readMaterialParams(context.Background(), eval, 0, "$Sprite", 0)
+	if err != nil || result["status"] != "no_material" {
+		t.Errorf("expected no_material, got %v (%v)", result, err)
+	}
+
+	eval = &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`$Timer.is_class("MeshInstance3D")`:     {"false", "bool"},
+		`$Timer.is_class("GeometryInstance3D")`: {"false", "bool"},
+		`$Timer.is_class("CanvasItem")`:         {"false", "bool"},
+	}}
+	if _, err := readMaterialParams(context.Background(), eval, 0, "$Timer", 0); err == nil {
+		t.Error("expected an error for a node that can't have a material")
+	}
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	godap "github.com/google/go-dap"
+)
+
+// When Godot expands an Object, each property name is prefixed with the
+// category it belongs to: "Members/health" for script variables,
+// "Constants/MAX" for script constants, and the engine class that declares
+// the property for everything else ("Node/name", "Node2D/position", ...).
+
+// splitPropertyCategory splits "Category/name" into its category and name.
+// Properties without a category return an empty category.
+func splitPropertyCategory(name string) (string, string) {
+	category, key, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", name
+	}
+	return category, key
+}
+
+// filterPropertyCategories keeps the properties whose category is in
+// categories (case-insensitive) and returns them with the number dropped.
+// Properties without a category (scope variables, array elements) are always
+// kept, since only expanded objects are categorized.
+func filterPropertyCategories(properties []godap.Variable, categories []string) ([]godap.Variable, int) {
+	if len(categories) == 0 {
+		return properties, 0
+	}
+
+	kept := make([]godap.Variable, 0, len(properties))
+	for _, property := range properties {
+		category, _ := splitPropertyCategory(property.Name)
+		if category == "" || containsFold(categories, category) {
+			kept = append(kept, property)
+		}
+	}
+	return kept, len(properties) - len(kept)
+}
+
+// propertyCategories lists the distinct categories of properties, in order
+func propertyCategories(properties []godap.Variable) []string {
+	seen := map[string]bool{}
+	categories := []string{}
+	for _, property := range properties {
+		category, _ := splitPropertyCategory(property.Name)
+		if category != "" && !seen[category] {
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// transformPropertyNames are the engine properties reported as a node's
+// transform by godot_inspect_self
+var transformPropertyNames = map[string]bool{
+	"position":         true,
+	"rotation":         true,
+	"rotation_degrees": true,
+	"scale":            true,
+	"skew":             true,
+	"transform":        true,
+	"global_position":  true,
+	"global_transform": true,
+}
+
+// summarizeNode picks the commonly needed parts out of an expanded node:
+// class, name, scene file, transform, and script members. Properties that
+// aren't present (e.g. transform on a plain Node) are omitted.
+func summarizeNode(node godap.Variable, properties []godap.Variable) map[string]interface{} {
+	summary := map[string]interface{}{
+		"class": node.Type,
+	}
+	if matches := nodeInstancePattern.FindStringSubmatch(node.Value); len(matches) == 3 {
+		summary["class"] = matches[1]
+		summary["instance_id"] = matches[2]
+	}
+
+	transform := map[string]interface{}{}
+	members := make([]map[string]interface{}, 0)
+	for _, property := range properties {
+		category, key := splitPropertyCategory(property.Name)
+		switch {
+		case category == "Members":
+			member := formatVariable(property)
+			member["name"] = key
+			members = append(members, member)
+		case category == "Node" && key == "name":
+			summary["name"] = strings.Trim(property.Value, `"`)
+		case category == "Node" && key == "scene_file_path":
+			if path := strings.Trim(property.Value, `"`); path != "" {
+				summary["scene_file_path"] = path
+			}
+		case transformPropertyNames[key] || strings.HasPrefix(category, "Transform"):
+			if formatted := formatGodotType(property.Type, property.Value); formatted != "" {
+				transform[key] = formatted
+			} else {
+				transform[key] = property.Value
+			}
+		}
+	}
+
+	if len(transform) > 0 {
+		summary["transform"] = transform
+	}
+	summary["members"] = members
+	return summary
+}
+
+// maxNodePathLookups bounds the evaluates one variable listing spends on
+// scene tree paths
+const maxNodePathLookups = 32
+
+// lookupNodePaths finds the scene tree path of each object among variables,
+// keyed by instance ID. Godot shows objects only as <Class#id>, so the path
+// is evaluated through instance_from_id. Objects that aren't nodes in the
+// tree (resources, freed or orphaned nodes) are left out.
+func lookupNodePaths(ctx context.Context, client expressionEvaluator, variables []godap.Variable) map[string]string {
+	paths := map[string]string{}
+	tried := map[string]bool{}
+	for _, variable := range variables {
+		matches := nodeInstancePattern.FindStringSubmatch(variable.Value)
+		if len(matches) != 3 || tried[matches[2]] {
+			continue
+		}
+		if len(tried) >= maxNodePathLookups {
+			break
+		}
+		id := matches[2]
+		tried[id] = true
+
+		resp, err := client.Evaluate(ctx, fmt.Sprintf("str(instance_from_id(%s).get_path())", id), 0, "watch")
+		if err != nil {
+			continue
+		}
+		// Resources have get_path() too, but return res:// paths
+		if path := unquoteGodotString(resp.Body.Result); strings.HasPrefix(path, "/") {
+			paths[id] = path
+		}
+	}
+	return paths
+}
+
+// addNodeBreadcrumbs adds the scene tree path to each formatted variable
+// that holds a node in the tree, as node_path and in formatted:
+// "Player (CharacterBody2D) @ /root/Main/Player". formatted must be
+// formatVariableList(variables).
+func addNodeBreadcrumbs(ctx context.Context, client expressionEvaluator, variables []godap.Variable, formatted []map[string]interface{}) {
+	paths := lookupNodePaths(ctx, client, variables)
+	if len(paths) == 0 {
+		return
+	}
+	for i, variable := range variables {
+		matches := nodeInstancePattern.FindStringSubmatch(variable.Value)
+		if len(matches) != 3 {
+			continue
+		}
+		path, ok := paths[matches[2]]
+		if !ok {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		formatted[i]["node_path"] = path
+		formatted[i]["formatted"] = fmt.Sprintf("%s (%s) @ %s", name, matches[1], path)
+	}
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+func TestSplitPropertyCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		key      string
+	}{
+		{"Members/health", "Members", "health"},
+		{"Node2D/position", "Node2D", "position"},
+		{"self", "", "self"},
+	}
+	for _, tt := range tests {
+		category, key := splitPropertyCategory(tt.name)
+		if category != tt.category || key != tt.key {
+			t.Errorf("splitPropertyCategory(%q) = (%q, %q), want (%q, %q)", tt.name, category, key, tt.category, tt.key)
+		}
+	}
+}
+
+func TestSummarizeNode(t *testing.T) {
+	self := godap.Variable{Name: "self", Type: "Object", Value: "<CharacterBody2D#1234>", VariablesReference: 5}
+	properties := []godap.Variable{
+		{Name: "Members/health", Type: "int", Value: "100"},
+		{Name: "Members/inventory", Type: "Array", Value: "[]", VariablesReference: 6},
+		{Name: "Constants/MAX_SPEED", Type: "float", Value: "200.0"},
+		{Name: "Node/name", Type: "StringName", Value: "Player"},
+		{Name: "Node/scene_file_path", Type: "String", Value: "res://player.tscn"},
+		{Name: "Node/process_mode", Type: "int", Value: "0"},
+		{Name: "Node2D/position", Type: "Vector2", Value: "(10, 20)"},
+		{Name: "Node2D/rotation", Type: "float", Value: "0.5"},
+	}
+
+	summary := summarizeNode(self, properties)
+
+	if summary["class"] != "CharacterBody2D" || summary["instance_id"] != "1234" {
+		t.Errorf("unexpected class: %v (id %v)", summary["class"], summary["instance_id"])
+	}
+	if summary["name"] != "Player" || summary["scene_file_path"] != "res://player.tscn" {
+		t.Errorf("unexpected name/scene: %v, %v", summary["name"], summary["scene_file_path"])
+	}
+
+	transform, ok := summary["transform"].(map[string]interface{})
+	if !ok || len(transform) != 2 || transform["rotation"] != "0.5" {
+		t.Errorf("unexpected transform: %v", summary["transform"])
+	}
+
+	members := summary["members"].([]map[string]interface{})
+	if len(members) != 2 || members[0]["name"] != "health" || members[1]["expandable"] != true {
+		t.Errorf("unexpected members: %v", members)
+	}
+}
+
+func TestFilterPropertyCategories(t *testing.T) {
+	properties := []godap.Variable{
+		{Name: "Members/health"},
+		{Name: "Node/name"},
+		{Name: "Node2D/position"},
+		{Name: "CanvasItem/visible"},
+		{Name: "0"},
+	}
+
+	if got := propertyCategories(properties); len(got) != 4 || got[0] != "Members" || got[3] != "CanvasItem" {
+		t.Errorf("unexpected categories: %v", got)
+	}
+
+	kept, dropped := filterPropertyCategories(properties, nil)
+	if len(kept) != len(properties) || dropped != 0 {
+		t.Error("no categories should keep every property")
+	}
+
+	kept, dropped = filterPropertyCategories(properties, []string{"members", "Node"})
+	if dropped != 2 || len(kept) != 3 {
+		t.Fatalf("expected 3 kept and 2 dropped, got %d kept, %d dropped", len(kept), dropped)
+	}
+	if kept[0].Name != "Members/health" || kept[1].Name != "Node/name" || kept[2].Name != "0" {
+		t.Errorf("unexpected filtered properties: %v", kept)
+	}
+}
+
+func TestAddNodeBreadcrumbs(t *testing.T) {
+	variables := []godap.Variable{
+		{Name: "player", Type: "Object", Value: "CharacterBody2D:<CharacterBody2D#101>"},
+		{Name: "target", Type: "Object", Value: "CharacterBody2D:<CharacterBody2D#101>"},
+		{Name: "texture", Type: "Object", Value: "CompressedTexture2D:<CompressedTexture2D#202>"},
+		{Name: "orphan", Type: "Object", Value: "Node2D:<Node2D#303>"},
+		{Name: "health", Type: "int", Value: "100"},
+	}
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		"str(instance_from_id(101).get_path())": {"/root/Main/Player", "String"},
+		"str(instance_from_id(202).get_path())": {"res://icon.png", "String"},
+	}}
+	formatted := formatVariableList(variables)
+	addNodeBreadcrumbs(context.Background(), eval, variables, formatted)
+
+	if formatted[0]["formatted"] != "Player (CharacterBody2D) @ /root/Main/Player" || formatted[1]["node_path"] != "/root/Main/Player" {
+		t.Errorf("expected breadcrumbs for the player, got %v and %v", formatted[0], formatted[1])
+	}
+	for _, i := range []int{2, 3, 4} {
+		if _, ok := formatted[i]["node_path"]; ok {
+			t.Errorf("%s should have no node path, got %v", variables[i].Name, formatted[i])
+		}
+	}
+	if len(eval.evaluated) != 3 {
+		t.Errorf("each instance should be looked up once, got %v", eval.evaluated)
+	}
+}
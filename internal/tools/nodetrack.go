@@ -0,0 +1,459 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// maxFreedChecks caps the is_instance_id_valid calls per subtree and stop;
+// further vanished nodes are reported as gone without telling freed from
+// moved
+const maxFreedChecks = 20
+
+// trackedNode is a node seen under a tracked subtree
+type trackedNode struct {
+	Name  string `json:"name"`
+	Class string `json:"class"`
+	ID    int64  `json:"instance_id"`
+}
+
+// nodeReport describes how a tracked subtree changed since the previous stop
+type nodeReport struct {
+	Time     time.Time
+	Reason   string // Stop reason, or "baseline" for the first snapshot
+	Count    int
+	Created  []trackedNode
+	Freed    []trackedNode // Instance no longer valid
+	Removed  []trackedNode // Still valid, but no longer under the subtree
+	Gone     []trackedNode // Vanished; not checked (over maxFreedChecks)
+	Err      string
+	Baseline bool
+}
+
+// trackedSubtree is the state of one godot_track_nodes prefix
+type trackedSubtree struct {
+	nodes   map[int64]trackedNode // At the last snapshot; nil before the first
+	last    *nodeReport
+	created int // Totals since tracking started
+	freed   int
+	removed int
+}
+
+// nodeTracker snapshots the nodes under the tracked subtrees at every stop
+// and reports which were created or freed since the previous stop
+type nodeTracker struct {
+	mu       sync.Mutex
+	prefixes []string // Registration order
+	subtrees map[string]*trackedSubtree
+	stop     chan struct{}
+}
+
+// Tracked subtrees for godot_track_nodes
+var nodeTracking = nodeTracker{subtrees: map[string]*trackedSubtree{}}
+
+// nodeStringPattern matches Godot's Node string form, "Name:<Class#id>"
+var nodeStringPattern = regexp.MustCompile(`([^\[\],]*?):<(\w+)#(\d+)>`)
+
+// parseNodeList extracts the nodes from the str() of a node or an array of nodes
+func parseNodeList(value string) []trackedNode {
+	var nodes []trackedNode
+	for _, m := range nodeStringPattern.FindAllStringSubmatch(value, -1) {
+		id, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, trackedNode{Name: strings.TrimSpace(m[1]), Class: m[2], ID: id})
+	}
+	return nodes
+}
+
+// nodePathExpression returns the expression for a node path: absolute
+// paths ("/root/Main/Enemies") start at the scene tree's root, others at the
+// current scene
+func nodePathExpression(prefix string) string {
+	if strings.HasPrefix(prefix, "/") {
+		return fmt.Sprintf("Engine.get_main_loop().root.get_node_or_null(%s)", strconv.Quote(prefix))
+	}
+	return fmt.Sprintf("Engine.get_main_loop().current_scene.get_node_or_null(%s)", strconv.Quote(prefix))
+}
+
+// listSubtree returns the node at prefix and all its descendants
+func listSubtree(ctx context.Context, client expressionEvaluator, frameId int, prefix string) (map[int64]trackedNode, error) {
+	root := nodePathExpression(prefix)
+	resp, err := client.Evaluate(ctx, fmt.Sprintf("str(%s)", root), frameId, "watch")
+	if err != nil {
+		return nil, err
+	}
+	rootNodes := parseNodeList(resp.Body.Result)
+	if len(rootNodes) == 0 {
+		return nil, fmt.Errorf("no node at %s", prefix)
+	}
+
+	resp, err = client.Evaluate(ctx, fmt.Sprintf(`str(%s.find_children("*", "", true, false))`, root), frameId, "watch")
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[int64]trackedNode{rootNodes[0].ID: rootNodes[0]}
+	for _, node := range parseNodeList(resp.Body.Result) {
+		nodes[node.ID] = node
+	}
+	return nodes, nil
+}
+
+// diffNodes compares two snapshots. Vanished nodes are checked with
+// is_instance_id_valid (up to maxFreedChecks) to tell freed from moved.
+func diffNodes(ctx context.Context, client expressionEvaluator, frameId int, before, after map[int64]trackedNode) (created, freed, removed, gone []trackedNode) {
+	for id, node := range after {
+		if _, ok := before[id]; !ok {
+			created = append(created, node)
+		}
+	}
+	var vanished []trackedNode
+	for id, node := range before {
+		if _, ok := after[id]; !ok {
+			vanished = append(vanished, node)
+		}
+	}
+	sortNodes(created)
+	sortNodes(vanished)
+
+	for i, node := range vanished {
+		if i >= maxFreedChecks {
+			gone = append(gone, vanished[i:]...)
+			break
+		}
+		resp, err := client.Evaluate(ctx, fmt.Sprintf("is_instance_id_valid(%d)", node.ID), frameId, "watch")
+		switch {
+		case err != nil:
+			gone = append(gone, node)
+		case resp.Body.Result == "true":
+			removed = append(removed, node)
+		default:
+			freed = append(freed, node)
+		}
+	}
+	return created, freed, removed, gone
+}
+
+// sortNodes orders nodes by instance id, i.e. roughly by creation
+func sortNodes(nodes []trackedNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+}
+
+// add starts tracking prefix; returns false if it is already tracked
+func (nt *nodeTracker) add(prefix string) bool {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	if _, ok := nt.subtrees[prefix]; ok {
+		return false
+	}
+	nt.prefixes = append(nt.prefixes, prefix)
+	nt.subtrees[prefix] = &trackedSubtree{}
+	return true
+}
+
+// remove stops tracking prefix and drops its state
+func (nt *nodeTracker) remove(prefix string) bool {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	if _, ok := nt.subtrees[prefix]; !ok {
+		return false
+	}
+	delete(nt.subtrees, prefix)
+	for i, p := range nt.prefixes {
+		if p == prefix {
+			nt.prefixes = append(nt.prefixes[:i], nt.prefixes[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the tracked prefixes in registration order
+func (nt *nodeTracker) list() []string {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	return append([]string(nil), nt.prefixes...)
+}
+
+// previous returns a prefix's last snapshot; ok is false if it isn't tracked
+func (nt *nodeTracker) previous(prefix string) (map[int64]trackedNode, bool) {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	subtree, ok := nt.subtrees[prefix]
+	if !ok {
+		return nil, false
+	}
+	return subtree.nodes, true
+}
+
+// record stores a snapshot and its report, unless the prefix was untracked
+// in the meantime. A failed snapshot keeps the previous nodes.
+func (nt *nodeTracker) record(prefix string, nodes map[int64]trackedNode, report nodeReport) {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	subtree, ok := nt.subtrees[prefix]
+	if !ok {
+		return
+	}
+	if report.Err == "" {
+		subtree.nodes = nodes
+		subtree.created += len(report.Created)
+		subtree.freed += len(report.Freed)
+		subtree.removed += len(report.Removed)
+	}
+	subtree.last = &report
+}
+
+// sampleSubtree snapshots one prefix in frameId and records how it changed
+func (nt *nodeTracker) sampleSubtree(ctx context.Context, client expressionEvaluator, frameId int, prefix string, reason string) {
+	before, ok := nt.previous(prefix)
+	if !ok {
+		return
+	}
+	report := nodeReport{Time: time.Now(), Reason: reason}
+	nodes, err := listSubtree(ctx, client, frameId, prefix)
+	switch {
+	case err != nil:
+		report.Err = err.Error()
+	case before == nil:
+		report.Baseline = true
+		report.Count = len(nodes)
+	default:
+		report.Count = len(nodes)
+		report.Created, report.Freed, report.Removed, report.Gone = diffNodes(ctx, client, frameId, before, nodes)
+	}
+	nt.record(prefix, nodes, report)
+}
+
+// sample snapshots every tracked subtree in the top frame of threadId
+func (nt *nodeTracker) sample(client watchEvaluator, threadId int, reason string, prefixes []string) {
+	if len(prefixes) == 0 {
+		return
+	}
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	frameId := 0
+	if resp, err := client.StackTrace(ctx, threadId, 0, 1); err == nil && len(resp.Body.StackFrames) > 0 {
+		frameId = resp.Body.StackFrames[0].Id
+	}
+	for _, prefix := range prefixes {
+		nt.sampleSubtree(ctx, client, frameId, prefix, reason)
+	}
+}
+
+// reset drops every subtree's snapshots and totals, keeping the prefixes.
+// Instance IDs from an earlier game mean nothing in the next one.
+func (nt *nodeTracker) reset() {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	for prefix := range nt.subtrees {
+		nt.subtrees[prefix] = &trackedSubtree{}
+	}
+}
+
+// attach starts snapshotting on the client's stopped events, replacing any
+// previous attachment. Snapshots from an earlier game are dropped.
+func (nt *nodeTracker) attach(client *dap.Client) {
+	nt.reset()
+
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+
+	if nt.stop != nil {
+		close(nt.stop)
+	}
+	stop := make(chan struct{})
+	nt.stop = stop
+
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("node tracker"))
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				switch e := msg.(type) {
+				case *godap.StoppedEvent:
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					nt.sample(client, threadId, e.Body.Reason, nt.list())
+				case *godap.TerminatedEvent:
+					nt.reset()
+				}
+			}
+		}
+	}()
+}
+
+// detach stops snapshotting; tracked prefixes and their state are kept
+func (nt *nodeTracker) detach() {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	if nt.stop != nil {
+		close(nt.stop)
+		nt.stop = nil
+	}
+}
+
+// attached reports whether subtrees are being snapshotted
+func (nt *nodeTracker) attached() bool {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	return nt.stop != nil
+}
+
+// formatNodeReport converts a prefix's latest report and totals for tool
+// responses
+func (nt *nodeTracker) formatNodeReport(prefix string) map[string]interface{} {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	result := map[string]interface{}{"path_prefix": prefix}
+	subtree, ok := nt.subtrees[prefix]
+	if !ok {
+		return result
+	}
+	result["totals"] = map[string]interface{}{
+		"created": subtree.created,
+		"freed":   subtree.freed,
+		"removed": subtree.removed,
+	}
+	report := subtree.last
+	if report == nil {
+		result["message"] = "No snapshot yet: the subtree is snapshotted at the next stop"
+		return result
+	}
+	result["time"] = report.Time.Format(time.RFC3339Nano)
+	result["reason"] = report.Reason
+	if report.Err != "" {
+		result["error"] = report.Err
+		return result
+	}
+	result["count"] = report.Count
+	if report.Baseline {
+		result["baseline"] = true
+		return result
+	}
+	result["created"] = nodesOrEmpty(report.Created)
+	result["freed"] = nodesOrEmpty(report.Freed)
+	if len(report.Removed) > 0 {
+		result["removed"] = report.Removed
+	}
+	if len(report.Gone) > 0 {
+		result["gone_unchecked"] = report.Gone
+	}
+	return result
+}
+
+// nodesOrEmpty keeps empty lists as [] rather than null in JSON
+func nodesOrEmpty(nodes []trackedNode) []trackedNode {
+	if nodes == nil {
+		return []trackedNode{}
+	}
+	return nodes
+}
+
+// RegisterNodeTrackingTools registers godot_track_nodes
+func RegisterNodeTrackingTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_track_nodes",
+		Description: `Track nodes created and freed under a subtree between stops.
+
+Once a subtree is tracked, the instance IDs of the node at path_prefix and all
+its descendants are snapshotted at every stop (breakpoint, step, pause). Each
+call returns what changed between the last two stops: created nodes, freed
+nodes, and nodes removed from the subtree but still alive, plus running
+totals. Useful for hunting node leaks (counts that only grow) and unexpected
+instantiation.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Absolute paths ("/root/Main/Enemies") start at the scene tree's root; other
+paths ("Enemies", "." for the scene itself) are relative to the current scene.
+If the game is paused when tracking starts, the first snapshot (baseline) is
+taken right away; otherwise at the next stop.
+
+Example: Watch the bullets container for leaks
+godot_track_nodes(path_prefix="/root/Main/Bullets")
+godot_continue()  # ...until the next breakpoint
+godot_track_nodes(path_prefix="/root/Main/Bullets")
+→ {"created": [{"name": "Bullet7", "class": "Area2D", "instance_id": 3221}],
+   "freed": [], "count": 12, "totals": {"created": 7, "freed": 0, ...}}
+
+Example: Stop tracking
+godot_track_nodes(path_prefix="/root/Main/Bullets", untrack=true)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "path_prefix",
+				Type:        "string",
+				Required:    true,
+				Description: "Node path of the subtree to track (absolute /root/... or relative to the current scene)",
+			},
+			{
+				Name:        "untrack",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, stop tracking the subtree",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			prefix, ok := params["path_prefix"].(string)
+			if !ok || strings.TrimSpace(prefix) == "" {
+				return nil, fmt.Errorf("path_prefix is required and must be a non-empty string")
+			}
+
+			if getBoolParam(params, "untrack") {
+				if !nodeTracking.remove(prefix) {
+					return nil, fmt.Errorf("%s is not tracked (tracked: %s)", prefix, strings.Join(nodeTracking.list(), ", "))
+				}
+				if len(nodeTracking.list()) == 0 {
+					nodeTracking.detach()
+				}
+				return map[string]interface{}{
+					"status":      "untracked",
+					"path_prefix": prefix,
+				}, nil
+			}
+
+			client := session.GetClient()
+			status := "tracking"
+			added := nodeTracking.add(prefix)
+			if !nodeTracking.attached() {
+				nodeTracking.attach(client)
+			}
+			if added {
+				status = "started"
+				if client.RunState() == dap.RunStatePaused {
+					nodeTracking.sample(client, 1, "baseline", []string{prefix})
+				}
+			}
+
+			result := nodeTracking.formatNodeReport(prefix)
+			result["status"] = status
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestParseNodeList(t *testing.T) {
+	nodes := parseNodeList("[Bullet:<Area2D#3221>, Enemy 2:<CharacterBody2D#4410>, @Node2D@7:<Node2D#5002>]")
+	if len(nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %+v", nodes)
+	}
+	if nodes[0] != (trackedNode{Name: "Bullet", Class: "Area2D", ID: 3221}) {
+		t.Errorf("unexpected first node: %+v", nodes[0])
+	}
+	if nodes[1].Name != "Enemy 2" || nodes[2].Name != "@Node2D@7" {
+		t.Errorf("unexpected names: %+v", nodes)
+	}
+	if got := parseNodeList("<null>"); len(got) != 0 {
+		t.Errorf("expected no nodes for null, got %+v", got)
+	}
+}
+
+func TestNodePathExpression(t *testing.T) {
+	if got := nodePathExpression("/root/Main"); got != `Engine.get_main_loop().root.get_node_or_null("/root/Main")` {
+		t.Errorf("unexpected absolute expression: %s", got)
+	}
+	if got := nodePathExpression("Enemies"); got != `Engine.get_main_loop().current_scene.get_node_or_null("Enemies")` {
+		t.Errorf("unexpected relative expression: %s", got)
+	}
+}
+
+func TestNodeTracker_CreatedAndFreed(t *testing.T) {
+	nt := nodeTracker{subtrees: map[string]*trackedSubtree{}}
+	if !nt.add("/root/Main/Bullets") || nt.add("/root/Main/Bullets") {
+		t.Fatal("add should track a prefix once")
+	}
+
+	root := nodePathExpression("/root/Main/Bullets")
+	rootKey := "str(" + root + ")"
+	childrenKey := `str(` + root + `.find_children("*", "", true, false))`
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		rootKey:     "Bullets:<Node2D#100>",
+		childrenKey: "[A:<Area2D#101>, B:<Area2D#102>]",
+	}}
+
+	nt.sample(eval, 1, "breakpoint", nt.list())
+	report := nt.formatNodeReport("/root/Main/Bullets")
+	if report["baseline"] != true || report["count"] != 3 {
+		t.Fatalf("expected a baseline of 3 nodes, got %v", report)
+	}
+
+	// A was freed, B moved elsewhere, C and D were created
+	eval.values[childrenKey] = "[C:<Area2D#103>, D:<Area2D#104>]"
+	eval.values["is_instance_id_valid(101)"] = "false"
+	eval.values["is_instance_id_valid(102)"] = "true"
+	nt.sample(eval, 1, "step", nt.list())
+
+	report = nt.formatNodeReport("/root/Main/Bullets")
+	created := report["created"].([]trackedNode)
+	freed := report["freed"].([]trackedNode)
+	removed := report["removed"].([]trackedNode)
+	if len(created) != 2 || created[0].Name != "C" || created[1].Name != "D" {
+		t.Errorf("unexpected created nodes: %+v", created)
+	}
+	if len(freed) != 1 || freed[0].ID != 101 {
+		t.Errorf("unexpected freed nodes: %+v", freed)
+	}
+	if len(removed) != 1 || removed[0].ID != 102 {
+		t.Errorf("unexpected removed nodes: %+v", removed)
+	}
+	totals := report["totals"].(map[string]interface{})
+	if totals["created"] != 2 || totals["freed"] != 1 || totals["removed"] != 1 {
+		t.Errorf("unexpected totals: %v", totals)
+	}
+
+	// A stop without changes reports empty lists but keeps the totals
+	nt.sample(eval, 1, "step", nt.list())
+	report = nt.formatNodeReport("/root/Main/Bullets")
+	if len(report["created"].([]trackedNode)) != 0 || len(report["freed"].([]trackedNode)) != 0 {
+		t.Errorf("expected no changes, got %v", report)
+	}
+	if report["totals"].(map[string]interface{})["created"] != 2 {
+		t.Errorf("totals should accumulate, got %v", report["totals"])
+	}
+}
+
+func TestNodeTracker_MissingSubtree(t *testing.T) {
+	nt := nodeTracker{subtrees: map[string]*trackedSubtree{}}
+	nt.add("Enemies")
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		"str(" + nodePathExpression("Enemies") + ")": "<null>",
+	}}
+
+	nt.sample(eval, 1, "breakpoint", nt.list())
+	report := nt.formatNodeReport("Enemies")
+	if report["error"] != "no node at Enemies" {
+		t.Errorf("expected a missing-node error, got %v", report)
+	}
+
+	nt.reset()
+	if _, ok := nt.formatNodeReport("Enemies")["message"]; !ok {
+		t.Error("reset should drop the last report")
+	}
+	if !nt.remove("Enemies") || nt.remove("Enemies") || len(nt.list()) != 0 {
+		t.Error("remove should untrack a prefix once")
+	}
+}
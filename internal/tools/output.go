@@ -0,0 +1,742 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// Output formats accepted by godot_get_output
+const (
+	outputFormatPlain    = "plain"
+	outputFormatMarkdown = "markdown"
+	outputFormatRaw      = "raw"
+)
+
+// Precompiled patterns for markup in Godot's printed output
+var (
+	// ANSI CSI sequences (colors, cursor movement): ESC [ params final-byte
+	ansiCSIPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+	// ANSI OSC sequences (e.g. hyperlinks): ESC ] ... BEL or ESC \
+	ansiOSCPattern = regexp.MustCompile(`\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+	// print_rich() BBCode tags. Only known tag names are matched so that
+	// printed arrays like "[1, 2]" are left alone.
+	bbcodeTagPattern = regexp.MustCompile(`\[/?(?:b|i|u|s|code|p|center|left|right|fill|indent|ul|ol|color|bgcolor|fgcolor|font|font_size|outline_size|outline_color|url|hint|img|table|cell|wave|tornado|shake|fade|rainbow|pulse)(?:[= ][^\[\]]*)?\]`)
+
+	// [url=target]text[/url] and [url]target[/url]
+	bbcodeURLPattern     = regexp.MustCompile(`\[url=([^\[\]]+)\](.*?)\[/url\]`)
+	bbcodeBareURLPattern = regexp.MustCompile(`\[url\](.*?)\[/url\]`)
+)
+
+// bbcodeMarkdownReplacer converts the BBCode tags that have a markdown equivalent
+var bbcodeMarkdownReplacer = strings.NewReplacer(
+	"[b]", "**", "[/b]", "**",
+	"[i]", "*", "[/i]", "*",
+	"[s]", "~~", "[/s]", "~~",
+	"[code]", "`", "[/code]", "`",
+)
+
+// stripANSI removes ANSI escape sequences
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	s = ansiOSCPattern.ReplaceAllString(s, "")
+	return ansiCSIPattern.ReplaceAllString(s, "")
+}
+
+// unescapeBBCodeBrackets turns [lb]/[rb] back into literal brackets.
+// Must run last so the produced brackets aren't mistaken for tags.
+func unescapeBBCodeBrackets(s string) string {
+	return strings.NewReplacer("[lb]", "[", "[rb]", "]").Replace(s)
+}
+
+// stripOutputMarkup converts Godot output to plain text by removing ANSI
+// sequences and print_rich() BBCode tags
+func stripOutputMarkup(s string) string {
+	s = stripANSI(s)
+	s = bbcodeTagPattern.ReplaceAllString(s, "")
+	return unescapeBBCodeBrackets(s)
+}
+
+// outputMarkupToMarkdown converts Godot output to markdown: bold, italic,
+// strikethrough, code, and links are kept; other markup is removed
+func outputMarkupToMarkdown(s string) string {
+	s = stripANSI(s)
+	s = bbcodeURLPattern.ReplaceAllString(s, "[$2]($1)")
+	s = bbcodeBareURLPattern.ReplaceAllString(s, "<$1>")
+	s = bbcodeMarkdownReplacer.Replace(s)
+	s = bbcodeTagPattern.ReplaceAllString(s, "")
+	return unescapeBBCodeBrackets(s)
+}
+
+// renderOutput renders raw game output in the requested format
+func renderOutput(s string, format string) string {
+	switch format {
+	case outputFormatRaw:
+		return s
+	case outputFormatMarkdown:
+		return outputMarkupToMarkdown(s)
+	default:
+		return stripOutputMarkup(s)
+	}
+}
+
+// Severities reported by godot_get_errors
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// diagnostic is a distinct error or warning message with its repeat count.
+// Per-frame warnings repeat thousands of times, so identical messages are
+// collapsed into one entry instead of being returned individually.
+type diagnostic struct {
+	Severity  string
+	Message   string
+	Category  string
+	Count     int
+	FirstSeq  int
+	LastSeq   int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	File      string // res:// script the message points at, if any
+	Line      int
+}
+
+// scriptLocationPattern matches a script location in an error, as in
+// "at: _ready (res://player.gd:12)" or a backtrace's "[0] _ready (res://player.gd:12)"
+var scriptLocationPattern = regexp.MustCompile(`(res://[^\s:()]+):(\d+)`)
+
+// isErrorContinuation reports whether an output line continues the previous
+// error: Godot prints "at: ..." and the GDScript backtrace as separate lines
+func isErrorContinuation(text string) bool {
+	if strings.HasPrefix(text, "at:") || strings.HasPrefix(text, "GDScript backtrace") {
+		return true
+	}
+	return strings.HasPrefix(text, "[") && strings.Contains(text, "] ") && scriptLocationPattern.MatchString(text)
+}
+
+// locate records the first script location found in text
+func (d *diagnostic) locate(text string) {
+	if d.File != "" {
+		return
+	}
+	if m := scriptLocationPattern.FindStringSubmatch(text); m != nil {
+		d.File = m[1]
+		d.Line, _ = strconv.Atoi(m[2])
+	}
+}
+
+// classifyOutput returns the severity of an output line, or "" if it is
+// regular output. Godot prefixes push_error()/push_warning() and script
+// errors with ERROR:/WARNING:, and sends engine errors on stderr.
+func classifyOutput(category string, text string) string {
+	upper := strings.ToUpper(strings.TrimSpace(text))
+	for _, prefix := range []string{"WARNING:", "USER WARNING:", "SCRIPT WARNING:"} {
+		if strings.HasPrefix(upper, prefix) {
+			return severityWarning
+		}
+	}
+	for _, prefix := range []string{"ERROR:", "USER ERROR:", "SCRIPT ERROR:"} {
+		if strings.HasPrefix(upper, prefix) {
+			return severityError
+		}
+	}
+	if category == "stderr" {
+		return severityError
+	}
+	return ""
+}
+
+// aggregateDiagnostics collects the errors and warnings in the output and
+// collapses identical messages. Entries are ordered by first occurrence.
+// "at:" and backtrace lines following a message are folded into it, giving
+// its script location.
+func aggregateDiagnostics(records []dap.OutputRecord) []*diagnostic {
+	var diagnostics []*diagnostic
+	byKey := make(map[string]*diagnostic)
+	var previous *diagnostic
+
+	for _, r := range records {
+		text := strings.TrimSpace(stripOutputMarkup(r.Output))
+		if text == "" {
+			continue
+		}
+		if previous != nil && isErrorContinuation(text) {
+			previous.locate(text)
+			continue
+		}
+		severity := classifyOutput(r.Category, text)
+		if severity == "" {
+			previous = nil
+			continue
+		}
+
+		key := severity + "\x00" + text
+		if d, ok := byKey[key]; ok {
+			d.Count++
+			d.LastSeq = r.Seq
+			d.LastSeen = r.Time
+			previous = d
+			continue
+		}
+
+		d := &diagnostic{
+			Severity:  severity,
+			Message:   text,
+			Category:  r.Category,
+			Count:     1,
+			FirstSeq:  r.Seq,
+			LastSeq:   r.Seq,
+			FirstSeen: r.Time,
+			LastSeen:  r.Time,
+		}
+		d.locate(text)
+		byKey[key] = d
+		diagnostics = append(diagnostics, d)
+		previous = d
+	}
+
+	return diagnostics
+}
+
+// addDiagnosticSource adds a diagnostic's script location to entry, with a
+// snippet of the source around it when the file can be read
+func addDiagnosticSource(entry map[string]interface{}, d *diagnostic, projectRoot string) {
+	if d.File == "" {
+		return
+	}
+	entry["file"] = d.File
+	entry["line"] = d.Line
+	if path, err := resolveGodotPath(d.File, projectRoot); err == nil {
+		if snippet := sourceSnippet(path, d.Line, whereSnippetContext); snippet != "" {
+			entry["snippet"] = snippet
+		}
+	}
+}
+
+// lastLocatedDiagnostic returns the diagnostic with a script location that
+// was printed most recently, limited to severity unless it is "all"
+func lastLocatedDiagnostic(diagnostics []*diagnostic, severity string) *diagnostic {
+	var last *diagnostic
+	for _, d := range diagnostics {
+		if d.File == "" || (severity != "all" && d.Severity != severity) {
+			continue
+		}
+		if last == nil || d.LastSeq > last.LastSeq {
+			last = d
+		}
+	}
+	return last
+}
+
+// notifier sends notifications to the MCP client (implemented by *mcp.Server)
+type notifier interface {
+	Notify(method string, params map[string]interface{}) error
+}
+
+// outputFollower streams output events to the MCP client while enabled.
+// At most one stream is active; enabling again replaces it.
+type outputFollower struct {
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// Active output stream for godot_follow_output
+var follower outputFollower
+
+// start begins forwarding the client's output events, replacing any active stream
+func (f *outputFollower) start(client *dap.Client, n notifier, format string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stop != nil {
+		close(f.stop)
+	}
+	stop := make(chan struct{})
+	f.stop = stop
+
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("output follower"))
+	go func() {
+		defer cleanup()
+		forwardOutput(events, stop, n, format)
+	}()
+}
+
+// stopFollowing ends the active stream; returns false if none was active
+func (f *outputFollower) stopFollowing() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stop == nil {
+		return false
+	}
+	close(f.stop)
+	f.stop = nil
+	return true
+}
+
+// forwardOutput sends each output event as a notifications/message log
+// notification until stop is closed. Errors and warnings are sent at the
+// matching log level so clients can surface them differently.
+func forwardOutput(events <-chan godap.Message, stop <-chan struct{}, n notifier, format string) {
+	for {
+		select {
+		case <-stop:
+			return
+		case msg := <-events:
+			e, ok := msg.(*godap.OutputEvent)
+			if !ok {
+				continue
+			}
+
+			level := "info"
+			if severity := classifyOutput(e.Body.Category, stripOutputMarkup(e.Body.Output)); severity != "" {
+				level = severity
+			}
+
+			err := n.Notify("notifications/message", map[string]interface{}{
+				"level":  level,
+				"logger": "godot",
+				"data": map[string]interface{}{
+					"category": e.Body.Category,
+					"output":   renderOutput(e.Body.Output, format),
+				},
+			})
+			if err != nil {
+				log.Printf("Failed to forward game output: %v", err)
+			}
+		}
+	}
+}
+
+// filterOutput returns the records whose category is in categories (all if
+// empty), keeping only the last tail of them if tail is positive
+func filterOutput(records []dap.OutputRecord, categories []string, tail int) []dap.OutputRecord {
+	if len(categories) > 0 {
+		wanted := make(map[string]bool, len(categories))
+		for _, c := range categories {
+			wanted[c] = true
+		}
+		var filtered []dap.OutputRecord
+		for _, r := range records {
+			if wanted[r.Category] {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+	if tail > 0 && len(records) > tail {
+		records = records[len(records)-tail:]
+	}
+	return records
+}
+
+// RegisterOutputTools registers tools for reading the game's captured output
+func RegisterOutputTools(server *mcp.Server) {
+	// godot_get_output - Read captured output events
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_output",
+		Description: `Get the output printed by the debugged game.
+
+Godot forwards the game's print(), print_rich(), push_warning(), and push_error()
+output as DAP output events. This tool returns the most recent ones (up to 1000).
+
+Output is cleaned before it is returned: ANSI color sequences and print_rich()
+BBCode tags are stripped. Use format="markdown" to keep bold, italic, code, and
+links as markdown, or format="raw" to get the output exactly as Godot sent it.
+
+Each entry is one output event, usually one printed line. Pass tail to get
+only the last entries, and category to keep only some streams: "stdout" for
+print(), "stderr" for errors and warnings, "console" for logpoint messages
+and the debugger's own notes.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To read print() debugging output from the game
+- To check for runtime errors after a test run
+- After the game exits, to see what it printed
+
+Example: Get output as plain text
+godot_get_output()
+
+Example: Keep print_rich() formatting as markdown
+godot_get_output(format="markdown")
+
+Example: Last 20 errors the game printed
+godot_get_output(category=["stderr"], tail=20)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "format",
+				Type:        "string",
+				Required:    false,
+				Default:     outputFormatPlain,
+				Description: "Output format: 'plain' (markup stripped), 'markdown' (BBCode converted), or 'raw' (default: 'plain')",
+				Enum:        []string{outputFormatPlain, outputFormatMarkdown, outputFormatRaw},
+			},
+			{
+				Name:        "tail",
+				Type:        "number",
+				Required:    false,
+				Description: "Return only the last N entries (default: all buffered)",
+				Minimum:     mcp.Float64(1),
+			},
+			{
+				Name:        "category",
+				Type:        "array",
+				Required:    false,
+				Items:       &mcp.Parameter{Type: "string"},
+				Description: "Output categories to return, e.g. [\"stdout\"] or [\"stderr\"] (default: all)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			categories, err := getStringListParam(params, "category")
+			if err != nil {
+				return nil, err
+			}
+			tail := 0
+			if t, ok := params["tail"].(float64); ok && t > 0 {
+				tail = int(t)
+			}
+
+			format := outputFormatPlain
+			if f, ok := params["format"].(string); ok && f != "" {
+				format = f
+			}
+			switch format {
+			case outputFormatPlain, outputFormatMarkdown, outputFormatRaw:
+			default:
+				return nil, fmt.Errorf("invalid format '%s': must be 'plain', 'markdown', or 'raw'", format)
+			}
+
+			all := session.GetClient().Output()
+			records := filterOutput(all, categories, tail)
+
+			lines := make([]map[string]interface{}, len(records))
+			for i, r := range records {
+				lines[i] = map[string]interface{}{
+					"seq":      r.Seq,
+					"category": r.Category,
+					"output":   renderOutput(r.Output, format),
+					"time":     r.Time.Format(time.RFC3339),
+				}
+			}
+
+			return map[string]interface{}{
+				"status": "success",
+				"format": format,
+				"output": lines,
+				"count":  len(lines),
+				"total":  len(all),
+			}, nil
+		},
+	})
+
+	// godot_get_errors - Deduplicated errors and warnings from the output
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_errors",
+		Description: `Get the errors and warnings printed by the debugged game, deduplicated.
+
+This tool scans the captured game output (see godot_get_output) for errors and
+warnings (push_error(), push_warning(), script errors, and engine errors on
+stderr). Identical messages are collapsed into a single entry with a repeat
+count and first/last timestamps, so a warning printed every frame doesn't
+flood the response.
+
+Messages that point at a script location ("at: _ready (res://player.gd:12)",
+or a GDScript backtrace) include file, line, and a snippet of the source
+around it. godot_break_at_last_error sets a breakpoint there.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Use this tool:
+- To check whether a test run produced errors
+- To find the warnings that repeat most often
+- Before digging through the full output with godot_get_output
+
+Example: Get all errors and warnings
+godot_get_errors()
+
+Example: Get only errors
+godot_get_errors(severity="error")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "severity",
+				Type:        "string",
+				Required:    false,
+				Default:     "all",
+				Description: "Which messages to return: 'error', 'warning', or 'all' (default: 'all')",
+				Enum:        []string{"error", "warning", "all"},
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			severity := "all"
+			if s, ok := params["severity"].(string); ok && s != "" {
+				severity = s
+			}
+			switch severity {
+			case "all", severityError, severityWarning:
+			default:
+				return nil, fmt.Errorf("invalid severity '%s': must be 'error', 'warning', or 'all'", severity)
+			}
+
+			diagnostics := aggregateDiagnostics(session.GetClient().Output())
+
+			entries := make([]map[string]interface{}, 0, len(diagnostics))
+			errorCount, warningCount := 0, 0
+			for _, d := range diagnostics {
+				if d.Severity == severityError {
+					errorCount += d.Count
+				} else {
+					warningCount += d.Count
+				}
+				if severity != "all" && d.Severity != severity {
+					continue
+				}
+				entry := map[string]interface{}{
+					"severity":   d.Severity,
+					"message":    d.Message,
+					"category":   d.Category,
+					"count":      d.Count,
+					"first_seq":  d.FirstSeq,
+					"last_seq":   d.LastSeq,
+					"first_seen": d.FirstSeen.Format(time.RFC3339),
+					"last_seen":  d.LastSeen.Format(time.RFC3339),
+				}
+				addDiagnosticSource(entry, d, session.GetProjectRoot())
+				entries = append(entries, entry)
+			}
+
+			return map[string]interface{}{
+				"status":        "success",
+				"errors":        entries,
+				"unique":        len(entries),
+				"error_count":   errorCount,
+				"warning_count": warningCount,
+			}, nil
+		},
+	})
+
+	// godot_break_at_last_error - Breakpoint at the most recent error's location
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_break_at_last_error",
+		Description: `Set a breakpoint where the most recent error was reported.
+
+Finds the last error (see godot_get_errors) that points at a script location
+and sets a breakpoint at that file and line, keeping the other breakpoints in
+the file. Run the code path again to stop just before the error happens.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- An error with a res:// location must have been printed
+
+Godot reports the line that failed, so the breakpoint stops before that line
+runs; the values that cause the error can then be inspected.
+
+Example: Stop where the last script error happened
+godot_get_errors(severity="error")
+godot_break_at_last_error()
+→ {"status": "verified", "file": "res://scripts/player.gd", "line": 42,
+   "error": "SCRIPT ERROR: Invalid get index 'position' (on base: 'null instance')."}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "severity",
+				Type:        "string",
+				Required:    false,
+				Default:     "error",
+				Description: "Which messages to consider: 'error', 'warning', or 'all' (default: 'error')",
+				Enum:        []string{"error", "warning", "all"},
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			severity := severityError
+			if s, ok := params["severity"].(string); ok && s != "" {
+				severity = s
+			}
+			switch severity {
+			case "all", severityError, severityWarning:
+			default:
+				return nil, fmt.Errorf("invalid severity '%s': must be 'error', 'warning', or 'all'", severity)
+			}
+
+			d := lastLocatedDiagnostic(aggregateDiagnostics(session.GetClient().Output()), severity)
+			if d == nil {
+				return nil, FormatError(
+					"No error with a script location found",
+					fmt.Sprintf("severity=%s", severity),
+					[]string{
+						"Check godot_get_errors: engine errors without a res:// location can't be mapped to a script",
+						"Output is only captured while connected; reproduce the error first",
+					},
+					nil,
+				)
+			}
+
+			file, err := resolveGodotPath(d.File, session.GetProjectRoot())
+			if err != nil {
+				return nil, err
+			}
+			lines := []int{d.Line}
+			for _, line := range session.Breakpoints().Lines(file) {
+				if line != d.Line {
+					lines = append(lines, line)
+				}
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			breakpoints := session.Breakpoints().SourceBreakpoints(file, lines)
+			resp, err := session.GetClient().SetSourceBreakpoints(ctx, file, breakpoints)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set breakpoint: %w", err)
+			}
+			session.Breakpoints().Set(file, lines)
+			autosaveSession()
+
+			bp := diagnoseBreakpoints(file, lines, resp.Body.Breakpoints)[0]
+			result := map[string]interface{}{
+				"status":      "verified",
+				"file":        d.File,
+				"line":        d.Line,
+				"actual_line": bp.ActualLine,
+				"diagnosis":   bp.Diagnosis,
+				"error":       d.Message,
+				"severity":    d.Severity,
+				"count":       d.Count,
+			}
+			switch bp.Status {
+			case breakpointRejected:
+				return nil, fmt.Errorf("no breakpoint was set at %s:%d: %s", d.File, d.Line, bp.Diagnosis)
+			case breakpointUnverified:
+				result["status"] = "unverified"
+			}
+			addDiagnosticSource(result, d, session.GetProjectRoot())
+			return result, nil
+		},
+	})
+
+	// godot_follow_output - Stream output events as notifications
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_follow_output",
+		Description: `Stream the game's output to the MCP client as it is printed.
+
+While enabled, every output event from the game is sent immediately as an MCP
+log notification (notifications/message, logger "godot") instead of waiting for
+the next godot_get_output call. Errors and warnings use the "error" and
+"warning" log levels; everything else is "info". Output is also still
+buffered for godot_get_output and godot_get_errors.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- The MCP client must display log notifications
+
+Streaming is tied to the current connection and stops on godot_disconnect;
+after reconnecting, call this tool again to resume following.
+
+Use this tool:
+- To watch print() output live while the game runs
+- Instead of polling godot_get_output in a loop
+
+Example: Start following output
+godot_follow_output(enable=true)
+
+Example: Stop following output
+godot_follow_output(enable=false)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "enable",
+				Type:        "boolean",
+				Required:    false,
+				Default:     true,
+				Description: "true to start streaming output notifications, false to stop (default: true)",
+			},
+			{
+				Name:        "format",
+				Type:        "string",
+				Required:    false,
+				Default:     outputFormatPlain,
+				Description: "Output format: 'plain' (markup stripped), 'markdown' (BBCode converted), or 'raw' (default: 'plain')",
+				Enum:        []string{outputFormatPlain, outputFormatMarkdown, outputFormatRaw},
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			if enable, ok := params["enable"].(bool); ok && !enable {
+				if !follower.stopFollowing() {
+					return map[string]interface{}{
+						"status":  "not_following",
+						"message": "Output was not being followed",
+					}, nil
+				}
+				return map[string]interface{}{
+					"status":  "stopped",
+					"message": "Stopped streaming game output",
+				}, nil
+			}
+
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			format := outputFormatPlain
+			if f, ok := params["format"].(string); ok && f != "" {
+				format = f
+			}
+			switch format {
+			case outputFormatPlain, outputFormatMarkdown, outputFormatRaw:
+			default:
+				return nil, fmt.Errorf("invalid format '%s': must be 'plain', 'markdown', or 'raw'", format)
+			}
+
+			follower.start(session.GetClient(), server, format)
+
+			return map[string]interface{}{
+				"status":  "following",
+				"message": "Streaming game output as notifications/message (logger: godot)",
+				"format":  format,
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	godap "github.com/google/go-dap"
+)
+
+func TestStripOutputMarkup(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "Hello world\n", "Hello world\n"},
+		{"ansi colors", "\x1b[1;31mERROR:\x1b[0m bad thing", "ERROR: bad thing"},
+		{"ansi hyperlink", "\x1b]8;;https://godotengine.org\x07docs\x1b]8;;\x07", "docs"},
+		{"bbcode", "[b]Score:[/b] [color=yellow]42[/color]", "Score: 42"},
+		{"bbcode with attributes", "[font_size=24][url=https://x.y]link[/url][/font_size]", "link"},
+		{"escaped brackets", "[lb]b[rb] is bold", "[b] is bold"},
+		{"arrays untouched", "[1, 2, 3] [Node2D:123]", "[1, 2, 3] [Node2D:123]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripOutputMarkup(tt.input); got != tt.want {
+				t.Errorf("stripOutputMarkup(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputMarkupToMarkdown(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"[b]bold[/b] [i]it[/i] [s]gone[/s] [code]x[/code]", "**bold** *it* ~~gone~~ `x`"},
+		{"see [url=https://godotengine.org]docs[/url]", "see [docs](https://godotengine.org)"},
+		{"[url]https://godotengine.org[/url]", "<https://godotengine.org>"},
+		{"[color=red][b]Error[/b][/color]", "**Error**"},
+		{"\x1b[33mwarn\x1b[0m", "warn"},
+	}
+
+	for _, tt := range tests {
+		if got := outputMarkupToMarkdown(tt.input); got != tt.want {
+			t.Errorf("outputMarkupToMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRenderOutput(t *testing.T) {
+	input := "[b]hi[/b]"
+	if got := renderOutput(input, outputFormatRaw); got != input {
+		t.Errorf("raw format should not change output, got %q", got)
+	}
+	if got := renderOutput(input, outputFormatPlain); got != "hi" {
+		t.Errorf("plain format should strip markup, got %q", got)
+	}
+	if got := renderOutput(input, outputFormatMarkdown); got != "**hi**" {
+		t.Errorf("markdown format should convert markup, got %q", got)
+	}
+}
+
+func TestFilterOutput(t *testing.T) {
+	records := []dap.OutputRecord{
+		{Seq: 1, Category: "stdout", Output: "a\n"},
+		{Seq: 2, Category: "stderr", Output: "ERROR: b\n"},
+		{Seq: 3, Category: "stdout", Output: "c\n"},
+		{Seq: 4, Category: "console", Output: "d\n"},
+	}
+
+	if got := filterOutput(records, nil, 0); len(got) != 4 {
+		t.Errorf("no filter should keep everything, got %v", got)
+	}
+	if got := filterOutput(records, nil, 2); len(got) != 2 || got[0].Seq != 3 || got[1].Seq != 4 {
+		t.Errorf("tail should keep the last entries, got %v", got)
+	}
+	if got := filterOutput(records, []string{"stdout"}, 1); len(got) != 1 || got[0].Seq != 3 {
+		t.Errorf("tail should apply after the category filter, got %v", got)
+	}
+	if got := filterOutput(records, []string{"stderr", "console"}, 10); len(got) != 2 || got[0].Seq != 2 {
+		t.Errorf("unexpected filtered output %v", got)
+	}
+}
+
+func TestClassifyOutput(t *testing.T) {
+	tests := []struct {
+		category string
+		text     string
+		want     string
+	}{
+		{"stdout", "Hello", ""},
+		{"stdout", "WARNING: Node not found", severityWarning},
+		{"stdout", "USER WARNING: deprecated", severityWarning},
+		{"stderr", "WARNING: on stderr", severityWarning},
+		{"stdout", "SCRIPT ERROR: Invalid call", severityError},
+		{"stderr", "something broke", severityError},
+	}
+
+	for _, tt := range tests {
+		if got := classifyOutput(tt.category, tt.text); got != tt.want {
+			t.Errorf("classifyOutput(%q, %q) = %q, want %q", tt.category, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateDiagnostics(t *testing.T) {
+	start := time.Now()
+	var records []dap.OutputRecord
+	seq := 0
+	add := func(category, text string) {
+		seq++
+		records = append(records, dap.OutputRecord{Seq: seq, Category: category, Output: text, Time: start.Add(time.Duration(seq) * time.Second)})
+	}
+
+	add("stdout", "Game started\n")
+	add("stderr", "ERROR: Missing texture\n")
+	for i := 0; i < 100; i++ {
+		add("stdout", "\x1b[33mWARNING: Physics body outside world\x1b[0m\n")
+	}
+	add("stderr", "ERROR: Missing texture\n")
+
+	diagnostics := aggregateDiagnostics(records)
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected 2 distinct diagnostics, got %d", len(diagnostics))
+	}
+
+	errDiag := diagnostics[0]
+	if errDiag.Severity != severityError || errDiag.Count != 2 || errDiag.FirstSeq != 2 || errDiag.LastSeq != seq {
+		t.Errorf("unexpected error entry: %+v", errDiag)
+	}
+
+	warnDiag := diagnostics[1]
+	if warnDiag.Severity != severityWarning || warnDiag.Count != 100 {
+		t.Errorf("unexpected warning entry: %+v", warnDiag)
+	}
+	if warnDiag.Message != "WARNING: Physics body outside world" {
+		t.Errorf("warning message should be stripped and trimmed, got %q", warnDiag.Message)
+	}
+	if !warnDiag.LastSeen.After(warnDiag.FirstSeen) {
+		t.Error("last_seen should be after first_seen for repeated warnings")
+	}
+}
+
+func TestAggregateDiagnostics_Location(t *testing.T) {
+	var records []dap.OutputRecord
+	add := func(category, text string) {
+		records = append(records, dap.OutputRecord{Seq: len(records) + 1, Category: category, Output: text, Time: time.Now()})
+	}
+
+	// Engine error: the "at:" line is C++, the backtrace has the script
+	add("stderr", "ERROR: Node not found: \"Player\" (relative to \"/root/Main\").\n")
+	add("stderr", "   at: get_node (scene/main/node.cpp:1651)\n")
+	add("stderr", "   GDScript backtrace (most recent call first):\n")
+	add("stderr", "       [0] _ready (res://scripts/main.gd:8)\n")
+	add("stdout", "Level loaded\n")
+	// Script error with its location in the same output event
+	add("stderr", "SCRIPT ERROR: Invalid get index 'position' (on base: 'null instance').\n   at: _process (res://scripts/enemy.gd:21)\n")
+
+	diagnostics := aggregateDiagnostics(records)
+	if len(diagnostics) != 2 {
+		t.Fatalf("continuation lines should be folded into their error, got %d diagnostics", len(diagnostics))
+	}
+	if diagnostics[0].File != "res://scripts/main.gd" || diagnostics[0].Line != 8 {
+		t.Errorf("unexpected engine error location: %s:%d", diagnostics[0].File, diagnostics[0].Line)
+	}
+	if diagnostics[1].File != "res://scripts/enemy.gd" || diagnostics[1].Line != 21 {
+		t.Errorf("unexpected script error location: %s:%d", diagnostics[1].File, diagnostics[1].Line)
+	}
+
+	if last := lastLocatedDiagnostic(diagnostics, severityError); last != diagnostics[1] {
+		t.Errorf("expected the enemy.gd error to be the last, got %+v", last)
+	}
+	if last := lastLocatedDiagnostic(diagnostics, severityWarning); last != nil {
+		t.Errorf("expected no located warning, got %+v", last)
+	}
+}
+
+func TestAddDiagnosticSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	source := "extends Node\n\nfunc _ready():\n\tvar player = null\n\tprint(player.position)\n"
+	if err := os.WriteFile(filepath.Join(dir, "scripts", "main.gd"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := map[string]interface{}{}
+	addDiagnosticSource(entry, &diagnostic{File: "res://scripts/main.gd", Line: 5}, dir)
+	if entry["file"] != "res://scripts/main.gd" || entry["line"] != 5 {
+		t.Errorf("unexpected location: %v", entry)
+	}
+	if snippet, _ := entry["snippet"].(string); !strings.Contains(snippet, ">    5 | \tprint(player.position)") {
+		t.Errorf("unexpected snippet:\n%v", entry["snippet"])
+	}
+
+	entry = map[string]interface{}{}
+	addDiagnosticSource(entry, &diagnostic{}, dir)
+	if len(entry) != 0 {
+		t.Errorf("a diagnostic without location should add nothing, got %v", entry)
+	}
+}
+
+// recordingNotifier captures notifications sent by forwardOutput
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []map[string]interface{}
+}
+
+func (r *recordingNotifier) Notify(method string, params map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if method == "notifications/message" {
+		r.notifications = append(r.notifications, params)
+	}
+	return nil
+}
+
+func (r *recordingNotifier) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.notifications)
+}
+
+func TestForwardOutput(t *testing.T) {
+	events := make(chan godap.Message, 10)
+	stop := make(chan struct{})
+	n := &recordingNotifier{}
+
+	done := make(chan struct{})
+	go func() {
+		forwardOutput(events, stop, n, outputFormatPlain)
+		close(done)
+	}()
+
+	events <- &godap.StoppedEvent{} // not output, ignored
+	events <- &godap.OutputEvent{Body: godap.OutputEventBody{Category: "stdout", Output: "[b]hello[/b]\n"}}
+	events <- &godap.OutputEvent{Body: godap.OutputEventBody{Category: "stderr", Output: "ERROR: boom\n"}}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for n.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(stop)
+	<-done
+
+	if n.count() != 2 {
+		t.Fatalf("expected 2 notifications, got %d", n.count())
+	}
+
+	first := n.notifications[0]
+	if first["level"] != "info" || first["logger"] != "godot" {
+		t.Errorf("unexpected first notification: %v", first)
+	}
+	if data := first["data"].(map[string]interface{}); data["output"] != "hello\n" {
+		t.Errorf("output should be rendered in plain format, got %q", data["output"])
+	}
+	if n.notifications[1]["level"] != severityError {
+		t.Errorf("stderr error should be sent at error level, got %v", n.notifications[1]["level"])
+	}
+}
+
+func TestOutputFollower_StopWithoutStart(t *testing.T) {
+	var f outputFollower
+	if f.stopFollowing() {
+		t.Error("stopFollowing should report false when nothing is being followed")
+	}
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -11,6 +12,8 @@ import (
 // 1. Absolute paths: returned as-is
 // 2. res:// paths: converted to absolute path using projectRoot
 // 3. Relative paths: rejected (must be absolute or res://)
+//
+// Resolved paths outside the directory allowlist are rejected.
 func resolveGodotPath(path string, projectRoot string) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("path cannot be empty")
@@ -23,13 +26,117 @@ func resolveGodotPath(path string, projectRoot string) (string, error) {
 		}
 
 		relativePath := strings.TrimPrefix(path, "res://")
-		return filepath.Join(projectRoot, relativePath), nil
+		resolved := filepath.Join(projectRoot, relativePath)
+		if err := checkPathAllowed(resolved); err != nil {
+			return "", err
+		}
+		return resolved, nil
 	}
 
 	// Handle absolute paths
 	if filepath.IsAbs(path) {
+		if err := checkPathAllowed(path); err != nil {
+			return "", err
+		}
 		return path, nil
 	}
 
 	return "", fmt.Errorf("path must be absolute or start with res:// (got: %s)", path)
 }
+
+// toResPath converts an absolute path inside projectRoot to a res:// path.
+// Returns "" if the project root is unknown or the path is outside it.
+func toResPath(path string, projectRoot string) string {
+	if strings.HasPrefix(path, "res://") {
+		return path
+	}
+	if projectRoot == "" || !filepath.IsAbs(path) {
+		return ""
+	}
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return "res://" + filepath.ToSlash(rel)
+}
+
+// validateGodotPathParam is a parameter validator for script paths:
+// the value must be an absolute path or a res:// path
+func validateGodotPathParam(value interface{}) error {
+	path, ok := value.(string)
+	if !ok || path == "" {
+		return fmt.Errorf("must be a non-empty string")
+	}
+	if !strings.HasPrefix(path, "res://") && !filepath.IsAbs(path) {
+		return fmt.Errorf("must be absolute or start with res:// (got: %s)", path)
+	}
+	return nil
+}
+
+// validateAbsolutePathParam is a parameter validator for directory paths
+func validateAbsolutePathParam(value interface{}) error {
+	path, ok := value.(string)
+	if !ok || path == "" {
+		return fmt.Errorf("must be a non-empty string")
+	}
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("must be absolute (got: %s)", path)
+	}
+	return nil
+}
+
+// absolutePathTools return paths meant to be passed back to tools that take
+// absolute paths (snapshot files, search and project directories), so their
+// results keep absolute paths
+var absolutePathTools = map[string]bool{
+	"godot_find_projects":   true,
+	"godot_list_projects":   true,
+	"godot_save_session":    true,
+	"godot_restore_session": true,
+}
+
+// pathFields matches result keys whose values may hold project file paths
+// (a string or a list of strings): path fields and the tool's own messages.
+// Data such as variable values, evaluate results and game output is left as
+// the game produced it.
+var pathFields = regexp.MustCompile(`"(file|files|failed_files|path|script|scene|scene_path|location|message)":(?:"(?:[^"\\]|\\.)*"|\[(?:"(?:[^"\\]|\\.)*",?)*\])`)
+
+// shortenProjectPaths rewrites absolute paths inside the project root to
+// res:// paths in tool results and errors, keeping them short and portable
+// across machines. Installed as the MCP server's text filter. Errors are
+// rewritten throughout; results only in the pathFields of JSON, where a
+// Windows root's backslashes are escaped (markdown results shorten their own
+// paths).
+func shortenProjectPaths(tool string, text string, isError bool) string {
+	if absolutePathTools[tool] {
+		return text
+	}
+	root := getDiscoveredProjectRoot()
+	if session := currentSession(); session != nil && session.GetProjectRoot() != "" {
+		root = session.GetProjectRoot()
+	}
+	if root == "" {
+		return text
+	}
+	root = strings.TrimRight(root, `/\`)
+	if root == "" {
+		return text
+	}
+
+	prefixes := []string{filepath.ToSlash(root) + "/"}
+	if filepath.Separator != '/' {
+		native := root + string(filepath.Separator)
+		prefixes = append(prefixes, native, strings.ReplaceAll(native, `\`, `\\`))
+	}
+	shorten := func(s string) string {
+		for _, prefix := range prefixes {
+			s = strings.ReplaceAll(s, prefix, "res://")
+		}
+		return s
+	}
+
+	if isError {
+		return shorten(text)
+	}
+	return pathFields.ReplaceAllStringFunc(text, shorten)
+}
@@ -0,0 +1,272 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// maxSlideCollisions caps the slide collisions read from a character body
+const maxSlideCollisions = 16
+
+// collisionKind is the physics object kind godot_get_collisions reports on
+type collisionKind struct {
+	class string // Engine class checked with is_class()
+	kind  string // "area", "character", "rigid", or "body"
+}
+
+// collisionKinds are checked in order; the first class the node is wins
+var collisionKinds = []collisionKind{
+	{"Area2D", "area"},
+	{"Area3D", "area"},
+	{"CharacterBody2D", "character"},
+	{"CharacterBody3D", "character"},
+	{"RigidBody2D", "rigid"},
+	{"RigidBody3D", "rigid"},
+	{"CollisionObject2D", "body"},
+	{"CollisionObject3D", "body"},
+}
+
+// collisionLayers lists the layer numbers (1-32) set in a collision bitmask,
+// as shown in the inspector
+func collisionLayers(mask string) []int {
+	bits, err := strconv.ParseUint(strings.TrimSpace(mask), 10, 32)
+	if err != nil {
+		return nil
+	}
+	layers := []int{}
+	for layer := 1; layer <= 32; layer++ {
+		if bits&(1<<(layer-1)) != 0 {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// memberReader evaluates members of one object in one frame
+type memberReader struct {
+	ctx     context.Context
+	client  expressionEvaluator
+	frameId int
+	node    string
+}
+
+// eval evaluates node.member
+func (r memberReader) eval(member string) (string, error) {
+	resp, err := r.client.Evaluate(r.ctx, fmt.Sprintf("%s.%s", r.node, member), r.frameId, "watch")
+	if err != nil {
+		return "", err
+	}
+	return resp.Body.Result, nil
+}
+
+// nodes evaluates str(node.member) into the nodes it lists
+func (r memberReader) nodes(member string) ([]trackedNode, error) {
+	resp, err := r.client.Evaluate(r.ctx, fmt.Sprintf("str(%s.%s)", r.node, member), r.frameId, "watch")
+	if err != nil {
+		return nil, err
+	}
+	return nodesOrEmpty(parseNodeList(resp.Body.Result)), nil
+}
+
+// physicsKind finds which physics object kind r's node is; "" if none
+func physicsKind(r memberReader) (string, string, error) {
+	for _, k := range collisionKinds {
+		value, err := r.eval(fmt.Sprintf("is_class(%q)", k.class))
+		if err != nil {
+			return "", "", err
+		}
+		if value == "true" {
+			return k.class, k.kind, nil
+		}
+	}
+	return "", "", nil
+}
+
+// readCollisions gathers the contacts of a physics node: overlaps for areas,
+// slide collisions and floor/wall/ceiling contact for character bodies, and
+// colliding bodies for rigid bodies, plus its collision layers and mask.
+// Settings that keep contacts from being reported are listed as hints.
+func readCollisions(ctx context.Context, client expressionEvaluator, frameId int, node string) (map[string]interface{}, error) {
+	r := memberReader{ctx: ctx, client: client, frameId: frameId, node: node}
+	class, kind, err := physicsKind(r)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("%s is not a physics object (Area, CharacterBody, RigidBody, or other CollisionObject)", node)
+	}
+
+	result := map[string]interface{}{
+		"status":     "success",
+		"node":       node,
+		"class":      class,
+		"kind":       kind,
+		"collisions": []trackedNode{},
+	}
+	hints := []string{}
+
+	if layer, err := r.eval("collision_layer"); err == nil {
+		result["collision_layer"] = collisionLayers(layer)
+	}
+	if mask, err := r.eval("collision_mask"); err == nil {
+		layers := collisionLayers(mask)
+		result["collision_mask"] = layers
+		if layers != nil && len(layers) == 0 {
+			hints = append(hints, "collision_mask is empty, so this object detects nothing")
+		}
+	}
+
+	switch kind {
+	case "area":
+		if monitoring, err := r.eval("monitoring"); err == nil && monitoring == "false" {
+			hints = append(hints, "monitoring is off, so overlaps aren't detected")
+		}
+		bodies, err := r.nodes("get_overlapping_bodies()")
+		if err != nil {
+			return nil, err
+		}
+		areas, err := r.nodes("get_overlapping_areas()")
+		if err != nil {
+			return nil, err
+		}
+		result["overlapping_bodies"] = bodies
+		result["overlapping_areas"] = areas
+		result["collisions"] = append(append([]trackedNode{}, bodies...), areas...)
+
+	case "character":
+		for _, contact := range []string{"is_on_floor()", "is_on_wall()", "is_on_ceiling()"} {
+			if value, err := r.eval(contact); err == nil {
+				result[strings.TrimSuffix(strings.TrimPrefix(contact, "is_"), "()")] = value == "true"
+			}
+		}
+		countText, err := r.eval("get_slide_collision_count()")
+		if err != nil {
+			return nil, err
+		}
+		count, _ := strconv.Atoi(countText)
+		result["slide_collision_count"] = count
+		if count > maxSlideCollisions {
+			result["slide_collisions_truncated"] = true
+			count = maxSlideCollisions
+		}
+		colliders := []trackedNode{}
+		for i := 0; i < count; i++ {
+			collider, err := r.nodes(fmt.Sprintf("get_slide_collision(%d).get_collider()", i))
+			if err == nil && len(collider) > 0 {
+				colliders = append(colliders, collider[0])
+			}
+		}
+		result["collisions"] = colliders
+		hints = append(hints, "Slide collisions are from the last move_and_slide() call")
+
+	case "rigid":
+		contactMonitor, _ := r.eval("contact_monitor")
+		maxContacts, _ := r.eval("max_contacts_reported")
+		if contactMonitor == "false" || maxContacts == "0" {
+			hints = append(hints, "Set contact_monitor = true and max_contacts_reported > 0 for get_colliding_bodies() to report contacts")
+		}
+		bodies, err := r.nodes("get_colliding_bodies()")
+		if err != nil {
+			return nil, err
+		}
+		result["collisions"] = bodies
+
+	case "body":
+		hints = append(hints, "Static and animatable bodies don't report contacts; inspect the other body or an Area instead")
+	}
+
+	if len(hints) > 0 {
+		result["hints"] = hints
+	}
+	return result, nil
+}
+
+// RegisterPhysicsTools registers godot_get_collisions
+func RegisterPhysicsTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_collisions",
+		Description: `Get what a physics node is currently touching or overlapping.
+
+Evaluates the contacts of an Area, CharacterBody, or RigidBody (2D or 3D) and
+formats them, with the node's collision layers and mask:
+- Area: get_overlapping_bodies() and get_overlapping_areas()
+- CharacterBody: colliders of the last move_and_slide(), is_on_floor/wall/ceiling
+- RigidBody: get_colliding_bodies()
+
+Hints point out settings that keep contacts from being reported (an empty
+mask, monitoring off, contact_monitor off). Launch with debug_collisions=true
+to also see the collision shapes in the running game.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Example: Why doesn't the pickup trigger?
+godot_get_collisions(node_expression="$Pickup")
+→ {"kind": "area", "overlapping_bodies": [], "collision_mask": [],
+   "hints": ["collision_mask is empty, so this object detects nothing"], ...}
+
+Example: What is the player standing on?
+godot_get_collisions(node_expression="self")
+→ {"kind": "character", "on_floor": true,
+   "collisions": [{"name": "Ground", "class": "StaticBody2D", ...}], ...}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "node_expression",
+				Type:        "string",
+				Required:    true,
+				Description: "Expression for the physics node (e.g. self, $Hitbox, get_node(\"/root/Main/Player\"))",
+			},
+			{
+				Name:        "frame_id",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Stack frame ID for evaluation context (default: 0 = top frame)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get collisions"); err != nil {
+				return nil, err
+			}
+
+			node, ok := params["node_expression"].(string)
+			if !ok || strings.TrimSpace(node) == "" {
+				return nil, fmt.Errorf("node_expression is required and must be a non-empty string")
+			}
+			frameId := 0
+			if fid, ok := params["frame_id"].(float64); ok {
+				frameId = int(fid)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := readCollisions(ctx, session.GetClient(), frameId, node)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read collisions",
+					node,
+					[]string{
+						"The expression must evaluate to an Area, CharacterBody, or RigidBody node",
+						"Check the node path with godot_evaluate or godot_inspect_self",
+						"Use godot_diagnose_expression if part of the expression is null",
+					},
+					err,
+				)
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// physicsValues answers is_class() for node as class, false for the others
+func physicsValues(node, class string) map[string]string {
+	values := map[string]string{}
+	for _, k := range collisionKinds {
+		value := "false"
+		if k.class == class {
+			value = "true"
+		}
+		values[node+`.is_class("`+k.class+`")`] = value
+	}
+	return values
+}
+
+func TestCollisionLayers(t *testing.T) {
+	if got := collisionLayers("5"); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("expected layers [1 3], got %v", got)
+	}
+	if got := collisionLayers("0"); got == nil || len(got) != 0 {
+		t.Errorf("expected no layers, got %v", got)
+	}
+	if got := collisionLayers("oops"); got != nil {
+		t.Errorf("expected nil for an invalid mask, got %v", got)
+	}
+}
+
+func TestReadCollisions_Area(t *testing.T) {
+	values := physicsValues("$Pickup", "Area2D")
+	values["$Pickup.collision_layer"] = "2"
+	values["$Pickup.collision_mask"] = "0"
+	values["$Pickup.monitoring"] = "true"
+	values["str($Pickup.get_overlapping_bodies())"] = "[Player:<CharacterBody2D#300>]"
+	values["str($Pickup.get_overlapping_areas())"] = "[]"
+
+	result, err := readCollisions(context.Background(), &fakeWatchEvaluator{values: values}, 0, "$Pickup")
+	if err != nil {
+		t.Fatalf("readCollisions failed: %v", err)
+	}
+	if result["kind"] != "area" || result["class"] != "Area2D" {
+		t.Errorf("unexpected kind: %v", result)
+	}
+	bodies := result["overlapping_bodies"].([]trackedNode)
+	if len(bodies) != 1 || bodies[0].Name != "Player" || len(result["collisions"].([]trackedNode)) != 1 {
+		t.Errorf("unexpected overlaps: %v", result)
+	}
+	if !reflect.DeepEqual(result["collision_layer"], []int{2}) {
+		t.Errorf("unexpected layer: %v", result["collision_layer"])
+	}
+	hints := result["hints"].([]string)
+	if len(hints) != 1 || hints[0] != "collision_mask is empty, so this object detects nothing" {
+		t.Errorf("unexpected hints: %v", hints)
+	}
+}
+
+func TestReadCollisions_Character(t *testing.T) {
+	values := physicsValues("self", "CharacterBody2D")
+	values["self.collision_layer"] = "1"
+	values["self.collision_mask"] = "1"
+	values["self.is_on_floor()"] = "true"
+	values["self.is_on_wall()"] = "false"
+	values["self.is_on_ceiling()"] = "false"
+	values["self.get_slide_collision_count()"] = "1"
+	values["str(self.get_slide_collision(0).get_collider())"] = "Ground:<StaticBody2D#42>"
+
+	result, err := readCollisions(context.Background(), &fakeWatchEvaluator{values: values}, 0, "self")
+	if err != nil {
+		t.Fatalf("readCollisions failed: %v", err)
+	}
+	if result["on_floor"] != true || result["on_wall"] != false || result["slide_collision_count"] != 1 {
+		t.Errorf("unexpected contacts: %v", result)
+	}
+	collisions := result["collisions"].([]trackedNode)
+	if len(collisions) != 1 || collisions[0].ID != 42 {
+		t.Errorf("unexpected colliders: %v", collisions)
+	}
+}
+
+func TestReadCollisions_RigidWithoutContactMonitor(t *testing.T) {
+	values := physicsValues("$Ball", "RigidBody3D")
+	values["$Ball.contact_monitor"] = "false"
+	values["$Ball.max_contacts_reported"] = "0"
+	values["str($Ball.get_colliding_bodies())"] = "[]"
+
+	result, err := readCollisions(context.Background(), &fakeWatchEvaluator{values: values}, 0, "$Ball")
+	if err != nil {
+		t.Fatalf("readCollisions failed: %v", err)
+	}
+	if result["kind"] != "rigid" || len(result["hints"].([]string)) != 1 {
+		t.Errorf("expected a contact_monitor hint, got %v", result)
+	}
+}
+
+func TestReadCollisions_NotPhysics(t *testing.T) {
+	if _, err := readCollisions(context.Background(), &fakeWatchEvaluator{values: physicsValues("$Label", "")}, 0, "$Label"); err == nil {
+		t.Error("expected an error for a non-physics node")
+	}
+}
@@ -0,0 +1,39 @@
+package tools
+
+import "github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+
+// Precondition checks run before issuing DAP requests. Godot silently ignores
+// requests it can't serve (e.g. a stack trace while the game is running), so
+// without these checks the tool would only fail after the command timeout.
+
+// requireGameActive fails if the game hasn't been launched or has ended
+func requireGameActive(session *dap.Session, action string) error {
+	client := session.GetClient()
+	switch client.RunState() {
+	case dap.RunStateNotLaunched:
+		return ErrGameNotLaunched(action)
+	case dap.RunStateEnded:
+		return ErrGameEnded(action, exitReason(client))
+	}
+	return nil
+}
+
+// requirePaused fails unless the game is paused, as needed for inspection
+// and stepping
+func requirePaused(session *dap.Session, action string) error {
+	if err := requireGameActive(session, action); err != nil {
+		return err
+	}
+	if session.GetClient().RunState() == dap.RunStateRunning {
+		return ErrGameRunning(action)
+	}
+	return nil
+}
+
+// exitReason describes how the last run ended, for error messages
+func exitReason(client *dap.Client) string {
+	if st := client.ExitStatus(); st != nil {
+		return st.Reason
+	}
+	return "unknown"
+}
@@ -0,0 +1,22 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+)
+
+func TestPreconditions_NotLaunched(t *testing.T) {
+	session := dap.NewSession("localhost", 6006)
+
+	err := requirePaused(session, "get stack trace")
+	if err == nil || !strings.Contains(err.Error(), "game not launched") {
+		t.Errorf("expected game not launched error, got %v", err)
+	}
+
+	err = requireGameActive(session, "pause execution")
+	if err == nil || !strings.Contains(err.Error(), "godot_launch_main_scene") {
+		t.Errorf("expected launch suggestion, got %v", err)
+	}
+}
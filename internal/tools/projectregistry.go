@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// projectsFileEnv names the environment variable holding the project
+// registry file, a JSON config naming the projects tools can refer to:
+//
+//	{"projects": {"platformer": "/repos/games/platformer", "tools": "tools/editor"}}
+//
+// Relative paths are relative to the file's directory, so a registry can be
+// checked into a monorepo. The file is read on every lookup, so edits apply
+// without restarting the server.
+const projectsFileEnv = "GODOT_MCP_PROJECTS_FILE"
+
+// projectsConfig is the content of the project registry file
+type projectsConfig struct {
+	Projects map[string]string `json:"projects"` // Name → project directory
+}
+
+// loadProjectRegistry reads the registry file at path, returning names
+// mapped to cleaned absolute directories
+func loadProjectRegistry(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config projectsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid project registry %s: %w", path, err)
+	}
+	projects := make(map[string]string, len(config.Projects))
+	for name, dir := range config.Projects {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(path), dir)
+		}
+		projects[name] = filepath.Clean(dir)
+	}
+	return projects, nil
+}
+
+// registeredProjects reads the registry named by GODOT_MCP_PROJECTS_FILE.
+// Returns nil without error when the variable is unset.
+func registeredProjects() (map[string]string, error) {
+	path := os.Getenv(projectsFileEnv)
+	if path == "" {
+		return nil, nil
+	}
+	return loadProjectRegistry(path)
+}
+
+// resolveProject turns a project parameter into a directory: absolute paths
+// are returned as given, other values are looked up by name in the registry
+func resolveProject(value string) (string, error) {
+	if value == "" || filepath.IsAbs(value) {
+		return value, nil
+	}
+	projects, err := registeredProjects()
+	if err != nil {
+		return "", FormatError(
+			"Failed to read the project registry",
+			os.Getenv(projectsFileEnv),
+			[]string{fmt.Sprintf("Check the file named by %s", projectsFileEnv)},
+			err,
+		)
+	}
+	if dir, ok := projects[value]; ok {
+		return dir, nil
+	}
+
+	solutions := []string{"Pass the absolute path of the project directory"}
+	if projects == nil {
+		solutions = append(solutions, fmt.Sprintf("Or set %s to a registry file to refer to projects by name", projectsFileEnv))
+	} else {
+		solutions = append(solutions, fmt.Sprintf("Registered projects: %v (see godot_list_projects)", sortedProjectNames(projects)))
+	}
+	return "", FormatError("Unknown project", value, solutions, nil)
+}
+
+// sortedProjectNames returns the registry's names in order
+func sortedProjectNames(projects map[string]string) []string {
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterProjectRegistryTools registers godot_list_projects
+func RegisterProjectRegistryTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_list_projects",
+		Description: `List the projects registered by name in the project registry.
+
+The server operator can register projects in a JSON file named by the
+GODOT_MCP_PROJECTS_FILE environment variable:
+{"projects": {"platformer": "/repos/games/platformer", "tools": "tools/editor"}}
+Relative paths are relative to the file. Every tool with a project parameter
+(godot_connect, the launch tools, ...) then accepts a registered name instead
+of an absolute path, e.g. project="platformer".
+
+Each entry includes the project's name and main scene from project.godot, or
+an error if the directory isn't a readable Godot project.
+
+Example: Pick a project in a monorepo
+godot_list_projects()
+→ {"projects": [{"name": "platformer", "path": "/repos/games/platformer",
+                 "title": "Platformer", "main_scene": "res://main.tscn"}, ...]}
+godot_launch_main_scene(project="platformer")`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			projects, err := registeredProjects()
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read the project registry",
+					os.Getenv(projectsFileEnv),
+					[]string{fmt.Sprintf("Check the file named by %s", projectsFileEnv)},
+					err,
+				)
+			}
+			if projects == nil {
+				return map[string]interface{}{
+					"status":   "success",
+					"projects": []map[string]interface{}{},
+					"count":    0,
+					"message":  fmt.Sprintf("No project registry configured; set %s to a JSON file, or use godot_find_projects", projectsFileEnv),
+				}, nil
+			}
+
+			entries := make([]map[string]interface{}, 0, len(projects))
+			for _, name := range sortedProjectNames(projects) {
+				dir := projects[name]
+				entry := map[string]interface{}{
+					"name": name,
+					"path": dir,
+				}
+				if !isPathAllowed(dir, allowedDirs) {
+					entry["error"] = fmt.Sprintf("outside the directories allowed by %s", allowedDirsEnv)
+				} else if info, err := parseProjectFile(dir); err != nil {
+					entry["error"] = err.Error()
+				} else {
+					if info.Name != "" {
+						entry["title"] = info.Name
+					}
+					if info.MainScene != "" {
+						entry["main_scene"] = info.MainScene
+					}
+				}
+				entries = append(entries, entry)
+			}
+			return map[string]interface{}{
+				"status":   "success",
+				"registry": os.Getenv(projectsFileEnv),
+				"projects": entries,
+				"count":    len(entries),
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveProject(t *testing.T) {
+	dir := t.TempDir()
+	registry := filepath.Join(dir, "godot-projects.json")
+	config := `{"projects": {"platformer": "games/platformer", "shared": "/opt/godot/shared"}}`
+	if err := os.WriteFile(registry, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a registry, only absolute paths work
+	t.Setenv(projectsFileEnv, "")
+	if got, err := resolveProject("/games/demo"); err != nil || got != "/games/demo" {
+		t.Errorf("absolute paths should pass through, got %q, %v", got, err)
+	}
+	if _, err := resolveProject("platformer"); err == nil || !strings.Contains(err.Error(), projectsFileEnv) {
+		t.Errorf("expected a hint to configure the registry, got %v", err)
+	}
+
+	t.Setenv(projectsFileEnv, registry)
+	if got, err := resolveProject("platformer"); err != nil || got != filepath.Join(dir, "games", "platformer") {
+		t.Errorf("relative registry paths should resolve against the file, got %q, %v", got, err)
+	}
+	if got, err := resolveProject("shared"); err != nil || got != "/opt/godot/shared" {
+		t.Errorf("unexpected path for shared: %q, %v", got, err)
+	}
+	if _, err := resolveProject("racer"); err == nil || !strings.Contains(err.Error(), "[platformer shared]") {
+		t.Errorf("expected the registered names in the error, got %v", err)
+	}
+	if got, _ := resolveProject(""); got != "" {
+		t.Errorf("an empty project should stay empty, got %q", got)
+	}
+}
+
+func TestLoadProjectRegistry_Invalid(t *testing.T) {
+	registry := filepath.Join(t.TempDir(), "projects.json")
+	if err := os.WriteFile(registry, []byte(`{"projects": ["platformer"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadProjectRegistry(registry); err == nil {
+		t.Error("expected an error for a malformed registry")
+	}
+}
@@ -0,0 +1,320 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+const (
+	// defaultProjectSearchDepth limits how far below a workspace root we look for project.godot
+	defaultProjectSearchDepth = 3
+
+	// maxProjectSearchDepth caps user-requested search depth to keep walks bounded
+	maxProjectSearchDepth = 10
+)
+
+// quotedStringPattern extracts the quoted entries of a PackedStringArray(...) value
+var quotedStringPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// projectInfo summarizes the [application] section of a project.godot file
+type projectInfo struct {
+	Path      string   // Project directory
+	Name      string   // application/config/name
+	MainScene string   // application/run/main_scene
+	Features  []string // application/config/features (first entry is usually the Godot version)
+}
+
+// Project discovered from the MCP client's workspace roots.
+// godot_connect falls back to it when no project argument is given.
+var (
+	discoveredProjectRoot string
+	discoveredProjectMu   sync.RWMutex
+)
+
+// getDiscoveredProjectRoot returns the project found in the client's roots, or ""
+func getDiscoveredProjectRoot() string {
+	discoveredProjectMu.RLock()
+	defer discoveredProjectMu.RUnlock()
+	return discoveredProjectRoot
+}
+
+// setDiscoveredProjectRoot records the discovered project and pre-populates
+// the active session if it doesn't have a project root yet
+func setDiscoveredProjectRoot(path string) {
+	discoveredProjectMu.Lock()
+	discoveredProjectRoot = path
+	discoveredProjectMu.Unlock()
+
+	if session := currentSession(); session != nil && session.GetProjectRoot() == "" && path != "" {
+		session.SetProjectRoot(path)
+	}
+}
+
+// RegisterProjectDiscovery hooks project discovery into the MCP client's
+// workspace roots, so res:// paths work without passing project to godot_connect
+func RegisterProjectDiscovery(server *mcp.Server) {
+	server.OnRootsChanged(func(roots []mcp.Root) {
+		var projects []string
+		for _, root := range roots {
+			dir, ok := rootToPath(root.URI)
+			if !ok {
+				continue
+			}
+			for _, project := range findProjectDirs(dir, defaultProjectSearchDepth) {
+				if isPathAllowed(project, allowedDirs) {
+					projects = append(projects, project)
+				}
+			}
+		}
+
+		if len(projects) == 0 {
+			log.Printf("No Godot projects found in %d client root(s)", len(roots))
+			setDiscoveredProjectRoot("")
+			return
+		}
+
+		if len(projects) > 1 {
+			log.Printf("Found %d Godot projects in client roots, using %s (others: %v)", len(projects), projects[0], projects[1:])
+		} else {
+			log.Printf("Discovered Godot project from client roots: %s", projects[0])
+		}
+		setDiscoveredProjectRoot(projects[0])
+	})
+}
+
+// rootToPath converts a file:// root URI to a local directory path
+func rootToPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// findProjectDirs returns directories under root (inclusive) that contain a
+// project.godot file, searching at most maxDepth levels deep.
+// Hidden directories (.godot, .git, ...) are skipped, and the search does not
+// descend into a directory once it is identified as a project.
+func findProjectDirs(root string, maxDepth int) []string {
+	var projects []string
+	root = filepath.Clean(root)
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable entries are skipped rather than aborting the search
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(path, "project.godot")); err == nil {
+			projects = append(projects, path)
+			return fs.SkipDir
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if rel != "." && strings.Count(rel, string(filepath.Separator))+1 >= maxDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+
+	return projects
+}
+
+// parseProjectFile reads the [application] settings from a project.godot file.
+// Only the handful of keys we report are parsed; everything else is ignored.
+func parseProjectFile(projectDir string) (projectInfo, error) {
+	info := projectInfo{Path: projectDir}
+
+	f, err := os.Open(filepath.Join(projectDir, "project.godot"))
+	if err != nil {
+		return info, err
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		if section != "application" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "config/name":
+			info.Name = unquoteGodotString(value)
+		case "run/main_scene":
+			info.MainScene = unquoteGodotString(value)
+		case "config/features":
+			for _, m := range quotedStringPattern.FindAllStringSubmatch(value, -1) {
+				info.Features = append(info.Features, m[1])
+			}
+		}
+	}
+
+	return info, scanner.Err()
+}
+
+// unquoteGodotString strips quotes from a project.godot string value
+func unquoteGodotString(value string) string {
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return strings.Trim(value, `"`)
+}
+
+// RegisterProjectTools registers project discovery tools
+func RegisterProjectTools(server *mcp.Server) {
+	// godot_find_projects - Locate Godot projects under a directory
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_find_projects",
+		Description: `Find Godot projects under a directory.
+
+This tool recursively searches for project.godot files and returns each project's
+directory, name, and main scene (parsed from project.godot). Use it to find the
+right path to pass to godot_connect and the launch tools.
+
+C# (.NET) projects are flagged with csharp=true: their C# scripts are debugged
+via the .NET debugger, and only GDScript can be debugged through Godot's DAP.
+
+Search behavior:
+- Hidden directories (.godot, .git, ...) are skipped
+- Directories inside a project are not searched (addons are not reported)
+- Search depth is limited (default: 3 levels, maximum: 10)
+
+Use this tool:
+- When you don't know the absolute path of the Godot project
+- When a repository contains several Godot projects
+- Before godot_connect(project=...) or godot_launch_main_scene(project=...)
+
+Example: Search a repository
+godot_find_projects(search_path="/Users/dev/repos/my-game")
+
+Example: Search deeper
+godot_find_projects(search_path="/Users/dev/repos", max_depth=5)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "search_path",
+				Type:        "string",
+				Required:    true,
+				Description: "Absolute path of the directory to search",
+				Validate:    validateAbsolutePathParam,
+			},
+			{
+				Name:        "max_depth",
+				Type:        "number",
+				Required:    false,
+				Default:     defaultProjectSearchDepth,
+				Description: fmt.Sprintf("Maximum directory depth to search (default: %d, max: %d)", defaultProjectSearchDepth, maxProjectSearchDepth),
+				Minimum:     mcp.Float64(1),
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			searchPath, ok := params["search_path"].(string)
+			if !ok || searchPath == "" {
+				return nil, fmt.Errorf("search_path parameter is required and must be a non-empty string")
+			}
+			if !filepath.IsAbs(searchPath) {
+				return nil, fmt.Errorf("search_path must be absolute (got: %s)", searchPath)
+			}
+			if err := checkPathAllowed(searchPath); err != nil {
+				return nil, err
+			}
+
+			maxDepth := defaultProjectSearchDepth
+			if d, ok := params["max_depth"].(float64); ok {
+				maxDepth = int(d)
+			}
+			if maxDepth < 1 {
+				maxDepth = 1
+			}
+			if maxDepth > maxProjectSearchDepth {
+				maxDepth = maxProjectSearchDepth
+			}
+
+			if stat, err := os.Stat(searchPath); err != nil || !stat.IsDir() {
+				return nil, FormatError(
+					"Search path is not a readable directory",
+					searchPath,
+					[]string{
+						"Check that the path exists",
+						"Use an absolute directory path, not a file",
+					},
+					err,
+				)
+			}
+
+			dirs := findProjectDirs(searchPath, maxDepth)
+
+			projects := make([]map[string]interface{}, 0, len(dirs))
+			for _, dir := range dirs {
+				info, err := parseProjectFile(dir)
+				project := map[string]interface{}{
+					"path": info.Path,
+				}
+				if err != nil {
+					project["error"] = err.Error()
+				}
+				if info.Name != "" {
+					project["name"] = info.Name
+				}
+				if info.MainScene != "" {
+					project["main_scene"] = info.MainScene
+				}
+				if len(info.Features) > 0 {
+					project["features"] = info.Features
+				}
+				if isCSharpProject(dir) {
+					project["csharp"] = true
+				}
+				projects = append(projects, project)
+			}
+
+			result := map[string]interface{}{
+				"status":    "success",
+				"projects":  projects,
+				"count":     len(projects),
+				"max_depth": maxDepth,
+			}
+			if len(projects) == 0 {
+				result["message"] = fmt.Sprintf("No project.godot found within %d level(s) of %s", maxDepth, searchPath)
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeProject creates a directory containing a project.godot file
+func makeProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "project.godot"), []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create project.godot: %v", err)
+	}
+}
+
+func TestFindProjectDirs(t *testing.T) {
+	root := t.TempDir()
+	makeProject(t, filepath.Join(root, "game"))
+	makeProject(t, filepath.Join(root, "game", "addons", "nested")) // inside a project, skipped
+	makeProject(t, filepath.Join(root, "tools", "editor_plugin"))
+	makeProject(t, filepath.Join(root, ".hidden", "project"))      // hidden, skipped
+	makeProject(t, filepath.Join(root, "a", "b", "c", "too_deep")) // beyond depth 3
+
+	projects := findProjectDirs(root, 3)
+
+	want := map[string]bool{
+		filepath.Join(root, "game"):                   true,
+		filepath.Join(root, "tools", "editor_plugin"): true,
+	}
+	if len(projects) != len(want) {
+		t.Fatalf("expected %d projects, got %v", len(want), projects)
+	}
+	for _, p := range projects {
+		if !want[p] {
+			t.Errorf("unexpected project %s", p)
+		}
+	}
+}
+
+func TestFindProjectDirs_RootIsProject(t *testing.T) {
+	root := t.TempDir()
+	makeProject(t, root)
+
+	projects := findProjectDirs(root, 3)
+	if len(projects) != 1 || projects[0] != root {
+		t.Errorf("expected root to be the only project, got %v", projects)
+	}
+}
+
+func TestRootToPath(t *testing.T) {
+	tests := []struct {
+		uri    string
+		want   string
+		wantOK bool
+	}{
+		{"file:///home/dev/game", filepath.FromSlash("/home/dev/game"), true},
+		{"file:///home/dev/my%20game", filepath.FromSlash("/home/dev/my game"), true},
+		{"https://example.com/game", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := rootToPath(tt.uri)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("rootToPath(%q) = %q, %v; want %q, %v", tt.uri, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseProjectFile(t *testing.T) {
+	dir := t.TempDir()
+	content := `; Engine configuration file.
+config_version=5
+
+[application]
+
+config/name="My \"Quoted\" Game"
+run/main_scene="res://scenes/main.tscn"
+config/features=PackedStringArray("4.3", "Forward Plus")
+
+[debug]
+
+config/name="ignored"
+`
+	if err := os.WriteFile(filepath.Join(dir, "project.godot"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write project.godot: %v", err)
+	}
+
+	info, err := parseProjectFile(dir)
+	if err != nil {
+		t.Fatalf("parseProjectFile failed: %v", err)
+	}
+	if info.Name != `My "Quoted" Game` {
+		t.Errorf("unexpected name %q", info.Name)
+	}
+	if info.MainScene != "res://scenes/main.tscn" {
+		t.Errorf("unexpected main scene %q", info.MainScene)
+	}
+	if len(info.Features) != 2 || info.Features[0] != "4.3" || info.Features[1] != "Forward Plus" {
+		t.Errorf("unexpected features %v", info.Features)
+	}
+}
+
+func TestParseProjectFile_Fixture(t *testing.T) {
+	info, err := parseProjectFile(filepath.Join("..", "..", "tests", "fixtures", "test-project"))
+	if err != nil {
+		t.Fatalf("parseProjectFile failed: %v", err)
+	}
+	if info.Name != "DAP MCP Test Project" || info.MainScene != "res://test_scene.tscn" {
+		t.Errorf("unexpected fixture info: %+v", info)
+	}
+}
@@ -11,17 +11,48 @@ func RegisterAll(server *mcp.Server) {
 	RegisterPingTool(server)
 
 	// Phase 3: Core debugging tools
+	RegisterProjectDiscovery(server)
+	RegisterProjectTools(server)
+	RegisterProjectRegistryTools(server)
 	RegisterConnectionTools(server)
 	RegisterExecutionTools(server)
+	RegisterEventTools(server)
 	RegisterBreakpointTools(server)
+	RegisterFunctionBreakpointTools(server)
+	RegisterBreakpointSyncTools(server)
+	RegisterTracepointTools(server)
 
 	// Phase 4: Runtime inspection tools
 	RegisterInspectionTools(server)
+	RegisterStopContextTools(server)
+	RegisterDiagnoseTools(server)
+	RegisterStringChunkTools(server)
+	RegisterCompareTools(server)
+	RegisterOutputTools(server)
+	RegisterWatchTools(server)
+	RegisterSceneTools(server)
+	RegisterNodeTrackingTools(server)
+	RegisterPhysicsTools(server)
+	RegisterAnimationTools(server)
+	RegisterAudioTools(server)
+	RegisterMaterialTools(server)
+	RegisterInputTools(server)
+	RegisterResourceTools(server)
+	RegisterStartupTools(server)
+	RegisterGameStateTools(server)
+	RegisterSeedTools(server)
 
 	// Phase 5: Launch tools
 	RegisterLaunchTools(server)
 	RegisterAttachTools(server)
+	RegisterSnapshotTools(server)
 
 	// Phase 6: Advanced debugging tools
 	RegisterAdvancedTools(server)
+
+	// Results and errors show project files as res:// paths
+	server.SetTextFilter(shortenProjectPaths)
+
+	// The session doesn't idle while a tool call runs
+	server.SetCallHook(idle.begin)
 }
@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
+)
+
+// Keys that vary from run to run and are left out of transcript comparisons
+var transcriptVolatileKeys = []string{"timeline", "elapsed_ms", "waited_ms", "time", "timestamp", "connected_at"}
+
+// serveGodot answers requests the way Godot's DAP server does for a game
+// that hits a breakpoint right after launch
+func serveGodot(server *daptest.MockServer, scriptPath string) {
+	server.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{
+				server.Success(req, map[string]interface{}{"supportsConfigurationDoneRequest": true}),
+				server.NewEvent("initialized", nil),
+			}
+		case "setBreakpoints":
+			args := req.(*godap.SetBreakpointsRequest).Arguments
+			breakpoints := []map[string]interface{}{}
+			for i, bp := range args.Breakpoints {
+				breakpoints = append(breakpoints, map[string]interface{}{"id": i + 1, "verified": true, "line": bp.Line})
+			}
+			return []godap.Message{server.Success(req, map[string]interface{}{"breakpoints": breakpoints})}
+		case "configurationDone":
+			// The stop comes first so it's recorded before the launch returns
+			return []godap.Message{
+				server.NewEvent("stopped", map[string]interface{}{"reason": "breakpoint", "threadId": 1, "allThreadsStopped": true}),
+				server.Success(req, nil),
+			}
+		case "threads":
+			return []godap.Message{server.Success(req, map[string]interface{}{
+				"threads": []map[string]interface{}{{"id": 1, "name": "Main"}},
+			})}
+		case "stackTrace":
+			return []godap.Message{server.Success(req, map[string]interface{}{
+				"stackFrames": []map[string]interface{}{
+					{"id": 0, "name": "_process", "line": 12, "column": 1, "source": map[string]interface{}{"path": scriptPath}},
+					{"id": 1, "name": "_ready", "line": 5, "column": 1, "source": map[string]interface{}{"path": scriptPath}},
+				},
+				"totalFrames": 2,
+			})}
+		case "scopes":
+			return []godap.Message{server.Success(req, map[string]interface{}{
+				"scopes": []map[string]interface{}{
+					{"name": "Locals", "variablesReference": 1000},
+					{"name": "Members", "variablesReference": 1001},
+					{"name": "Globals", "variablesReference": 1002},
+				},
+			})}
+		case "evaluate":
+			return []godap.Message{server.Success(req, map[string]interface{}{"result": "200", "type": "int", "variablesReference": 0})}
+		case "continue":
+			return []godap.Message{
+				server.Success(req, map[string]interface{}{"allThreadsContinued": true}),
+				server.NewEvent("continued", map[string]interface{}{"threadId": 1, "allThreadsContinued": true}),
+			}
+		}
+		return nil
+	})
+}
+
+// connectMockSession connects and initializes a session with mock and makes
+// it the global session until the test ends
+func connectMockSession(t *testing.T, mock *daptest.MockServer) *dap.Session {
+	t.Helper()
+	session := dap.NewSession("localhost", mock.Port())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	swapSession(session)
+	t.Cleanup(func() {
+		clearSession(session)
+		session.Close()
+	})
+	return session
+}
+
+// TestTranscripts replays the recorded MCP sessions in testdata/transcripts
+// against a mock Godot and compares every response with the recording.
+// To re-record after an intended change:
+//
+//	go test ./internal/tools -run TestTranscripts -update
+//
+// Transcripts use $PROJECT and $PORT for the test project and mock port.
+func TestTranscripts(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "transcripts", "*.jsonl"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no transcripts found: %v", err)
+	}
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".jsonl"), func(t *testing.T) {
+			replayTranscript(t, file)
+		})
+	}
+}
+
+func replayTranscript(t *testing.T, file string) {
+	fixture := daptest.NewProject(t, daptest.Script{Path: "scripts/player.gd", Source: "extends Node\n"})
+	project := fixture.Dir
+	script := fixture.Path("scripts/player.gd")
+
+	mock := daptest.NewServer(t)
+	defer mock.Close()
+	go serveGodot(mock, script)
+
+	t.Cleanup(func() {
+		if session := currentSession(); session != nil {
+			shutdownSession(session, false)
+		}
+	})
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording, err := mcp.ReadRecording(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := fmt.Sprint(mock.Port())
+	exchanges, err := mcp.Replay(recording, RegisterAll, mcp.ReplayOptions{
+		Timeout: 5 * time.Second,
+		Rewrite: func(message map[string]interface{}) {
+			params, _ := message["params"].(map[string]interface{})
+			args, _ := params["arguments"].(map[string]interface{})
+			for k, v := range args {
+				switch v {
+				case "$PROJECT":
+					args[k] = project
+				case "$PORT":
+					args[k] = mock.Port()
+				}
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	// Put the placeholders back so responses compare across runs
+	for i := range exchanges {
+		if exchanges[i].Replayed == nil {
+			continue
+		}
+		replayed := bytes.ReplaceAll(exchanges[i].Replayed, []byte(project), []byte("$PROJECT"))
+		replayed = bytes.ReplaceAll(replayed, []byte(":"+port), []byte(":$PORT"))
+		exchanges[i].Replayed = replayed
+	}
+
+	if *updateGolden {
+		writeTranscript(t, file, recording, exchanges)
+		return
+	}
+	for _, e := range exchanges {
+		if diff := e.Diff(transcriptVolatileKeys...); diff != "" {
+			t.Errorf("%s\n(if intended, rerun with -update and review the transcript diff)", diff)
+		}
+	}
+}
+
+// writeTranscript rewrites a transcript with the replayed responses, each
+// following the request it answers
+func writeTranscript(t *testing.T, file string, recording []mcp.RecordedMessage, exchanges []mcp.ReplayExchange) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	next := 0
+	for _, msg := range recording {
+		if msg.From != mcp.FromClient {
+			continue
+		}
+		enc.Encode(mcp.RecordedMessage{From: mcp.FromClient, Message: msg.Message})
+
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(msg.Message, &request) != nil || len(request.ID) == 0 {
+			continue
+		}
+		if next < len(exchanges) && exchanges[next].Replayed != nil {
+			enc.Encode(mcp.RecordedMessage{From: mcp.FromServer, Message: exchanges[next].Replayed})
+		}
+		next++
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// maxResourceChecks caps the project files checked against the resource
+// cache, since each check is an evaluate request
+const maxResourceChecks = 500
+
+// resourceExtensions are the file types the game can load as resources
+var resourceExtensions = map[string]bool{
+	".tres": true, ".res": true, ".tscn": true, ".scn": true,
+	".gd": true, ".cs": true, ".gdshader": true, ".gdshaderinc": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".webp": true, ".svg": true, ".bmp": true, ".tga": true, ".exr": true, ".hdr": true,
+	".wav": true, ".ogg": true, ".mp3": true,
+	".ttf": true, ".otf": true, ".woff": true, ".woff2": true, ".fnt": true,
+	".glb": true, ".gltf": true, ".obj": true, ".fbx": true, ".blend": true,
+	".json": true, ".csv": true, ".translation": true,
+}
+
+// objectMonitors are the Performance monitors reported with the resources
+var objectMonitors = []struct{ name, monitor string }{
+	{"objects", "OBJECT_COUNT"},
+	{"resources", "OBJECT_RESOURCE_COUNT"},
+	{"nodes", "OBJECT_NODE_COUNT"},
+	{"orphan_nodes", "OBJECT_ORPHAN_NODE_COUNT"},
+}
+
+// objectClassPattern extracts the class from an Object's string form, "<Class#id>"
+var objectClassPattern = regexp.MustCompile(`<(\w+)#-?\d+>`)
+
+// loadedResource is a project file found in the game's resource cache
+type loadedResource struct {
+	Path           string `json:"path"`
+	Class          string `json:"class,omitempty"`
+	ReferenceCount *int   `json:"reference_count,omitempty"`
+}
+
+// findResourceFiles lists the project's loadable files as res:// paths,
+// sorted, skipping hidden directories such as .godot. Only paths starting
+// with prefix are returned.
+func findResourceFiles(projectRoot, prefix string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != projectRoot && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !resourceExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		if resPath := toResPath(path, projectRoot); resPath != "" && strings.HasPrefix(resPath, prefix) {
+			paths = append(paths, resPath)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// readLoadedResources checks which of paths are in the resource cache, with
+// their class and reference count where the Godot version exposes them
+// (ResourceLoader.get_cached_ref, Godot 4.4+)
+func readLoadedResources(ctx context.Context, client expressionEvaluator, frameId int, paths []string) ([]loadedResource, error) {
+	loaded := []loadedResource{}
+	for _, path := range paths {
+		quoted := strconv.Quote(path)
+		resp, err := client.Evaluate(ctx, fmt.Sprintf("ResourceLoader.has_cached(%s)", quoted), frameId, "watch")
+		if err != nil {
+			return nil, err
+		}
+		if resp.Body.Result != "true" {
+			continue
+		}
+
+		resource := loadedResource{Path: path}
+		ref := fmt.Sprintf("ResourceLoader.get_cached_ref(%s)", quoted)
+		if resp, err := client.Evaluate(ctx, fmt.Sprintf("str(%s)", ref), frameId, "watch"); err == nil {
+			if m := objectClassPattern.FindStringSubmatch(resp.Body.Result); m != nil {
+				resource.Class = m[1]
+			}
+		}
+		if resp, err := client.Evaluate(ctx, ref+".get_reference_count()", frameId, "watch"); err == nil {
+			if count, err := strconv.Atoi(strings.TrimSpace(resp.Body.Result)); err == nil {
+				resource.ReferenceCount = &count
+			}
+		}
+		loaded = append(loaded, resource)
+	}
+	return loaded, nil
+}
+
+// readObjectMonitors reads the object count monitors; failed ones are left out
+func readObjectMonitors(ctx context.Context, client expressionEvaluator, frameId int) map[string]interface{} {
+	monitors := map[string]interface{}{}
+	for _, m := range objectMonitors {
+		resp, err := client.Evaluate(ctx, fmt.Sprintf("Performance.get_monitor(Performance.%s)", m.monitor), frameId, "watch")
+		if err != nil {
+			continue
+		}
+		if value := parseGodotFloat(resp.Body.Result); value != nil {
+			monitors[m.name] = value
+		}
+	}
+	return monitors
+}
+
+// RegisterResourceTools registers godot_get_loaded_resources
+func RegisterResourceTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_loaded_resources",
+		Description: `List the project's resources currently loaded by the game.
+
+Godot has no API listing its resource cache, so this tool walks the project's
+loadable files (scenes, scripts, textures, audio, ...) and checks each with
+ResourceLoader.has_cached(). For cached resources, the class and reference
+count are read with ResourceLoader.get_cached_ref() where available (Godot
+4.4+). Object, resource, node, and orphan node counts from the Performance
+monitors are included.
+
+Useful for load-order problems (is it loaded yet?) and leaks (resources that
+stay cached, reference counts that keep growing, orphan nodes).
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- The project root must be known (godot_connect with project)
+
+Each file is a separate evaluate request; at most 500 files are checked. Use
+prefix to narrow large projects.
+
+Reference counts include the temporary reference held while evaluating.
+
+Example: Which level resources are still loaded?
+godot_get_loaded_resources(prefix="res://levels/")
+→ {"resources": [{"path": "res://levels/level_1.tscn", "class": "PackedScene",
+   "reference_count": 2}], "checked": 14, "monitors": {"orphan_nodes": 0, ...}}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "prefix",
+				Type:        "string",
+				Required:    false,
+				Default:     "res://",
+				Description: "Only check files whose res:// path starts with this prefix",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get loaded resources"); err != nil {
+				return nil, err
+			}
+			projectRoot := session.GetProjectRoot()
+			if projectRoot == "" {
+				return nil, fmt.Errorf("project root not set: call godot_connect with the project argument first")
+			}
+
+			prefix := "res://"
+			if p, ok := params["prefix"].(string); ok && p != "" {
+				prefix = p
+			}
+			if !strings.HasPrefix(prefix, "res://") {
+				return nil, fmt.Errorf("prefix must be a res:// path, got %q", prefix)
+			}
+
+			paths, err := findResourceFiles(projectRoot, prefix)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to list project files",
+					projectRoot,
+					[]string{"Check that the project root is readable"},
+					err,
+				)
+			}
+			result := map[string]interface{}{
+				"status":  "success",
+				"prefix":  prefix,
+				"checked": len(paths),
+			}
+			if len(paths) > maxResourceChecks {
+				result["truncated"] = true
+				result["file_count"] = len(paths)
+				result["checked"] = maxResourceChecks
+				paths = paths[:maxResourceChecks]
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			resources, err := readLoadedResources(ctx, client, 0, paths)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to check the resource cache",
+					"ResourceLoader.has_cached",
+					[]string{
+						"The game might have resumed; pause it and try again",
+						"Use a narrower prefix if the request timed out",
+					},
+					err,
+				)
+			}
+			result["resources"] = resources
+			result["loaded"] = len(resources)
+			result["monitors"] = readObjectMonitors(ctx, client, 0)
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindResourceFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"project.godot", "main.tscn", "levels/level_1.tscn", "levels/notes.txt", "sfx/jump.WAV", ".godot/imported/x.ctex"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := findResourceFiles(dir, "res://")
+	if err != nil {
+		t.Fatalf("findResourceFiles failed: %v", err)
+	}
+	if want := []string{"res://levels/level_1.tscn", "res://main.tscn", "res://sfx/jump.WAV"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected %v, got %v", want, paths)
+	}
+
+	paths, _ = findResourceFiles(dir, "res://levels/")
+	if !reflect.DeepEqual(paths, []string{"res://levels/level_1.tscn"}) {
+		t.Errorf("prefix should narrow the files, got %v", paths)
+	}
+}
+
+func TestReadLoadedResources(t *testing.T) {
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		`ResourceLoader.has_cached("res://main.tscn")`:                           {"true", "bool"},
+		`str(ResourceLoader.get_cached_ref("res://main.tscn"))`:                  {"<PackedScene#-9223372036>", "String"},
+		`ResourceLoader.get_cached_ref("res://main.tscn").get_reference_count()`: {"3", "int"},
+		`ResourceLoader.has_cached("res://old.tres")`:                            {"true", "bool"},
+		`ResourceLoader.has_cached("res://unused.png")`:                          {"false", "bool"},
+		"Performance.get_monitor(Performance.OBJECT_ORPHAN_NODE_COUNT)":          {"4", "float"},
+	}}
+
+	loaded, err := readLoadedResources(context.Background(), eval, 0, []string{"res://main.tscn", "res://old.tres", "res://unused.png"})
+	if err != nil {
+		t.Fatalf("readLoadedResources failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 cached resources, got %+v", loaded)
+	}
+	if loaded[0].Class != "PackedScene" || loaded[0].ReferenceCount == nil || *loaded[0].ReferenceCount != 3 {
+		t.Errorf("unexpected main scene: %+v", loaded[0])
+	}
+	// Without get_cached_ref (before Godot 4.4) only the path is known
+	if loaded[1].Class != "" || loaded[1].ReferenceCount != nil {
+		t.Errorf("unexpected resource without cached ref: %+v", loaded[1])
+	}
+
+	monitors := readObjectMonitors(context.Background(), eval, 0)
+	if !reflect.DeepEqual(monitors, map[string]interface{}{"orphan_nodes": 4.0}) {
+		t.Errorf("unexpected monitors: %v", monitors)
+	}
+}
@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// currentSceneExpression reads the active scene's file. It goes through
+// Engine rather than get_tree() so it works in frames of non-Node scripts.
+const currentSceneExpression = "Engine.get_main_loop().current_scene.scene_file_path"
+
+// maxSceneHistory caps the scene changes kept; the oldest are dropped
+const maxSceneHistory = 50
+
+// sceneObservation is the active scene seen at one stop
+type sceneObservation struct {
+	Scene  string
+	Time   time.Time
+	Reason string // Stop reason ("breakpoint", "step", ...) or "query"
+}
+
+// sceneTracker follows the active scene. Godot only evaluates expressions
+// while paused, so the scene is read at every stop; changes are kept in a
+// history and reported to the MCP client as log notifications.
+type sceneTracker struct {
+	mu      sync.Mutex
+	current *sceneObservation
+	history []sceneObservation // Each entry is a change to a new scene
+	stop    chan struct{}
+	notify  notifier
+}
+
+// Active scene tracking for godot_get_current_scene
+var scenes sceneTracker
+
+// observe records the scene seen at a stop, reporting it if it changed.
+// Returns true if it changed.
+func (st *sceneTracker) observe(obs sceneObservation) bool {
+	st.mu.Lock()
+	if st.current != nil && st.current.Scene == obs.Scene {
+		st.current.Time = obs.Time
+		st.mu.Unlock()
+		return false
+	}
+	from := ""
+	if st.current != nil {
+		from = st.current.Scene
+	}
+	st.current = &obs
+	st.history = append(st.history, obs)
+	if len(st.history) > maxSceneHistory {
+		st.history = st.history[len(st.history)-maxSceneHistory:]
+	}
+	n := st.notify
+	st.mu.Unlock()
+
+	if n != nil && from != "" {
+		err := n.Notify("notifications/message", map[string]interface{}{
+			"level":  "info",
+			"logger": "scene",
+			"data": map[string]interface{}{
+				"from":   from,
+				"to":     obs.Scene,
+				"reason": obs.Reason,
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to report scene change: %v", err)
+		}
+	}
+	return true
+}
+
+// read evaluates the active scene in the top frame of threadId
+func (st *sceneTracker) read(client watchEvaluator, threadId int) (string, error) {
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	frameId := 0
+	resp, err := client.StackTrace(ctx, threadId, 0, 1)
+	if err != nil {
+		return "", fmt.Errorf("stack trace failed: %w", err)
+	}
+	if len(resp.Body.StackFrames) > 0 {
+		frameId = resp.Body.StackFrames[0].Id
+	}
+	eval, err := client.Evaluate(ctx, currentSceneExpression, frameId, "watch")
+	if err != nil {
+		return "", err
+	}
+	return unquoteGodotString(eval.Body.Result), nil
+}
+
+// snapshot returns the last observed scene (nil if none) and the history
+func (st *sceneTracker) snapshot() (*sceneObservation, []sceneObservation) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	history := append([]sceneObservation(nil), st.history...)
+	if st.current == nil {
+		return nil, history
+	}
+	current := *st.current
+	return &current, history
+}
+
+// forget clears the observations, e.g. when the game ends
+func (st *sceneTracker) forget() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.current = nil
+	st.history = nil
+}
+
+// attach starts reading the scene on the client's stopped events, replacing
+// any previous attachment. Observations from an earlier game are dropped.
+func (st *sceneTracker) attach(client *dap.Client, n notifier) {
+	st.detach()
+	st.forget()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	stop := make(chan struct{})
+	st.stop = stop
+	st.notify = n
+
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("scene tracker"))
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				switch e := msg.(type) {
+				case *godap.StoppedEvent:
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					// Errors (no scene yet, or the game resumed) leave the last observation
+					if scene, err := st.read(client, threadId); err == nil {
+						st.observe(sceneObservation{Scene: scene, Time: time.Now(), Reason: e.Body.Reason})
+					}
+				case *godap.TerminatedEvent:
+					st.forget()
+				}
+			}
+		}
+	}()
+}
+
+// detach stops following the scene; observations are kept
+func (st *sceneTracker) detach() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.stop != nil {
+		close(st.stop)
+		st.stop = nil
+	}
+}
+
+// formatSceneObservation converts an observation for tool responses
+func formatSceneObservation(obs sceneObservation) map[string]interface{} {
+	return map[string]interface{}{
+		"scene":  obs.Scene,
+		"time":   obs.Time.Format(time.RFC3339Nano),
+		"reason": obs.Reason,
+	}
+}
+
+// RegisterSceneTools registers godot_get_current_scene
+func RegisterSceneTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_current_scene",
+		Description: `Get the game's active scene and the scene changes seen so far.
+
+Godot only evaluates expressions while the game is paused, so the active scene
+is read at every stop (breakpoint, step, pause) and when this tool is called
+while paused. Each change is also sent to the client as a log notification
+(logger "scene"), which helps keep track of multi-scene games during long runs.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+While the game runs, the result is the scene seen at the last stop
+(fresh=false, with observed_at). Scenes changed and left again between two
+stops aren't seen.
+
+Example: Which level is loaded?
+godot_get_current_scene()
+→ {"scene": "res://levels/level_2.tscn", "fresh": true, "changes": [...]}`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requireGameActive(session, "get current scene"); err != nil {
+				return nil, err
+			}
+
+			result := map[string]interface{}{"status": "success", "fresh": false}
+			client := session.GetClient()
+			if client.RunState() == dap.RunStatePaused {
+				scene, err := scenes.read(client, 1)
+				if err != nil {
+					return nil, FormatError(
+						"Failed to read the current scene",
+						currentSceneExpression,
+						[]string{
+							"The scene might be changing (current_scene is null between scenes)",
+							"The game might have resumed",
+						},
+						err,
+					)
+				}
+				scenes.observe(sceneObservation{Scene: scene, Time: time.Now(), Reason: "query"})
+				result["fresh"] = true
+			}
+
+			current, history := scenes.snapshot()
+			if current == nil {
+				result["status"] = "unknown"
+				result["message"] = "No scene seen yet: the scene is read when the game stops. Pause the game (godot_pause) to read it now."
+				return result, nil
+			}
+			result["scene"] = current.Scene
+			result["observed_at"] = current.Time.Format(time.RFC3339Nano)
+
+			changes := make([]map[string]interface{}, len(history))
+			for i, obs := range history {
+				changes[i] = formatSceneObservation(obs)
+			}
+			result["changes"] = changes
+			return result, nil
+		},
+	})
+}
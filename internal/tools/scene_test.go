@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSceneTracker_Observe(t *testing.T) {
+	n := &recordingNotifier{}
+	st := sceneTracker{notify: n}
+
+	if current, _ := st.snapshot(); current != nil {
+		t.Fatal("expected no scene before the first stop")
+	}
+
+	// The first observation isn't a change worth reporting
+	if !st.observe(sceneObservation{Scene: "res://menu.tscn", Time: time.Now(), Reason: "breakpoint"}) {
+		t.Error("first observation should be recorded")
+	}
+	if st.observe(sceneObservation{Scene: "res://menu.tscn", Time: time.Now(), Reason: "step"}) {
+		t.Error("the same scene again isn't a change")
+	}
+	if !st.observe(sceneObservation{Scene: "res://level_1.tscn", Time: time.Now(), Reason: "breakpoint"}) {
+		t.Error("a new scene should be recorded")
+	}
+	if n.count() != 1 {
+		t.Fatalf("expected one change notification, got %d", n.count())
+	}
+	data := n.notifications[0]["data"].(map[string]interface{})
+	if n.notifications[0]["logger"] != "scene" || data["from"] != "res://menu.tscn" || data["to"] != "res://level_1.tscn" {
+		t.Errorf("unexpected notification: %v", n.notifications[0])
+	}
+
+	current, history := st.snapshot()
+	if current == nil || current.Scene != "res://level_1.tscn" {
+		t.Errorf("unexpected current scene: %+v", current)
+	}
+	if len(history) != 2 {
+		t.Errorf("expected 2 scenes in the history, got %d", len(history))
+	}
+
+	st.forget()
+	if current, history := st.snapshot(); current != nil || len(history) != 0 {
+		t.Error("forget should clear the observations")
+	}
+}
+
+func TestSceneTracker_Read(t *testing.T) {
+	var st sceneTracker
+	eval := &fakeWatchEvaluator{values: map[string]string{
+		currentSceneExpression: `"res://levels/level_2.tscn"`,
+	}}
+	scene, err := st.read(eval, 1)
+	if err != nil || scene != "res://levels/level_2.tscn" {
+		t.Errorf("read = %q, %v; want the unquoted scene path", scene, err)
+	}
+
+	if _, err := st.read(&fakeWatchEvaluator{values: map[string]string{}}, 1); err == nil {
+		t.Error("expected an error when the scene can't be evaluated")
+	}
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// seedApplication records one call to seed() in the game
+type seedApplication struct {
+	Seed   int64
+	Reason string // Stop reason, or "manual" for godot_set_seed while paused
+	Time   time.Time
+	Error  string
+}
+
+// seedController seeds the game's global random number generator with a
+// fixed seed at the first stop of every run, so randomized gameplay repeats
+// across launches
+type seedController struct {
+	mu      sync.Mutex
+	seed    int64
+	auto    bool
+	applied bool // Seeded in the current run
+	last    *seedApplication
+	stop    chan struct{}
+}
+
+// Seed applied at the first stop of each run (godot_set_seed with auto=true)
+var seeding = &seedController{}
+
+// applySeed calls seed() in the top frame of threadId
+func applySeed(ctx context.Context, client watchEvaluator, threadId int, seed int64) error {
+	frameId := 0
+	if resp, err := client.StackTrace(ctx, threadId, 0, 1); err == nil && len(resp.Body.StackFrames) > 0 {
+		frameId = resp.Body.StackFrames[0].Id
+	}
+	_, err := client.Evaluate(ctx, fmt.Sprintf("seed(%d)", seed), frameId, "repl")
+	return err
+}
+
+// record remembers an application of seed; a successful one marks the run seeded
+func (sc *seedController) record(seed int64, reason string, err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	application := seedApplication{Seed: seed, Reason: reason, Time: time.Now()}
+	if err != nil {
+		application.Error = err.Error()
+	} else {
+		sc.applied = true
+	}
+	sc.last = &application
+}
+
+// onStopped seeds the game if auto seeding is on and this run isn't seeded yet
+func (sc *seedController) onStopped(client watchEvaluator, threadId int, reason string) {
+	sc.mu.Lock()
+	pending := sc.auto && !sc.applied
+	seed := sc.seed
+	sc.mu.Unlock()
+	if !pending {
+		return
+	}
+
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+	err := applySeed(ctx, client, threadId, seed)
+	if err != nil {
+		log.Printf("Failed to seed the game at the %s stop: %v", reason, err)
+	}
+	sc.record(seed, reason, err)
+}
+
+// newRun forgets that the current run was seeded
+func (sc *seedController) newRun() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.applied = false
+}
+
+// configure sets the seed and whether it is applied automatically. A new
+// automatic seed is applied at the next stop even if the run was seeded.
+func (sc *seedController) configure(seed int64, auto bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.seed = seed
+	sc.auto = auto
+	sc.applied = false
+}
+
+// enabled reports whether the seed is applied automatically
+func (sc *seedController) enabled() bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.auto
+}
+
+// attach starts seeding at the client's first stop of each run, replacing
+// any previous attachment
+func (sc *seedController) attach(client *dap.Client) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.stop != nil {
+		close(sc.stop)
+	}
+	stop := make(chan struct{})
+	sc.stop = stop
+
+	// Stops are handled on a queue so the listener keeps draining events
+	// while the seed is set
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("seeding"), dap.WithBlockWhenFull())
+	queue := newWorkQueue(stop)
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				switch e := msg.(type) {
+				case *godap.StoppedEvent:
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					reason := e.Body.Reason
+					queue.push(func() { sc.onStopped(client, threadId, reason) })
+				case *godap.TerminatedEvent, *godap.ExitedEvent:
+					queue.push(sc.newRun)
+				}
+			}
+		}
+	}()
+}
+
+// detach stops automatic seeding; the configured seed is kept
+func (sc *seedController) detach() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.stop != nil {
+		close(sc.stop)
+		sc.stop = nil
+	}
+}
+
+// status describes the seeding configuration and the last application
+func (sc *seedController) status() map[string]interface{} {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	result := map[string]interface{}{
+		"auto":            sc.auto,
+		"seeded_this_run": sc.applied,
+	}
+	if sc.auto {
+		result["seed"] = sc.seed
+	}
+	if sc.last != nil {
+		last := map[string]interface{}{
+			"seed":   sc.last.Seed,
+			"reason": sc.last.Reason,
+			"time":   sc.last.Time.Format(time.RFC3339),
+		}
+		if sc.last.Error != "" {
+			last["error"] = sc.last.Error
+		}
+		result["last_applied"] = last
+	}
+	return result
+}
+
+// RegisterSeedTools registers godot_set_seed
+func RegisterSeedTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_set_seed",
+		Description: `Seed the game's global random number generator for reproducible runs.
+
+Calls seed(<seed>) in the game, so randi(), randf(), randf_range(), and the
+other global random functions return the same sequence on every run. Godot
+randomizes the global seed at startup, so a randomized bug otherwise shows up
+differently (or not at all) each launch.
+
+- While paused, the seed is applied right away.
+- With auto=true, the seed is also applied at the first stop of every run.
+  Launch with stop_on_entry=true so that stop happens before gameplay code
+  draws any random numbers, then continue.
+- auto=false without a seed turns automatic seeding off.
+
+Only the global generator is seeded: RandomNumberGenerator instances and
+noise resources keep their own seeds.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused, unless auto=true
+
+Example: Reproduce a randomized bug with the same seed every launch
+godot_set_seed(seed=12345, auto=true)
+godot_launch_main_scene(project="/path/to/project", stop_on_entry=true)
+godot_continue()`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "seed",
+				Type:        "number",
+				Required:    false,
+				Description: "Integer seed for the global random number generator",
+			},
+			{
+				Name:        "auto",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, also apply the seed at the first stop of every run; false without a seed turns this off",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			auto := getBoolParam(params, "auto")
+			s, hasSeed := params["seed"].(float64)
+			if !hasSeed {
+				if auto {
+					return nil, fmt.Errorf("seed is required with auto=true")
+				}
+				seeding.configure(0, false)
+				seeding.detach()
+				result := seeding.status()
+				result["status"] = "disabled"
+				return result, nil
+			}
+			if s != math.Trunc(s) || math.Abs(s) > 1<<53 {
+				return nil, fmt.Errorf("seed must be an integer between -2^53 and 2^53, got %v", s)
+			}
+			seed := int64(s)
+
+			client := session.GetClient()
+			paused := client.RunState() == dap.RunStatePaused
+			if !paused && !auto {
+				if err := requirePaused(session, "set the seed"); err != nil {
+					return nil, fmt.Errorf("%w\n\nOr pass auto=true to apply the seed at the next stop", err)
+				}
+			}
+
+			seeding.configure(seed, auto)
+			if auto {
+				seeding.attach(client)
+			} else {
+				seeding.detach()
+			}
+
+			status := "scheduled"
+			if paused {
+				ctx, cancel := dap.WithCommandTimeout(context.Background())
+				defer cancel()
+				err := applySeed(ctx, client, 1, seed)
+				seeding.record(seed, "manual", err)
+				if err != nil {
+					return nil, FormatError(
+						"Failed to set the seed",
+						fmt.Sprintf("seed(%d)", seed),
+						[]string{"The game might have resumed; pause it and try again"},
+						err,
+					)
+				}
+				status = "applied"
+			}
+
+			result := seeding.status()
+			result["status"] = status
+			result["seed"] = seed
+			if status == "scheduled" {
+				result["message"] = "The seed is applied at the next stop; launch with stop_on_entry=true to seed before gameplay starts"
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package tools
+
+import "testing"
+
+func TestSeedController_OncePerRun(t *testing.T) {
+	sc := &seedController{}
+	eval := &fakeWatchEvaluator{values: map[string]string{"seed(42)": "null"}}
+
+	// Nothing happens until auto seeding is configured
+	sc.onStopped(eval, 1, "entry")
+	if sc.status()["last_applied"] != nil {
+		t.Fatal("the seed should not be applied without auto")
+	}
+
+	sc.configure(42, true)
+	sc.onStopped(eval, 1, "entry")
+	status := sc.status()
+	if status["seeded_this_run"] != true || status["seed"] != int64(42) {
+		t.Fatalf("expected the first stop to seed the run, got %v", status)
+	}
+	last := sc.last
+	sc.onStopped(eval, 1, "breakpoint")
+	if sc.last != last {
+		t.Error("later stops of the same run should not seed again")
+	}
+
+	sc.newRun()
+	sc.onStopped(eval, 1, "breakpoint")
+	if sc.last == last || sc.last.Reason != "breakpoint" {
+		t.Errorf("the next run should be seeded at its first stop, got %+v", sc.last)
+	}
+}
+
+func TestSeedController_Failure(t *testing.T) {
+	sc := &seedController{}
+	sc.configure(7, true)
+	sc.onStopped(&fakeWatchEvaluator{values: map[string]string{}}, 1, "entry")
+
+	status := sc.status()
+	if status["seeded_this_run"] != false {
+		t.Error("a failed seed should leave the run unseeded so the next stop retries")
+	}
+	last, _ := status["last_applied"].(map[string]interface{})
+	if last == nil || last["error"] == nil {
+		t.Errorf("expected the failure to be reported, got %v", status)
+	}
+}
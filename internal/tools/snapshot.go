@@ -0,0 +1,426 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// sessionFileEnv names the environment variable holding the session snapshot
+// file. When set, the snapshot is saved there after every change to the
+// debugging setup, and godot_save_session/godot_restore_session use it by
+// default, so a restarted server can pick up where a crashed one left off.
+const sessionFileEnv = "GODOT_MCP_SESSION_FILE"
+
+// sessionSnapshotVersion is bumped when the snapshot format changes incompatibly
+const sessionSnapshotVersion = 1
+
+// sessionSnapshot is the logical debugging setup saved to disk: everything
+// needed to recreate it on a fresh connection, but no runtime state
+type sessionSnapshot struct {
+	Version       int                       `json:"version"`
+	SavedAt       time.Time                 `json:"saved_at"`
+	ProjectRoot   string                    `json:"project_root,omitempty"`
+	Breakpoints   map[string][]int          `json:"breakpoints,omitempty"`               // Absolute path → lines
+	Conditions    map[string]map[int]string `json:"breakpoint_conditions,omitempty"`     // Absolute path → line → condition
+	HitConditions map[string]map[int]string `json:"breakpoint_hit_conditions,omitempty"` // Absolute path → line → hit condition
+	LogMessages   map[string]map[int]string `json:"breakpoint_log_messages,omitempty"`   // Absolute path → line → logpoint message
+	Functions     map[string]map[int]string `json:"breakpoint_functions,omitempty"`      // Absolute path → line → function of a function breakpoint
+	Watches       []string                  `json:"watches,omitempty"`
+	Launch        *dap.GodotLaunchConfig    `json:"launch,omitempty"` // Most recent launch
+	GameState     *gameStateHelpers         `json:"game_state,omitempty"`
+}
+
+// breakpointSetter is the subset of *dap.Client used to re-apply breakpoints
+type breakpointSetter interface {
+	SetSourceBreakpoints(ctx context.Context, file string, breakpoints []godap.SourceBreakpoint) (*godap.SetBreakpointsResponse, error)
+}
+
+// Breakpoints restored by godot_restore_session while disconnected, handed
+// to the session of the next godot_connect
+var (
+	restoredBreakpoints   *dap.BreakpointRegistry
+	restoredBreakpointsMu sync.Mutex
+)
+
+// holdRestoredBreakpoints returns the breakpoints waiting for the next
+// godot_connect, starting an empty set if there are none
+func holdRestoredBreakpoints() *dap.BreakpointRegistry {
+	restoredBreakpointsMu.Lock()
+	defer restoredBreakpointsMu.Unlock()
+	if restoredBreakpoints == nil {
+		restoredBreakpoints = &dap.BreakpointRegistry{}
+	}
+	return restoredBreakpoints
+}
+
+// takeRestoredBreakpoints returns the breakpoints restored while
+// disconnected, if any, and forgets them
+func takeRestoredBreakpoints() *dap.BreakpointRegistry {
+	restoredBreakpointsMu.Lock()
+	defer restoredBreakpointsMu.Unlock()
+	breakpoints := restoredBreakpoints
+	restoredBreakpoints = nil
+	return breakpoints
+}
+
+// currentBreakpoints returns the breakpoints of the current session, or
+// while disconnected those waiting for the next godot_connect; nil if there
+// are neither
+func currentBreakpoints() *dap.BreakpointRegistry {
+	if session := currentSession(); session != nil {
+		return session.Breakpoints()
+	}
+	restoredBreakpointsMu.Lock()
+	defer restoredBreakpointsMu.Unlock()
+	return restoredBreakpoints
+}
+
+// Most recent successful launch, restored by godot_restore_session(launch=true)
+var (
+	lastLaunch   *dap.GodotLaunchConfig
+	lastLaunchMu sync.Mutex
+)
+
+// recordLaunch remembers a successful launch configuration
+func recordLaunch(config *dap.GodotLaunchConfig) {
+	copied := *config
+	lastLaunchMu.Lock()
+	lastLaunch = &copied
+	lastLaunchMu.Unlock()
+	autosaveSession()
+}
+
+// getLastLaunch returns a copy of the most recent launch configuration, or nil
+func getLastLaunch() *dap.GodotLaunchConfig {
+	lastLaunchMu.Lock()
+	defer lastLaunchMu.Unlock()
+	if lastLaunch == nil {
+		return nil
+	}
+	copied := *lastLaunch
+	return &copied
+}
+
+// captureSession builds a snapshot of the current debugging setup
+func captureSession() sessionSnapshot {
+	snapshot := sessionSnapshot{
+		Version:     sessionSnapshotVersion,
+		SavedAt:     time.Now(),
+		ProjectRoot: getDiscoveredProjectRoot(),
+		Watches:     watches.list(),
+		Launch:      getLastLaunch(),
+	}
+	if breakpoints := currentBreakpoints(); breakpoints != nil {
+		snapshot.Breakpoints = breakpoints.All()
+		snapshot.Conditions = breakpoints.AllConditions()
+		snapshot.HitConditions = breakpoints.AllHitConditions()
+		snapshot.LogMessages = breakpoints.AllLogMessages()
+		snapshot.Functions = breakpoints.AllFunctions()
+	}
+	if h := getGameStateHelpers(); h != defaultGameStateHelpers {
+		snapshot.GameState = &h
+	}
+	if session := currentSession(); session != nil && session.GetProjectRoot() != "" {
+		snapshot.ProjectRoot = session.GetProjectRoot()
+	}
+	return snapshot
+}
+
+// writeSessionSnapshot saves a snapshot as JSON. The file is written to a
+// temporary name and renamed, so a crash mid-write keeps the previous snapshot.
+func writeSessionSnapshot(path string, snapshot sessionSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readSessionSnapshot loads a snapshot saved by writeSessionSnapshot
+func readSessionSnapshot(path string) (sessionSnapshot, error) {
+	var snapshot sessionSnapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("invalid session snapshot: %w", err)
+	}
+	if snapshot.Version != sessionSnapshotVersion {
+		return snapshot, fmt.Errorf("unsupported session snapshot version %d (expected %d)", snapshot.Version, sessionSnapshotVersion)
+	}
+	return snapshot, nil
+}
+
+// autosaveSession saves the current setup to the GODOT_MCP_SESSION_FILE
+// snapshot, if configured. Failures are logged; they never fail a tool call.
+func autosaveSession() {
+	path := os.Getenv(sessionFileEnv)
+	if path == "" {
+		return
+	}
+	if err := writeSessionSnapshot(path, captureSession()); err != nil {
+		log.Printf("Failed to save session snapshot to %s: %v", path, err)
+	}
+}
+
+// applyBreakpoints sets breakpoints on a connection, one setBreakpoints
+// request per file in path order, with the conditions, hit conditions, and
+// log messages recorded in breakpoints. Returns one result per file.
+func applyBreakpoints(ctx context.Context, client breakpointSetter, breakpoints *dap.BreakpointRegistry, files map[string][]int) []map[string]interface{} {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	results := make([]map[string]interface{}, 0, len(paths))
+	for _, path := range paths {
+		result := map[string]interface{}{
+			"file":  path,
+			"lines": files[path],
+		}
+		resp, err := client.SetSourceBreakpoints(ctx, path, breakpoints.SourceBreakpoints(path, files[path]))
+		if err != nil {
+			result["error"] = err.Error()
+		} else {
+			verified := 0
+			for _, bp := range resp.Body.Breakpoints {
+				if bp.Verified {
+					verified++
+				}
+			}
+			result["verified"] = verified
+			if warnings := breakpointWarnings(diagnoseBreakpoints(path, files[path], resp.Body.Breakpoints)); len(warnings) > 0 {
+				result["warnings"] = warnings
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// sessionFileParam resolves the snapshot file from the path parameter or
+// GODOT_MCP_SESSION_FILE
+func sessionFileParam(params map[string]interface{}) (string, error) {
+	path, _ := params["path"].(string)
+	if path == "" {
+		path = os.Getenv(sessionFileEnv)
+	}
+	if path == "" {
+		return "", FormatError(
+			"No session snapshot file given",
+			"",
+			[]string{
+				"Pass path=\"/absolute/path/session.json\"",
+				fmt.Sprintf("Or set the %s environment variable for the server", sessionFileEnv),
+			},
+			nil,
+		)
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be absolute (got: %s)", path)
+	}
+	if err := checkPathAllowed(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// RegisterSnapshotTools registers session snapshot tools
+func RegisterSnapshotTools(server *mcp.Server) {
+	// godot_save_session - Save the debugging setup to disk
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_save_session",
+		Description: `Save the debugging setup to a JSON file so it can be restored later.
+
+The snapshot holds the project root, the breakpoints set with
+godot_set_breakpoint, the registered watches, the most recent launch
+configuration, and the game state helpers of godot_save_game_state. Runtime state (threads, variables, output) is not saved.
+
+If the server was started with GODOT_MCP_SESSION_FILE set, the snapshot is
+also saved there automatically after every change, and path defaults to it.
+
+Example: Save the current setup
+godot_save_session(path="/Users/dev/my-game/.godot/mcp_session.json")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "path",
+				Type:        "string",
+				Required:    false,
+				Description: "Absolute path of the snapshot file (default: GODOT_MCP_SESSION_FILE)",
+				Validate:    validateAbsolutePathParam,
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			path, err := sessionFileParam(params)
+			if err != nil {
+				return nil, err
+			}
+
+			snapshot := captureSession()
+			if err := writeSessionSnapshot(path, snapshot); err != nil {
+				return nil, FormatError("Failed to save session snapshot", path, []string{"Check that the directory is writable"}, err)
+			}
+
+			return map[string]interface{}{
+				"status":      "saved",
+				"path":        path,
+				"project":     snapshot.ProjectRoot,
+				"breakpoints": len(snapshot.Breakpoints),
+				"watches":     len(snapshot.Watches),
+				"has_launch":  snapshot.Launch != nil,
+			}, nil
+		},
+	})
+
+	// godot_restore_session - Recreate a saved debugging setup
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_restore_session",
+		Description: `Restore a debugging setup saved by godot_save_session.
+
+Registers the saved watches and breakpoints and, if no project is known yet,
+uses the saved project root. When connected, the breakpoints are set right
+away; otherwise they are set by the next godot_connect. With launch=true, the
+saved launch configuration is launched again.
+
+Use this tool:
+- After the MCP server restarted, to resume debugging quickly
+- To switch between debugging setups
+
+Example: Resume after a server restart (GODOT_MCP_SESSION_FILE set)
+godot_connect()
+godot_restore_session(launch=true)
+
+Example: Restore from a specific file
+godot_restore_session(path="/Users/dev/my-game/.godot/mcp_session.json")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "path",
+				Type:        "string",
+				Required:    false,
+				Description: "Absolute path of the snapshot file (default: GODOT_MCP_SESSION_FILE)",
+				Validate:    validateAbsolutePathParam,
+			},
+			{
+				Name:        "launch",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "If true, relaunch the saved launch configuration (requires a connection)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			path, err := sessionFileParam(params)
+			if err != nil {
+				return nil, err
+			}
+
+			snapshot, err := readSessionSnapshot(path)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read session snapshot",
+					path,
+					[]string{"Check that the file was written by godot_save_session"},
+					err,
+				)
+			}
+
+			for _, expression := range snapshot.Watches {
+				watches.add(expression)
+			}
+			session := currentSession()
+			var breakpoints *dap.BreakpointRegistry
+			if session != nil {
+				breakpoints = session.Breakpoints()
+			} else {
+				breakpoints = holdRestoredBreakpoints()
+			}
+			for file, lines := range snapshot.Breakpoints {
+				breakpoints.Set(file, lines)
+				breakpoints.SetConditions(file, snapshot.Conditions[file])
+				breakpoints.SetHitConditions(file, snapshot.HitConditions[file])
+				breakpoints.SetLogMessages(file, snapshot.LogMessages[file])
+				breakpoints.SetFunctions(file, snapshot.Functions[file])
+			}
+			if snapshot.Launch != nil {
+				recordLaunch(snapshot.Launch)
+			}
+			if snapshot.GameState != nil {
+				setGameStateHelpers(*snapshot.GameState)
+			}
+			autosaveSession()
+
+			if session == nil {
+				if snapshot.ProjectRoot != "" && getDiscoveredProjectRoot() == "" {
+					setDiscoveredProjectRoot(snapshot.ProjectRoot)
+				}
+				if getBoolParam(params, "launch") {
+					return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to launch the restored configuration", ErrNotConnected())
+				}
+				return map[string]interface{}{
+					"status":  "restored",
+					"message": "Setup restored; breakpoints will be set by godot_connect",
+					"path":    path,
+					"project": snapshot.ProjectRoot,
+					"watches": snapshot.Watches,
+				}, nil
+			}
+
+			if snapshot.ProjectRoot != "" && session.GetProjectRoot() == "" {
+				session.SetProjectRoot(snapshot.ProjectRoot)
+			}
+			client := session.GetClient()
+			if len(snapshot.Watches) > 0 && !watches.attached() {
+				watches.attach(client)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result := map[string]interface{}{
+				"status":      "restored",
+				"path":        path,
+				"project":     session.GetProjectRoot(),
+				"watches":     snapshot.Watches,
+				"breakpoints": applyBreakpoints(ctx, client, breakpoints, snapshot.Breakpoints),
+			}
+			enforceConditions(session)
+
+			if getBoolParam(params, "launch") {
+				if snapshot.Launch == nil {
+					result["launch"] = map[string]interface{}{"launched": false, "error": "the snapshot has no launch configuration"}
+					return result, nil
+				}
+				if _, err := launchScene(ctx, session, snapshot.Launch, snapshot.Launch.StopOnEntry); err != nil {
+					result["launch"] = map[string]interface{}{"launched": false, "error": err.Error()}
+				} else {
+					result["launch"] = map[string]interface{}{"launched": true, "scene": string(snapshot.Launch.Scene)}
+				}
+			}
+			return result, nil
+		},
+	})
+}
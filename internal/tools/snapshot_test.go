@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/pkg/daptest"
+	godap "github.com/google/go-dap"
+)
+
+// recordingBreakpointSetter verifies every requested line except failFile
+type recordingBreakpointSetter struct {
+	calls    []string
+	sent     []godap.SourceBreakpoint
+	failFile string
+}
+
+func (r *recordingBreakpointSetter) SetSourceBreakpoints(ctx context.Context, file string, breakpoints []godap.SourceBreakpoint) (*godap.SetBreakpointsResponse, error) {
+	r.calls = append(r.calls, file)
+	r.sent = append(r.sent, breakpoints...)
+	if file == r.failFile {
+		return nil, fmt.Errorf("file not found")
+	}
+	resp := &godap.SetBreakpointsResponse{}
+	for _, bp := range breakpoints {
+		resp.Body.Breakpoints = append(resp.Body.Breakpoints, godap.Breakpoint{Verified: true, Line: bp.Line})
+	}
+	return resp, nil
+}
+
+func TestSessionSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "session.json")
+	snapshot := sessionSnapshot{
+		Version:       sessionSnapshotVersion,
+		SavedAt:       time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		ProjectRoot:   "/games/demo",
+		Breakpoints:   map[string][]int{"/games/demo/player.gd": {12, 40}},
+		Conditions:    map[string]map[int]string{"/games/demo/player.gd": {40: "health <= 0"}},
+		HitConditions: map[string]map[int]string{"/games/demo/player.gd": {12: ">= 10"}},
+		LogMessages:   map[string]map[int]string{"/games/demo/player.gd": {40: "hit for {damage}"}},
+		Functions:     map[string]map[int]string{"/games/demo/player.gd": {12: "_process"}},
+		Watches:       []string{"velocity", "health"},
+		Launch: &dap.GodotLaunchConfig{
+			Project:   "/games/demo",
+			Scene:     dap.SceneLaunchCustom,
+			ScenePath: "res://levels/one.tscn",
+			Profiling: true,
+		},
+		GameState: &gameStateHelpers{NodePath: "/root/GameState", SaveMethod: "save_to_slot", LoadMethod: "load_slot", LastSlot: "boss"},
+	}
+
+	if err := writeSessionSnapshot(path, snapshot); err != nil {
+		t.Fatalf("writeSessionSnapshot failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file should be renamed away, stat err = %v", err)
+	}
+
+	got, err := readSessionSnapshot(path)
+	if err != nil {
+		t.Fatalf("readSessionSnapshot failed: %v", err)
+	}
+	if !got.SavedAt.Equal(snapshot.SavedAt) {
+		t.Errorf("SavedAt = %v, want %v", got.SavedAt, snapshot.SavedAt)
+	}
+	got.SavedAt = snapshot.SavedAt
+	if !reflect.DeepEqual(got, snapshot) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, snapshot)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"scene_path": "res://levels/one.tscn"`) {
+		t.Errorf("launch config should use snake_case keys:\n%s", data)
+	}
+}
+
+func TestReadSessionSnapshot_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := readSessionSnapshot(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("missing file should return a not-exist error, got %v", err)
+	}
+
+	garbage := filepath.Join(dir, "garbage.json")
+	os.WriteFile(garbage, []byte("not json"), 0o644)
+	if _, err := readSessionSnapshot(garbage); err == nil || !strings.Contains(err.Error(), "invalid session snapshot") {
+		t.Errorf("expected invalid snapshot error, got %v", err)
+	}
+
+	future := filepath.Join(dir, "future.json")
+	os.WriteFile(future, []byte(`{"version": 99}`), 0o644)
+	if _, err := readSessionSnapshot(future); err == nil || !strings.Contains(err.Error(), "unsupported session snapshot version 99") {
+		t.Errorf("expected version error, got %v", err)
+	}
+}
+
+func TestApplyBreakpoints(t *testing.T) {
+	breakpoints := &dap.BreakpointRegistry{}
+	breakpoints.Set("/p/a.gd", []int{3})
+	breakpoints.Set("/p/z.gd", []int{1, 2})
+	breakpoints.SetConditions("/p/a.gd", map[int]string{3: "i > 100"})
+	breakpoints.SetHitConditions("/p/z.gd", map[int]string{2: "% 10"})
+	breakpoints.SetLogMessages("/p/z.gd", map[int]string{1: "hp={hp}"})
+
+	setter := &recordingBreakpointSetter{failFile: "/p/missing.gd"}
+	results := applyBreakpoints(context.Background(), setter, breakpoints, map[string][]int{
+		"/p/z.gd":       {1, 2},
+		"/p/missing.gd": {4},
+		"/p/a.gd":       {3},
+	})
+
+	wantOrder := []string{"/p/a.gd", "/p/missing.gd", "/p/z.gd"}
+	if !reflect.DeepEqual(setter.calls, wantOrder) {
+		t.Errorf("files should be applied in path order, got %v", setter.calls)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[1]["error"] != "file not found" {
+		t.Errorf("failed file should report its error, got %v", results[1])
+	}
+	if results[2]["verified"] != 2 {
+		t.Errorf("expected 2 verified breakpoints, got %v", results[2])
+	}
+	if setter.sent[0].Condition != "i > 100" || setter.sent[1].Condition != "" {
+		t.Errorf("conditions should be sent with their lines, got %+v", setter.sent)
+	}
+	if last := setter.sent[len(setter.sent)-1]; last.Line != 2 || last.HitCondition != "% 10" {
+		t.Errorf("hit conditions should be sent with their lines, got %+v", setter.sent)
+	}
+	if logpoint := setter.sent[2]; logpoint.Line != 1 || logpoint.LogMessage != "hp={hp}" {
+		t.Errorf("log messages should be sent with their lines, got %+v", setter.sent)
+	}
+}
+
+func TestBreakpoints_EndWithSession(t *testing.T) {
+	t.Setenv(sessionFileEnv, "")
+	fixture := daptest.NewProject(t, daptest.Script{Path: "scripts/player.gd", Source: "extends Node\n"})
+	script := fixture.Path("scripts/player.gd")
+	snapshotFile := filepath.Join(t.TempDir(), "session.json")
+	if err := writeSessionSnapshot(snapshotFile, sessionSnapshot{
+		Version:     sessionSnapshotVersion,
+		ProjectRoot: fixture.Dir,
+		Breakpoints: map[string][]int{script: {3}},
+		Conditions:  map[string]map[int]string{script: {3: "hp < 10"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := mcp.NewServer()
+	RegisterAll(server)
+	t.Cleanup(func() {
+		takeRestoredBreakpoints()
+		if session := currentSession(); session != nil {
+			shutdownSession(session, false)
+		}
+	})
+	connect := func() (interface{}, error) {
+		mock := daptest.NewServer(t)
+		t.Cleanup(mock.Close)
+		go serveGodot(mock, script)
+		return server.CallTool("godot_connect", map[string]interface{}{"port": float64(mock.Port()), "project": fixture.Dir})
+	}
+
+	// Restored while disconnected, the breakpoints wait for the next connect
+	if _, err := server.CallTool("godot_restore_session", map[string]interface{}{"path": snapshotFile}); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if lines := currentBreakpoints().Lines(script); !reflect.DeepEqual(lines, []int{3}) {
+		t.Fatalf("restored breakpoints should be held, got %v", lines)
+	}
+	result, err := connect()
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	if _, ok := result.(map[string]interface{})["breakpoints"]; !ok {
+		t.Errorf("connect should set the restored breakpoints, got %v", result)
+	}
+	if got := currentSession().Breakpoints().Condition(script, 3); got != "hp < 10" {
+		t.Errorf("the session should own the restored breakpoints, got condition %q", got)
+	}
+
+	// A new connection starts without the previous one's breakpoints
+	result, err = connect()
+	if err != nil {
+		t.Fatalf("reconnect failed: %v", err)
+	}
+	if _, ok := result.(map[string]interface{})["breakpoints"]; ok {
+		t.Errorf("reconnecting should not set the previous session's breakpoints, got %v", result)
+	}
+	if files := currentSession().Breakpoints().All(); len(files) != 0 {
+		t.Errorf("the new session should have no breakpoints, got %v", files)
+	}
+}
+
+func TestSessionFileParam(t *testing.T) {
+	t.Setenv(sessionFileEnv, "")
+	if _, err := sessionFileParam(map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), sessionFileEnv) {
+		t.Errorf("missing path should mention %s, got %v", sessionFileEnv, err)
+	}
+
+	t.Setenv(sessionFileEnv, "/tmp/from-env.json")
+	if path, err := sessionFileParam(map[string]interface{}{}); err != nil || path != "/tmp/from-env.json" {
+		t.Errorf("expected path from environment, got %q (%v)", path, err)
+	}
+	if path, err := sessionFileParam(map[string]interface{}{"path": "/tmp/explicit.json"}); err != nil || path != "/tmp/explicit.json" {
+		t.Errorf("explicit path should win, got %q (%v)", path, err)
+	}
+	if _, err := sessionFileParam(map[string]interface{}{"path": "relative.json"}); err == nil {
+		t.Error("relative path should be rejected")
+	}
+}
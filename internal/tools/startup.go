@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// defaultStartupWindow is how much of the startup output the report covers
+// when the game doesn't stop sooner
+const defaultStartupWindow = 10 * time.Second
+
+// Categories of startup issues
+const (
+	issueMissingNode     = "missing_node"
+	issueMissingResource = "missing_resource"
+	issueShader          = "shader"
+	issueScript          = "script"
+	issueDeprecated      = "deprecated"
+	issueOther           = "other"
+)
+
+// issuePatterns classify startup errors and warnings by lowercase substrings
+// of the message, checked in order: a shader that fails to load is a shader
+// issue, not a missing resource
+var issuePatterns = []struct {
+	category string
+	patterns []string
+}{
+	{issueShader, []string{"shader"}},
+	{issueMissingNode, []string{"node not found", "has no node", "get_node", "node_not_found"}},
+	{issueDeprecated, []string{"deprecated"}},
+	{issueScript, []string{"parse error", "compile error", "script error", "failed to load script", "script warning", "gdscript"}},
+	{issueMissingResource, []string{"failed loading resource", "cannot open file", "file not found", "failed to load", "no loader found", "missing dependencies", "dependency"}},
+}
+
+// issueHints suggests where to look for each category
+var issueHints = map[string]string{
+	issueMissingNode:     "A node path in a script or scene doesn't match the scene tree; check renamed or moved nodes and @onready paths",
+	issueMissingResource: "A resource path is missing or broken; check moved or renamed files and reimport the project in the editor",
+	issueShader:          "A shader failed to compile; open it in the editor to see the error in context",
+	issueScript:          "A script failed to parse or reported a warning; the issue's file and line point at the code",
+	issueDeprecated:      "An API the project uses is deprecated; it still works but may be removed in a later Godot version",
+}
+
+// classifyIssue returns the category of a startup error or warning
+func classifyIssue(message string) string {
+	lower := strings.ToLower(message)
+	for _, p := range issuePatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(lower, pattern) {
+				return p.category
+			}
+		}
+	}
+	return issueOther
+}
+
+// startupRecords returns the startup output printed within window of the
+// launch, and when the report's span ends
+func startupRecords(startup dap.StartupOutput, window time.Duration) ([]dap.OutputRecord, time.Time) {
+	cutoff := startup.Start.Add(window)
+	end := cutoff
+	if !startup.End.IsZero() && startup.End.Before(cutoff) {
+		end = startup.End
+	}
+	var records []dap.OutputRecord
+	for _, r := range startup.Records {
+		if r.Time.After(end) {
+			break
+		}
+		records = append(records, r)
+	}
+	return records, end
+}
+
+// buildStartupReport summarizes the errors and warnings of the startup
+// output: counts per category, the distinct issues (errors first, then most
+// repeated), and an overall health
+func buildStartupReport(records []dap.OutputRecord, projectRoot string) map[string]interface{} {
+	diagnostics := aggregateDiagnostics(records)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Severity != diagnostics[j].Severity {
+			return diagnostics[i].Severity == severityError
+		}
+		return diagnostics[i].Count > diagnostics[j].Count
+	})
+
+	issues := make([]map[string]interface{}, 0, len(diagnostics))
+	categories := map[string]int{}
+	hints := []string{}
+	errorCount, warningCount := 0, 0
+	for _, d := range diagnostics {
+		if d.Severity == severityError {
+			errorCount += d.Count
+		} else {
+			warningCount += d.Count
+		}
+		category := classifyIssue(d.Message)
+		if categories[category] == 0 && issueHints[category] != "" {
+			hints = append(hints, issueHints[category])
+		}
+		categories[category] += d.Count
+
+		issue := map[string]interface{}{
+			"category": category,
+			"severity": d.Severity,
+			"message":  d.Message,
+			"count":    d.Count,
+		}
+		addDiagnosticSource(issue, d, projectRoot)
+		issues = append(issues, issue)
+	}
+
+	health := "clean"
+	switch {
+	case errorCount > 0:
+		health = "errors"
+	case warningCount > 0:
+		health = "warnings"
+	}
+	return map[string]interface{}{
+		"health":        health,
+		"error_count":   errorCount,
+		"warning_count": warningCount,
+		"categories":    categories,
+		"issues":        issues,
+		"hints":         hints,
+		"output_lines":  len(records),
+	}
+}
+
+// RegisterStartupTools registers godot_get_startup_report
+func RegisterStartupTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_startup_report",
+		Description: `Summarize the errors and warnings the game printed while starting up.
+
+The output of each launch or attach is collected from the start until the
+game first stops (breakpoint or pause), it exits, or a minute passes. The
+report covers that output up to window_seconds after the launch and sorts
+its errors and warnings into categories:
+- missing_node: node paths that don't resolve (get_node, @onready)
+- missing_resource: files that fail to load or have broken dependencies
+- shader: shader compilation errors
+- script: parse errors, script errors, and GDScript warnings
+- deprecated: deprecated API use
+- other: everything else
+
+Health is "clean", "warnings", or "errors". Identical messages are collapsed
+with a count, and messages with a script location include a source snippet.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- The game must have been launched or attached in this session
+
+Startup output is kept separately from godot_get_output, so the report is
+still available after long sessions push boot messages out of that buffer.
+
+Example: Check the project's health right after launching
+godot_launch_main_scene(project="/path/to/project")
+godot_get_startup_report()
+→ {"health": "warnings", "error_count": 0, "warning_count": 2,
+   "categories": {"deprecated": 1, "missing_node": 1},
+   "issues": [{"category": "missing_node", "severity": "warning", ...}], ...}`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "window_seconds",
+				Type:        "number",
+				Required:    false,
+				Default:     10,
+				Description: "Seconds after the launch to cover, if the game didn't stop sooner (default: 10, max: 60)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			window := defaultStartupWindow
+			if w, ok := params["window_seconds"].(float64); ok {
+				if w <= 0 || w > 60 {
+					return nil, fmt.Errorf("window_seconds must be between 0 and 60, got %v", w)
+				}
+				window = time.Duration(w * float64(time.Second))
+			}
+
+			startup := session.GetClient().StartupOutput()
+			if startup.Start.IsZero() {
+				return nil, FormatError(
+					"No launch or attach in this session",
+					"startup output",
+					[]string{
+						"Launch the game with godot_launch_main_scene, godot_launch_scene, or godot_launch_current_scene",
+						"Or attach to a running game with godot_attach",
+					},
+					nil,
+				)
+			}
+
+			records, end := startupRecords(startup, window)
+			result := buildStartupReport(records, session.GetProjectRoot())
+			result["status"] = "success"
+			result["window_seconds"] = window.Seconds()
+			result["ended_by"] = startup.EndReason
+			if startup.EndReason == dap.StartupCollecting || end.Before(startup.End) {
+				result["ended_by"] = "window"
+			}
+			if end.After(time.Now()) {
+				// Still inside the window: the report covers what was printed so far
+				end = time.Now()
+				result["collecting"] = true
+			}
+			result["duration_seconds"] = end.Sub(startup.Start).Round(time.Millisecond).Seconds()
+			if startup.Dropped > 0 {
+				result["dropped_output"] = startup.Dropped
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+)
+
+func TestClassifyIssue(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{`ERROR: Node not found: "HUD/Health" (relative to "/root/Main").`, issueMissingNode},
+		{`ERROR: Failed loading resource: res://textures/missing.png.`, issueMissingResource},
+		{`ERROR: Failed to load resource: res://shaders/water.gdshader.`, issueShader},
+		{`SHADER ERROR: Expected ';' after uniform.`, issueShader},
+		{`SCRIPT ERROR: Parse Error: Identifier "speed" not declared in the current scope.`, issueScript},
+		{`WARNING: The method "get_rect" is deprecated.`, issueDeprecated},
+		{`WARNING: Viewport texture too large.`, issueOther},
+	}
+	for _, tt := range tests {
+		if got := classifyIssue(tt.message); got != tt.want {
+			t.Errorf("classifyIssue(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestStartupRecords(t *testing.T) {
+	start := time.Now()
+	at := func(seconds float64, text string) dap.OutputRecord {
+		return dap.OutputRecord{Category: "stdout", Output: text, Time: start.Add(time.Duration(seconds * float64(time.Second)))}
+	}
+	startup := dap.StartupOutput{
+		Start:     start,
+		End:       start.Add(20 * time.Second),
+		EndReason: dap.StartupFirstStop,
+		Records:   []dap.OutputRecord{at(1, "boot"), at(5, "loaded"), at(15, "late")},
+	}
+
+	records, end := startupRecords(startup, 10*time.Second)
+	if len(records) != 2 || !end.Equal(start.Add(10*time.Second)) {
+		t.Errorf("expected the window to cut off late output, got %d records ending %v", len(records), end.Sub(start))
+	}
+
+	startup.End = start.Add(3 * time.Second)
+	records, end = startupRecords(startup, 10*time.Second)
+	if len(records) != 1 || !end.Equal(startup.End) {
+		t.Errorf("expected the first stop to end the report, got %d records ending %v", len(records), end.Sub(start))
+	}
+}
+
+func TestBuildStartupReport(t *testing.T) {
+	var records []dap.OutputRecord
+	add := func(category, text string) {
+		records = append(records, dap.OutputRecord{Seq: len(records) + 1, Category: category, Output: text, Time: time.Now()})
+	}
+
+	add("stdout", "Godot Engine v4.3.stable\n")
+	add("stderr", "WARNING: The method \"get_rect\" is deprecated.\n")
+	add("stderr", "WARNING: The method \"get_rect\" is deprecated.\n")
+	add("stderr", "ERROR: Node not found: \"HUD/Health\" (relative to \"/root/Main\").\n")
+	add("stderr", "   at: get_node (scene/main/node.cpp:1651)\n")
+
+	report := buildStartupReport(records, "")
+	if report["health"] != "errors" || report["error_count"] != 1 || report["warning_count"] != 2 {
+		t.Errorf("unexpected summary: %v", report)
+	}
+	categories := report["categories"].(map[string]int)
+	if categories[issueMissingNode] != 1 || categories[issueDeprecated] != 2 {
+		t.Errorf("unexpected categories: %v", categories)
+	}
+	issues := report["issues"].([]map[string]interface{})
+	if len(issues) != 2 || issues[0]["category"] != issueMissingNode {
+		t.Errorf("errors should come first, got %v", issues)
+	}
+	if hints := report["hints"].([]string); len(hints) != 2 {
+		t.Errorf("expected a hint per category, got %v", hints)
+	}
+
+	if report := buildStartupReport(records[:1], ""); report["health"] != "clean" {
+		t.Errorf("expected clean health without errors or warnings, got %v", report["health"])
+	}
+}
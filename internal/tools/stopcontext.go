@@ -0,0 +1,678 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// maxConcurrentScopeFetches bounds the number of in-flight variables requests
+// when fetching all scopes of a frame. Godot answers requests one at a time
+// from the editor's main loop, so concurrency doesn't add throughput on its
+// side; what it saves is waiting out each round trip before sending the next
+// request. Godot returns three scopes (Locals, Members, Globals), so three
+// gets them all in a single round trip (see BenchmarkFetchScopeVariables). A
+// higher limit gains nothing for Godot and would let an adapter reporting
+// many scopes queue a burst that delays other requests, such as a continue,
+// behind it.
+const maxConcurrentScopeFetches = 3
+
+// whereSnippetContext is the number of source lines shown above and below
+// the current line by godot_where
+const whereSnippetContext = 2
+
+// variablesFetcher is the subset of *dap.Client used to fetch scope variables
+type variablesFetcher interface {
+	Variables(ctx context.Context, variablesReference int) (*godap.VariablesResponse, error)
+}
+
+// scopeVariables holds the result of fetching a single scope's variables
+type scopeVariables struct {
+	Scope     godap.Scope
+	Variables []godap.Variable
+	Err       error
+}
+
+// fetchScopeVariables fetches the variables of every scope concurrently,
+// with at most maxConcurrentScopeFetches requests in flight.
+// Results are returned in the same order as scopes. A failure in one scope
+// is recorded on its result and does not abort the others.
+func fetchScopeVariables(ctx context.Context, client variablesFetcher, scopes []godap.Scope) []scopeVariables {
+	results := make([]scopeVariables, len(scopes))
+	sem := make(chan struct{}, maxConcurrentScopeFetches)
+
+	var wg sync.WaitGroup
+	for i, scope := range scopes {
+		results[i].Scope = scope
+
+		// Scopes without a reference have nothing to fetch
+		if scope.VariablesReference == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, ref int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+
+			resp, err := client.Variables(ctx, ref)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Variables = resp.Body.Variables
+		}(i, scope.VariablesReference)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// variableScopeOrder is the order godot_get_variable searches Godot's scopes.
+// Scopes not listed here (future adapter versions) are searched last.
+var variableScopeOrder = []string{"Locals", "Members", "Globals"}
+
+// scopeSearchRank returns a scope's position in variableScopeOrder
+func scopeSearchRank(name string) int {
+	for i, scope := range variableScopeOrder {
+		if scope == name {
+			return i
+		}
+	}
+	return len(variableScopeOrder)
+}
+
+// findScopeVariable searches scopes in variableScopeOrder for a variable named
+// name, fetching one scope at a time so Globals is only requested if needed.
+// Returns the scope it was found in; found is false if no scope has it. Scopes
+// that fail to load are skipped, and the first such error is returned if the
+// variable isn't found elsewhere.
+func findScopeVariable(ctx context.Context, client variablesFetcher, scopes []godap.Scope, name string) (godap.Scope, godap.Variable, bool, error) {
+	ordered := append([]godap.Scope(nil), scopes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return scopeSearchRank(ordered[i].Name) < scopeSearchRank(ordered[j].Name)
+	})
+
+	var firstErr error
+	for _, scope := range ordered {
+		if scope.VariablesReference == 0 {
+			continue
+		}
+		resp, err := client.Variables(ctx, scope.VariablesReference)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", scope.Name, err)
+			}
+			continue
+		}
+		for _, variable := range resp.Body.Variables {
+			if variable.Name == name {
+				return scope, variable, true, nil
+			}
+		}
+	}
+	return godap.Scope{}, godap.Variable{}, false, firstErr
+}
+
+// sourceSnippet returns the lines of file around line (1-indexed), with the
+// current line marked by ">". Returns "" if the file can't be read or is
+// shorter than line.
+func sourceSnippet(file string, line int, contextLines int) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	first, last := line-contextLines, line+contextLines
+	if first < 1 {
+		first = 1
+	}
+
+	var lines []string
+	found := false
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan() && n <= last; n++ {
+		if n < first {
+			continue
+		}
+		marker := " "
+		if n == line {
+			marker = ">"
+			found = true
+		}
+		lines = append(lines, fmt.Sprintf("%s %4d | %s", marker, n, scanner.Text()))
+	}
+	if !found {
+		return ""
+	}
+	return strings.Join(lines, "\n")
+}
+
+// currentLocation describes the top frame of a paused thread: function,
+// line, res:// and absolute paths, and a source snippet (used by godot_where)
+func currentLocation(ctx context.Context, session *dap.Session, threadId int) (map[string]interface{}, error) {
+	stackResp, err := session.GetClient().StackTrace(ctx, threadId, 0, 1)
+	if err != nil {
+		return nil, FormatError(
+			"Failed to get stack trace",
+			"",
+			[]string{
+				"Game might not be paused (cannot get stack trace while running)",
+				"Thread ID might be invalid",
+			},
+			err,
+		)
+	}
+	if len(stackResp.Body.StackFrames) == 0 {
+		return nil, FormatError(
+			"No stack frame available",
+			fmt.Sprintf("thread_id=%d", threadId),
+			[]string{
+				"Game might not be paused",
+			},
+			nil,
+		)
+	}
+	frame := stackResp.Body.StackFrames[0]
+
+	result := map[string]interface{}{
+		"function": frame.Name,
+		"line":     frame.Line,
+		"frame_id": frame.Id,
+	}
+	if isNativeFrame(frame) {
+		result["native"] = true
+		result["message"] = nativeFrameMessage
+		return result, nil
+	}
+
+	path := frame.Source.Path
+	if strings.HasPrefix(path, "res://") {
+		if abs, err := resolveGodotPath(path, session.GetProjectRoot()); err == nil {
+			path = abs
+		}
+	}
+	if filepath.IsAbs(path) {
+		result["path"] = path
+	}
+	if resPath := toResPath(frame.Source.Path, session.GetProjectRoot()); resPath != "" {
+		result["file"] = resPath
+	}
+	if snippet := sourceSnippet(path, frame.Line, whereSnippetContext); snippet != "" {
+		result["snippet"] = snippet
+	}
+	return result, nil
+}
+
+// RegisterStopContextTools registers tools that bundle the state of a paused game
+func RegisterStopContextTools(server *mcp.Server) {
+	// godot_get_stop_context - Bundle top frame, scopes, and variables
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_stop_context",
+		Description: `Get a bundle of the current stop location and all variables in one call.
+
+This tool combines godot_get_stack_trace, godot_get_scopes, and godot_get_variables
+for a single stack frame. Variables for every scope (Locals, Members, Globals) are
+fetched concurrently, which is noticeably faster than calling godot_get_variables
+once per scope.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Use this tool:
+- Right after hitting a breakpoint to see where you are and what's in scope
+- Instead of chaining stack trace → scopes → variables calls
+- When you need a quick overview before drilling into specific variables
+
+If the selected frame is native (engine or GDExtension code), only the frame is
+returned with native=true, since it has no GDScript variables.
+
+Complex variables are not expanded; use godot_get_variables with the returned
+variables_reference to drill down.
+
+Example: Get context for the top frame
+godot_get_stop_context()
+
+Example: Get context for the caller's frame
+godot_get_stop_context(frame_index=1)
+
+Example: Get a compact markdown summary (one table per scope)
+godot_get_stop_context(format="markdown_compact")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "thread_id",
+				Type:        "number",
+				Required:    false,
+				Default:     1,
+				Description: "Thread ID to inspect (default: 1)",
+			},
+			{
+				Name:        "frame_index",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Index of the stack frame to inspect (default: 0 = top frame)",
+				Minimum:     mcp.Float64(0),
+			},
+			inspectFormatParam(),
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get stop context"); err != nil {
+				return nil, err
+			}
+
+			// Get parameters
+			threadId := 1
+			if tid, ok := params["thread_id"].(float64); ok {
+				threadId = int(tid)
+			}
+
+			frameIndex := 0
+			if fi, ok := params["frame_index"].(float64); ok {
+				frameIndex = int(fi)
+			}
+			if frameIndex < 0 {
+				return nil, fmt.Errorf("frame_index must be a non-negative integer")
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+
+			// Request just enough frames to reach the requested one
+			stackResp, err := client.StackTrace(ctx, threadId, frameIndex, 1)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to get stack trace",
+					"",
+					[]string{
+						"Game might not be paused (cannot get stack trace while running)",
+						"Thread ID might be invalid",
+					},
+					err,
+				)
+			}
+			if len(stackResp.Body.StackFrames) == 0 {
+				return nil, FormatError(
+					"No stack frame available",
+					fmt.Sprintf("frame_index=%d", frameIndex),
+					[]string{
+						"Game might not be paused",
+						"frame_index might exceed the stack depth (check godot_get_stack_trace)",
+					},
+					nil,
+				)
+			}
+			frame := stackResp.Body.StackFrames[0]
+
+			frameData := map[string]interface{}{
+				"id":   frame.Id,
+				"name": frame.Name,
+				"line": frame.Line,
+			}
+			addFrameSource(frameData, frame)
+
+			// Native frames have no scopes, so don't ask Godot for them
+			if isNativeFrame(frame) {
+				return map[string]interface{}{
+					"status":  "native_frame",
+					"message": nativeFrameMessage,
+					"frame":   frameData,
+					"scopes":  []map[string]interface{}{},
+				}, nil
+			}
+
+			scopesResp, err := client.Scopes(ctx, frame.Id)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to get scopes",
+					fmt.Sprintf("frame_id=%d", frame.Id),
+					[]string{
+						"Game might not be paused",
+					},
+					err,
+				)
+			}
+
+			// Fetch all scopes concurrently
+			fetched := fetchScopeVariables(ctx, client, scopesResp.Body.Scopes)
+			if wantsCompact(params) {
+				return renderStopContextCompact(frame, session.GetProjectRoot(), fetched), nil
+			}
+
+			scopes := make([]map[string]interface{}, len(fetched))
+			for i, sv := range fetched {
+				scopeData := map[string]interface{}{
+					"name":                sv.Scope.Name,
+					"variables_reference": sv.Scope.VariablesReference,
+				}
+				if sv.Err != nil {
+					scopeData["error"] = sv.Err.Error()
+				} else {
+					variables := formatVariableList(sv.Variables)
+					addNodeBreadcrumbs(ctx, client, sv.Variables, variables)
+					addSummaries(ctx, client, sv.Variables, variables)
+					scopeData["variables"] = variables
+					scopeData["count"] = len(variables)
+				}
+				scopes[i] = scopeData
+			}
+
+			return map[string]interface{}{
+				"status": "success",
+				"frame":  frameData,
+				"scopes": scopes,
+			}, nil
+		},
+	})
+
+	// godot_where - Report the current location only
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_where",
+		Description: `Get the current execution location of the paused game.
+
+This tool returns just the top stack frame: the file (as a res:// path and an
+absolute path), line, function name, and a few source lines around the current
+line. It answers "where am I?" after a step or breakpoint without a full stack
+trace.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+The res:// path needs the project root (godot_connect project argument or a
+discovered project). The snippet is read from disk, so it is omitted if the
+file isn't readable locally (e.g. when debugging over ssh).
+
+Example: Check the location after stepping
+godot_where()`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "thread_id",
+				Type:        "number",
+				Required:    false,
+				Default:     1,
+				Description: "Thread ID to inspect (default: 1)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get current location"); err != nil {
+				return nil, err
+			}
+
+			threadId := 1
+			if tid, ok := params["thread_id"].(float64); ok {
+				threadId = int(tid)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			result, err := currentLocation(ctx, session, threadId)
+			if err != nil {
+				return nil, err
+			}
+			result["status"] = "success"
+			return result, nil
+		},
+	})
+
+	// godot_get_variable - Look up a single variable by name
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_variable",
+		Description: `Get a single variable by name from a paused stack frame.
+
+This tool searches the frame's scopes in order — Locals, then Members, then
+Globals — and returns the first variable with the given name, formatted the same
+way as godot_get_variables. It replaces the scopes → variables → filter sequence
+when you already know which variable you want.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Scopes are fetched one at a time and the search stops at the first match, so
+Globals is only requested if the name isn't local or a member. A local variable
+shadows a member with the same name, as in GDScript.
+
+If the variable is expandable (objects, arrays, dictionaries), pass its
+variables_reference to godot_get_variables to see its contents.
+
+Example: Look up a local variable in the top frame
+godot_get_variable(name="velocity")
+
+Example: Look up a member in the caller's frame
+godot_get_variable(name="health", frame_id=1)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "name",
+				Type:        "string",
+				Required:    true,
+				Description: "Variable name (e.g. velocity, self, health)",
+			},
+			{
+				Name:        "frame_id",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Stack frame ID (from godot_get_stack_trace, default: 0 = top frame)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "get variable"); err != nil {
+				return nil, err
+			}
+
+			name, ok := params["name"].(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("name is required and must be a non-empty string")
+			}
+
+			frameId := 0
+			if fid, ok := params["frame_id"].(float64); ok {
+				frameId = int(fid)
+			}
+
+			// Native frames have no scopes; Godot would answer with an error
+			if isKnownNativeFrame(frameId) {
+				return map[string]interface{}{
+					"status":  "native_frame",
+					"message": nativeFrameMessage,
+					"native":  true,
+				}, nil
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			scopesResp, err := client.Scopes(ctx, frameId)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to get scopes",
+					fmt.Sprintf("frame_id=%d", frameId),
+					[]string{
+						"Frame ID might be invalid (get fresh IDs from godot_get_stack_trace)",
+						"Game might not be paused",
+					},
+					err,
+				)
+			}
+
+			scope, variable, found, err := findScopeVariable(ctx, client, scopesResp.Body.Scopes, name)
+			if !found {
+				return nil, FormatError(
+					fmt.Sprintf("Variable '%s' not found", name),
+					fmt.Sprintf("frame_id=%d", frameId),
+					[]string{
+						"Check the spelling; names are case-sensitive",
+						"Use godot_get_stop_context to list every variable in the frame",
+						"For properties of an object, look up the object and expand it with godot_get_variables",
+					},
+					err,
+				)
+			}
+
+			return map[string]interface{}{
+				"status":   "success",
+				"scope":    scope.Name,
+				"variable": formatVariable(variable),
+			}, nil
+		},
+	})
+
+	// godot_inspect_self - Summarize the node the paused script runs on
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_inspect_self",
+		Description: `Inspect 'self' — the object whose script is paused — in one call.
+
+This tool finds 'self' in the frame's Members scope, expands it one level, and
+returns a summary: class, node name, scene file, transform (position, rotation,
+scale, ...), and the script's member variables. It replaces the usual
+scopes → Members → expand self sequence after hitting a breakpoint in a node script.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+- The frame must be inside a method (static functions have no 'self')
+
+Fields that don't apply are omitted (e.g. transform for a plain Node, name for
+a non-Node object). Expandable members include a variables_reference for
+godot_get_variables, and self_reference expands the full object.
+
+Example: Inspect the current node
+godot_inspect_self()
+
+Example: Inspect the caller's node
+godot_inspect_self(frame_id=1)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "frame_id",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Stack frame ID (from godot_get_stack_trace, default: 0 = top frame)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			// Get active session
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "inspect self"); err != nil {
+				return nil, err
+			}
+
+			frameId := 0
+			if fid, ok := params["frame_id"].(float64); ok {
+				frameId = int(fid)
+			}
+
+			// Native frames have no scopes; Godot would answer with an error
+			if isKnownNativeFrame(frameId) {
+				return map[string]interface{}{
+					"status":  "native_frame",
+					"message": nativeFrameMessage,
+					"native":  true,
+				}, nil
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			scopesResp, err := client.Scopes(ctx, frameId)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to get scopes",
+					fmt.Sprintf("frame_id=%d", frameId),
+					[]string{
+						"Frame ID might be invalid (get fresh IDs from godot_get_stack_trace)",
+						"Game might not be paused",
+					},
+					err,
+				)
+			}
+
+			var members []godap.Scope
+			for _, scope := range scopesResp.Body.Scopes {
+				if scope.Name == "Members" {
+					members = append(members, scope)
+				}
+			}
+
+			_, self, found, err := findScopeVariable(ctx, client, members, "self")
+			if !found || self.VariablesReference == 0 {
+				return nil, FormatError(
+					"No 'self' in this frame",
+					fmt.Sprintf("frame_id=%d", frameId),
+					[]string{
+						"The frame may be a static function or a script without an instance",
+						"Try the caller's frame (frame_id=1) or check godot_get_stack_trace",
+					},
+					err,
+				)
+			}
+
+			propsResp, err := client.Variables(ctx, self.VariablesReference)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to expand 'self'",
+					fmt.Sprintf("ref=%d", self.VariablesReference),
+					[]string{
+						"Game might not be paused",
+					},
+					err,
+				)
+			}
+
+			result := summarizeNode(self, propsResp.Body.Variables)
+			result["status"] = "success"
+			result["self_reference"] = self.VariablesReference
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// fakeVariablesFetcher returns one variable per reference after a delay
+// standing in for the adapter's round trip (20ms unless set), tracking the
+// peak number of concurrent calls
+type fakeVariablesFetcher struct {
+	inFlight int32
+	peak     int32
+	failRef  int
+	delay    time.Duration
+}
+
+func (f *fakeVariablesFetcher) Variables(ctx context.Context, ref int) (*godap.VariablesResponse, error) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+
+	delay := f.delay
+	if delay == 0 {
+		delay = 20 * time.Millisecond
+	}
+	time.Sleep(delay)
+
+	if ref == f.failRef {
+		return nil, fmt.Errorf("variables failed for ref %d", ref)
+	}
+
+	resp := &godap.VariablesResponse{}
+	resp.Body.Variables = []godap.Variable{{Name: fmt.Sprintf("var_%d", ref)}}
+	return resp, nil
+}
+
+func TestStopContextTools_Registration(t *testing.T) {
+	server := mcp.NewServer()
+	RegisterStopContextTools(server)
+
+	// Verify registration doesn't panic
+}
+
+func TestFetchScopeVariables(t *testing.T) {
+	scopes := []godap.Scope{
+		{Name: "Locals", VariablesReference: 1},
+		{Name: "Members", VariablesReference: 2},
+		{Name: "Globals", VariablesReference: 3},
+		{Name: "Empty", VariablesReference: 0},
+	}
+
+	fetcher := &fakeVariablesFetcher{failRef: 2}
+	results := fetchScopeVariables(context.Background(), fetcher, scopes)
+
+	if len(results) != len(scopes) {
+		t.Fatalf("expected %d results, got %d", len(scopes), len(results))
+	}
+
+	// Results must stay in scope order
+	for i, r := range results {
+		if r.Scope.Name != scopes[i].Name {
+			t.Errorf("result %d: expected scope %s, got %s", i, scopes[i].Name, r.Scope.Name)
+		}
+	}
+
+	if len(results[0].Variables) != 1 || results[0].Variables[0].Name != "var_1" {
+		t.Errorf("unexpected Locals variables: %+v", results[0].Variables)
+	}
+	if results[1].Err == nil {
+		t.Error("expected Members fetch to fail")
+	}
+	if len(results[2].Variables) != 1 || results[2].Variables[0].Name != "var_3" {
+		t.Errorf("unexpected Globals variables: %+v", results[2].Variables)
+	}
+	if results[3].Err != nil || results[3].Variables != nil {
+		t.Errorf("expected scope without reference to be skipped, got %+v", results[3])
+	}
+
+	if fetcher.peak < 2 {
+		t.Errorf("expected scopes to be fetched concurrently, peak concurrency was %d", fetcher.peak)
+	}
+}
+
+func TestFetchScopeVariables_Bounded(t *testing.T) {
+	scopes := make([]godap.Scope, 10)
+	for i := range scopes {
+		scopes[i] = godap.Scope{Name: fmt.Sprintf("scope_%d", i), VariablesReference: i + 1}
+	}
+
+	fetcher := &fakeVariablesFetcher{}
+	fetchScopeVariables(context.Background(), fetcher, scopes)
+
+	if fetcher.peak > maxConcurrentScopeFetches {
+		t.Errorf("expected at most %d concurrent fetches, got %d", maxConcurrentScopeFetches, fetcher.peak)
+	}
+}
+
+// godotScopes are the scopes Godot reports for a frame
+var godotScopes = []godap.Scope{
+	{Name: "Locals", VariablesReference: 1},
+	{Name: "Members", VariablesReference: 2},
+	{Name: "Globals", VariablesReference: 3},
+}
+
+// fetchScopeVariablesSequentially is the one-request-at-a-time baseline that
+// fetchScopeVariables replaced
+func fetchScopeVariablesSequentially(ctx context.Context, client variablesFetcher, scopes []godap.Scope) {
+	for _, scope := range scopes {
+		client.Variables(ctx, scope.VariablesReference)
+	}
+}
+
+func TestFetchScopeVariables_Latency(t *testing.T) {
+	const roundTrip = 50 * time.Millisecond
+	fetcher := &fakeVariablesFetcher{delay: roundTrip}
+
+	start := time.Now()
+	fetchScopeVariables(context.Background(), fetcher, godotScopes)
+	elapsed := time.Since(start)
+
+	// Godot's three scopes fit under the limit, so they take one round trip
+	// instead of three; allow generous slack for slow machines
+	if elapsed >= 2*roundTrip {
+		t.Errorf("fetching %d scopes took %v, expected about one %v round trip", len(godotScopes), elapsed, roundTrip)
+	}
+}
+
+// BenchmarkFetchScopeVariables compares fetching Godot's three scopes with
+// a 1ms round trip sequentially and concurrently
+func BenchmarkFetchScopeVariables(b *testing.B) {
+	fetcher := &fakeVariablesFetcher{delay: time.Millisecond}
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fetchScopeVariablesSequentially(context.Background(), fetcher, godotScopes)
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fetchScopeVariables(context.Background(), fetcher, godotScopes)
+		}
+	})
+}
+
+func TestSourceSnippet(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "player.gd")
+	source := "extends Node\n\nfunc _ready():\n\tvar speed = 10\n\tprint(speed)\n"
+	if err := os.WriteFile(file, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snippet := sourceSnippet(file, 1, 2)
+	lines := strings.Split(snippet, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], ">    1 | extends Node") {
+		t.Errorf("unexpected snippet at top of file:\n%s", snippet)
+	}
+
+	snippet = sourceSnippet(file, 4, 1)
+	if !strings.Contains(snippet, ">    4 | \tvar speed = 10") || strings.Count(snippet, "\n") != 2 {
+		t.Errorf("unexpected snippet:\n%s", snippet)
+	}
+
+	if sourceSnippet(file, 99, 2) != "" {
+		t.Error("line past end of file should give no snippet")
+	}
+	if sourceSnippet(filepath.Join(t.TempDir(), "missing.gd"), 1, 2) != "" {
+		t.Error("missing file should give no snippet")
+	}
+}
+
+func TestToResPath(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "projects", "game")
+	tests := []struct {
+		path     string
+		root     string
+		expected string
+	}{
+		{filepath.Join(root, "scripts", "player.gd"), root, "res://scripts/player.gd"},
+		{"res://player.gd", "", "res://player.gd"},
+		{filepath.Join(root, "player.gd"), "", ""},
+		{filepath.Join(string(filepath.Separator), "projects", "other", "a.gd"), root, ""},
+	}
+	for _, tt := range tests {
+		if got := toResPath(tt.path, tt.root); got != tt.expected {
+			t.Errorf("toResPath(%q, %q) = %q, want %q", tt.path, tt.root, got, tt.expected)
+		}
+	}
+}
+
+// scopeVariablesFetcher serves fixed variables per reference and records
+// which references were requested
+type scopeVariablesFetcher struct {
+	variables map[int][]godap.Variable
+	requested []int
+}
+
+func (f *scopeVariablesFetcher) Variables(ctx context.Context, ref int) (*godap.VariablesResponse, error) {
+	f.requested = append(f.requested, ref)
+	vars, ok := f.variables[ref]
+	if !ok {
+		return nil, fmt.Errorf("unknown reference %d", ref)
+	}
+	resp := &godap.VariablesResponse{}
+	resp.Body.Variables = vars
+	return resp, nil
+}
+
+func TestFindScopeVariable(t *testing.T) {
+	scopes := []godap.Scope{
+		{Name: "Globals", VariablesReference: 3},
+		{Name: "Members", VariablesReference: 2},
+		{Name: "Locals", VariablesReference: 1},
+	}
+	fetcher := &scopeVariablesFetcher{variables: map[int][]godap.Variable{
+		1: {{Name: "speed", Value: "10", Type: "int"}},
+		2: {{Name: "speed", Value: "5", Type: "int"}, {Name: "health", Value: "100", Type: "int"}},
+		3: {{Name: "GameState", Value: "<Node#1>", Type: "Node"}},
+	}}
+
+	// Locals shadow members, and later scopes aren't fetched
+	scope, variable, found, err := findScopeVariable(context.Background(), fetcher, scopes, "speed")
+	if !found || err != nil || scope.Name != "Locals" || variable.Value != "10" {
+		t.Errorf("expected local speed, got scope=%s var=%+v found=%v err=%v", scope.Name, variable, found, err)
+	}
+	if len(fetcher.requested) != 1 {
+		t.Errorf("only Locals should be fetched, got %v", fetcher.requested)
+	}
+
+	fetcher.requested = nil
+	scope, _, found, _ = findScopeVariable(context.Background(), fetcher, scopes, "GameState")
+	if !found || scope.Name != "Globals" {
+		t.Errorf("expected GameState in Globals, got %s (found=%v)", scope.Name, found)
+	}
+	if len(fetcher.requested) != 3 || fetcher.requested[0] != 1 || fetcher.requested[1] != 2 {
+		t.Errorf("scopes should be searched Locals, Members, Globals, got %v", fetcher.requested)
+	}
+
+	// A failed scope is skipped and its error reported when nothing matches
+	delete(fetcher.variables, 2)
+	_, _, found, err = findScopeVariable(context.Background(), fetcher, scopes, "missing")
+	if found || err == nil || !strings.Contains(err.Error(), "Members") {
+		t.Errorf("expected not found with Members error, got found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	godap "github.com/google/go-dap"
+)
+
+// maxStopDecisions bounds the decisions a listener keeps; waiting tools look
+// up the stops they just received, so only the most recent matter
+const maxStopDecisions = 32
+
+// stopDecisions records whether a listener that may resume the game right
+// after a breakpoint stop (breakpoint conditions, tracepoints) resumed each
+// stop it handled, so tools reporting stops can skip the resumed ones
+type stopDecisions struct {
+	mu        sync.Mutex
+	client    *dap.Client // Client the listener is attached to; nil when detached
+	since     int         // Last event history sequence number before attaching
+	decisions []stopDecision
+	changed   chan struct{} // Closed when a decision is recorded or the listener detaches
+}
+
+// stopDecision is the outcome of one breakpoint stop
+type stopDecision struct {
+	stop    *godap.StoppedEvent
+	resumed bool
+}
+
+// begin starts recording for a listener just subscribed to client's events
+func (d *stopDecisions) begin(client *dap.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.client = client
+	d.since = client.LastEventSeq()
+	d.decisions = nil
+	d.notifyLocked()
+}
+
+// end stops recording when the listener detaches; pending lookups no longer
+// wait for it
+func (d *stopDecisions) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.client = nil
+	d.decisions = nil
+	d.notifyLocked()
+}
+
+// record stores whether the listener resumed a stop
+func (d *stopDecisions) record(stop *godap.StoppedEvent, resumed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.decisions = append(d.decisions, stopDecision{stop: stop, resumed: resumed})
+	if len(d.decisions) > maxStopDecisions {
+		d.decisions = d.decisions[len(d.decisions)-maxStopDecisions:]
+	}
+	d.notifyLocked()
+}
+
+func (d *stopDecisions) notifyLocked() {
+	if d.changed != nil {
+		close(d.changed)
+		d.changed = nil
+	}
+}
+
+// decision reports whether the listener resumed a stop on client. A stop
+// the listener won't decide (it isn't attached to client, or the stop
+// arrived before it attached) counts as decided and not resumed.
+func (d *stopDecisions) decision(client *dap.Client, stop *godap.StoppedEvent) (resumed, decided bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != client {
+		return false, true
+	}
+	for _, decision := range d.decisions {
+		if decision.stop == stop {
+			return decision.resumed, true
+		}
+	}
+	if client.EventSeq(stop) <= d.since {
+		return false, true
+	}
+	return false, false
+}
+
+// wait returns a channel closed at the next decision or detach
+func (d *stopDecisions) wait() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.changed == nil {
+		d.changed = make(chan struct{})
+	}
+	return d.changed
+}
+
+// autoResumeListeners are the listeners that may resume the game right after
+// a breakpoint stop
+func autoResumeListeners() []*stopDecisions {
+	return []*stopDecisions{&breakpointConditions.decisions, &tracepoints.decisions}
+}
+
+// resumedAtOnce reports whether a stop was resumed right away by a
+// breakpoint condition or tracepoint, and whether all of them have decided
+func resumedAtOnce(client *dap.Client, stop *godap.StoppedEvent) (resumed, decided bool) {
+	if stop.Body.Reason != "breakpoint" {
+		return false, true
+	}
+	decided = true
+	for _, listener := range autoResumeListeners() {
+		r, ok := listener.decision(client, stop)
+		if r {
+			return true, true
+		}
+		decided = decided && ok
+	}
+	return false, decided
+}
+
+// lastStop returns the newest stopped event in the client's history, or nil
+func lastStop(client *dap.Client) *godap.StoppedEvent {
+	stops := client.Events(0, []string{"stopped"}).Events
+	if len(stops) == 0 {
+		return nil
+	}
+	stop, _ := stops[len(stops)-1].Message.(*godap.StoppedEvent)
+	return stop
+}
+
+// awaitReportedEvent waits for the first event whose type is in types and
+// returns it, or nil once deadline passes or ended is closed. Events already received go in
+// pending and are considered first. Breakpoint stops that a breakpoint
+// condition or tracepoint resumes right away are skipped; the subscription
+// keeps being drained while their decision is pending. A stop still
+// undecided at the deadline is returned as is.
+func awaitReportedEvent(client *dap.Client, events <-chan godap.Message, types []string, deadline <-chan time.Time, ended <-chan struct{}, pending ...godap.Message) godap.Message {
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+
+	candidates := pending
+	for {
+		// Take the channels signalling decisions before checking them, so a
+		// decision made in between still wakes the wait below
+		gateDecided, tracerDecided := breakpointConditions.decisions.wait(), tracepoints.decisions.wait()
+
+		// Report the oldest candidate once it's decided
+		for len(candidates) > 0 {
+			stop, ok := candidates[0].(*godap.StoppedEvent)
+			if !ok {
+				return candidates[0]
+			}
+			resumed, decided := resumedAtOnce(client, stop)
+			if !decided {
+				break
+			}
+			if !resumed {
+				return stop
+			}
+			candidates = candidates[1:]
+		}
+		if len(candidates) == 0 {
+			gateDecided, tracerDecided = nil, nil
+		}
+
+		select {
+		case <-deadline:
+			if len(candidates) > 0 {
+				return candidates[0]
+			}
+			return nil
+		case <-ended:
+			return nil
+		case msg := <-events:
+			if e, ok := msg.(godap.EventMessage); ok && wanted[e.GetEvent().Event] {
+				candidates = append(candidates, msg)
+			}
+		case <-gateDecided:
+		case <-tracerDecided:
+		}
+	}
+}
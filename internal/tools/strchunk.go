@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+)
+
+// Bounds of godot_get_string_chunk's length, in characters
+const (
+	defaultStringChunkLength = 4000
+	maxStringChunkLength     = 32000
+)
+
+// stringChunk is one slice of the formatted value of an expression
+type stringChunk struct {
+	Text        string // UTF-8 text of the chunk
+	TotalLength int    // Characters in the whole formatted value
+}
+
+// readStringChunk returns length characters of str(expression) starting at
+// offset. The slice is base64-encoded in the game, so quotes, newlines,
+// control characters, and non-ASCII text survive the evaluate response and
+// Godot's formatting of String results untouched.
+func readStringChunk(ctx context.Context, client expressionEvaluator, frameId int, expression string, offset, length int) (stringChunk, error) {
+	value := fmt.Sprintf("str(%s)", expression)
+
+	resp, err := client.Evaluate(ctx, value+".length()", frameId, "watch")
+	if err != nil {
+		return stringChunk{}, err
+	}
+	total, err := strconv.Atoi(resp.Body.Result)
+	if err != nil {
+		return stringChunk{}, fmt.Errorf("unexpected length %q", resp.Body.Result)
+	}
+
+	chunk := stringChunk{TotalLength: total}
+	if offset >= total {
+		return chunk, nil
+	}
+	resp, err = client.Evaluate(ctx, fmt.Sprintf("Marshalls.utf8_to_base64(%s.substr(%d, %d))", value, offset, length), frameId, "watch")
+	if err != nil {
+		return stringChunk{}, err
+	}
+	data, err := base64.StdEncoding.DecodeString(unquoteGodotString(resp.Body.Result))
+	if err != nil {
+		return stringChunk{}, fmt.Errorf("unexpected chunk encoding: %w", err)
+	}
+	chunk.Text = string(data)
+	return chunk, nil
+}
+
+// RegisterStringChunkTools registers godot_get_string_chunk
+func RegisterStringChunkTools(server *mcp.Server) {
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_string_chunk",
+		Description: `Read a long string value piece by piece.
+
+Variables holding JSON blobs, file contents, or logs can be far too long to
+read in one response. This tool returns length characters of str(expression)
+starting at offset, plus the total length and the offset of the next chunk,
+so the complete value can be recovered by calling it until complete=true.
+
+Each chunk is base64-encoded in the game before it is sent, so quotes,
+newlines, control characters, and non-ASCII text arrive exactly as stored.
+With encoding="base64" the chunk is returned still encoded: the UTF-8 bytes
+of the text, for content that isn't meant to be read as text.
+
+Offsets and lengths count characters (Unicode code points), as Godot's
+String.substr() does. The expression is evaluated twice per call (once for
+the length, once for the chunk), so it should not have side effects.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+- Game must be paused (at breakpoint or manually paused)
+
+Example: Read a save file loaded into a variable
+godot_get_string_chunk(expression="save_json")
+→ {"text": "{\"level\": 3, ...", "offset": 0, "length": 4000,
+   "total_length": 10250, "next_offset": 4000, "complete": false}
+godot_get_string_chunk(expression="save_json", offset=4000)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "expression",
+				Type:        "string",
+				Required:    true,
+				Description: "Variable or expression whose formatted value to read (non-strings are converted with str())",
+			},
+			{
+				Name:        "offset",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Character offset of the chunk (default: 0)",
+			},
+			{
+				Name:        "length",
+				Type:        "number",
+				Required:    false,
+				Default:     defaultStringChunkLength,
+				Description: "Characters to return (default: 4000, max: 32000)",
+			},
+			{
+				Name:        "encoding",
+				Type:        "string",
+				Required:    false,
+				Default:     "text",
+				Description: "Return the chunk as 'text' (default) or as 'base64' of its UTF-8 bytes",
+				Enum:        []string{"text", "base64"},
+			},
+			{
+				Name:        "frame_id",
+				Type:        "number",
+				Required:    false,
+				Default:     0,
+				Description: "Stack frame ID for evaluation context (default: 0 = top frame)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+			if err := requirePaused(session, "read a string"); err != nil {
+				return nil, err
+			}
+
+			expression, ok := params["expression"].(string)
+			if !ok || expression == "" {
+				return nil, fmt.Errorf("expression is required and must be a non-empty string")
+			}
+			offset := 0
+			if o, ok := params["offset"].(float64); ok {
+				offset = int(o)
+			}
+			if offset < 0 {
+				return nil, fmt.Errorf("offset must not be negative, got %d", offset)
+			}
+			length := defaultStringChunkLength
+			if l, ok := params["length"].(float64); ok && l > 0 {
+				length = int(l)
+			}
+			if length > maxStringChunkLength {
+				length = maxStringChunkLength
+			}
+			encoding := "text"
+			if e, ok := params["encoding"].(string); ok && e != "" {
+				encoding = e
+			}
+			if encoding != "text" && encoding != "base64" {
+				return nil, fmt.Errorf("encoding must be 'text' or 'base64', got %q", encoding)
+			}
+			frameId := 0
+			if fid, ok := params["frame_id"].(float64); ok {
+				frameId = int(fid)
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			chunk, err := readStringChunk(ctx, session.GetClient(), frameId, expression, offset, length)
+			if err != nil {
+				return nil, FormatError(
+					"Failed to read string",
+					fmt.Sprintf("expr='%s'", expression),
+					[]string{
+						"Check the expression with godot_evaluate first",
+						"Variables might not be available in current scope",
+					},
+					err,
+				)
+			}
+
+			returned := utf8.RuneCountInString(chunk.Text)
+			result := map[string]interface{}{
+				"status":       "success",
+				"expression":   expression,
+				"offset":       offset,
+				"length":       returned,
+				"total_length": chunk.TotalLength,
+				"complete":     offset+returned >= chunk.TotalLength,
+				"encoding":     encoding,
+			}
+			if encoding == "base64" {
+				result["base64"] = base64.StdEncoding.EncodeToString([]byte(chunk.Text))
+			} else {
+				result["text"] = chunk.Text
+			}
+			if offset+returned < chunk.TotalLength {
+				result["next_offset"] = offset + returned
+			}
+			return result, nil
+		},
+	})
+}
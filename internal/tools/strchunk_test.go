@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+func TestReadStringChunk(t *testing.T) {
+	chunk := "\"quoted\"\nline two ünïcode"
+	encoded := base64.StdEncoding.EncodeToString([]byte(chunk))
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		"str(save_json).length()":                                    {"10250", "int"},
+		"Marshalls.utf8_to_base64(str(save_json).substr(4000, 100))": {encoded, "String"},
+	}}
+	ctx := context.Background()
+
+	got, err := readStringChunk(ctx, eval, 0, "save_json", 4000, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != chunk || got.TotalLength != 10250 {
+		t.Errorf("expected the decoded chunk and total length, got %+v", got)
+	}
+
+	// Past the end, only the length is read
+	eval.evaluated = nil
+	got, err = readStringChunk(ctx, eval, 0, "save_json", 10250, 100)
+	if err != nil || got.Text != "" || len(eval.evaluated) != 1 {
+		t.Errorf("expected an empty chunk without a second evaluate, got %+v, %v, %v", got, err, eval.evaluated)
+	}
+
+	if _, err := readStringChunk(ctx, eval, 0, "missing", 0, 100); err == nil {
+		t.Error("expected an error for an expression that fails to evaluate")
+	}
+}
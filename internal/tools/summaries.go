@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	godap "github.com/google/go-dap"
+)
+
+// summariesFileEnv names the environment variable holding the summary
+// expressions file, which maps classes to a GDScript expression describing
+// an instance in domain terms:
+//
+//	{"summaries": {"Inventory": "str(items.size()) + \" items\""}}
+//
+// Classes are engine classes or script class_names. Expressions are written
+// as if inside the class: bare names refer to the instance's members. Like
+// the project registry, the file is read on every listing.
+const summariesFileEnv = "GODOT_MCP_SUMMARIES_FILE"
+
+// maxSummaryLookups bounds the objects one variable listing summarizes
+const maxSummaryLookups = 32
+
+// summariesConfig is the content of the summary expressions file
+type summariesConfig struct {
+	Summaries map[string]string `json:"summaries"` // Class → expression
+}
+
+// loadSummaries reads the summary expressions file at path
+func loadSummaries(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config summariesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid summaries file %s: %w", path, err)
+	}
+	return config.Summaries, nil
+}
+
+// configuredSummaries reads the file named by GODOT_MCP_SUMMARIES_FILE.
+// Returns nil without error when the variable is unset.
+func configuredSummaries() (map[string]string, error) {
+	path := os.Getenv(summariesFileEnv)
+	if path == "" {
+		return nil, nil
+	}
+	return loadSummaries(path)
+}
+
+// summaryGlobals are the lowercase names a summary expression can use
+// without referring to the instance: keywords and global functions
+var summaryGlobals = map[string]bool{
+	"true": true, "false": true, "null": true, "and": true, "or": true, "not": true,
+	"in": true, "is": true, "as": true, "if": true, "else": true,
+	"str": true, "int": true, "float": true, "bool": true, "len": true, "range": true,
+	"abs": true, "ceil": true, "floor": true, "round": true, "sign": true, "sqrt": true, "pow": true,
+	"min": true, "max": true, "clamp": true, "lerp": true, "snapped": true, "fmod": true, "posmod": true,
+	"typeof": true, "type_string": true, "var_to_str": true, "char": true,
+	"is_instance_valid": true, "instance_from_id": true, "is_equal_approx": true,
+}
+
+// bindSummaryExpression rewrites a summary expression to run against object
+// (an expression for the instance): self becomes object, and bare lowercase
+// names other than keywords and global functions become object.<name>.
+// Capitalized names (classes, singletons, constants like PI) are left alone,
+// so the instance's own constants need self.NAME.
+func bindSummaryExpression(expression, object string) string {
+	var sb strings.Builder
+	runes := []rune(expression)
+	var prev rune // Last non-space rune written
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(runes) {
+				j++
+			}
+			sb.WriteString(string(runes[i:j]))
+			prev, i = r, j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '.' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			sb.WriteString(string(runes[i:j]))
+			prev, i = runes[j-1], j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			name := string(runes[i:j])
+			switch {
+			case prev == '.' || summaryGlobals[name] || unicode.IsUpper(r):
+				sb.WriteString(name)
+			case name == "self":
+				sb.WriteString(object)
+			default:
+				sb.WriteString(object + "." + name)
+			}
+			prev, i = runes[j-1], j
+		default:
+			sb.WriteRune(r)
+			if !unicode.IsSpace(r) {
+				prev = r
+			}
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// summaryClass picks the class whose summary applies to an instance: its
+// engine class if one is configured, otherwise its script's class_name
+func summaryClass(ctx context.Context, client expressionEvaluator, summaries map[string]string, object, engineClass string) string {
+	if _, ok := summaries[engineClass]; ok {
+		return engineClass
+	}
+	resp, err := client.Evaluate(ctx, fmt.Sprintf("str(%s.get_script().get_global_name())", object), 0, "watch")
+	if err != nil {
+		return ""
+	}
+	if class := unquoteGodotString(resp.Body.Result); summaries[class] != "" {
+		return class
+	}
+	return ""
+}
+
+// addSummaries evaluates the configured summary expression of each object
+// among variables and adds the result as summary. Failures are reported as
+// summary_error, so a broken expression shows up where it's used.
+// formatted must be formatVariableList(variables).
+func addSummaries(ctx context.Context, client expressionEvaluator, variables []godap.Variable, formatted []map[string]interface{}) {
+	summaries, err := configuredSummaries()
+	if err != nil || len(summaries) == 0 {
+		return
+	}
+
+	type instanceSummary struct {
+		value string
+		err   error
+	}
+	done := map[string]*instanceSummary{}
+	for i, variable := range variables {
+		matches := nodeInstancePattern.FindStringSubmatch(variable.Value)
+		if len(matches) != 3 {
+			continue
+		}
+		id := matches[2]
+		s, seen := done[id]
+		if !seen {
+			if len(done) >= maxSummaryLookups {
+				break
+			}
+			object := fmt.Sprintf("instance_from_id(%s)", id)
+			if class := summaryClass(ctx, client, summaries, object, matches[1]); class != "" {
+				resp, err := client.Evaluate(ctx, bindSummaryExpression(summaries[class], object), 0, "watch")
+				s = &instanceSummary{err: err}
+				if err == nil {
+					s.value = unquoteGodotString(resp.Body.Result)
+				}
+			}
+			done[id] = s
+		}
+		switch {
+		case s == nil:
+		case s.err != nil:
+			formatted[i]["summary_error"] = s.err.Error()
+		default:
+			formatted[i]["summary"] = s.value
+		}
+	}
+}
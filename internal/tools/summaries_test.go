@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+func TestBindSummaryExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`str(items.size()) + " items"`, `str(o.items.size()) + " items"`},
+		{`name + ": " + str(health) + "/" + str(self.MAX_HEALTH)`, `o.name + ": " + str(o.health) + "/" + str(o.MAX_HEALTH)`},
+		{`get_count() if not is_empty() else 0`, `o.get_count() if not o.is_empty() else 0`},
+		{`"hp: %d" % hp`, `"hp: %d" % o.hp`},
+		{`snapped(speed * 1.5, 0.1)`, `snapped(o.speed * 1.5, 0.1)`},
+		{`Vector2(x, y).length() > PI`, `Vector2(o.x, o.y).length() > PI`},
+		{`stats["it's"]`, `o.stats["it's"]`},
+	}
+	for _, tt := range tests {
+		if got := bindSummaryExpression(tt.expr, "o"); got != tt.want {
+			t.Errorf("bindSummaryExpression(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestAddSummaries(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "summaries.json")
+	config := `{"summaries": {"Inventory": "str(items.size()) + \" items\"", "Timer": "str(time_left)"}}`
+	if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(summariesFileEnv, file)
+
+	variables := []godap.Variable{
+		{Name: "inventory", Type: "Object", Value: "Node:<Node#11>"},
+		{Name: "cooldown", Type: "Object", Value: "Timer:<Timer#12>"},
+		{Name: "sprite", Type: "Object", Value: "Sprite2D:<Sprite2D#13>"},
+		{Name: "gold", Type: "int", Value: "40"},
+	}
+	eval := &fakeDiagnoseEvaluator{values: map[string][2]string{
+		"str(instance_from_id(11).get_script().get_global_name())": {"Inventory", "String"},
+		`str(instance_from_id(11).items.size()) + " items"`:        {"3 items", "String"},
+		"str(instance_from_id(13).get_script().get_global_name())": {"", "String"},
+	}}
+	formatted := formatVariableList(variables)
+	addSummaries(context.Background(), eval, variables, formatted)
+
+	if formatted[0]["summary"] != "3 items" {
+		t.Errorf("expected the script class summary, got %v", formatted[0])
+	}
+	if formatted[1]["summary_error"] == nil {
+		t.Errorf("a failing summary expression should be reported, got %v", formatted[1])
+	}
+	if formatted[2]["summary"] != nil || formatted[2]["summary_error"] != nil || formatted[3]["summary"] != nil {
+		t.Errorf("unconfigured classes should not be summarized, got %v, %v", formatted[2], formatted[3])
+	}
+
+	// Without a file nothing is evaluated
+	t.Setenv(summariesFileEnv, "")
+	eval.evaluated = nil
+	addSummaries(context.Background(), eval, variables, formatVariableList(variables))
+	if len(eval.evaluated) != 0 {
+		t.Errorf("expected no evaluates without a summaries file, got %v", eval.evaluated)
+	}
+}
@@ -0,0 +1,431 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// tracepointClient is the subset of *dap.Client used to handle tracepoint hits
+type tracepointClient interface {
+	StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*godap.StackTraceResponse, error)
+	Continue(ctx context.Context, threadId int) (*godap.ContinueResponse, error)
+}
+
+// tracepointKey identifies a tracepoint by resolved path and verified line
+type tracepointKey struct {
+	Path string
+	Line int
+}
+
+// tracepointStats accumulates the hits of one tracepoint
+type tracepointStats struct {
+	File        string // Path as given by the user (res:// or absolute)
+	Line        int
+	Added       time.Time
+	Hits        int
+	FirstHit    time.Time
+	LastHit     time.Time
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	SumInterval time.Duration
+}
+
+// hit records a hit at t
+func (s *tracepointStats) hit(t time.Time) {
+	if s.Hits > 0 {
+		interval := t.Sub(s.LastHit)
+		if s.Hits == 1 || interval < s.MinInterval {
+			s.MinInterval = interval
+		}
+		if interval > s.MaxInterval {
+			s.MaxInterval = interval
+		}
+		s.SumInterval += interval
+	} else {
+		s.FirstHit = t
+	}
+	s.Hits++
+	s.LastHit = t
+}
+
+// tracer turns breakpoints into tracepoints: when one is hit, the hit is
+// counted and execution continues immediately. Breakpoints that aren't
+// tracepoints still pause as usual.
+type tracer struct {
+	mu     sync.Mutex
+	points map[tracepointKey]*tracepointStats
+	lines  map[string][]int // Requested lines per resolved path, for setBreakpoints
+	stop   chan struct{}
+
+	decisions stopDecisions // Whether each breakpoint stop was continued
+}
+
+// Active tracepoints for godot_set_tracepoint
+var tracepoints = tracer{points: map[tracepointKey]*tracepointStats{}, lines: map[string][]int{}}
+
+// addLine records a requested line for path and returns every requested
+// line of that file, since setBreakpoints replaces the whole file's list
+func (tr *tracer) addLine(path string, line int) []int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	path = filepath.Clean(path)
+	for _, l := range tr.lines[path] {
+		if l == line {
+			return append([]int(nil), tr.lines[path]...)
+		}
+	}
+	tr.lines[path] = append(tr.lines[path], line)
+	return append([]int(nil), tr.lines[path]...)
+}
+
+// register starts counting hits at the verified line of a tracepoint
+func (tr *tracer) register(file string, path string, line int) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	key := tracepointKey{Path: filepath.Clean(path), Line: line}
+	if _, ok := tr.points[key]; !ok {
+		tr.points[key] = &tracepointStats{File: file, Line: line, Added: time.Now()}
+	}
+}
+
+// recordHit counts a hit if path:line is a tracepoint; returns false otherwise
+func (tr *tracer) recordHit(path string, line int, t time.Time) bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	stats, ok := tr.points[tracepointKey{Path: filepath.Clean(path), Line: line}]
+	if !ok {
+		return false
+	}
+	stats.hit(t)
+	return true
+}
+
+// handleStop continues execution if the thread stopped at a tracepoint.
+// Returns true if it was a tracepoint hit.
+func (tr *tracer) handleStop(client tracepointClient, threadId int) bool {
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	resp, err := client.StackTrace(ctx, threadId, 0, 1)
+	if err != nil || len(resp.Body.StackFrames) == 0 {
+		return false
+	}
+	frame := resp.Body.StackFrames[0]
+	if isNativeFrame(frame) || !tr.recordHit(frame.Source.Path, frame.Line, now) {
+		return false
+	}
+
+	if _, err := client.Continue(ctx, threadId); err != nil {
+		log.Printf("Failed to continue after tracepoint hit: %v", err)
+	}
+	return true
+}
+
+// attach starts handling breakpoint stops on the client, replacing any
+// previous attachment
+func (tr *tracer) attach(client *dap.Client) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.stop != nil {
+		close(tr.stop)
+	}
+	stop := make(chan struct{})
+	tr.stop = stop
+
+	// Stops are handled on a queue so the listener keeps draining events
+	// while a hit is recorded and the game resumed
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("tracepoints"), dap.WithBlockWhenFull())
+	tr.decisions.begin(client)
+	queue := newWorkQueue(stop)
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				if e, ok := msg.(*godap.StoppedEvent); ok && e.Body.Reason == "breakpoint" {
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					queue.push(func() { tr.decisions.record(e, tr.handleStop(client, threadId)) })
+				}
+			}
+		}
+	}()
+}
+
+// detach stops handling stops; collected statistics are kept
+func (tr *tracer) detach() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.stop != nil {
+		close(tr.stop)
+		tr.stop = nil
+		tr.decisions.end()
+	}
+}
+
+// clear detaches and forgets all tracepoints, returning the files that had
+// tracepoints so their breakpoints can be removed
+func (tr *tracer) clear() []string {
+	tr.detach()
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	files := make([]string, 0, len(tr.lines))
+	for path := range tr.lines {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	tr.points = map[tracepointKey]*tracepointStats{}
+	tr.lines = map[string][]int{}
+	return files
+}
+
+// snapshot returns copies of every tracepoint's statistics, most hit first
+func (tr *tracer) snapshot() []tracepointStats {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	stats := make([]tracepointStats, 0, len(tr.points))
+	for _, s := range tr.points {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Hits != stats[j].Hits {
+			return stats[i].Hits > stats[j].Hits
+		}
+		if stats[i].File != stats[j].File {
+			return stats[i].File < stats[j].File
+		}
+		return stats[i].Line < stats[j].Line
+	})
+	return stats
+}
+
+// formatTracepointStats converts a tracepoint's statistics for tool
+// responses. Hit frequency is measured from when the tracepoint was set.
+func formatTracepointStats(s tracepointStats, now time.Time) map[string]interface{} {
+	result := map[string]interface{}{
+		"file": s.File,
+		"line": s.Line,
+		"hits": s.Hits,
+	}
+	if elapsed := now.Sub(s.Added).Seconds(); elapsed > 0 {
+		result["hits_per_second"] = float64(s.Hits) / elapsed
+	}
+	if s.Hits > 0 {
+		result["first_hit"] = s.FirstHit.Format(time.RFC3339Nano)
+		result["last_hit"] = s.LastHit.Format(time.RFC3339Nano)
+	}
+	if s.Hits > 1 {
+		result["interval_ms"] = map[string]interface{}{
+			"min": s.MinInterval.Milliseconds(),
+			"avg": (s.SumInterval / time.Duration(s.Hits-1)).Milliseconds(),
+			"max": s.MaxInterval.Milliseconds(),
+		}
+	}
+	return result
+}
+
+// RegisterTracepointTools registers tracepoint profiling tools
+func RegisterTracepointTools(server *mcp.Server) {
+	// godot_set_tracepoint - Count hits at a line without pausing
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_set_tracepoint",
+		Description: `Set a tracepoint: a breakpoint that records hits and continues automatically.
+
+Each time the game reaches the line, the hit time is recorded and execution
+resumes immediately. godot_get_tracepoint_stats then reports how often each
+line ran and the time between hits — a simple profiler that works entirely
+through the debugger.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+Caveats:
+- Every hit is a real pause plus a stack trace and continue round trip, so hot
+  lines slow the game down and timings include that overhead. Compare lines
+  relative to each other rather than reading absolute numbers.
+- setBreakpoints replaces all breakpoints of a file, so a tracepoint removes
+  regular breakpoints in the same file. Keep tracepoints and breakpoints in
+  separate files.
+- Regular breakpoints elsewhere still pause the game.
+
+Example: Count how often a function runs
+godot_set_tracepoint(file="res://scripts/enemy.gd", line=30)`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "file",
+				Type:        "string",
+				Required:    true,
+				Description: "Path to GDScript file (absolute or res:// path)",
+				Validate:    validateGodotPathParam,
+			},
+			{
+				Name:        "line",
+				Type:        "number",
+				Required:    true,
+				Description: "Line number to trace (1-indexed)",
+				Minimum:     mcp.Float64(1),
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			file, ok := params["file"].(string)
+			if !ok || file == "" {
+				return nil, fmt.Errorf("file parameter is required and must be a non-empty string")
+			}
+			lineFloat, ok := params["line"].(float64)
+			if !ok || lineFloat < 1 {
+				return nil, fmt.Errorf("line parameter is required and must be a positive integer")
+			}
+			line := int(lineFloat)
+
+			if isCSharpScript(file) {
+				return nil, ErrCSharpBreakpoint(file, session.GetProjectRoot())
+			}
+			normalizedFile, err := resolveGodotPath(file, session.GetProjectRoot())
+			if err != nil {
+				return nil, err
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			client := session.GetClient()
+			lines := tracepoints.addLine(normalizedFile, line)
+			resp, err := client.SetBreakpoints(ctx, normalizedFile, lines)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set tracepoint: %w", err)
+			}
+			// The request replaced any regular breakpoints in the file
+			session.Breakpoints().Set(normalizedFile, nil)
+
+			// The last breakpoint is the one just requested; Godot may have
+			// moved it to the next executable line
+			if len(resp.Body.Breakpoints) != len(lines) {
+				return nil, fmt.Errorf("no tracepoint was set (file may not exist or line may be invalid)")
+			}
+			bp := resp.Body.Breakpoints[len(lines)-1]
+			actualLine := line
+			if bp.Line > 0 {
+				actualLine = bp.Line
+			}
+			tracepoints.register(file, normalizedFile, actualLine)
+			tracepoints.attach(client)
+
+			result := map[string]interface{}{
+				"status":         "set",
+				"message":        fmt.Sprintf("Tracepoint set at %s:%d. Hits are counted and execution continues automatically.", file, actualLine),
+				"file":           file,
+				"requested_line": line,
+				"actual_line":    actualLine,
+				"verified":       bp.Verified,
+			}
+			if actualLine != line {
+				result["adjusted"] = true
+			}
+			return result, nil
+		},
+	})
+
+	// godot_get_tracepoint_stats - Report hit counts and timing
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_tracepoint_stats",
+		Description: `Get hit counts and timing for every tracepoint.
+
+Lines are sorted by hit count (hottest first). For each line the result has the
+number of hits, hits per second since the tracepoint was set, first and last hit
+times, and the min/avg/max interval between consecutive hits in milliseconds.
+
+Statistics are kept after disconnecting and reset by godot_clear_tracepoints.
+
+Example: Find the hottest lines
+godot_get_tracepoint_stats()`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			now := time.Now()
+			stats := tracepoints.snapshot()
+			lines := make([]map[string]interface{}, len(stats))
+			total := 0
+			for i, s := range stats {
+				lines[i] = formatTracepointStats(s, now)
+				total += s.Hits
+			}
+			return map[string]interface{}{
+				"status":      "success",
+				"tracepoints": lines,
+				"count":       len(lines),
+				"total_hits":  total,
+			}, nil
+		},
+	})
+
+	// godot_clear_tracepoints - Remove all tracepoints
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_clear_tracepoints",
+		Description: `Remove all tracepoints and reset their statistics.
+
+This clears the breakpoints of every file that had a tracepoint (which also
+removes regular breakpoints in those files).
+
+Example: Stop profiling
+godot_clear_tracepoints()`,
+
+		Parameters: []mcp.Parameter{},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			files := tracepoints.clear()
+
+			// Without a session the breakpoints are already gone
+			session := currentSession()
+			if session == nil {
+				return map[string]interface{}{
+					"status": "cleared",
+					"files":  files,
+				}, nil
+			}
+
+			ctx, cancel := dap.WithCommandTimeout(context.Background())
+			defer cancel()
+
+			var failed []string
+			for _, path := range files {
+				if _, err := session.GetClient().SetBreakpoints(ctx, path, []int{}); err != nil {
+					failed = append(failed, path)
+				}
+			}
+
+			result := map[string]interface{}{
+				"status": "cleared",
+				"files":  files,
+			}
+			if len(failed) > 0 {
+				result["status"] = "partially_cleared"
+				result["failed_files"] = failed
+				result["message"] = "Some breakpoints could not be removed; use godot_clear_breakpoint on these files"
+			}
+			return result, nil
+		},
+	})
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	godap "github.com/google/go-dap"
+)
+
+// fakeTracepointClient is stopped at a fixed location and counts continues
+type fakeTracepointClient struct {
+	path      string
+	line      int
+	continues int
+}
+
+func (f *fakeTracepointClient) StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*godap.StackTraceResponse, error) {
+	resp := &godap.StackTraceResponse{}
+	resp.Body.StackFrames = []godap.StackFrame{{Id: 0, Name: "_process", Line: f.line, Source: &godap.Source{Path: f.path}}}
+	return resp, nil
+}
+
+func (f *fakeTracepointClient) Continue(ctx context.Context, threadId int) (*godap.ContinueResponse, error) {
+	f.continues++
+	return &godap.ContinueResponse{}, nil
+}
+
+func TestTracer_HandleStop(t *testing.T) {
+	tr := tracer{points: map[tracepointKey]*tracepointStats{}, lines: map[string][]int{}}
+	if lines := tr.addLine("/game/enemy.gd", 30); len(lines) != 1 {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+	if lines := tr.addLine("/game/enemy.gd", 42); len(lines) != 2 || lines[0] != 30 {
+		t.Fatalf("lines of the same file should accumulate, got %v", lines)
+	}
+	tr.register("res://enemy.gd", "/game/enemy.gd", 30)
+
+	client := &fakeTracepointClient{path: "/game/enemy.gd", line: 30}
+	if !tr.handleStop(client, 1) || !tr.handleStop(client, 1) || client.continues != 2 {
+		t.Fatalf("tracepoint hits should continue execution, got %d continues", client.continues)
+	}
+
+	// A regular breakpoint stays paused
+	client.line = 50
+	if tr.handleStop(client, 1) || client.continues != 2 {
+		t.Error("stops outside tracepoints should not continue")
+	}
+
+	stats := tr.snapshot()
+	if len(stats) != 1 || stats[0].Hits != 2 || stats[0].File != "res://enemy.gd" {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if files := tr.clear(); len(files) != 1 || files[0] != "/game/enemy.gd" {
+		t.Errorf("clear should return traced files, got %v", files)
+	}
+	if len(tr.snapshot()) != 0 {
+		t.Error("clear should reset statistics")
+	}
+}
+
+func TestFormatTracepointStats(t *testing.T) {
+	start := time.Now()
+	s := tracepointStats{File: "res://enemy.gd", Line: 30, Added: start}
+	s.hit(start.Add(100 * time.Millisecond))
+	s.hit(start.Add(150 * time.Millisecond))
+	s.hit(start.Add(300 * time.Millisecond))
+
+	result := formatTracepointStats(s, start.Add(time.Second))
+	if result["hits"] != 3 || result["hits_per_second"] != 3.0 {
+		t.Errorf("unexpected hits: %v", result)
+	}
+	interval := result["interval_ms"].(map[string]interface{})
+	if interval["min"] != int64(50) || interval["avg"] != int64(100) || interval["max"] != int64(150) {
+		t.Errorf("unexpected intervals: %v", interval)
+	}
+
+	if _, ok := formatTracepointStats(tracepointStats{Added: start}, start.Add(time.Second))["interval_ms"]; ok {
+		t.Error("intervals need at least two hits")
+	}
+}
@@ -0,0 +1,408 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/mcp"
+	godap "github.com/google/go-dap"
+)
+
+// maxWatchHistory caps the samples kept per watch; the oldest are dropped
+const maxWatchHistory = 200
+
+// watchEvaluator is the subset of *dap.Client used to sample watches
+type watchEvaluator interface {
+	StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*godap.StackTraceResponse, error)
+	Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error)
+}
+
+// watchSample is the value of a watch expression observed at one stop
+type watchSample struct {
+	Time     time.Time
+	Reason   string // Stop reason ("breakpoint", "step", ...) or "added"
+	Function string
+	Line     int
+	Value    string
+	Type     string
+	Err      string // Evaluation error, if the expression couldn't be evaluated
+}
+
+// watchRegistry holds the registered watch expressions and their history.
+// While attached to a client, every stopped event evaluates each watch in
+// the top frame and appends the result to its history.
+type watchRegistry struct {
+	mu          sync.Mutex
+	expressions []string // Registration order
+	history     map[string][]watchSample
+	stop        chan struct{}
+}
+
+// Registered watches for godot_add_watch and godot_get_watch_history
+var watches = watchRegistry{history: map[string][]watchSample{}}
+
+// add registers an expression; returns false if it was already registered
+func (w *watchRegistry) add(expression string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.history[expression]; ok {
+		return false
+	}
+	w.expressions = append(w.expressions, expression)
+	w.history[expression] = []watchSample{}
+	return true
+}
+
+// remove unregisters an expression and drops its history
+func (w *watchRegistry) remove(expression string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.history[expression]; !ok {
+		return false
+	}
+	delete(w.history, expression)
+	for i, e := range w.expressions {
+		if e == expression {
+			w.expressions = append(w.expressions[:i], w.expressions[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the registered expressions in registration order
+func (w *watchRegistry) list() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.expressions...)
+}
+
+// samples returns a copy of an expression's history; ok is false if the
+// expression isn't registered
+func (w *watchRegistry) samples(expression string) ([]watchSample, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history, ok := w.history[expression]
+	return append([]watchSample(nil), history...), ok
+}
+
+// record appends a sample, unless the watch was removed in the meantime
+func (w *watchRegistry) record(expression string, sample watchSample) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history, ok := w.history[expression]
+	if !ok {
+		return
+	}
+	history = append(history, sample)
+	if len(history) > maxWatchHistory {
+		history = history[len(history)-maxWatchHistory:]
+	}
+	w.history[expression] = history
+}
+
+// sample evaluates every watch in the top frame of threadId and records the
+// results. Frame lookup failures are recorded on each watch so gaps in the
+// history are visible.
+func (w *watchRegistry) sample(client watchEvaluator, threadId int, reason string) {
+	expressions := w.list()
+	if len(expressions) == 0 {
+		return
+	}
+
+	ctx, cancel := dap.WithCommandTimeout(context.Background())
+	defer cancel()
+
+	base := watchSample{Time: time.Now(), Reason: reason}
+	frameId := 0
+	if resp, err := client.StackTrace(ctx, threadId, 0, 1); err != nil {
+		base.Err = fmt.Sprintf("stack trace failed: %v", err)
+	} else if len(resp.Body.StackFrames) > 0 {
+		frame := resp.Body.StackFrames[0]
+		frameId = frame.Id
+		base.Function = frame.Name
+		base.Line = frame.Line
+	}
+
+	for _, expression := range expressions {
+		sample := base
+		if sample.Err == "" {
+			resp, err := client.Evaluate(ctx, expression, frameId, "watch")
+			if err != nil {
+				sample.Err = err.Error()
+			} else {
+				sample.Value = resp.Body.Result
+				sample.Type = resp.Body.Type
+			}
+		}
+		w.record(expression, sample)
+	}
+}
+
+// attach starts sampling watches on the client's stopped events, replacing
+// any previous attachment
+func (w *watchRegistry) attach(client *dap.Client) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		close(w.stop)
+	}
+	stop := make(chan struct{})
+	w.stop = stop
+
+	events, cleanup := client.SubscribeToEvents(dap.WithListenerName("watches"))
+	go func() {
+		defer cleanup()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-events:
+				if e, ok := msg.(*godap.StoppedEvent); ok {
+					threadId := e.Body.ThreadId
+					if threadId == 0 {
+						threadId = 1
+					}
+					w.sample(client, threadId, e.Body.Reason)
+				}
+			}
+		}
+	}()
+}
+
+// detach stops sampling; the registered watches and history are kept
+func (w *watchRegistry) detach() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// attached reports whether watches are being sampled
+func (w *watchRegistry) attached() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stop != nil
+}
+
+// formatWatchSample converts a sample for tool responses
+func formatWatchSample(sample watchSample) map[string]interface{} {
+	result := map[string]interface{}{
+		"time":   sample.Time.Format(time.RFC3339Nano),
+		"reason": sample.Reason,
+	}
+	if sample.Function != "" {
+		result["function"] = sample.Function
+		result["line"] = sample.Line
+	}
+	if sample.Err != "" {
+		result["error"] = sample.Err
+		return result
+	}
+	result["value"] = sample.Value
+	result["type"] = sample.Type
+	if formatted := formatGodotType(sample.Type, sample.Value); formatted != "" {
+		result["formatted"] = formatted
+	}
+	return result
+}
+
+// formatWatchHistory converts an expression's history, noting how many
+// times the value changed between consecutive successful samples
+func formatWatchHistory(expression string, samples []watchSample) map[string]interface{} {
+	formatted := make([]map[string]interface{}, len(samples))
+	changes := 0
+	var last *watchSample
+	for i := range samples {
+		formatted[i] = formatWatchSample(samples[i])
+		if samples[i].Err != "" {
+			continue
+		}
+		if last != nil && last.Value != samples[i].Value {
+			formatted[i]["changed"] = true
+			changes++
+		}
+		last = &samples[i]
+	}
+	return map[string]interface{}{
+		"expression": expression,
+		"samples":    formatted,
+		"count":      len(formatted),
+		"changes":    changes,
+	}
+}
+
+// RegisterWatchTools registers watch expression tools
+func RegisterWatchTools(server *mcp.Server) {
+	// godot_add_watch - Register a watch expression
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_add_watch",
+		Description: `Register a GDScript expression to record at every stop.
+
+Each time the game stops (breakpoint, step, pause), every registered watch is
+evaluated in the top stack frame and its value is appended to the watch's
+history. Use godot_get_watch_history to see how the value evolved — a
+lightweight time series over a stepping session.
+
+Prerequisites:
+- Must be connected to Godot DAP server (call godot_connect first)
+
+If the game is paused when the watch is added, its current value is recorded
+immediately. Watches survive reconnects; history is capped at the most recent
+200 samples per watch.
+
+Like godot_evaluate, the expression is executed in the game, so avoid
+expressions with side effects.
+
+Example: Track a member while stepping
+godot_add_watch(expression="velocity")
+
+Example: Track a derived value
+godot_add_watch(expression="position.distance_to(target.position)")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "expression",
+				Type:        "string",
+				Required:    true,
+				Description: "GDScript expression to evaluate at each stop",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			session, err := GetSession()
+			if err != nil {
+				return nil, fmt.Errorf("%w\n\nPlease call godot_connect first to establish a DAP session", err)
+			}
+
+			expression, ok := params["expression"].(string)
+			if !ok || expression == "" {
+				return nil, fmt.Errorf("expression is required and must be a non-empty string")
+			}
+
+			added := watches.add(expression)
+			autosaveSession()
+			client := session.GetClient()
+			if !watches.attached() {
+				watches.attach(client)
+			}
+			if added && client.RunState() == dap.RunStatePaused {
+				watches.sample(client, 1, "added")
+			}
+
+			status := "added"
+			if !added {
+				status = "already_watching"
+			}
+			return map[string]interface{}{
+				"status":     status,
+				"expression": expression,
+				"watches":    watches.list(),
+			}, nil
+		},
+	})
+
+	// godot_remove_watch - Unregister a watch expression
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_remove_watch",
+		Description: `Stop recording a watch expression and discard its history.
+
+Example: Remove a watch
+godot_remove_watch(expression="velocity")`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "expression",
+				Type:        "string",
+				Required:    true,
+				Description: "Watch expression to remove (as passed to godot_add_watch)",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			expression, ok := params["expression"].(string)
+			if !ok || expression == "" {
+				return nil, fmt.Errorf("expression is required and must be a non-empty string")
+			}
+
+			removed := watches.remove(expression)
+			autosaveSession()
+			if !removed {
+				return map[string]interface{}{
+					"status":  "not_watching",
+					"message": fmt.Sprintf("'%s' is not a registered watch", expression),
+					"watches": watches.list(),
+				}, nil
+			}
+			return map[string]interface{}{
+				"status":     "removed",
+				"expression": expression,
+				"watches":    watches.list(),
+			}, nil
+		},
+	})
+
+	// godot_get_watch_history - Values of watches over time
+	server.RegisterTool(mcp.Tool{
+		Name: "godot_get_watch_history",
+		Description: `Get the recorded values of watch expressions over time.
+
+Each sample holds the value observed at one stop, the stop reason, and the
+function and line where the game was stopped. Samples whose value differs from
+the previous one are marked changed=true, and changes counts them.
+
+Without an expression, the history of every registered watch is returned.
+
+Example: See how a value evolved
+godot_get_watch_history(expression="velocity")
+
+Example: All watches
+godot_get_watch_history()`,
+
+		Parameters: []mcp.Parameter{
+			{
+				Name:        "expression",
+				Type:        "string",
+				Required:    false,
+				Description: "Watch expression (as passed to godot_add_watch); omit for all watches",
+			},
+		},
+
+		Handler: func(params map[string]interface{}) (interface{}, error) {
+			if expression, ok := params["expression"].(string); ok && expression != "" {
+				samples, ok := watches.samples(expression)
+				if !ok {
+					return nil, FormatError(
+						fmt.Sprintf("'%s' is not a registered watch", expression),
+						"",
+						[]string{
+							"Register it with godot_add_watch(expression=...) first",
+							"Call godot_get_watch_history() without arguments to list all watches",
+						},
+						nil,
+					)
+				}
+				result := formatWatchHistory(expression, samples)
+				result["status"] = "success"
+				return result, nil
+			}
+
+			histories := make([]map[string]interface{}, 0)
+			for _, expression := range watches.list() {
+				samples, _ := watches.samples(expression)
+				histories = append(histories, formatWatchHistory(expression, samples))
+			}
+			return map[string]interface{}{
+				"status":  "success",
+				"watches": histories,
+				"count":   len(histories),
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	godap "github.com/google/go-dap"
+)
+
+// fakeWatchEvaluator stops in a fixed frame and evaluates expressions from a
+// table of values
+type fakeWatchEvaluator struct {
+	values map[string]string
+}
+
+func (f *fakeWatchEvaluator) StackTrace(ctx context.Context, threadId int, startFrame int, levels int) (*godap.StackTraceResponse, error) {
+	resp := &godap.StackTraceResponse{}
+	resp.Body.StackFrames = []godap.StackFrame{{Id: 0, Name: "_process", Line: 12}}
+	return resp, nil
+}
+
+func (f *fakeWatchEvaluator) Evaluate(ctx context.Context, expression string, frameId int, context string) (*godap.EvaluateResponse, error) {
+	value, ok := f.values[expression]
+	if !ok {
+		return nil, fmt.Errorf("identifier '%s' not declared", expression)
+	}
+	resp := &godap.EvaluateResponse{}
+	resp.Body.Result = value
+	resp.Body.Type = "int"
+	return resp, nil
+}
+
+func TestWatchRegistry_History(t *testing.T) {
+	w := watchRegistry{history: map[string][]watchSample{}}
+	if !w.add("health") || w.add("health") {
+		t.Fatal("add should register a watch once")
+	}
+	w.add("missing")
+
+	eval := &fakeWatchEvaluator{values: map[string]string{"health": "100"}}
+	w.sample(eval, 1, "breakpoint")
+	w.sample(eval, 1, "step")
+	eval.values["health"] = "90"
+	w.sample(eval, 1, "step")
+
+	samples, ok := w.samples("health")
+	if !ok || len(samples) != 3 || samples[2].Value != "90" || samples[0].Function != "_process" {
+		t.Fatalf("unexpected history: %+v", samples)
+	}
+	history := formatWatchHistory("health", samples)
+	if history["changes"] != 1 || history["samples"].([]map[string]interface{})[2]["changed"] != true {
+		t.Errorf("expected one change on the last sample, got %v", history)
+	}
+
+	if missing, _ := w.samples("missing"); len(missing) != 3 || missing[0].Err == "" {
+		t.Errorf("evaluation errors should be recorded, got %+v", missing)
+	}
+
+	if !w.remove("health") || w.remove("health") {
+		t.Error("remove should unregister a watch once")
+	}
+	if _, ok := w.samples("health"); ok {
+		t.Error("removed watch should have no history")
+	}
+}
+
+func TestWatchRegistry_HistoryCap(t *testing.T) {
+	w := watchRegistry{history: map[string][]watchSample{}}
+	w.add("x")
+	for i := 0; i < maxWatchHistory+10; i++ {
+		w.record("x", watchSample{Value: fmt.Sprint(i)})
+	}
+	samples, _ := w.samples("x")
+	if len(samples) != maxWatchHistory || samples[0].Value != "10" {
+		t.Errorf("history should keep the newest %d samples, got %d starting at %s", maxWatchHistory, len(samples), samples[0].Value)
+	}
+}
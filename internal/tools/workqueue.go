@@ -0,0 +1,64 @@
+package tools
+
+import "sync"
+
+// workQueue runs queued functions one at a time, in order, on its own
+// goroutine until stop is closed. Event listeners subscribed WithBlockWhenFull
+// queue the handling of stops here instead of sending requests from their read
+// loop: the loop keeps draining its subscription while a handler waits on a
+// response, so a burst of events can't stall the client's reader.
+type workQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	wake    chan struct{}
+}
+
+// newWorkQueue starts a queue that runs until stop is closed; work still
+// queued then is dropped
+func newWorkQueue(stop <-chan struct{}) *workQueue {
+	q := &workQueue{wake: make(chan struct{}, 1)}
+	go q.run(stop)
+	return q
+}
+
+// push queues work without blocking
+func (q *workQueue) push(work func()) {
+	q.mu.Lock()
+	q.pending = append(q.pending, work)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next removes and returns the oldest queued work, or nil
+func (q *workQueue) next() func() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	work := q.pending[0]
+	q.pending[0] = nil
+	q.pending = q.pending[1:]
+	return work
+}
+
+func (q *workQueue) run(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-q.wake:
+		}
+		for work := q.next(); work != nil; work = q.next() {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			work()
+		}
+	}
+}
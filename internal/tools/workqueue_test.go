@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorkQueue(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	queue := newWorkQueue(stop)
+
+	ran := make(chan int, 3)
+	release := make(chan struct{})
+	queue.push(func() { <-release; ran <- 1 })
+	queue.push(func() { ran <- 2 })
+	queue.push(func() { ran <- 3 })
+
+	// push doesn't wait for earlier work
+	close(release)
+	var order []int
+	for len(order) < 3 {
+		select {
+		case n := <-ran:
+			order = append(order, n)
+		case <-time.After(time.Second):
+			t.Fatalf("queued work didn't run, got %v", order)
+		}
+	}
+	if !reflect.DeepEqual(order, []int{1, 2, 3}) {
+		t.Errorf("work should run in order, got %v", order)
+	}
+}
@@ -0,0 +1,88 @@
+package daptest
+
+import (
+	"sync"
+
+	"github.com/google/go-dap"
+)
+
+// SimulateGodot returns a handler that answers requests the way Godot's DAP
+// server does for a game that stops at its first breakpoint:
+//
+//   - initialize replies with capabilities and an initialized event
+//   - setBreakpoints verifies every requested line
+//   - configurationDone starts the game, which stops at the first breakpoint
+//     set (if any)
+//   - next, stepIn, and stepOut stop again one line further
+//   - continue resumes; pause stops with reason "pause"
+//   - terminate ends the game with terminated and exited events
+//
+// stackTrace reports a single frame at the current stop. Other requests get
+// a plain success response. Run it with server.Serve.
+func SimulateGodot(server *MockServer) Handler {
+	var mu sync.Mutex
+	path, line := "", 0 // Location of the current stop, or of the first breakpoint before the game starts
+
+	stopped := func(reason string) dap.Message {
+		return server.NewEvent("stopped", map[string]interface{}{"reason": reason, "threadId": 1, "allThreadsStopped": true})
+	}
+
+	return func(req dap.RequestMessage) []dap.Message {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []dap.Message{
+				server.Success(req, map[string]interface{}{"supportsConfigurationDoneRequest": true}),
+				server.NewEvent("initialized", nil),
+			}
+		case "setBreakpoints":
+			args := req.(*dap.SetBreakpointsRequest).Arguments
+			breakpoints := []map[string]interface{}{}
+			for i, bp := range args.Breakpoints {
+				breakpoints = append(breakpoints, map[string]interface{}{"id": i + 1, "verified": true, "line": bp.Line})
+			}
+			if line == 0 && len(args.Breakpoints) > 0 {
+				path, line = args.Source.Path, args.Breakpoints[0].Line
+			}
+			return []dap.Message{server.Success(req, map[string]interface{}{"breakpoints": breakpoints})}
+		case "configurationDone":
+			replies := []dap.Message{server.Success(req, nil)}
+			if line > 0 {
+				replies = append(replies, stopped("breakpoint"))
+			}
+			return replies
+		case "threads":
+			return []dap.Message{server.Success(req, map[string]interface{}{
+				"threads": []map[string]interface{}{{"id": 1, "name": "Main"}},
+			})}
+		case "stackTrace":
+			frames := []map[string]interface{}{}
+			if line > 0 {
+				frames = append(frames, map[string]interface{}{
+					"id": 0, "name": "_ready", "line": line, "column": 1,
+					"source": map[string]interface{}{"path": path},
+				})
+			}
+			return []dap.Message{server.Success(req, map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)})}
+		case "next", "stepIn", "stepOut":
+			line++
+			return []dap.Message{server.Success(req, nil), stopped("step")}
+		case "continue":
+			return []dap.Message{
+				server.Success(req, map[string]interface{}{"allThreadsContinued": true}),
+				server.NewEvent("continued", map[string]interface{}{"threadId": 1, "allThreadsContinued": true}),
+			}
+		case "pause":
+			return []dap.Message{server.Success(req, nil), stopped("pause")}
+		case "terminate":
+			return []dap.Message{
+				server.Success(req, nil),
+				server.NewEvent("terminated", nil),
+				server.NewEvent("exited", map[string]interface{}{"exitCode": 0}),
+			}
+		}
+		return nil
+	}
+}
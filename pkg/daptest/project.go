@@ -0,0 +1,154 @@
+package daptest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Script is a GDScript file of a generated test project
+type Script struct {
+	// Path relative to the project root, e.g. "scripts/player.gd"
+	Path string
+
+	// Source of the script. A line ending in a "# bp: <label>" comment marks
+	// a breakpoint location that Project.Breakpoint looks up by label.
+	Source string
+}
+
+// Location is a marked line in a generated project's script
+type Location struct {
+	ResPath string // res:// path of the script
+	File    string // Absolute path of the script
+	Line    int    // 1-based line number
+}
+
+// Project is a minimal Godot project generated into a temporary directory
+type Project struct {
+	Dir       string // Project root, containing project.godot
+	Name      string // config/name in project.godot
+	MainScene string // res:// path of the main scene
+
+	t           *testing.T
+	breakpoints map[string]Location
+}
+
+// breakpointMarker matches the "# bp: <label>" comment marking a line
+var breakpointMarker = regexp.MustCompile(`#\s*bp:\s*(\S+)\s*$`)
+
+// DefaultMainScript is the script attached to the main scene when
+// NewProject is given no scripts. Its marked lines run once at startup
+// ("ready", "sum", "loop") or every frame ("process").
+var DefaultMainScript = Script{
+	Path: "main.gd",
+	Source: `extends Node
+
+var counter: int = 0
+
+func _ready() -> void:
+	print("Test project starting") # bp: ready
+	var total: int = add(2, 3)
+	print("Total: ", total)
+	for i in range(3):
+		print("Iteration: ", i) # bp: loop
+
+func _process(delta: float) -> void:
+	counter += 1 # bp: process
+
+func add(a: int, b: int) -> int:
+	var sum: int = a + b # bp: sum
+	return sum
+`,
+}
+
+// NewProject generates a Godot project in a temporary directory removed when
+// the test ends: project.godot, the given scripts, and a main scene
+// (main.tscn) whose root node "Main" runs the first script. Without scripts,
+// DefaultMainScript is used.
+func NewProject(t *testing.T, scripts ...Script) *Project {
+	t.Helper()
+	if len(scripts) == 0 {
+		scripts = []Script{DefaultMainScript}
+	}
+
+	p := &Project{
+		Dir:         t.TempDir(),
+		Name:        "DAP Test Project",
+		MainScene:   "res://main.tscn",
+		t:           t,
+		breakpoints: map[string]Location{},
+	}
+
+	p.WriteFile("project.godot", fmt.Sprintf(`config_version=5
+
+[application]
+
+config/name=%q
+run/main_scene=%q
+`, p.Name, p.MainScene))
+
+	for _, script := range scripts {
+		p.WriteFile(script.Path, script.Source)
+		for i, line := range strings.Split(script.Source, "\n") {
+			matches := breakpointMarker.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			if _, ok := p.breakpoints[matches[1]]; ok {
+				t.Fatalf("breakpoint label %q is used twice", matches[1])
+			}
+			p.breakpoints[matches[1]] = Location{
+				ResPath: p.ResPath(script.Path),
+				File:    p.Path(script.Path),
+				Line:    i + 1,
+			}
+		}
+	}
+
+	p.WriteFile("main.tscn", fmt.Sprintf(`[gd_scene load_steps=2 format=3]
+
+[ext_resource type="Script" path=%q id="1"]
+
+[node name="Main" type="Node"]
+script = ExtResource("1")
+`, p.ResPath(scripts[0].Path)))
+
+	return p
+}
+
+// Path returns the absolute path of a file given relative to the project root
+func (p *Project) Path(rel string) string {
+	return filepath.Join(p.Dir, filepath.FromSlash(rel))
+}
+
+// ResPath returns the res:// path of a file given relative to the project root
+func (p *Project) ResPath(rel string) string {
+	return "res://" + filepath.ToSlash(rel)
+}
+
+// WriteFile writes a file relative to the project root, creating its
+// directory. Use it to add scenes or resources a test needs.
+func (p *Project) WriteFile(rel, content string) {
+	p.t.Helper()
+	path := p.Path(rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		p.t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		p.t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// Breakpoint returns the line marked "# bp: <label>", failing the test if
+// no script marks it
+func (p *Project) Breakpoint(label string) Location {
+	p.t.Helper()
+	location, ok := p.breakpoints[label]
+	if !ok {
+		p.t.Fatalf("No line is marked with breakpoint label %q", label)
+	}
+	return location
+}
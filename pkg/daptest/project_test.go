@@ -0,0 +1,51 @@
+package daptest
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewProject(t *testing.T) {
+	p := NewProject(t)
+
+	config, err := os.ReadFile(p.Path("project.godot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), `run/main_scene="res://main.tscn"`) {
+		t.Errorf("project.godot should name the main scene, got:\n%s", config)
+	}
+	scene, err := os.ReadFile(p.Path("main.tscn"))
+	if err != nil || !strings.Contains(string(scene), `path="res://main.gd"`) {
+		t.Errorf("main scene should use the main script, got %q, %v", scene, err)
+	}
+
+	sum := p.Breakpoint("sum")
+	if sum.ResPath != "res://main.gd" || sum.File != p.Path("main.gd") {
+		t.Errorf("unexpected location: %+v", sum)
+	}
+	lines := strings.Split(DefaultMainScript.Source, "\n")
+	if !strings.Contains(lines[sum.Line-1], "var sum: int = a + b") {
+		t.Errorf("line %d is %q, not the marked line", sum.Line, lines[sum.Line-1])
+	}
+}
+
+func TestNewProject_Scripts(t *testing.T) {
+	p := NewProject(t,
+		Script{Path: "scripts/player.gd", Source: "extends CharacterBody2D\n\nfunc jump() -> void:\n\tvelocity.y = -400 # bp: jump\n"},
+		Script{Path: "scripts/enemy.gd", Source: "extends Node2D\n"},
+	)
+
+	jump := p.Breakpoint("jump")
+	if jump.ResPath != "res://scripts/player.gd" || jump.Line != 4 {
+		t.Errorf("unexpected location: %+v", jump)
+	}
+	if _, err := os.Stat(p.Path("scripts/enemy.gd")); err != nil {
+		t.Errorf("every script should be written: %v", err)
+	}
+	scene, _ := os.ReadFile(p.Path("main.tscn"))
+	if !strings.Contains(string(scene), `path="res://scripts/player.gd"`) {
+		t.Errorf("the main scene should use the first script, got:\n%s", scene)
+	}
+}
@@ -23,8 +23,10 @@ type MockServer struct {
 	addr     string
 	conn     net.Conn
 	mu       sync.Mutex
+	writeMu  sync.Mutex // Keeps concurrent Sends from interleaving frames
 	closed   bool
 	seq      int
+	done     chan struct{} // Closed by Close
 
 	// Channels for coordination
 	receivedMsgs chan dap.Message
@@ -33,22 +35,33 @@ type MockServer struct {
 
 // NewServer starts a new mock DAP server on a random port
 func NewServer(t *testing.T) *MockServer {
-	listener, err := net.Listen("tcp", "localhost:0")
+	s, err := Listen()
 	if err != nil {
 		t.Fatalf("Failed to start mock server: %v", err)
 	}
+	s.t = t
+	return s
+}
+
+// Listen starts a new mock DAP server on a random port outside of a test,
+// e.g. to stand in for Godot in a command-line tool
+func Listen() (*MockServer, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
 
 	s := &MockServer{
-		t:            t,
 		listener:     listener,
 		addr:         listener.Addr().String(),
 		receivedMsgs: make(chan dap.Message, 100),
 		errors:       make(chan error, 10),
+		done:         make(chan struct{}),
 	}
 
 	go s.acceptLoop()
 
-	return s
+	return s, nil
 }
 
 // Address returns the address the server is listening on
@@ -70,6 +83,7 @@ func (s *MockServer) Close() {
 		return
 	}
 	s.closed = true
+	close(s.done)
 	if s.conn != nil {
 		s.conn.Close()
 	}
@@ -177,6 +191,8 @@ func (s *MockServer) Send(msg dap.Message) error {
 		return err
 	}
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	header := fmt.Sprintf("Content-Length: %d\r\n\r\n", len(body))
 	_, err = conn.Write([]byte(header))
 	if err != nil {
@@ -245,3 +261,75 @@ func (s *MockServer) ExpectRequest(command string) (dap.Message, error) {
 		return nil, fmt.Errorf("timeout waiting for request %s", command)
 	}
 }
+
+// Reply is a response with an arbitrary body, for answering requests without
+// building go-dap's typed responses. Clients decode it by command as usual.
+type Reply struct {
+	dap.Response
+	Body interface{} `json:"body,omitempty"`
+}
+
+// Notice is an event with an arbitrary body
+type Notice struct {
+	dap.Event
+	Body interface{} `json:"body,omitempty"`
+}
+
+// Success builds a successful response to req with an optional body
+func (s *MockServer) Success(req dap.RequestMessage, body interface{}) dap.Message {
+	r := req.GetRequest()
+	return &Reply{
+		Response: dap.Response{
+			ProtocolMessage: dap.ProtocolMessage{Seq: s.NextSeq(), Type: "response"},
+			RequestSeq:      r.Seq,
+			Success:         true,
+			Command:         r.Command,
+		},
+		Body: body,
+	}
+}
+
+// NewEvent builds an event with an optional body
+func (s *MockServer) NewEvent(event string, body interface{}) dap.Message {
+	return &Notice{
+		Event: dap.Event{
+			ProtocolMessage: dap.ProtocolMessage{Seq: s.NextSeq(), Type: "event"},
+			Event:           event,
+		},
+		Body: body,
+	}
+}
+
+// Handler answers one request with the messages to send back, in order.
+// Returning nil sends a plain Success response; an empty slice sends nothing
+// (e.g. to answer later from another goroutine).
+type Handler func(req dap.RequestMessage) []dap.Message
+
+// Serve answers every request with handle until the server is closed. Run it
+// in a goroutine instead of scripting requests with ExpectRequest. A nil
+// handle answers everything with Success.
+func (s *MockServer) Serve(handle Handler) {
+	for {
+		select {
+		case msg := <-s.receivedMsgs:
+			req, ok := msg.(dap.RequestMessage)
+			if !ok {
+				continue
+			}
+			var replies []dap.Message
+			if handle != nil {
+				replies = handle(req)
+			}
+			if replies == nil {
+				replies = []dap.Message{s.Success(req, nil)}
+			}
+			for _, reply := range replies {
+				if err := s.Send(reply); err != nil {
+					return
+				}
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
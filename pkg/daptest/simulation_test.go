@@ -1,11 +1,17 @@
 package daptest
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap"
+	"github.com/TransitionMatrix/godot-dap-mcp-server/internal/dap/quirks"
 	godap "github.com/google/go-dap"
 )
 
@@ -169,3 +175,551 @@ func TestGodotLaunchSimulation(t *testing.T) {
 		t.Errorf("Expected stop reason 'entry', got '%s'", stopped.Reason)
 	}
 }
+
+// TestServe answers a real client's requests with a handler
+func TestServe(t *testing.T) {
+	server := NewServer(t)
+	defer server.Close()
+	go server.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{
+				server.Success(req, map[string]interface{}{"supportsConfigurationDoneRequest": true}),
+				server.NewEvent("initialized", nil),
+			}
+		case "threads":
+			return []godap.Message{server.Success(req, map[string]interface{}{
+				"threads": []map[string]interface{}{{"id": 1, "name": "Main"}},
+			})}
+		}
+		return nil
+	})
+
+	client := dap.NewClient("localhost", server.Port())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Disconnect()
+
+	initResp, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if !initResp.Body.SupportsConfigurationDoneRequest {
+		t.Error("Expected capabilities from the handler's body")
+	}
+
+	threads, err := client.Threads(ctx)
+	if err != nil {
+		t.Fatalf("Threads failed: %v", err)
+	}
+	if len(threads.Body.Threads) != 1 || threads.Body.Threads[0].Name != "Main" {
+		t.Errorf("Unexpected threads: %+v", threads.Body.Threads)
+	}
+
+	// Requests the handler doesn't know get a plain success response
+	if _, err := client.Pause(ctx, 1); err != nil {
+		t.Errorf("Pause failed: %v", err)
+	}
+}
+
+// TestGotoRoundTrip checks that gotoTargets and goto responses reach the
+// waiting request instead of timing out
+func TestGotoRoundTrip(t *testing.T) {
+	server := NewServer(t)
+	defer server.Close()
+	gotoArgs := make(chan godap.GotoArguments, 1)
+	go server.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch r := req.(type) {
+		case *godap.GotoTargetsRequest:
+			if r.Arguments.Source.Path != "/game/player.gd" || r.Arguments.Line != 20 {
+				return []godap.Message{server.NewEvent("output", map[string]interface{}{"output": "unexpected gotoTargets arguments"})}
+			}
+			return []godap.Message{server.Success(req, map[string]interface{}{
+				"targets": []map[string]interface{}{{"id": 7, "label": "player.gd:20", "line": 20}},
+			})}
+		case *godap.GotoRequest:
+			gotoArgs <- r.Arguments
+		}
+		return nil
+	})
+
+	client := dap.NewClient("localhost", server.Port(), dap.WithTimeouts(2*time.Second, 2*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Disconnect()
+
+	targets, err := client.GotoTargets(ctx, "/game/player.gd", 20)
+	if err != nil {
+		t.Fatalf("GotoTargets failed: %v", err)
+	}
+	if len(targets.Body.Targets) != 1 || targets.Body.Targets[0].Id != 7 || targets.Body.Targets[0].Line != 20 {
+		t.Fatalf("Unexpected targets: %+v", targets.Body.Targets)
+	}
+
+	if _, err := client.Goto(ctx, 1, targets.Body.Targets[0].Id); err != nil {
+		t.Fatalf("Goto failed: %v", err)
+	}
+	if args := <-gotoArgs; args.ThreadId != 1 || args.TargetId != 7 {
+		t.Errorf("Unexpected goto arguments: %+v", args)
+	}
+}
+
+// sendOnConnect sends msgs as soon as the client has connected, before it
+// sends any request
+func sendOnConnect(t *testing.T, server *MockServer, msgs ...godap.Message) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for server.Send(msgs[0]) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("client never connected")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, msg := range msgs[1:] {
+		if err := server.Send(msg); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+}
+
+// TestInitializeEventRace sends the initialized event at the moments a
+// subscription made by Initialize could miss it: before the response, before
+// the initialize request, and behind more output events than a listener
+// buffers
+func TestInitializeEventRace(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		onConnect bool // initialized is sent as soon as the client connects
+		reply     func(server *MockServer, req godap.RequestMessage) []godap.Message
+	}{
+		{"before response", false, func(server *MockServer, req godap.RequestMessage) []godap.Message {
+			return []godap.Message{server.NewEvent("initialized", nil), server.Success(req, nil)}
+		}},
+		{"before initialize request", true, func(server *MockServer, req godap.RequestMessage) []godap.Message {
+			return []godap.Message{server.Success(req, nil)}
+		}},
+		{"after output burst", false, func(server *MockServer, req godap.RequestMessage) []godap.Message {
+			replies := []godap.Message{server.Success(req, nil)}
+			for i := 0; i < 50; i++ {
+				replies = append(replies, server.NewEvent("output", map[string]interface{}{"category": "stdout", "output": "loading\n"}))
+			}
+			return append(replies, server.NewEvent("initialized", nil))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer(t)
+			defer server.Close()
+			go server.Serve(func(req godap.RequestMessage) []godap.Message {
+				if req.GetRequest().Command == "initialize" {
+					return tc.reply(server, req)
+				}
+				return nil
+			})
+
+			client := dap.NewClient("localhost", server.Port(), dap.WithEventBufferSize(8))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer client.Disconnect()
+
+			if tc.onConnect {
+				sendOnConnect(t, server,
+					server.NewEvent("output", map[string]interface{}{"category": "stdout", "output": "early\n"}),
+					server.NewEvent("initialized", nil))
+				// Let the client read the events before Initialize is called
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			initCtx, initCancel := context.WithTimeout(ctx, time.Second)
+			defer initCancel()
+			if _, err := client.Initialize(initCtx); err != nil {
+				t.Fatalf("Initialize missed the initialized event: %v", err)
+			}
+
+			if !tc.onConnect {
+				return
+			}
+			// Events from before the first subscription are held for it
+			events, cleanup := client.SubscribeToEvents()
+			defer cleanup()
+			select {
+			case msg := <-events:
+				if e, ok := msg.(*godap.OutputEvent); !ok || e.Body.Output != "early\n" {
+					t.Errorf("expected the early output event first, got %T", msg)
+				}
+			case <-time.After(time.Second):
+				t.Error("early events were not held for the first subscriber")
+			}
+		})
+	}
+}
+
+// serveAutoloadGame simulates a game whose autoload script runs as soon as
+// configurationDone starts it. Godot acknowledges the autoload breakpoint
+// after a delay; the breakpoint is hit only if it was acknowledged before
+// configurationDone arrived. requested is signalled when setBreakpoints arrives.
+func serveAutoloadGame(server *MockServer, requested chan<- struct{}) {
+	var mu sync.Mutex
+	acked := false
+	server.Serve(func(req godap.RequestMessage) []godap.Message {
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{server.Success(req, nil), server.NewEvent("initialized", nil)}
+		case "setBreakpoints":
+			requested <- struct{}{}
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				mu.Lock()
+				acked = true
+				mu.Unlock()
+				server.Send(server.Success(req, map[string]interface{}{
+					"breakpoints": []map[string]interface{}{{"id": 1, "verified": true, "line": 8}},
+				}))
+			}()
+			return []godap.Message{}
+		case "configurationDone":
+			mu.Lock()
+			hit := acked
+			mu.Unlock()
+			replies := []godap.Message{server.Success(req, nil)}
+			if hit {
+				replies = append(replies, server.NewEvent("stopped", map[string]interface{}{"reason": "breakpoint", "threadId": 1}))
+			}
+			return replies
+		}
+		return nil
+	})
+}
+
+// TestAutoloadBreakpointBeforeConfigurationDone sets a breakpoint in an
+// autoload script concurrently with the launch. LaunchAfterBreakpoints holds
+// configurationDone until Godot acknowledges it, so it is hit;
+// LaunchWithConfigurationDone starts the game too early and misses it.
+func TestAutoloadBreakpointBeforeConfigurationDone(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		wait    bool
+		wantHit bool
+	}{
+		{"wait for breakpoints", true, true},
+		{"no wait", false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer(t)
+			defer server.Close()
+			requested := make(chan struct{}, 1)
+			go serveAutoloadGame(server, requested)
+
+			client := dap.NewClient("localhost", server.Port())
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer client.Disconnect()
+			if _, err := client.Initialize(ctx); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			events, cleanup := client.SubscribeToEvents()
+			defer cleanup()
+
+			bpErr := make(chan error, 1)
+			go func() {
+				_, err := client.SetBreakpoints(ctx, "/project/autoload/game_state.gd", []int{8})
+				bpErr <- err
+			}()
+			<-requested
+
+			launch := client.LaunchWithConfigurationDone
+			if tc.wait {
+				launch = client.LaunchAfterBreakpoints
+			}
+			if _, err := launch(ctx, map[string]interface{}{"project": "/project"}); err != nil {
+				t.Fatalf("Launch failed: %v", err)
+			}
+			if err := <-bpErr; err != nil {
+				t.Fatalf("SetBreakpoints failed: %v", err)
+			}
+
+			hit := false
+			timeout := time.After(300 * time.Millisecond)
+		wait:
+			for {
+				select {
+				case msg := <-events:
+					if e, ok := msg.(*godap.StoppedEvent); ok && e.Body.Reason == "breakpoint" {
+						hit = true
+						break wait
+					}
+				case <-timeout:
+					break wait
+				}
+			}
+			if hit != tc.wantHit {
+				t.Errorf("breakpoint hit = %v, want %v", hit, tc.wantHit)
+			}
+
+			acked := false
+			for _, m := range client.LaunchTimeline() {
+				if m.Name == dap.MilestoneBreakpointsAcked {
+					acked = true
+				}
+			}
+			if acked != tc.wait {
+				t.Errorf("breakpoints_acknowledged milestone = %v, want %v", acked, tc.wait)
+			}
+		})
+	}
+}
+
+// TestLaunchWatchdog stalls the launch sequence the ways Godot can: the
+// interleaving deadlock of a client without Godot's quirks (the adapter still
+// answers other requests) and a frozen adapter that answers nothing
+func TestLaunchWatchdog(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		quirks    quirks.Set
+		frozen    bool // Nothing is answered after initialize
+		wantStall bool
+		wantProbe bool
+		wantHint  string
+	}{
+		{"quirk off", quirks.None, false, true, true, "quirks"},
+		{"frozen editor", quirks.Godot(""), true, true, false, "modal dialog"},
+		{"healthy", quirks.Godot(""), false, false, false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer(t)
+			defer server.Close()
+			// Like Godot, launch is answered only once configurationDone arrives
+			godot := SimulateGodot(server)
+			var launch godap.RequestMessage
+			go server.Serve(func(req godap.RequestMessage) []godap.Message {
+				switch command := req.GetRequest().Command; {
+				case tc.frozen && command != "initialize":
+					return []godap.Message{}
+				case command == "launch":
+					launch = req
+					return []godap.Message{}
+				case command == "configurationDone" && launch != nil:
+					return append([]godap.Message{server.Success(launch, nil)}, godot(req)...)
+				}
+				return godot(req)
+			})
+
+			client := dap.NewClient("localhost", server.Port(), dap.WithQuirks(tc.quirks), dap.WithLaunchStallTimeout(200*time.Millisecond))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer client.Disconnect()
+			if _, err := client.Initialize(ctx); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			start := time.Now()
+			_, err := client.LaunchWithConfigurationDone(ctx, map[string]interface{}{"project": "/project"})
+			var stall *dap.LaunchStallError
+			if !tc.wantStall {
+				if err != nil {
+					t.Fatalf("Launch failed: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &stall) {
+				t.Fatalf("Expected a LaunchStallError, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 4*time.Second {
+				t.Errorf("Watchdog took %v, expected it to fire well before the command timeout", elapsed)
+			}
+			if stall.ProbeAnswered != tc.wantProbe {
+				t.Errorf("ProbeAnswered = %v, want %v", stall.ProbeAnswered, tc.wantProbe)
+			}
+			if len(stall.Pending) == 0 || stall.Pending[0] != "launch" {
+				t.Errorf("Expected launch to be pending first, got %v", stall.Pending)
+			}
+			if !strings.Contains(strings.Join(stall.Suggestions, "\n"), tc.wantHint) {
+				t.Errorf("Expected a suggestion mentioning %q, got %v", tc.wantHint, stall.Suggestions)
+			}
+			timeline := client.LaunchTimeline()
+			if len(timeline) == 0 || timeline[len(timeline)-1].Name != dap.MilestoneStalled {
+				t.Errorf("Expected the timeline to end with %s, got %+v", dap.MilestoneStalled, timeline)
+			}
+		})
+	}
+}
+
+func TestSimulateGodot(t *testing.T) {
+	server := NewServer(t)
+	defer server.Close()
+	go server.Serve(SimulateGodot(server))
+
+	client := dap.NewClient("localhost", server.Port())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Disconnect()
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	resp, err := client.SetBreakpoints(ctx, "/project/main.gd", []int{8})
+	if err != nil || len(resp.Body.Breakpoints) != 1 || !resp.Body.Breakpoints[0].Verified {
+		t.Fatalf("Expected a verified breakpoint, got %+v, %v", resp, err)
+	}
+
+	events, cleanup := client.SubscribeToEvents()
+	defer cleanup()
+	waitFor := func(name string) error {
+		for {
+			select {
+			case msg := <-events:
+				if event, ok := msg.(godap.EventMessage); ok && event.GetEvent().Event == name {
+					return nil
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	// The game stops at the breakpoint once configured, then one line further per step
+	for _, step := range []struct {
+		run  func() error
+		line int
+	}{
+		{func() error { return client.ConfigurationDone(ctx) }, 8},
+		{func() error { _, err := client.Next(ctx, 1); return err }, 9},
+	} {
+		if err := step.run(); err != nil {
+			t.Fatal(err)
+		}
+		if err := waitFor("stopped"); err != nil {
+			t.Fatalf("Expected a stop: %v", err)
+		}
+		trace, err := client.StackTrace(ctx, 1, 0, 1)
+		if err != nil || len(trace.Body.StackFrames) != 1 || trace.Body.StackFrames[0].Line != step.line {
+			t.Fatalf("Expected a frame at line %d, got %+v, %v", step.line, trace, err)
+		}
+	}
+
+	if err := client.Terminate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitFor("exited"); err != nil {
+		t.Errorf("Expected the game to exit: %v", err)
+	}
+}
+
+// serveLaunchOrder answers launch the way Godot does (only once
+// configurationDone arrives) or the way the spec expects (on its own, after
+// a delay), and reports whether configurationDone was sent before launch was
+// answered. singleThread receives the singleThread argument of each next.
+func serveLaunchOrder(server *MockServer, godot bool, early chan<- bool, singleThread chan<- bool) {
+	var mu sync.Mutex
+	var launch godap.RequestMessage
+	answered := false
+	server.Serve(func(req godap.RequestMessage) []godap.Message {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.GetRequest().Command {
+		case "initialize":
+			return []godap.Message{
+				server.Success(req, map[string]interface{}{"supportsSingleThreadExecutionRequests": true}),
+				server.NewEvent("initialized", nil),
+			}
+		case "launch":
+			if godot {
+				launch = req
+				return []godap.Message{}
+			}
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				mu.Lock()
+				answered = true
+				mu.Unlock()
+				server.Send(server.Success(req, nil))
+			}()
+			return []godap.Message{}
+		case "configurationDone":
+			early <- !answered
+			replies := []godap.Message{server.Success(req, nil)}
+			if launch != nil {
+				replies = append(replies, server.Success(launch, nil))
+			}
+			return replies
+		case "next":
+			singleThread <- req.(*godap.NextRequest).Arguments.SingleThread
+		}
+		return nil
+	})
+}
+
+// TestClientQuirks runs the client against a spec-compliant server without
+// quirks and against a Godot-like one with quirks.Godot
+func TestClientQuirks(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		quirks quirks.Set
+	}{
+		{"spec", quirks.None},
+		{"godot", quirks.Godot("4.3")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			godot := tc.quirks.LaunchResponseAfterConfigurationDone
+			server := NewServer(t)
+			defer server.Close()
+			early := make(chan bool, 1)
+			singleThread := make(chan bool, 1)
+			go serveLaunchOrder(server, godot, early, singleThread)
+
+			var logs bytes.Buffer
+			client := dap.NewClient("localhost", server.Port(), dap.WithQuirks(tc.quirks), dap.WithLogger(log.New(&logs, "", 0)))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer client.Disconnect()
+			if _, err := client.Initialize(ctx); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			// Godot logs an error for every optional field left out
+			explicit := strings.Contains(logs.String(), `"supportsInvalidatedEvent": false`)
+			if explicit != tc.quirks.ReadsOptionalFieldsUnsafely {
+				t.Errorf("explicit optional fields = %v, want %v", explicit, tc.quirks.ReadsOptionalFieldsUnsafely)
+			}
+
+			if _, err := client.LaunchWithConfigurationDone(ctx, map[string]interface{}{"project": "/project"}); err != nil {
+				t.Fatalf("Launch failed: %v", err)
+			}
+			if sentEarly := <-early; sentEarly != godot {
+				t.Errorf("configurationDone sent before the launch response = %v, want %v", sentEarly, godot)
+			}
+
+			// Godot steps every thread whatever singleThread says
+			if client.SupportsSingleThreadExecution() == tc.quirks.IgnoresStepArguments {
+				t.Errorf("SupportsSingleThreadExecution should be %v", !tc.quirks.IgnoresStepArguments)
+			}
+			if _, err := client.NextThread(ctx, 1, true); err != nil {
+				t.Fatalf("Next failed: %v", err)
+			}
+			if sent := <-singleThread; sent == tc.quirks.IgnoresStepArguments {
+				t.Errorf("singleThread sent = %v, want %v", sent, !tc.quirks.IgnoresStepArguments)
+			}
+		})
+	}
+}